@@ -42,6 +42,7 @@ func main() {
 			log.Printf("THERE IS A NEW RELEASE: %v", newRelease)
 		}
 	}
+	cli.Version = GitTag
 	ctx := kong.Parse(&cli.CLI, kong.Name("telliot"),
 		kong.Description("The official Tellor cli tool"),
 		kong.UsageOnError())
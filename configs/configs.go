@@ -0,0 +1,16 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+// Package configs embeds the starter index and manual data files
+// so that these can be generated without a checkout of the repository.
+package configs
+
+import (
+	_ "embed"
+)
+
+//go:embed index.json
+var Index []byte
+
+//go:embed manualData.json
+var ManualData []byte
@@ -5,6 +5,7 @@ go 1.16
 require (
 	github.com/alecthomas/kong v0.2.18-0.20210609031350-33ce628ecde8
 	github.com/allegro/bigcache v1.2.1 // indirect
+	github.com/aws/aws-sdk-go v1.38.35
 	github.com/bluele/gcache v0.0.2
 	github.com/btcsuite/btcd v0.21.0-beta // indirect
 	github.com/cespare/cp v1.1.1 // indirect
@@ -13,7 +14,10 @@ require (
 	github.com/ethereum/go-ethereum v1.10.3
 	github.com/fatih/structtag v1.2.0
 	github.com/go-kit/kit v0.10.0
+	github.com/golang/protobuf v1.5.2
+	github.com/golang/snappy v0.0.3
 	github.com/google/go-github/v35 v35.3.1-0.20210613000602-77dd0eb64ad2
+	github.com/google/uuid v1.1.5
 	github.com/itchyny/gojq v0.12.4
 	github.com/joho/godotenv v1.3.0
 	github.com/json-iterator/go v1.1.11
@@ -22,13 +26,23 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/pmezard/go-difflib v1.0.0
 	github.com/prometheus/client_golang v1.11.0
+	github.com/prometheus/client_model v0.2.0
 	github.com/prometheus/common v0.29.0
 	github.com/prometheus/prometheus v1.8.2-0.20210520210015-1838068db5df
 	github.com/rjeczalik/notify v0.9.2 // indirect
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/status-im/keycard-go v0.0.0-20190424133014-d95853db0f48 // indirect
 	github.com/tyler-smith/go-bip39 v1.0.2 // indirect
 	github.com/yalp/jsonpath v0.0.0-20180802001716-5cc68e5049a0
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
 	go.uber.org/goleak v1.1.10
 	golang.org/x/crypto v0.0.0-20210513164829-c07d793c2f9a
+	golang.org/x/sys v0.0.0-20210603081109-ebe580a85c40
+	golang.org/x/term v0.0.0-20210220032956-6a3ed077a48d
+	golang.org/x/time v0.0.0-20210220033141-f8bda1e9f3ba
 	golang.org/x/tools v0.1.1-0.20210317201901-4599a76b0b9a // indirect
+	google.golang.org/grpc v1.40.0
 )
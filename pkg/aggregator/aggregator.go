@@ -47,7 +47,7 @@ func New(
 	cfg Config,
 	tsDB storage.SampleAndChunkQueryable,
 ) (*Aggregator, error) {
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "apply filter logger")
 	}
@@ -72,17 +72,30 @@ func New(
 	}, nil
 }
 
-func (self *Aggregator) ManualValue(oracleName string, reqID int64, ts time.Time) (float64, error) {
-	jsonFile, err := os.Open(self.cfg.ManualDataFile)
+// ManualData are the values of the manual data file by the oracle name and the request id.
+// Each value has a VALUE and a DATE after which it expires.
+type ManualData map[string]map[string]map[string]float64
+
+// LoadManualData reads the manual data file.
+func LoadManualData(path string) (ManualData, error) {
+	jsonFile, err := os.Open(path)
 	if err != nil {
-		return 0, errors.Wrapf(err, "manual data file read Error")
+		return nil, errors.Wrapf(err, "manual data file read Error")
 	}
 	defer jsonFile.Close()
 	byteValue, _ := ioutil.ReadAll(jsonFile)
-	var result map[string]map[string]map[string]float64
+	var result ManualData
 	err = json.Unmarshal([]byte(byteValue), &result)
 	if err != nil {
-		return 0, errors.Wrap(err, "unmarshal manual data file")
+		return nil, errors.Wrap(err, "unmarshal manual data file")
+	}
+	return result, nil
+}
+
+func (self *Aggregator) ManualValue(oracleName string, reqID int64, ts time.Time) (float64, error) {
+	result, err := LoadManualData(self.cfg.ManualDataFile)
+	if err != nil {
+		return 0, err
 	}
 
 	oracleManualVals, ok := result[oracleName]
@@ -420,6 +433,97 @@ func (self *Aggregator) valsAtWithConfidence(symbol string, at time.Time) ([]flo
 	return prices, confidence.Value.(promql.Vector)[0].V * 100, nil
 }
 
+// Ready returns an error when there are no recent
+// index tracker values to produce an aggregated value from.
+func (self *Aggregator) Ready(ctx context.Context) error {
+	query, err := self.promqlEngine.NewInstantQuery(
+		self.tsDB,
+		`count(last_over_time(`+index.ValueMetricName+`[5m]))`,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	defer query.Close()
+	result := query.Exec(ctx)
+	if result.Err != nil {
+		return errors.Wrapf(result.Err, "error evaluating query:%v", query.Statement())
+	}
+	if len(result.Value.(promql.Vector)) == 0 {
+		return errors.New("no values to aggregate in the last 5m")
+	}
+	return nil
+}
+
+// LastUpdate returns the time of the most recent value of the symbol from any source
+// and the interval at which its sources are tracked.
+func (self *Aggregator) LastUpdate(symbol string, at time.Time) (time.Time, time.Duration, error) {
+	resolution, err := self.resolution(symbol, at)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	query, err := self.promqlEngine.NewInstantQuery(
+		self.tsDB,
+		`max(timestamp(last_over_time(`+index.ValueMetricName+`{symbol="`+format.SanitizeMetricName(symbol)+`"}[3h])))`,
+		at,
+	)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	defer query.Close()
+	result := query.Exec(self.ctx)
+	if result.Err != nil {
+		return time.Time{}, 0, errors.Wrapf(result.Err, "error evaluating query:%v", query.Statement())
+	}
+	if len(result.Value.(promql.Vector)) == 0 {
+		return time.Time{}, 0, errors.Errorf("no vals at:%v, query:%v", at, query.Statement())
+	}
+	return time.Unix(int64(result.Value.(promql.Vector)[0].V), 0), resolution, nil
+}
+
+// SymbolStatus is the state of a single tracked symbol.
+type SymbolStatus struct {
+	Symbol     string    `json:"symbol"`
+	LastUpdate time.Time `json:"lastUpdate"`
+	Value      float64   `json:"value"`
+	Confidence float64   `json:"confidence"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// SymbolsStatus returns the time of the last recorded value
+// together with the current median and its confidence for every tracked symbol.
+func (self *Aggregator) SymbolsStatus(ctx context.Context) ([]SymbolStatus, error) {
+	now := time.Now()
+	query, err := self.promqlEngine.NewInstantQuery(
+		self.tsDB,
+		`max by(symbol)(timestamp(last_over_time(`+index.ValueMetricName+`[1h])))`,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+	result := query.Exec(ctx)
+	if result.Err != nil {
+		return nil, errors.Wrapf(result.Err, "error evaluating query:%v", query.Statement())
+	}
+
+	statuses := []SymbolStatus{}
+	for _, sample := range result.Value.(promql.Vector) {
+		status := SymbolStatus{
+			Symbol:     sample.Metric.Get("symbol"),
+			LastUpdate: time.Unix(int64(sample.V), 0),
+		}
+		status.Value, status.Confidence, err = self.MedianAt(status.Symbol, now)
+		if err != nil {
+			status.Error = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Symbol < statuses[j].Symbol })
+	return statuses, nil
+}
+
 // valsAt returns all vals from all indexes at a given time.
 func (self *Aggregator) valsAt(symbol string, at time.Time, lookBack time.Duration) (promql.Vector, error) {
 	query, err := self.promqlEngine.NewInstantQuery(
@@ -0,0 +1,570 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/aggregator"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/notify"
+)
+
+const ComponentName = "alerting"
+
+// The names of the built in rules.
+const (
+	RuleTrackerStale     = "TrackerStale"
+	RuleConfidenceLow    = "ConfidenceLow"
+	RuleNodeDisconnected = "NodeDisconnected"
+	RuleSubmitFailed     = "SubmitFailed"
+	RuleNoSubmit         = "NoSubmit"
+)
+
+// checkTimeout is the maximum time to check the node and the tracked symbols.
+const checkTimeout = 30 * time.Second
+
+type Config struct {
+	LogLevel        string
+	Enabled         bool            `help:"Evaluate the alert rules and send the alerts to the notification sinks and Alertmanager."`
+	Interval        format.Duration `help:"How often to evaluate the rules."`
+	AlertmanagerURL string          `help:"Alertmanager URL like http://localhost:9093 to send the alerts to. Without it the alerts are only sent to the notification sinks."`
+	StaleAfter      format.Duration `help:"Alert when a tracked symbol has no new value for this long. 0 disables the rule."`
+	MinConfidence   float64         `help:"Alert when the confidence of a tracked symbol is below this percentage. 0 disables the rule."`
+	NodeDownFor     format.Duration `help:"How long the ethereum node has to be unreachable before the alert fires."`
+	NoSubmitFor     format.Duration `help:"Alert when an account has no successful submit for this long. 0 disables the rule."`
+	Pager           PagerConfig     `help:"On-call services that get an incident for each firing alert."`
+	Rules           []Rule          `help:"Rules over the internal metrics in addition to the built in ones."`
+}
+
+// Rule compares the series of an internal metric with a threshold.
+type Rule struct {
+	Name      string
+	Metric    string            `help:"Name of the internal metric like telliot_submitterTellor_submit_fails_total."`
+	Labels    map[string]string `help:"Only the series with these label values."`
+	Increase  bool              `help:"Compare how much a counter increased since the last evaluation instead of its value."`
+	Op        string            `help:"Comparison with the threshold: >, >=, <, <=, == or !=."`
+	Threshold float64
+	For       format.Duration `help:"How long the condition has to hold before the alert fires."`
+	Severity  notify.Severity `help:"info, warning or critical."`
+	Summary   string
+}
+
+func (self Rule) compare(v float64) bool {
+	switch self.Op {
+	case ">":
+		return v > self.Threshold
+	case ">=":
+		return v >= self.Threshold
+	case "<":
+		return v < self.Threshold
+	case "<=":
+		return v <= self.Threshold
+	case "==":
+		return v == self.Threshold
+	case "!=":
+		return v != self.Threshold
+	}
+	return false
+}
+
+// builtinRules are the rules over the internal metrics that are always evaluated.
+var builtinRules = []Rule{
+	{
+		Name:      RuleSubmitFailed,
+		Metric:    "telliot_submitterTellor_submit_fails_total",
+		Increase:  true,
+		Op:        ">",
+		Severity:  notify.SeverityWarning,
+		Summary:   "a submit failed",
+		Threshold: 0,
+	},
+	{
+		Name:      RuleSubmitFailed,
+		Metric:    "telliot_submitterTellorMesosphere_submit_fails_total",
+		Increase:  true,
+		Op:        ">",
+		Severity:  notify.SeverityWarning,
+		Summary:   "a submit failed",
+		Threshold: 0,
+	},
+}
+
+// Node is the part of the ethereum client used to check the node connection.
+type Node interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Symbols returns the status of the tracked symbols.
+type Symbols func(ctx context.Context) ([]aggregator.SymbolStatus, error)
+
+// Alert is the state of a rule for a single series.
+type Alert struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels"`
+	Severity    notify.Severity   `json:"severity"`
+	Summary     string            `json:"summary"`
+	ActiveSince time.Time         `json:"activeSince"`
+	Firing      bool              `json:"firing"`
+}
+
+// condition is a single evaluated series of a rule.
+type condition struct {
+	rule   Rule
+	labels map[string]string
+	active bool
+	detail string
+}
+
+// Alerting evaluates the rules at every interval,
+// notifies the sinks about the alerts that start and stop firing
+// and keeps Alertmanager updated with the firing alerts.
+type Alerting struct {
+	logger   log.Logger
+	ctx      context.Context
+	close    context.CancelFunc
+	cfg      Config
+	rules    []Rule
+	gatherer prometheus.Gatherer
+	node     Node
+	symbols  Symbols
+	notifier *notify.Notifier
+	am       *alertmanager
+	pagers   []pager
+
+	cfgMtx     sync.Mutex
+	intervalCh chan time.Duration
+
+	mtx      sync.Mutex
+	alerts   map[string]*Alert
+	counters map[string]float64 // The last values of the counters of the increase rules.
+
+	firing       *prometheus.GaugeVec
+	sendFailures *prometheus.CounterVec
+}
+
+func New(
+	logger log.Logger,
+	ctx context.Context,
+	cfg Config,
+	gatherer prometheus.Gatherer,
+	node Node,
+	symbols Symbols,
+	notifier *notify.Notifier,
+) (*Alerting, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	logger = log.With(logger, "component", ComponentName)
+
+	if cfg.Interval.Duration <= 0 {
+		return nil, errors.Errorf("invalid alerting interval:%v", cfg.Interval)
+	}
+	rules := append([]Rule{}, builtinRules...)
+	if cfg.NoSubmitFor.Duration > 0 {
+		for _, metric := range []string{"telliot_submitterTellor_submit_total", "telliot_submitterTellorMesosphere_submit_total"} {
+			rules = append(rules, Rule{
+				Name:     RuleNoSubmit,
+				Metric:   metric,
+				Increase: true,
+				Op:       "==",
+				For:      cfg.NoSubmitFor,
+				Severity: notify.SeverityCritical,
+				Summary:  "no successful submit",
+			})
+		}
+	}
+	for _, r := range cfg.Rules {
+		if r.Name == "" || r.Metric == "" {
+			return nil, errors.Errorf("alert rule without a name or metric:%+v", r)
+		}
+		if !r.valid() {
+			return nil, errors.Errorf("invalid comparison:%v of alert rule:%v", r.Op, r.Name)
+		}
+		if r.Severity == "" {
+			r.Severity = notify.SeverityWarning
+		}
+		rules = append(rules, r)
+	}
+
+	var am *alertmanager
+	if cfg.AlertmanagerURL != "" {
+		if am, err = newAlertmanager(cfg.AlertmanagerURL); err != nil {
+			return nil, err
+		}
+	}
+
+	pagers, err := newPagers(cfg.Pager)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, close := context.WithCancel(ctx)
+	return &Alerting{
+		logger:     logger,
+		ctx:        ctx,
+		close:      close,
+		cfg:        cfg,
+		rules:      rules,
+		gatherer:   gatherer,
+		node:       node,
+		symbols:    symbols,
+		notifier:   notifier,
+		am:         am,
+		pagers:     pagers,
+		intervalCh: make(chan time.Duration, 1),
+		alerts:     make(map[string]*Alert),
+		counters:   make(map[string]float64),
+		firing: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "firing",
+			Help:      "The number of firing alerts by rule",
+		}, []string{"alertname"}),
+		sendFailures: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "send_failures_total",
+			Help:      "The total number of failed deliveries to Alertmanager and the on-call services by receiver",
+		}, []string{"receiver"}),
+	}, nil
+}
+
+func (self Rule) valid() bool {
+	switch self.Op {
+	case ">", ">=", "<", "<=", "==", "!=":
+		return true
+	}
+	return false
+}
+
+func (self *Alerting) Start() error {
+	cfg := self.config()
+	level.Info(self.logger).Log("msg", "starting", "interval", cfg.Interval, "rules", len(self.rules))
+	ticker := time.NewTicker(cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-self.ctx.Done():
+			return nil
+		case interval := <-self.intervalCh:
+			ticker.Reset(interval)
+			continue
+		case <-ticker.C:
+		}
+		self.evaluate(time.Now())
+	}
+}
+
+func (self *Alerting) Stop() {
+	self.close()
+}
+
+// Reload applies the interval and the thresholds of the built-in rules of a new config without a restart.
+func (self *Alerting) Reload(cfg Config) {
+	self.cfgMtx.Lock()
+	defer self.cfgMtx.Unlock()
+	if cfg.Interval != self.cfg.Interval {
+		select {
+		case <-self.intervalCh:
+		default:
+		}
+		self.intervalCh <- cfg.Interval.Duration
+	}
+	self.cfg.Interval = cfg.Interval
+	self.cfg.StaleAfter = cfg.StaleAfter
+	self.cfg.MinConfidence = cfg.MinConfidence
+	self.cfg.NodeDownFor = cfg.NodeDownFor
+}
+
+func (self *Alerting) config() Config {
+	self.cfgMtx.Lock()
+	defer self.cfgMtx.Unlock()
+	return self.cfg
+}
+
+// evaluate checks all rules and updates the state of their alerts.
+func (self *Alerting) evaluate(now time.Time) {
+	cfg := self.config()
+	conditions := self.checkNode()
+	conditions = append(conditions, self.checkSymbols(now)...)
+	metrics, err := self.checkMetrics()
+	if err != nil {
+		level.Error(self.logger).Log("msg", "gathering the internal metrics", "err", err)
+	}
+	conditions = append(conditions, metrics...)
+
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	fired, resolved := self.update(conditions, now)
+
+	firing := make(map[string]float64)
+	var alerts []*Alert
+	for _, a := range self.alerts {
+		if a.Firing {
+			firing[a.Name]++
+			alerts = append(alerts, a)
+		}
+	}
+	for _, r := range append(self.rules, Rule{Name: RuleTrackerStale}, Rule{Name: RuleConfidenceLow}, Rule{Name: RuleNodeDisconnected}) {
+		self.firing.With(prometheus.Labels{"alertname": r.Name}).Set(firing[r.Name])
+	}
+
+	ctx, cancel := context.WithTimeout(self.ctx, checkTimeout)
+	defer cancel()
+
+	// Alertmanager resolves the alerts that are not sent again so the firing ones are sent at every evaluation.
+	if self.am != nil && (len(alerts) > 0 || len(resolved) > 0) {
+		if err := self.am.send(ctx, alerts, resolved, now, cfg.Interval.Duration); err != nil {
+			self.sendFailures.With(prometheus.Labels{"receiver": "alertmanager"}).Inc()
+			level.Error(self.logger).Log("msg", "sending the alerts to alertmanager", "err", err)
+		}
+	}
+	self.page(ctx, fired, resolved)
+}
+
+// page opens the incidents of the alerts that started firing and resolves the ones of the alerts that stopped.
+func (self *Alerting) page(ctx context.Context, fired, resolved []*Alert) {
+	cfg := self.config()
+	for _, p := range self.pagers {
+		receiver := p.name()
+		for _, a := range fired {
+			if !a.Severity.AtLeast(cfg.Pager.MinSeverity) {
+				continue
+			}
+			if err := p.trigger(ctx, a); err != nil {
+				self.sendFailures.With(prometheus.Labels{"receiver": receiver}).Inc()
+				level.Error(self.logger).Log("msg", "opening the incident", "receiver", receiver, "alert", a.Name, "err", err)
+			}
+		}
+		for _, a := range resolved {
+			if !a.Severity.AtLeast(cfg.Pager.MinSeverity) {
+				continue
+			}
+			if err := p.resolve(ctx, a); err != nil {
+				self.sendFailures.With(prometheus.Labels{"receiver": receiver}).Inc()
+				level.Error(self.logger).Log("msg", "resolving the incident", "receiver", receiver, "alert", a.Name, "err", err)
+			}
+		}
+	}
+}
+
+// update applies the evaluated conditions to the alerts
+// and returns the alerts that started and stopped firing.
+func (self *Alerting) update(conditions []condition, now time.Time) (fired, resolved []*Alert) {
+	seen := make(map[string]bool)
+	for _, c := range conditions {
+		if !c.active {
+			continue
+		}
+		key := alertKey(c.rule.Name, c.labels)
+		seen[key] = true
+		a, ok := self.alerts[key]
+		if !ok {
+			a = &Alert{Name: c.rule.Name, Labels: c.labels, Severity: c.rule.Severity, ActiveSince: now}
+			self.alerts[key] = a
+		}
+		a.Summary = c.rule.Summary
+		if c.detail != "" {
+			a.Summary += ": " + c.detail
+		}
+		if !a.Firing && now.Sub(a.ActiveSince) >= c.rule.For.Duration {
+			a.Firing = true
+			level.Warn(self.logger).Log("msg", "alert firing", "alert", a.Name, "labels", formatLabels(a.Labels), "summary", a.Summary)
+			self.notify(a.Severity, a.Name+" firing", a.Summary+formatLabels(a.Labels))
+			fired = append(fired, a)
+		}
+	}
+
+	for key, a := range self.alerts {
+		if seen[key] {
+			continue
+		}
+		delete(self.alerts, key)
+		if a.Firing {
+			level.Info(self.logger).Log("msg", "alert resolved", "alert", a.Name, "labels", formatLabels(a.Labels))
+			self.notify(notify.SeverityInfo, a.Name+" resolved", a.Summary+formatLabels(a.Labels))
+			resolved = append(resolved, a)
+		}
+	}
+	return fired, resolved
+}
+
+// Alerts returns the active alerts including the ones that are not firing yet.
+func (self *Alerting) Alerts() []Alert {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	alerts := []Alert{}
+	for _, a := range self.alerts {
+		alerts = append(alerts, *a)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alertKey(alerts[i].Name, alerts[i].Labels) < alertKey(alerts[j].Name, alerts[j].Labels)
+	})
+	return alerts
+}
+
+func (self *Alerting) checkNode() []condition {
+	cfg := self.config()
+	rule := Rule{Name: RuleNodeDisconnected, For: cfg.NodeDownFor, Severity: notify.SeverityCritical, Summary: "the ethereum node is not reachable"}
+	if self.node == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(self.ctx, checkTimeout)
+	defer cancel()
+	_, err := self.node.BlockNumber(ctx)
+	c := condition{rule: rule, labels: map[string]string{}, active: err != nil}
+	if err != nil {
+		c.detail = err.Error()
+	}
+	return []condition{c}
+}
+
+func (self *Alerting) checkSymbols(now time.Time) []condition {
+	cfg := self.config()
+	if self.symbols == nil || (cfg.StaleAfter.Duration <= 0 && cfg.MinConfidence <= 0) {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(self.ctx, checkTimeout)
+	defer cancel()
+	statuses, err := self.symbols(ctx)
+	if err != nil {
+		level.Error(self.logger).Log("msg", "getting the status of the tracked symbols", "err", err)
+		return nil
+	}
+	stale := Rule{Name: RuleTrackerStale, Severity: notify.SeverityWarning, Summary: "no new values of the symbol"}
+	confidence := Rule{Name: RuleConfidenceLow, Severity: notify.SeverityWarning, Summary: "the sources of the symbol disagree"}
+
+	var conditions []condition
+	for _, s := range statuses {
+		labels := map[string]string{"symbol": s.Symbol}
+		if cfg.StaleAfter.Duration > 0 {
+			age := now.Sub(s.LastUpdate)
+			conditions = append(conditions, condition{
+				rule:   stale,
+				labels: labels,
+				active: age > cfg.StaleAfter.Duration,
+				detail: fmt.Sprintf("last value %v ago", age.Round(time.Second)),
+			})
+		}
+		if cfg.MinConfidence > 0 && s.Error == "" {
+			conditions = append(conditions, condition{
+				rule:   confidence,
+				labels: labels,
+				active: s.Confidence < cfg.MinConfidence,
+				detail: fmt.Sprintf("confidence %.0f%%", s.Confidence),
+			})
+		}
+	}
+	return conditions
+}
+
+// checkMetrics evaluates the rules over the internal metrics.
+func (self *Alerting) checkMetrics() ([]condition, error) {
+	families, err := self.gatherer.Gather()
+	if err != nil {
+		// The gathered families are still usable when only some collectors failed.
+		level.Debug(self.logger).Log("msg", "gathering some metrics failed", "err", err)
+	}
+	values := make(map[string][]series)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			s := series{labels: make(map[string]string)}
+			for _, l := range m.GetLabel() {
+				s.labels[l.GetName()] = l.GetValue()
+			}
+			switch {
+			case m.GetGauge() != nil:
+				s.value = m.GetGauge().GetValue()
+			case m.GetCounter() != nil:
+				s.value = m.GetCounter().GetValue()
+			case m.GetUntyped() != nil:
+				s.value = m.GetUntyped().GetValue()
+			default:
+				continue
+			}
+			values[f.GetName()] = append(values[f.GetName()], s)
+		}
+	}
+
+	var conditions []condition
+	for _, r := range self.rules {
+		for _, s := range values[r.Metric] {
+			if !s.match(r.Labels) {
+				continue
+			}
+			v := s.value
+			if r.Increase {
+				key := alertKey(r.Metric, s.labels)
+				last, ok := self.counters[key]
+				self.counters[key] = s.value
+				switch {
+				case !ok:
+					v = 0
+				case s.value < last: // Reset.
+					v = s.value
+				default:
+					v = s.value - last
+				}
+			}
+			conditions = append(conditions, condition{rule: r, labels: s.labels, active: r.compare(v), detail: fmt.Sprintf("%v", v)})
+		}
+	}
+	return conditions, errors.Wrap(err, "gather")
+}
+
+// notify sends a notification when a notifier is configured.
+func (self *Alerting) notify(severity notify.Severity, title, msg string) {
+	if self.notifier == nil {
+		return
+	}
+	self.notifier.Notify(notify.Event{Component: ComponentName, Severity: severity, Title: title, Message: msg})
+}
+
+type series struct {
+	labels map[string]string
+	value  float64
+}
+
+func (self series) match(labels map[string]string) bool {
+	for name, value := range labels {
+		if self.labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// alertKey identifies the alert of a rule for a series.
+func alertKey(name string, labels map[string]string) string {
+	return name + formatLabels(labels)
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+labels[name])
+	}
+	return " {" + strings.Join(pairs, ", ") + "}"
+}
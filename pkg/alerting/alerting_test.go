@@ -0,0 +1,121 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/notify"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestIncreaseRule(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	fails := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "submit_fails_total"}, []string{"account"})
+	reg.MustRegister(fails)
+
+	rule := Rule{Name: "SubmitFailed", Metric: "submit_fails_total", Increase: true, Op: ">", Severity: notify.SeverityWarning, Summary: "a submit failed"}
+	a := &Alerting{
+		logger:   log.NewNopLogger(),
+		ctx:      context.Background(),
+		rules:    []Rule{rule},
+		gatherer: reg,
+		alerts:   make(map[string]*Alert),
+		counters: make(map[string]float64),
+	}
+	now := time.Unix(1600000000, 0)
+
+	fails.With(prometheus.Labels{"account": "0xA"}).Add(3)
+	conditions, err := a.checkMetrics()
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(conditions))
+	testutil.Assert(t, !conditions[0].active, "the first evaluation only records the counter")
+
+	fails.With(prometheus.Labels{"account": "0xA"}).Inc()
+	conditions, err = a.checkMetrics()
+	testutil.Ok(t, err)
+	testutil.Assert(t, conditions[0].active, "an increase should activate the rule")
+	fired, _ := a.update(conditions, now)
+	testutil.Equals(t, 1, len(fired))
+	testutil.Equals(t, []Alert{{
+		Name:        "SubmitFailed",
+		Labels:      map[string]string{"account": "0xA"},
+		Severity:    notify.SeverityWarning,
+		Summary:     "a submit failed: 1",
+		ActiveSince: now,
+		Firing:      true,
+	}}, a.Alerts())
+
+	conditions, err = a.checkMetrics()
+	testutil.Ok(t, err)
+	_, resolved := a.update(conditions, now.Add(time.Minute))
+	testutil.Equals(t, 1, len(resolved))
+	testutil.Equals(t, 0, len(a.Alerts()))
+}
+
+func TestRuleFor(t *testing.T) {
+	a := &Alerting{logger: log.NewNopLogger(), alerts: make(map[string]*Alert)}
+	rule := Rule{Name: RuleNodeDisconnected, For: format.Duration{Duration: 5 * time.Minute}}
+	active := []condition{{rule: rule, labels: map[string]string{}, active: true}}
+	now := time.Unix(1600000000, 0)
+
+	a.update(active, now)
+	testutil.Assert(t, !a.Alerts()[0].Firing, "the alert shouldn't fire before the rule holds for long enough")
+	a.update(active, now.Add(5*time.Minute))
+	testutil.Assert(t, a.Alerts()[0].Firing, "the alert should fire once the rule holds for long enough")
+
+	// A pending alert that resolves isn't reported as resolved.
+	a.alerts = make(map[string]*Alert)
+	a.update(active, now)
+	_, resolved := a.update(nil, now.Add(time.Minute))
+	testutil.Equals(t, 0, len(resolved))
+}
+
+func TestPagerDuty(t *testing.T) {
+	var events []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e map[string]interface{}
+		testutil.Ok(t, json.NewDecoder(r.Body).Decode(&e))
+		events = append(events, e)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	a := &Alerting{
+		logger: log.NewNopLogger(),
+		cfg:    Config{Pager: PagerConfig{MinSeverity: notify.SeverityCritical}},
+		pagers: []pager{&pagerDuty{url: srv.URL, key: "KEY", source: "miner", client: &http.Client{}}},
+		alerts: make(map[string]*Alert),
+	}
+	down := Rule{Name: RuleNodeDisconnected, Severity: notify.SeverityCritical, Summary: "the ethereum node is not reachable"}
+	stale := Rule{Name: RuleTrackerStale, Severity: notify.SeverityWarning}
+	active := []condition{
+		{rule: down, labels: map[string]string{}, active: true},
+		{rule: stale, labels: map[string]string{"symbol": "ETH/USD"}, active: true},
+	}
+	now := time.Unix(1600000000, 0)
+
+	// Only the transitions page and the alerts below the severity are left out.
+	for i := 0; i < 3; i++ {
+		fired, resolved := a.update(active, now)
+		a.page(context.Background(), fired, resolved)
+	}
+	fired, resolved := a.update(nil, now.Add(time.Minute))
+	a.page(context.Background(), fired, resolved)
+
+	testutil.Equals(t, 2, len(events))
+	testutil.Equals(t, "trigger", events[0]["event_action"])
+	testutil.Equals(t, "resolve", events[1]["event_action"])
+	testutil.Equals(t, RuleNodeDisconnected, events[0]["dedup_key"])
+	testutil.Equals(t, events[0]["dedup_key"], events[1]["dedup_key"])
+	testutil.Equals(t, "critical", events[0]["payload"].(map[string]interface{})["severity"])
+}
@@ -0,0 +1,75 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package alerting
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// amAlert is an alert in the format of the Alertmanager v2 API.
+type amAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// alertmanager posts the alerts to the Alertmanager v2 API.
+type alertmanager struct {
+	url    string
+	client *http.Client
+}
+
+func newAlertmanager(addr string) (*alertmanager, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse alertmanager url:%v", addr)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("alertmanager url without http or https scheme:%v", addr)
+	}
+	return &alertmanager{
+		url:    strings.TrimSuffix(u.String(), "/") + "/api/v2/alerts",
+		client: &http.Client{},
+	}, nil
+}
+
+// send posts the firing and the resolved alerts.
+// The firing alerts end a few intervals in the future
+// so that Alertmanager resolves them when telliot stops sending them.
+func (self *alertmanager) send(ctx context.Context, firing, resolved []*Alert, now time.Time, interval time.Duration) error {
+	alerts := make([]amAlert, 0, len(firing)+len(resolved))
+	for _, a := range firing {
+		alerts = append(alerts, toAMAlert(a, now.Add(4*interval)))
+	}
+	for _, a := range resolved {
+		alerts = append(alerts, toAMAlert(a, now))
+	}
+
+	return postJSON(ctx, self.client, self.url, nil, alerts)
+}
+
+func toAMAlert(a *Alert, endsAt time.Time) amAlert {
+	labels := map[string]string{
+		"alertname": a.Name,
+		"severity":  string(a.Severity),
+		"job":       "telliot",
+	}
+	for name, value := range a.Labels {
+		labels[name] = value
+	}
+	return amAlert{
+		Labels:      labels,
+		Annotations: map[string]string{"summary": a.Summary},
+		StartsAt:    a.ActiveSince,
+		EndsAt:      endsAt,
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/notify"
+)
+
+// Env variables with the keys of the on-call services.
+const (
+	PagerDutyRoutingKeyEnvName = "PAGERDUTY_ROUTING_KEY"
+	OpsgenieAPIKeyEnvName      = "OPSGENIE_API_KEY"
+)
+
+const (
+	pagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieURL  = "https://api.opsgenie.com/v2/alerts"
+)
+
+type PagerConfig struct {
+	PagerDuty   bool            `help:"Open and resolve PagerDuty incidents through the Events API v2 with the routing key in the PAGERDUTY_ROUTING_KEY env variable."`
+	Opsgenie    bool            `help:"Open and close Opsgenie alerts with the API key in the OPSGENIE_API_KEY env variable."`
+	MinSeverity notify.Severity `help:"Page only for the alerts with at least this severity - info, warning or critical."`
+}
+
+// pager opens an incident when an alert starts firing and resolves it when the alert stops.
+// The incidents are deduplicated by the alert key so an alert that fires again
+// while its incident is open doesn't page again.
+type pager interface {
+	name() string
+	trigger(ctx context.Context, a *Alert) error
+	resolve(ctx context.Context, a *Alert) error
+}
+
+func newPagers(cfg PagerConfig) ([]pager, error) {
+	source, err := os.Hostname()
+	if err != nil {
+		source = "telliot"
+	}
+	var pagers []pager
+	if cfg.PagerDuty {
+		key := os.Getenv(PagerDutyRoutingKeyEnvName)
+		if key == "" {
+			return nil, errors.Errorf("pagerduty is enabled without a routing key in the %v env variable", PagerDutyRoutingKeyEnvName)
+		}
+		pagers = append(pagers, &pagerDuty{url: pagerDutyURL, key: key, source: source, client: &http.Client{}})
+	}
+	if cfg.Opsgenie {
+		key := os.Getenv(OpsgenieAPIKeyEnvName)
+		if key == "" {
+			return nil, errors.Errorf("opsgenie is enabled without an api key in the %v env variable", OpsgenieAPIKeyEnvName)
+		}
+		pagers = append(pagers, &opsgenie{url: opsgenieURL, key: key, source: source, client: &http.Client{}})
+	}
+	return pagers, nil
+}
+
+type pagerDuty struct {
+	url    string
+	key    string
+	source string
+	client *http.Client
+}
+
+func (self *pagerDuty) name() string { return "pagerduty" }
+
+func (self *pagerDuty) trigger(ctx context.Context, a *Alert) error {
+	severity := string(a.Severity)
+	if severity == "" {
+		severity = string(notify.SeverityWarning)
+	}
+	return postJSON(ctx, self.client, self.url, nil, map[string]interface{}{
+		"routing_key":  self.key,
+		"event_action": "trigger",
+		"dedup_key":    alertKey(a.Name, a.Labels),
+		"payload": map[string]interface{}{
+			"summary":        a.Name + ": " + a.Summary + formatLabels(a.Labels),
+			"source":         self.source,
+			"severity":       severity,
+			"timestamp":      a.ActiveSince,
+			"component":      "telliot",
+			"custom_details": a.Labels,
+		},
+	})
+}
+
+func (self *pagerDuty) resolve(ctx context.Context, a *Alert) error {
+	return postJSON(ctx, self.client, self.url, nil, map[string]interface{}{
+		"routing_key":  self.key,
+		"event_action": "resolve",
+		"dedup_key":    alertKey(a.Name, a.Labels),
+	})
+}
+
+type opsgenie struct {
+	url    string
+	key    string
+	source string
+	client *http.Client
+}
+
+func (self *opsgenie) name() string { return "opsgenie" }
+
+func (self *opsgenie) trigger(ctx context.Context, a *Alert) error {
+	priority := "P3"
+	switch a.Severity {
+	case notify.SeverityCritical:
+		priority = "P1"
+	case notify.SeverityInfo:
+		priority = "P5"
+	}
+	return postJSON(ctx, self.client, self.url, self.headers(), map[string]interface{}{
+		"message":     a.Name + formatLabels(a.Labels),
+		"alias":       alertKey(a.Name, a.Labels),
+		"description": a.Summary,
+		"source":      self.source,
+		"priority":    priority,
+		"details":     a.Labels,
+		"tags":        []string{"telliot"},
+	})
+}
+
+func (self *opsgenie) resolve(ctx context.Context, a *Alert) error {
+	u := self.url + "/" + url.PathEscape(alertKey(a.Name, a.Labels)) + "/close?identifierType=alias"
+	return postJSON(ctx, self.client, u, self.headers(), map[string]interface{}{
+		"source": self.source,
+	})
+}
+
+func (self *opsgenie) headers() map[string]string {
+	return map[string]string{"Authorization": "GenieKey " + self.key}
+}
+
+// postJSON posts the payload as json and fails for a non 2xx response.
+func postJSON(ctx context.Context, client *http.Client, addr string, headers map[string]string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshal payload")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, "create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "post")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("response status code not OK code:%v, payload:%v", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
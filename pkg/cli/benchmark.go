@@ -0,0 +1,60 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/mining"
+)
+
+type benchmarkCmd struct {
+	cfg
+	Duration time.Duration `optional:"" default:"10s" help:"how long to run each worker count"`
+	Workers  []int         `optional:"" help:"worker counts to benchmark, defaults to the powers of two up to the number of CPUs"`
+}
+
+// Run hashes with each worker count without connecting to the network
+// so the mining settings can be tuned without sending any submits.
+// The cpu affinity and nice level are taken from the config.
+func (self benchmarkCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	cfg, err := self.parse(logger)
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+
+	workers := self.Workers
+	if len(workers) == 0 {
+		for n := 1; n < runtime.NumCPU(); n *= 2 {
+			workers = append(workers, n)
+		}
+		workers = append(workers, runtime.NumCPU())
+	}
+
+	// Each row is printed when its run completes so the columns have a fixed width.
+	row := "%-8v  %-10v  %v\n"
+	fmt.Fprintf(os.Stdout, row, "WORKERS", "HASHRATE", "PER WORKER")
+	for _, n := range workers {
+		if n < 1 {
+			return errors.Errorf("invalid worker count:%v", n)
+		}
+		miningCfg := cfg.Mining
+		miningCfg.NumProcessors = n
+		result, err := mining.Benchmark(ctx, miningCfg, self.Duration)
+		if err != nil {
+			return errors.Wrapf(err, "benchmarking workers:%v", n)
+		}
+		fmt.Fprintf(os.Stdout, row, n, mining.FormatHashRate(result.HashRate()), mining.FormatHashRate(result.HashRate()/float64(n)))
+	}
+	return nil
+}
@@ -4,10 +4,20 @@
 package cli
 
 import (
+	"context"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
 	"github.com/tellor-io/telliot/pkg/config"
 	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
+	"github.com/tellor-io/telliot/pkg/gasPrice/gasStation"
 	"github.com/tellor-io/telliot/pkg/logging"
 )
 
@@ -18,6 +28,9 @@ const VersionMessage = `
 	Github:  https://github.com/tellor-io/telliot
 `
 
+// Version is the release of the binary set by the main package.
+var Version string
+
 var CLI struct {
 	Transfer transferCmd `cmd:"" help:"Transfer tokens"`
 	Approve  approveCmd  `cmd:"" help:"Approve tokens"`
@@ -30,13 +43,37 @@ var CLI struct {
 		Status   statusCmd   `cmd:"" help:"show stake status"`
 	} `cmd:"" help:"Perform one of the stake operations"`
 	Dispute struct {
-		New   newDisputeCmd `cmd:"" help:"start a new dispute"`
-		Vote  voteCmd       `cmd:"" help:"vote on a open dispute"`
-		List  listCmd       `cmd:"" help:"list open disputes"`
-		Tally tallyCmd      `cmd:"" help:"tally votes for a dispute ID"`
+		New       newDisputeCmd `cmd:"" help:"start a new dispute"`
+		Vote      voteCmd       `cmd:"" help:"vote on a open dispute"`
+		List      listCmd       `cmd:"" help:"list open disputes"`
+		Tally     tallyCmd      `cmd:"" help:"tally votes for a dispute ID"`
+		Unlock    unlockFeeCmd  `cmd:"" help:"unlock the dispute fee of a tallied dispute"`
+		Recommend recommendCmd  `cmd:"" help:"recommend how to vote on open disputes based on the local historical data"`
+		Show      showCmd       `cmd:"" help:"show the dispute details and the local values around the disputed time"`
+		Fee       feeCmd        `cmd:"" help:"show the current dispute fee and the fees locked in the open disputes of the accounts"`
 	} `cmd:"" help:"Perform commands related to disputes"`
+	Gov struct {
+		List  govListCmd  `cmd:"" help:"list open governance votes"`
+		Vote  govVoteCmd  `cmd:"" help:"vote on an open governance vote"`
+		Tally govTallyCmd `cmd:"" help:"tally a governance vote after the voting period"`
+	} `cmd:"" help:"Perform commands related to the TellorX governance votes"`
+	Profit struct {
+		Export   profitExportCmd   `cmd:"" help:"export the rewards and expenses of a period as csv or json"`
+		Report   profitReportCmd   `cmd:"" help:"show the yearly tax report with the income, expenses and the cost basis of the sent TRB"`
+		Backfill profitBackfillCmd `cmd:"" help:"reconstruct the profit history from the chain logs since a block"`
+	} `cmd:"" help:"Perform commands related to the profit history"`
+	Config struct {
+		Generate configGenerateCmd `cmd:"" help:"write a commented default config with the starter index and manual data files"`
+	} `cmd:"" help:"Perform commands related to the config"`
+	Sources struct {
+		Doctor sourcesDoctorCmd `cmd:"" help:"get every source of the index file once and show which symbols have working sources"`
+		Sign   sourcesSignCmd   `cmd:"" help:"sign the index file with an account so that the reporters can get it from a registry"`
+	} `cmd:"" help:"Perform commands related to the data sources"`
+	Txs        txsCmd        `cmd:"" help:"Show the history of the transactions sent by telliot"`
 	Dataserver dataserverCmd `cmd:"" help:"launch only a dataserver instance"`
 	Mine       mineCmd       `cmd:"" help:"Submit data to oracle contracts"`
+	Benchmark  benchmarkCmd  `cmd:"" help:"Measure the mining hashrate with different worker counts"`
+	Worker     workerCmd     `cmd:"" help:"Mine the challenges of a pool coordinator"`
 	Version    VersionCmd    `cmd:"" help:"Show the CLI version information"`
 }
 
@@ -50,7 +87,41 @@ func (cmd *VersionCmd) Run() error {
 }
 
 type cfg struct {
-	Config configPath `type:"existingfile" help:"path to config file"`
+	Config   configPath `type:"existingfile" help:"path to config file"`
+	Profile  string     `optional:"" help:"name of the config file profile to apply on top of the shared settings"`
+	Set      []string   `optional:"" sep:"none" placeholder:"SECTION.FIELD=VALUE" help:"override a config field for this run, for example --set Web.ListenPort=9191, can be repeated"`
+	NodeURL  string     `optional:"" name:"node-url" help:"node URL overriding the NODE_URL env variable"`
+	LogLevel string     `optional:"" help:"log level of all components overriding the configured levels - debug, info, warn or error"`
+}
+
+// parse loads the config file with the selected profile and the overrides of the flags.
+func (self cfg) parse(logger log.Logger) (*config.Config, error) {
+	if self.NodeURL != "" {
+		// The env file doesn't override variables that are already set.
+		if err := os.Setenv(ethereum.NodeURLEnvName, self.NodeURL); err != nil {
+			return nil, errors.Wrap(err, "setting the node URL")
+		}
+	}
+	return config.ParseConfig(logger, string(self.Config), self.Profile, self.override)
+}
+
+// override applies the config overrides of the flags.
+func (self cfg) override(cfg *config.Config) error {
+	if self.LogLevel != "" {
+		if err := config.SetLogLevel(cfg, self.LogLevel); err != nil {
+			return err
+		}
+	}
+	for _, set := range self.Set {
+		parts := strings.SplitN(set, "=", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("invalid override:%v, expected SECTION.FIELD=VALUE", set)
+		}
+		if err := config.Set(cfg, parts[0], parts[1]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type addr struct {
@@ -59,7 +130,24 @@ type addr struct {
 
 type cfgGas struct {
 	cfg
-	GasPrice int `optional:"" help:"gas price to use when running the command"`
+	GasPrice int `optional:"" help:"gas price in gwei to use when running the command, overrides the configured gas price strategy"`
+}
+
+// gasPriceFor returns the gas price set with the flag in gwei
+// or otherwise the price of the strategy configured for the operation.
+func gasPriceFor(ctx context.Context, logger log.Logger, cfg *config.Config, client *ethclient.Client, op gasPrice.Operation, gasPriceGwei int) (*big.Int, error) {
+	if gasPriceGwei > 0 {
+		return big.NewInt(int64(gasPriceGwei) * params.GWei), nil
+	}
+	gasPrices, err := gasStation.New(logger, cfg.GasStation, client)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating gas price tracker")
+	}
+	price, err := gasPrices.For(op).Query(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting gas price for operation:%v", op)
+	}
+	return price, nil
 }
 
 type cfgGasAddr struct {
@@ -81,7 +169,7 @@ type accountsCmd struct {
 func (self *accountsCmd) Run() error {
 	logger := logging.NewLogger()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	_, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -0,0 +1,85 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/configs"
+	"github.com/tellor-io/telliot/pkg/config"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/logging"
+)
+
+type configGenerateCmd struct {
+	Dir      string `default:"configs" help:"folder to write the config.json, index.json and manualData.json files to"`
+	Network  string `optional:"" help:"network to use instead of selecting it by the chain id of the node - mainnet, rinkeby, goerli, polygon, arbitrumTestnet or hardhat"`
+	NoMining bool   `optional:"" help:"disable the submitter so only the trackers, the dataserver api and the voting run"`
+	Force    bool   `optional:"" help:"overwrite the existing files"`
+}
+
+func (self *configGenerateCmd) Run() error {
+	logger := logging.NewLogger()
+
+	cfg := config.DefaultConfig
+	if self.Network != "" {
+		found := false
+		for _, n := range contracts.Networks {
+			if strings.EqualFold(n.Name, self.Network) {
+				found = true
+			}
+		}
+		if !found {
+			return errors.Errorf("unknown network:%v", self.Network)
+		}
+		cfg.Ethereum.Network = self.Network
+	}
+	if self.NoMining {
+		cfg.SubmitterTellor.Enabled = false
+		cfg.SubmitterTellorMesosphere.Enabled = false
+	}
+	cfg.IndexTracker.IndexFile = filepath.Join(self.Dir, "index.json")
+	cfg.Aggregator.ManualDataFile = filepath.Join(self.Dir, "manualData.json")
+	cfg.EnvFile = filepath.Join(self.Dir, ".env")
+
+	var cfgFile bytes.Buffer
+	if err := config.Generate(&cfgFile, &cfg); err != nil {
+		return errors.Wrap(err, "generating the config")
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{name: "config.json", data: cfgFile.Bytes()},
+		{name: "index.json", data: configs.Index},
+		{name: "manualData.json", data: configs.ManualData},
+	}
+	if !self.Force {
+		for _, f := range files {
+			path := filepath.Join(self.Dir, f.name)
+			if _, err := os.Stat(path); err == nil {
+				return errors.Errorf("file already exists, use --force to overwrite it:%v", path)
+			}
+		}
+	}
+	if err := os.MkdirAll(self.Dir, 0755); err != nil {
+		return errors.Wrap(err, "creating the config folder")
+	}
+	for _, f := range files {
+		path := filepath.Join(self.Dir, f.name)
+		if err := ioutil.WriteFile(path, f.data, 0644); err != nil {
+			return errors.Wrapf(err, "writing file:%v", path)
+		}
+		level.Info(logger).Log("msg", "generated", "path", path)
+	}
+	level.Info(logger).Log("msg", "add the private keys and the node url to the env file", "path", cfg.EnvFile)
+	return nil
+}
@@ -6,32 +6,44 @@ package cli
 import (
 	"context"
 	"os"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/oklog/run"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/tellor-io/telliot/pkg/aggregator"
 	"github.com/tellor-io/telliot/pkg/config"
 	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/db"
 	"github.com/tellor-io/telliot/pkg/ethereum"
 	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/notify"
 	psrTellor "github.com/tellor-io/telliot/pkg/psr/tellor"
+	"github.com/tellor-io/telliot/pkg/supervisor"
+	"github.com/tellor-io/telliot/pkg/telemetry"
+	"github.com/tellor-io/telliot/pkg/tracing"
+	"github.com/tellor-io/telliot/pkg/tracker/deviation"
 	"github.com/tellor-io/telliot/pkg/tracker/dispute"
 	"github.com/tellor-io/telliot/pkg/tracker/index"
+	"github.com/tellor-io/telliot/pkg/tracker/tip"
 	"github.com/tellor-io/telliot/pkg/web"
 )
 
 type dataserverCmd struct {
-	Config configPath `type:"existingfile" help:"path to config file"`
+	cfg
 }
 
 func (self dataserverCmd) Run() error {
 	logger := logging.NewLogger()
 
-	cfg, err := config.ParseConfig(logger, string(self.Config))
+	cfg, err := self.parse(logger)
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -39,12 +51,48 @@ func (self dataserverCmd) Run() error {
 	// Defining a global context for starting and stopping of components.
 	ctx := context.Background()
 
+	shutdownTracing, err := tracing.Setup(logger, ctx, cfg.Tracing)
+	if err != nil {
+		return errors.Wrap(err, "setting up tracing")
+	}
+	defer func() {
+		// Flush the spans of the last operations.
+		if err := shutdownTracing(context.Background()); err != nil {
+			level.Error(logger).Log("msg", "shutting down tracing", "err", err)
+		}
+	}()
+
 	// We define our run groups here.
 	var g run.Group
 	// Run groups.
 	{
 		// Handle interupts.
-		g.Add(run.SignalHandler(context.Background(), syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM))
+		g.Add(run.SignalHandler(context.Background(), syscall.SIGINT, syscall.SIGTERM))
+
+		// Reload the config on SIGHUP.
+		reloader := config.NewReloader(logger, ctx, string(self.Config), self.Profile, cfg, self.override)
+		g.Add(func() error {
+			err := reloader.Start()
+			level.Info(logger).Log("msg", "config reloader shutdown complete")
+			return err
+		}, func(error) {
+			reloader.Stop()
+		})
+
+		// Restarts the failed components.
+		supervisor, err := supervisor.New(logger, cfg.Supervisor)
+		if err != nil {
+			return errors.Wrap(err, "creating supervisor")
+		}
+
+		// With a remote db this instance is an index tracker shard
+		// that writes the values of its symbols to the data server at the remote host.
+		if cfg.Db.RemoteHost != "" {
+			if err := addIndexShard(logger, ctx, cfg, &g, supervisor); err != nil {
+				return err
+			}
+			return runGroup(logger, g)
+		}
 
 		// Open the TSDB database.
 		tsdbOptions := tsdb.DefaultOptions()
@@ -74,18 +122,28 @@ func (self dataserverCmd) Run() error {
 			return errors.Wrap(err, "creating ethereum client")
 		}
 
-		index, err := index.New(logger, ctx, cfg.IndexTracker, tsDB, client)
+		netID, err := client.NetworkID(ctx)
+		if err != nil {
+			return errors.Wrap(err, "getting network ID")
+		}
+		if err := addTelemetry(logger, ctx, cfg, &g, netID.Int64(), 0); err != nil {
+			return err
+		}
+
+		appendable, err := addRetryQueue(logger, ctx, cfg, &g, tsDB)
+		if err != nil {
+			return err
+		}
+		index, err := index.New(logger, ctx, cfg.IndexTracker, appendable, client)
 		if err != nil {
 			return errors.Wrap(err, "creating index tracker")
 		}
 
-		g.Add(func() error {
+		supervisor.Add(&g, "indexTracker", func() error {
 			err := index.Run()
 			level.Info(logger).Log("msg", "index shutdown complete")
 			return err
-		}, func(error) {
-			index.Stop()
-		})
+		}, index.Stop)
 
 		// Aggregator.
 		aggregator, err := aggregator.New(logger, ctx, cfg.Aggregator, tsDB)
@@ -93,7 +151,20 @@ func (self dataserverCmd) Run() error {
 			return errors.Wrap(err, "creating aggregator")
 		}
 
-		contractTellor, err := contracts.NewITellor(client)
+		// Notifications.
+		notifier, err := notify.New(logger, ctx, cfg.Notify)
+		if err != nil {
+			return errors.Wrap(err, "creating notifier")
+		}
+		g.Add(func() error {
+			notifier.Start()
+			level.Info(logger).Log("msg", "notifier shutdown complete")
+			return nil
+		}, func(error) {
+			notifier.Stop()
+		})
+
+		contractTellor, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 		if err != nil {
 			return errors.Wrap(err, "create tellor contract instance")
 		}
@@ -106,6 +177,7 @@ func (self dataserverCmd) Run() error {
 			client,
 			contractTellor,
 			psrTellor.New(logger, cfg.PsrTellor, aggregator),
+			notifier,
 		)
 		if err != nil {
 			return errors.Wrap(err, "creating profit tracker")
@@ -118,22 +190,80 @@ func (self dataserverCmd) Run() error {
 			disputeTracker.Stop()
 		})
 
+		// Tip tracker.
+		known := knownRequestIDs(logger, cfg, index)
+		tipTracker, err := tip.New(logger, ctx, cfg.TipTracker, tsDB, client, contractTellor, known)
+		if err != nil {
+			return errors.Wrap(err, "creating tip tracker")
+		}
+		g.Add(func() error {
+			tipTracker.Start()
+			level.Info(logger).Log("msg", "tip tracker shutdown complete")
+			return nil
+		}, func(error) {
+			tipTracker.Stop()
+		})
+
+		if err := addDeviationTracker(logger, ctx, cfg, &g, tsDB, client, contractTellor, psrTellor.New(logger, cfg.PsrTellor, aggregator)); err != nil {
+			return err
+		}
+
+		// The data server has no accounts so the voter only gives recommendations.
+		voter, err := dispute.NewVoter(
+			logger,
+			ctx,
+			cfg.DisputeTracker,
+			client,
+			nil,
+			contractTellor,
+			psrTellor.New(logger, cfg.PsrTellor, aggregator),
+			nil,
+			nil,
+			notifier,
+			nil,
+		)
+		if err != nil {
+			return errors.Wrap(err, "creating dispute voter")
+		}
+		g.Add(func() error {
+			voter.Start()
+			level.Info(logger).Log("msg", "dispute voter shutdown complete")
+			return nil
+		}, func(error) {
+			voter.Stop()
+		})
+
 		// Web/Api server.
 		{
 			srv, err := web.New(logger, ctx, tsDB, cfg.Web)
 			if err != nil {
 				return errors.Wrap(err, "create web server")
 			}
-			g.Add(func() error {
+			supervisor.Add(&g, "web", func() error {
 				err := srv.Start()
 				level.Info(logger).Log("msg", "web server shutdown complete")
 				return err
-			}, func(error) {
-				srv.Stop()
+			}, srv.Stop)
+			srv.AddReadinessCheck("node", func(ctx context.Context) error {
+				return ethereum.NodeReady(ctx, client)
+			})
+			srv.AddReadinessCheck("indexTracker", index.Ready)
+			srv.AddReadinessCheck("aggregator", aggregator.Ready)
+			srv.AddStatusProvider("node", func(ctx context.Context) (interface{}, error) {
+				return ethereum.GetNodeStatus(ctx, client)
 			})
+			srv.AddStatusProvider("symbols", func(ctx context.Context) (interface{}, error) {
+				return aggregator.SymbolsStatus(ctx)
+			})
+			srv.AddStatusProvider("disputes", voter.Recommendations)
+			srv.AddAPIHandler("/tips/unknown", unknownTipsSummary, tipTracker.UnknownHandler())
 		}
 	}
 
+	return runGroup(logger, g)
+}
+
+func runGroup(logger log.Logger, g run.Group) error {
 	if err := g.Run(); err != nil {
 		level.Error(logger).Log("msg", "main exited with error", "err", err)
 		return err
@@ -142,3 +272,146 @@ func (self dataserverCmd) Run() error {
 	level.Info(logger).Log("msg", "main shutdown complete")
 	return nil
 }
+
+// addIndexShard adds the components of an index tracker shard.
+// It tracks only the symbols of its shard and sends the values to the remote write endpoint of the data server
+// which runs all the other trackers so that a large index file is split between more processes.
+func addIndexShard(logger log.Logger, ctx context.Context, cfg *config.Config, g *run.Group, supervisor *supervisor.Supervisor) error {
+	appendable, err := db.NewRemoteAppendable(cfg.Db, os.Getenv(web.APITokenEnvName))
+	if err != nil {
+		return errors.Wrap(err, "creating remote writer")
+	}
+	// The data server can be unavailable for a while during a restart.
+	appendable, err = addRetryQueue(logger, ctx, cfg, g, appendable)
+	if err != nil {
+		return err
+	}
+	tsDB, err := db.NewRemoteDB(cfg.Db)
+	if err != nil {
+		return errors.Wrap(err, "opening remote tsdb DB")
+	}
+	level.Info(logger).Log("msg", "running as an index tracker shard of remote db", "host", cfg.Db.RemoteHost, "port", cfg.Db.RemotePort)
+
+	client, err := ethereum.NewClient(ctx, logger)
+	if err != nil {
+		return errors.Wrap(err, "creating ethereum client")
+	}
+	index, err := index.New(logger, ctx, cfg.IndexTracker, appendable, client)
+	if err != nil {
+		return errors.Wrap(err, "creating index tracker")
+	}
+	supervisor.Add(g, "indexTracker", func() error {
+		err := index.Run()
+		level.Info(logger).Log("msg", "index shutdown complete")
+		return err
+	}, index.Stop)
+
+	// Web server for the metrics and the readiness of the shard.
+	srv, err := web.New(logger, ctx, tsDB, cfg.Web)
+	if err != nil {
+		return errors.Wrap(err, "create web server")
+	}
+	supervisor.Add(g, "web", func() error {
+		err := srv.Start()
+		level.Info(logger).Log("msg", "web server shutdown complete")
+		return err
+	}, srv.Stop)
+	srv.AddReadinessCheck("indexTracker", index.Ready)
+	return nil
+}
+
+// addRetryQueue wraps the appendable of the index tracker with a retry queue
+// so that the values of a failed commit are written later instead of dropped.
+func addRetryQueue(logger log.Logger, ctx context.Context, cfg *config.Config, g *run.Group, appendable storage.Appendable) (storage.Appendable, error) {
+	if cfg.Db.RetryQueueSize == 0 {
+		return appendable, nil
+	}
+	retry, err := db.NewRetryAppendable(logger, ctx, cfg.Db, appendable)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating db retry queue")
+	}
+	g.Add(func() error {
+		err := retry.Start()
+		level.Info(logger).Log("msg", "db retry queue shutdown complete")
+		return err
+	}, func(error) {
+		retry.Stop()
+	})
+	return retry, nil
+}
+
+// addTelemetry adds the reporter of the anonymous stats when the operator enabled it.
+func addTelemetry(logger log.Logger, ctx context.Context, cfg *config.Config, g *run.Group, chainID int64, accounts int) error {
+	if !cfg.Telemetry.Enabled {
+		return nil
+	}
+	// A stateless instance has no directory to keep its id.
+	idDir := cfg.Db.Path
+	if cfg.Db.Stateless {
+		idDir = ""
+	}
+	reporter, err := telemetry.New(logger, ctx, cfg.Telemetry, prometheus.DefaultGatherer, Version, chainID, accounts, idDir)
+	if err != nil {
+		return errors.Wrap(err, "creating telemetry reporter")
+	}
+	g.Add(func() error {
+		reporter.Start()
+		level.Info(logger).Log("msg", "telemetry shutdown complete")
+		return nil
+	}, func(error) {
+		reporter.Stop()
+	})
+	return nil
+}
+
+// addDeviationTracker adds the comparison of the Tellor values with Chainlink and the local aggregates when it is enabled.
+func addDeviationTracker(
+	logger log.Logger,
+	ctx context.Context,
+	cfg *config.Config,
+	g *run.Group,
+	appendable storage.Appendable,
+	client *ethclient.Client,
+	contract *contracts.ITellor,
+	psr *psrTellor.Psr,
+) error {
+	if !cfg.DeviationTracker.Enabled {
+		return nil
+	}
+	tracker, err := deviation.New(logger, ctx, cfg.DeviationTracker, appendable, client, contract, psr)
+	if err != nil {
+		return errors.Wrap(err, "creating deviation tracker")
+	}
+	g.Add(func() error {
+		tracker.Start()
+		level.Info(logger).Log("msg", "deviation tracker shutdown complete")
+		return nil
+	}, func(error) {
+		tracker.Stop()
+	})
+	return nil
+}
+
+// unknownTipsSummary is the API summary of the tipped request ids without sources.
+const unknownTipsSummary = "The tipped request ids without sources, highest tip first."
+
+// knownRequestIDs returns whether a request id has local sources,
+// a manual value or a symbol with sources in the index file used by the index tracker,
+// so that the tip tracker shows the tipped request ids that need new sources.
+func knownRequestIDs(logger log.Logger, cfg *config.Config, tracker *index.IndexTracker) func(id int64) bool {
+	// The manual values are optional.
+	manual, err := aggregator.LoadManualData(cfg.Aggregator.ManualDataFile)
+	if err != nil {
+		level.Debug(logger).Log("msg", "no manual values for the known request ids", "err", err)
+	}
+	return func(id int64) bool {
+		if _, ok := manual["tellor"][strconv.FormatInt(id, 10)]; ok {
+			return true
+		}
+		symbol, err := psrTellor.Symbol(id)
+		if err != nil {
+			return false
+		}
+		return tracker.HasSources(symbol)
+	}
+}
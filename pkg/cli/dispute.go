@@ -5,18 +5,35 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"os"
+	"text/tabwriter"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/tellor-io/telliot/pkg/aggregator"
 	"github.com/tellor-io/telliot/pkg/config"
 	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/db"
 	"github.com/tellor-io/telliot/pkg/ethereum"
 	tEthereum "github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
 	"github.com/tellor-io/telliot/pkg/logging"
 	"github.com/tellor-io/telliot/pkg/math"
+	psrTellor "github.com/tellor-io/telliot/pkg/psr/tellor"
+	"github.com/tellor-io/telliot/pkg/tracker/dispute"
+	"github.com/tellor-io/telliot/pkg/tracker/index"
+	"github.com/tellor-io/telliot/pkg/txs"
 )
 
 type disputeID struct {
@@ -34,7 +51,7 @@ func (self newDisputeCmd) Run() error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -48,7 +65,7 @@ func (self newDisputeCmd) Run() error {
 	if err != nil {
 		return err
 	}
-	contract, err := contracts.NewITellor(client)
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
@@ -62,9 +79,9 @@ func (self newDisputeCmd) Run() error {
 		return errors.Wrap(err, "fetch balance")
 	}
 
-	disputeCost, err := contract.GetUintVar(&bind.CallOpts{Context: ctx}, tEthereum.Keccak256([]byte("_DISPUTE_FEE")))
+	disputeCost, err := dispute.Fee(ctx, contract)
 	if err != nil {
-		return errors.Wrap(err, "get dispute cost")
+		return err
 	}
 
 	if balance.Cmp(disputeCost) < 0 {
@@ -73,21 +90,23 @@ func (self newDisputeCmd) Run() error {
 			math.BigInt18eToFloat(disputeCost))
 	}
 
-	var gasPrice *big.Int
-	if self.GasPrice > 0 {
-		gasPrice = big.NewInt(int64(self.GasPrice) * params.GWei)
+	price, err := gasPriceFor(ctx, logger, cfg, client, gasPrice.OpDispute, self.GasPrice)
+	if err != nil {
+		return err
 	}
 
-	auth, err := tEthereum.PrepareEthTransaction(ctx, client, account, gasPrice)
+	auth, release, err := tEthereum.PrepareEthTransaction(ctx, client, account, price)
 	if err != nil {
 		return errors.Wrapf(err, "prepare ethereum transaction")
 	}
 
 	tx, err := contract.BeginDispute(auth, big.NewInt(self.RequestID), big.NewInt(self.Timestamp), big.NewInt(self.MinerIndex))
 	if err != nil {
+		release()
 		return errors.Wrap(err, "send dispute txn")
 	}
 	level.Info(logger).Log("msg", "dispute started", "tx", tx.Hash())
+	recordTx(logger, cfg, txs.TypeDispute, account.Address, tx)
 	return nil
 }
 
@@ -101,7 +120,7 @@ func (self voteCmd) Run() error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -115,7 +134,7 @@ func (self voteCmd) Run() error {
 	if err != nil {
 		return err
 	}
-	contract, err := contracts.NewITellor(client)
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
@@ -129,34 +148,55 @@ func (self voteCmd) Run() error {
 		return nil
 	}
 
-	var gasPrice *big.Int
-	if self.GasPrice > 0 {
-		gasPrice = big.NewInt(int64(self.GasPrice) * params.GWei)
+	price, err := gasPriceFor(ctx, logger, cfg, client, gasPrice.OpVote, self.GasPrice)
+	if err != nil {
+		return err
 	}
 
-	auth, err := tEthereum.PrepareEthTransaction(ctx, client, account, gasPrice)
+	auth, release, err := tEthereum.PrepareEthTransaction(ctx, client, account, price)
 	if err != nil {
 		return errors.Wrapf(err, "prepare ethereum transaction")
 	}
 	tx, err := contract.Vote(auth, big.NewInt(self.DisputeID), self.Support)
 	if err != nil {
+		release()
 		return errors.Wrapf(err, "submit vote transaction")
 	}
 
 	level.Info(logger).Log("msg", "vote submitted with transaction", "tx", tx.Hash())
+	recordTx(logger, cfg, txs.TypeVote, account.Address, tx)
 	return nil
 }
 
+type optionalDisputeID struct {
+	DisputeID int64 `arg:"" optional:"" help:"the dispute id, when not set runs for all disputes that the accounts started, were disputed in or voted on"`
+}
+
 type tallyCmd struct {
 	cfgGas
-	disputeID
+	optionalDisputeID
 }
 
 func (self tallyCmd) Run() error {
+	return finalizeDisputes(self.cfg, self.GasPrice, self.DisputeID, false)
+}
+
+type unlockFeeCmd struct {
+	cfgGas
+	optionalDisputeID
+}
+
+func (self unlockFeeCmd) Run() error {
+	return finalizeDisputes(self.cfg, self.GasPrice, self.DisputeID, true)
+}
+
+// finalizeDisputes tallies the votes or unlocks the dispute fees
+// of a single dispute or of all disputes that the accounts participated in.
+func finalizeDisputes(flags cfg, gasPriceGwei int, disputeID int64, unlock bool) error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := flags.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -170,30 +210,343 @@ func (self tallyCmd) Run() error {
 	if err != nil {
 		return err
 	}
-	contract, err := contracts.NewITellor(client)
+	if len(accounts) == 0 {
+		return errors.New("no private keys configured")
+	}
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
 
-	var gasPrice *big.Int
-	if self.GasPrice > 0 {
-		gasPrice = big.NewInt(int64(self.GasPrice) * params.GWei)
+	ids := []*big.Int{big.NewInt(disputeID)}
+	if disputeID == 0 {
+		var addrs []common.Address
+		for _, acc := range accounts {
+			addrs = append(addrs, acc.Address)
+		}
+		ids, err = dispute.Participated(ctx, contract, addrs)
+		if err != nil {
+			return errors.Wrap(err, "getting the disputes of the accounts")
+		}
+		level.Info(logger).Log("msg", "disputes the accounts participated in", "count", len(ids))
 	}
 
-	auth, err := tEthereum.PrepareEthTransaction(ctx, client, accounts[0], gasPrice)
+	price, err := gasPriceFor(ctx, logger, cfg, client, gasPrice.OpDispute, gasPriceGwei)
 	if err != nil {
-		return errors.Wrapf(err, "prepare ethereum transaction")
+		return err
+	}
+
+	for _, id := range ids {
+		f, err := dispute.NextFinalization(ctx, contract, id)
+		if err != nil {
+			return err
+		}
+		if (unlock && !f.Unlock) || (!unlock && !f.Tally) {
+			level.Info(logger).Log("msg", "nothing to do for the dispute", "id", id, "tally", f.Tally, "unlock", f.Unlock)
+			continue
+		}
+
+		auth, release, err := tEthereum.PrepareEthTransaction(ctx, client, accounts[0], price)
+		if err != nil {
+			return errors.Wrapf(err, "prepare ethereum transaction")
+		}
+
+		if unlock {
+			tx, err := contract.UnlockDisputeFee(auth, id)
+			if err != nil {
+				release()
+				return errors.Wrapf(err, "unlock dispute fee id:%v", id)
+			}
+			level.Info(logger).Log("msg", "unlock dispute fee submitted", "id", id, "tx", tx.Hash().Hex())
+			recordTx(logger, cfg, txs.TypeUnlockFee, accounts[0].Address, tx)
+			continue
+		}
+		tx, err := contract.TallyVotes(auth, id)
+		if err != nil {
+			release()
+			return errors.Wrapf(err, "run tally votes id:%v", id)
+		}
+		level.Info(logger).Log("msg", "tally votes submitted", "id", id, "tx", tx.Hash().Hex())
+		recordTx(logger, cfg, txs.TypeTally, accounts[0].Address, tx)
+	}
+	return nil
+}
+
+type feeCmd struct {
+	cfg
+}
+
+func (self feeCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	cfg, err := self.parse(logger) // Load the env file.
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+
+	client, err := ethereum.NewClient(ctx, logger)
+	if err != nil {
+		return errors.Wrap(err, "creating ethereum client")
+	}
+
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
+	if err != nil {
+		return errors.Wrap(err, "create tellor contract instance")
+	}
+
+	accounts, err := ethereum.GetAccounts()
+	if err != nil {
+		return errors.Wrap(err, "getting accounts")
+	}
+	var addrs []common.Address
+	for _, acc := range accounts {
+		addrs = append(addrs, acc.Address)
+	}
+
+	fee, err := dispute.Fee(ctx, contract)
+	if err != nil {
+		return err
+	}
+	level.Info(logger).Log("msg", "current dispute fee", "TRB", math.BigInt18eToFloat(fee))
+
+	exposures, err := dispute.Exposures(ctx, contract, addrs)
+	if err != nil {
+		return err
+	}
+	for _, exp := range exposures {
+		balance, err := contract.BalanceOf(&bind.CallOpts{Context: ctx}, exp.Addr)
+		if err != nil {
+			return errors.Wrap(err, "get TRB balance")
+		}
+		level.Info(logger).Log(
+			"msg", "dispute fees locked",
+			"addr", exp.Addr.Hex(),
+			"openDisputes", len(exp.Disputes),
+			"lockedTRB", exp.Locked,
+			"balanceTRB", math.BigInt18eToFloat(balance),
+			"canAffordNewDispute", balance.Cmp(fee) >= 0,
+		)
+	}
+	return nil
+}
+
+type recommendCmd struct {
+	cfg
+	DisputeID int64 `arg:"" optional:"" help:"the dispute id, when not set shows recommendations for all open disputes"`
+}
+
+func (self recommendCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	cfg, err := self.parse(logger) // Load the env file.
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+
+	client, err := ethereum.NewClient(ctx, logger)
+	if err != nil {
+		return errors.Wrap(err, "creating ethereum client")
+	}
+
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
+	if err != nil {
+		return errors.Wrap(err, "create tellor contract instance")
+	}
+
+	tsDB, closeDB, err := openReadOnlyDB(logger, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	aggregator, err := aggregator.New(logger, ctx, cfg.Aggregator, tsDB)
+	if err != nil {
+		return errors.Wrap(err, "creating aggregator")
+	}
+	psr := psrTellor.New(logger, cfg.PsrTellor, aggregator)
+
+	ids := []*big.Int{big.NewInt(self.DisputeID)}
+	if self.DisputeID == 0 {
+		ids, err = dispute.OpenDisputes(ctx, contract)
+		if err != nil {
+			return errors.Wrap(err, "getting open disputes")
+		}
+		level.Info(logger).Log("msg", "open disputes", "count", len(ids))
+	}
+
+	for _, id := range ids {
+		rec, err := dispute.Recommend(ctx, contract, psr, id, cfg.DisputeTracker.AlertThreshold)
+		if err != nil {
+			level.Error(logger).Log("msg", "getting recommendation", "id", id, "err", err)
+			continue
+		}
+		level.Info(logger).Log(
+			"msg", "dispute recommendation",
+			"id", rec.DisputeID,
+			"requestId", rec.RequestID,
+			"timestamp", rec.Timestamp.Format(time.RFC3339),
+			"disputedValue", rec.DisputedValue,
+			"localValue", rec.LocalValue,
+			"support", rec.Support,
+			"confident", rec.Confident,
+			"reason", rec.Reason,
+		)
+	}
+	return nil
+}
+
+// evidenceWindow is how long before and after the disputed value
+// to show the values recorded from the local data sources.
+const evidenceWindow = 10 * time.Minute
+
+type showCmd struct {
+	cfg
+	disputeID
+}
+
+func (self showCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	cfg, err := self.parse(logger) // Load the env file.
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+
+	client, err := ethereum.NewClient(ctx, logger)
+	if err != nil {
+		return errors.Wrap(err, "creating ethereum client")
 	}
 
-	tx, err := contract.TallyVotes(auth, big.NewInt(self.DisputeID))
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
-		return errors.Wrapf(err, "run tally votes if you've already voted")
+		return errors.Wrap(err, "create tellor contract instance")
+	}
+
+	_, executed, votePassed, _, reportedMiner, reportingParty, _, uintVars, tally, err := contract.GetAllDisputeVars(&bind.CallOpts{Context: ctx}, big.NewInt(self.DisputeID))
+	if err != nil {
+		return errors.Wrap(err, "get dispute details")
+	}
+	if uintVars[1].Sign() == 0 {
+		return errors.Errorf("dispute id:%v doesn't exist", self.DisputeID)
+	}
+
+	reqID := uintVars[0].Int64()
+	disputedTime := time.Unix(uintVars[1].Int64(), 0)
+	disputedVal := float64(uintVars[2].Int64()) / psrTellor.DefaultGranularity
+
+	state := "voting open"
+	if executed {
+		state = "rejected"
+		if votePassed {
+			state = "passed"
+		}
+	} else if time.Now().After(time.Unix(uintVars[3].Int64(), 0)) {
+		state = "voting ended, waiting for tally"
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Dispute:\t%v\n", self.DisputeID)
+	fmt.Fprintf(w, "State:\t%v\n", state)
+	fmt.Fprintf(w, "Request ID:\t%v\n", reqID)
+	fmt.Fprintf(w, "Disputed value:\t%v\n", disputedVal)
+	fmt.Fprintf(w, "Disputed time:\t%v\n", disputedTime.UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "Reporter:\t%v\n", reportedMiner.Hex())
+	fmt.Fprintf(w, "Disputer:\t%v\n", reportingParty.Hex())
+	fmt.Fprintf(w, "Fee:\t%v TRB\n", math.BigInt18eToFloat(uintVars[8]))
+	fmt.Fprintf(w, "Votes:\t%v\n", uintVars[4])
+	fmt.Fprintf(w, "Tally:\t%v TRB\n", math.BigInt18eToFloat(tally))
+	fmt.Fprintf(w, "Quorum:\t%v TRB\n", math.BigInt18eToFloat(uintVars[7]))
+	fmt.Fprintf(w, "Voting deadline:\t%v\n", time.Unix(uintVars[3].Int64(), 0).UTC().Format(time.RFC3339))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	symbol, err := psrTellor.Symbol(reqID)
+	if err != nil {
+		level.Warn(logger).Log("msg", "no local data sources for the request id", "err", err)
+		return nil
 	}
 
-	level.Info(logger).Log("msg", "tally votes submitted", "tx", tx.Hash().Hex())
+	tsDB, closeDB, err := openReadOnlyDB(logger, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	mint := timestamp.FromTime(disputedTime.Add(-evidenceWindow))
+	maxt := timestamp.FromTime(disputedTime.Add(evidenceWindow))
+	q, err := tsDB.Querier(ctx, mint, maxt)
+	if err != nil {
+		return errors.Wrap(err, "creating db querier")
+	}
+	defer q.Close()
+
+	set := q.Select(true, &storage.SelectHints{Start: mint, End: maxt},
+		labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, index.ValueMetricName),
+		labels.MustNewMatcher(labels.MatchEqual, "symbol", format.SanitizeMetricName(symbol)),
+	)
+
+	fmt.Printf("\nLocal values for %v within %v of the disputed time:\n\n", symbol, evidenceWindow)
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tSOURCE\tVALUE\tDIFFERENCE")
+	var count int
+	for set.Next() {
+		series := set.At()
+		source := series.Labels().Get("source")
+		it := series.Iterator()
+		for it.Next() {
+			t, v := it.At()
+			if t < mint || t > maxt {
+				continue
+			}
+			count++
+			fmt.Fprintf(w, "%v\t%v\t%v\t%.2f%%\n",
+				timestamp.Time(t).UTC().Format(time.RFC3339),
+				source,
+				v,
+				math.PercentageDiff(v, disputedVal),
+			)
+		}
+		if err := it.Err(); err != nil {
+			return errors.Wrap(err, "reading samples")
+		}
+	}
+	if err := set.Err(); err != nil {
+		return errors.Wrap(err, "selecting series")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if count == 0 {
+		fmt.Println("no values recorded")
+	}
 	return nil
 }
 
+// openReadOnlyDB opens the remote DB when configured or
+// the local DB in read only mode so that it works while the miner is running.
+func openReadOnlyDB(logger log.Logger, cfg *config.Config) (storage.SampleAndChunkQueryable, func(), error) {
+	if cfg.Db.RemoteHost != "" {
+		tsDB, err := db.NewRemoteDB(cfg.Db)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "opening remote tsdb DB")
+		}
+		return tsDB, func() {}, nil
+	}
+	tsDB, err := tsdb.OpenDBReadOnly(cfg.Db.Path, logger)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "opening local tsdb DB")
+	}
+	return tsDB, func() {
+		if err := tsDB.Close(); err != nil {
+			level.Error(logger).Log("msg", "closing the tsdb", "err", err)
+		}
+	}, nil
+}
+
 type listCmd struct {
 	cfgAddr
 }
@@ -204,7 +557,7 @@ func (self listCmd) Run() error {
 	// logger := logging.NewLogger()
 	// ctx := context.Background()
 
-	// cfg, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	// cfg, err := self.parse(logger) // Load the env file.
 	// if err != nil {
 	// 	return errors.Wrap(err, "creating config")
 	// }
@@ -243,7 +596,7 @@ func (self listCmd) Run() error {
 	// }
 
 	// psr := psrTellor.New(logger, cfg.PsrTellor, aggregator)
-	// contract, err := contracts.NewITellor(client)
+	// contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	// if err != nil {
 	// 	return errors.Wrap(err, "create tellor contract instance")
 	// }
@@ -0,0 +1,226 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/config"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/math"
+	"github.com/tellor-io/telliot/pkg/txs"
+)
+
+// govVotesLookback is how far back to list governance votes.
+const govVotesLookback = 30 * 24 * time.Hour
+
+var govVoteResults = []string{"failed", "passed", "invalid"}
+
+type govVoteID struct {
+	VoteID int64 `arg:"" required:"" help:"the governance vote id"`
+}
+
+type govListCmd struct {
+	cfg
+	All bool `help:"include the tallied votes"`
+}
+
+func (self govListCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	_, _, gov, err := newGovernance(ctx, logger, self.cfg)
+	if err != nil {
+		return err
+	}
+
+	count, err := gov.GetVoteCount(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return errors.Wrap(err, "get vote count")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tROUND\tSTARTED\tSUPPORT TRB\tAGAINST TRB\tINVALID TRB\tSTATE\tINITIATOR")
+	for id := count.Int64(); id > 0; id-- {
+		_, uintVars, boolVars, result, _, _, addrVars, err := gov.GetVoteInfo(&bind.CallOpts{Context: ctx}, big.NewInt(id))
+		if err != nil {
+			return errors.Wrapf(err, "get vote info id:%v", id)
+		}
+		// The vote vars are:
+		// 0 - vote round, 1 - start date, 2 - block number, 3 - fee,
+		// 4 - tally date, 5 - support, 6 - against, 7 - invalid query.
+		started := time.Unix(uintVars[1].Int64(), 0)
+		if started.Before(time.Now().Add(-govVotesLookback)) {
+			break
+		}
+		tallied := uintVars[4].Sign() > 0
+		if tallied && !self.All {
+			continue
+		}
+
+		voteType := "proposal"
+		if boolVars[1] {
+			voteType = "dispute"
+		}
+		state := "open"
+		if tallied {
+			state = "tallied"
+			if int(result) < len(govVoteResults) {
+				state += " " + govVoteResults[result]
+			}
+			if boolVars[0] {
+				state += ", executed"
+			}
+		}
+
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			id,
+			voteType,
+			uintVars[0],
+			started.UTC().Format(time.RFC3339),
+			math.BigInt18eToFloat(uintVars[5]),
+			math.BigInt18eToFloat(uintVars[6]),
+			math.BigInt18eToFloat(uintVars[7]),
+			state,
+			addrVars[1].Hex(),
+		)
+	}
+	return w.Flush()
+}
+
+type govVoteCmd struct {
+	cfgGasAddr
+	govVoteID
+	Support bool   `arg:"" required:"" help:"true or false"`
+	Invalid bool   `help:"vote that the disputed query is invalid instead of for or against"`
+	Reason  string `help:"the reason for the vote, it is only logged as the contract doesn't store it"`
+}
+
+func (self govVoteCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	cfg, client, gov, err := newGovernance(ctx, logger, self.cfg)
+	if err != nil {
+		return err
+	}
+	account, err := ethereum.GetAccountByPubAddess(self.Addr)
+	if err != nil {
+		return err
+	}
+
+	voted, err := gov.DidVote(&bind.CallOpts{Context: ctx}, big.NewInt(self.VoteID), account.Address)
+	if err != nil {
+		return errors.Wrapf(err, "check if you've already voted")
+	}
+	if voted {
+		level.Info(logger).Log("msg", "you have already voted on this vote")
+		return nil
+	}
+
+	auth, release, err := prepareGovTransaction(ctx, logger, cfg, client, account, self.GasPrice)
+	if err != nil {
+		return err
+	}
+	tx, err := gov.Vote(auth, big.NewInt(self.VoteID), self.Support, self.Invalid)
+	if err != nil {
+		release()
+		return errors.Wrapf(err, "submit vote transaction")
+	}
+
+	level.Info(logger).Log(
+		"msg", "vote submitted with transaction",
+		"id", self.VoteID,
+		"support", self.Support,
+		"invalid", self.Invalid,
+		"reason", self.Reason,
+		"tx", tx.Hash(),
+	)
+	recordTx(logger, cfg, txs.TypeGovVote, account.Address, tx)
+	return nil
+}
+
+type govTallyCmd struct {
+	cfgGas
+	govVoteID
+}
+
+func (self govTallyCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	cfg, client, gov, err := newGovernance(ctx, logger, self.cfg)
+	if err != nil {
+		return err
+	}
+	accounts, err := ethereum.GetAccounts()
+	if err != nil {
+		return err
+	}
+	if len(accounts) == 0 {
+		return errors.New("no private keys configured")
+	}
+
+	auth, release, err := prepareGovTransaction(ctx, logger, cfg, client, accounts[0], self.GasPrice)
+	if err != nil {
+		return err
+	}
+	tx, err := gov.TallyVotes(auth, big.NewInt(self.VoteID))
+	if err != nil {
+		release()
+		return errors.Wrapf(err, "run tally votes")
+	}
+
+	level.Info(logger).Log("msg", "tally votes submitted", "tx", tx.Hash().Hex())
+	recordTx(logger, cfg, txs.TypeGovTally, accounts[0].Address, tx)
+	return nil
+}
+
+func newGovernance(ctx context.Context, logger log.Logger, flags cfg) (*config.Config, *ethclient.Client, *contracts.Governance, error) {
+	cfg, err := flags.parse(logger) // Load the env file.
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "creating config")
+	}
+
+	client, err := ethereum.NewClient(ctx, logger)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "creating ethereum client")
+	}
+
+	master, err := contracts.NewITellor(logger, client, cfg.Ethereum)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "create tellor contract instance")
+	}
+	gov, err := contracts.NewGovernance(ctx, client, master)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cfg, client, gov, nil
+}
+
+func prepareGovTransaction(ctx context.Context, logger log.Logger, cfg *config.Config, client *ethclient.Client, account *ethereum.Account, gasPriceGwei int) (*bind.TransactOpts, func(), error) {
+	price, err := gasPriceFor(ctx, logger, cfg, client, gasPrice.OpVote, gasPriceGwei)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	auth, release, err := ethereum.PrepareEthTransaction(ctx, client, account, price)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "prepare ethereum transaction")
+	}
+	return auth, release, nil
+}
@@ -5,52 +5,82 @@ package cli
 
 import (
 	"context"
+	"math/big"
+	"os"
 	"syscall"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/oklog/run"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/tellor-io/telliot/pkg/aggregator"
+	"github.com/tellor-io/telliot/pkg/alerting"
 	"github.com/tellor-io/telliot/pkg/config"
 	"github.com/tellor-io/telliot/pkg/contracts"
 	"github.com/tellor-io/telliot/pkg/db"
 	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
 	"github.com/tellor-io/telliot/pkg/gasPrice/gasStation"
+	"github.com/tellor-io/telliot/pkg/leader"
 	"github.com/tellor-io/telliot/pkg/logging"
 	"github.com/tellor-io/telliot/pkg/mining"
+	"github.com/tellor-io/telliot/pkg/notify"
+	"github.com/tellor-io/telliot/pkg/pool"
 	psrTellor "github.com/tellor-io/telliot/pkg/psr/tellor"
 	psrTellorMesosphere "github.com/tellor-io/telliot/pkg/psr/tellorMesosphere"
 	"github.com/tellor-io/telliot/pkg/reward"
+	"github.com/tellor-io/telliot/pkg/stake"
 	"github.com/tellor-io/telliot/pkg/submitter/tellor"
 	"github.com/tellor-io/telliot/pkg/submitter/tellorMesosphere"
+	"github.com/tellor-io/telliot/pkg/supervisor"
 	"github.com/tellor-io/telliot/pkg/tasker"
+	"github.com/tellor-io/telliot/pkg/tracing"
 	"github.com/tellor-io/telliot/pkg/tracker/dispute"
 	"github.com/tellor-io/telliot/pkg/tracker/index"
 	"github.com/tellor-io/telliot/pkg/tracker/profit"
+	"github.com/tellor-io/telliot/pkg/tracker/tip"
 	"github.com/tellor-io/telliot/pkg/transactor"
+	"github.com/tellor-io/telliot/pkg/txs"
 	"github.com/tellor-io/telliot/pkg/web"
+	"github.com/tellor-io/telliot/pkg/web/api"
 )
 
 type mineCmd struct {
-	Config configPath `type:"existingfile" help:"path to config file"`
+	cfg
+	ReplayFrom uint64 `optional:"" help:"replay the rewards and costs of the profit tracker from this block, overrides the configured block"`
 }
 
 func (self mineCmd) Run() error {
 	logger := logging.NewLogger()
 
-	cfg, err := config.ParseConfig(logger, string(self.Config))
+	cfg, err := self.parse(logger)
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
+	if self.ReplayFrom > 0 {
+		cfg.ProfitTracker.ReplayFrom = self.ReplayFrom
+	}
 
 	// Defining a global context for starting and stopping of components.
 	ctx := context.Background()
 
+	shutdownTracing, err := tracing.Setup(logger, ctx, cfg.Tracing)
+	if err != nil {
+		return errors.Wrap(err, "setting up tracing")
+	}
+	defer func() {
+		// Flush the spans of the last operations.
+		if err := shutdownTracing(context.Background()); err != nil {
+			level.Error(logger).Log("msg", "shutting down tracing", "err", err)
+		}
+	}()
+
 	client, err := ethereum.NewClient(ctx, logger)
 	if err != nil {
 		return errors.Wrap(err, "creating ethereum client")
@@ -60,16 +90,78 @@ func (self mineCmd) Run() error {
 	if err != nil {
 		return errors.Wrap(err, "getting accounts")
 	}
+	// Without private keys only the components that don't send transactions run.
+	readOnly := len(accounts) == 0
+	if readOnly {
+		level.Warn(logger).Log("msg", "no private keys so running in read only mode without submitting, staking and voting")
+	}
 
 	// We define our run groups here.
 	var g run.Group
 	// Run groups.
 	{
 		// Handle interupts.
-		g.Add(run.SignalHandler(context.Background(), syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM))
+		g.Add(run.SignalHandler(context.Background(), syscall.SIGINT, syscall.SIGTERM))
+
+		// Restarts the failed components.
+		supervisor, err := supervisor.New(logger, cfg.Supervisor)
+		if err != nil {
+			return errors.Wrap(err, "creating supervisor")
+		}
+
+		// Wait for the pending transactions on shutdown.
+		// It is stopped first so the other components keep running until the drain completes.
+		drainer, err := transactor.NewDrainer(logger, ctx, cfg.Transactor)
+		if err != nil {
+			return errors.Wrap(err, "creating transactions drainer")
+		}
+		g.Add(func() error {
+			err := drainer.Start()
+			level.Info(logger).Log("msg", "transactions drainer shutdown complete")
+			return err
+		}, func(error) {
+			drainer.Stop()
+		})
+
+		// Reload the config on SIGHUP.
+		reloader := config.NewReloader(logger, ctx, string(self.Config), self.Profile, cfg, func(cfg *config.Config) error {
+			if err := self.override(cfg); err != nil {
+				return err
+			}
+			if self.ReplayFrom > 0 {
+				cfg.ProfitTracker.ReplayFrom = self.ReplayFrom
+			}
+			return nil
+		})
+		g.Add(func() error {
+			err := reloader.Start()
+			level.Info(logger).Log("msg", "config reloader shutdown complete")
+			return err
+		}, func(error) {
+			reloader.Stop()
+		})
+
+		// Only the leader of a hot standby pair sends transactions.
+		var elector *leader.Elector
+		if cfg.Leader.Enabled && !readOnly {
+			elector, err = leader.New(logger, ctx, cfg.Leader)
+			if err != nil {
+				return errors.Wrap(err, "creating leader elector")
+			}
+			g.Add(func() error {
+				err := elector.Start()
+				level.Info(logger).Log("msg", "leader elector shutdown complete")
+				return err
+			}, func(error) {
+				elector.Stop()
+			})
+		}
 
 		// Open a local or remote instance of the TSDB database.
 		var tsDB storage.SampleAndChunkQueryable
+		if cfg.Db.Stateless && cfg.Db.RemoteHost == "" {
+			return errors.New("a stateless miner requires a remote DB host to read the data from")
+		}
 		if cfg.Db.RemoteHost != "" {
 			tsDB, err = db.NewRemoteDB(cfg.Db)
 			if err != nil {
@@ -95,28 +187,132 @@ func (self mineCmd) Run() error {
 			level.Warn(logger).Log("msg", "FOR NEW DB INSTANCES IT IS NORMAL TO SEE SOME QUERY ERRORS AS THE DATABASE IS NOT YET POPULATED WITH VALUES")
 		}
 
+		// Notifications.
+		notifier, err := notify.New(logger, ctx, cfg.Notify)
+		if err != nil {
+			return errors.Wrap(err, "creating notifier")
+		}
+		g.Add(func() error {
+			notifier.Start()
+			level.Info(logger).Log("msg", "notifier shutdown complete")
+			return nil
+		}, func(error) {
+			notifier.Stop()
+		})
+
 		// Web/Api server.
-		{
-			srv, err := web.New(logger, ctx, tsDB, cfg.Web)
+		srv, err := web.New(logger, ctx, tsDB, cfg.Web)
+		if err != nil {
+			return errors.Wrap(err, "create web server")
+		}
+		supervisor.Add(&g, "web", func() error {
+			err := srv.Start()
+			level.Info(logger).Log("msg", "web server shutdown complete")
+			return err
+		}, srv.Stop)
+		srv.AddReadinessCheck("node", func(ctx context.Context) error {
+			return ethereum.NodeReady(ctx, client)
+		})
+		srv.AddStatusProvider("node", func(ctx context.Context) (interface{}, error) {
+			return ethereum.GetNodeStatus(ctx, client)
+		})
+
+		// Aggregator.
+		aggregator, err := aggregator.New(logger, ctx, cfg.Aggregator, tsDB)
+		if err != nil {
+			return errors.Wrap(err, "creating aggregator")
+		}
+		srv.AddReadinessCheck("aggregator", aggregator.Ready)
+		srv.AddStatusProvider("symbols", func(ctx context.Context) (interface{}, error) {
+			return aggregator.SymbolsStatus(ctx)
+		})
+
+		// Alerting.
+		if cfg.Alerting.Enabled {
+			alerts, err := alerting.New(logger, ctx, cfg.Alerting, prometheus.DefaultGatherer, client, aggregator.SymbolsStatus, notifier)
 			if err != nil {
-				return errors.Wrap(err, "create web server")
+				return errors.Wrap(err, "creating alerting")
 			}
-			g.Add(func() error {
-				err := srv.Start()
-				level.Info(logger).Log("msg", "web server shutdown complete")
+			supervisor.Add(&g, "alerting", func() error {
+				err := alerts.Start()
+				level.Info(logger).Log("msg", "alerting shutdown complete")
 				return err
-			}, func(error) {
-				srv.Stop()
+			}, alerts.Stop)
+			srv.AddStatusProvider("alerts", func(ctx context.Context) (interface{}, error) {
+				return alerts.Alerts(), nil
+			})
+			reloader.OnReload(func(cfg *config.Config) {
+				alerts.Reload(cfg.Alerting)
 			})
 		}
 
-		// Aggregator.
-		aggregator, err := aggregator.New(logger, ctx, cfg.Aggregator, tsDB)
+		// Transaction and profit history.
+		// A stateless miner keeps these in memory so it doesn't need a persistent volume.
+		var txStore *txs.Store
+		var profitStore *profit.Store
+		if cfg.Db.Stateless {
+			txStore = txs.OpenMemory()
+			profitStore = profit.OpenMemory()
+			level.Info(logger).Log("msg", "running stateless, the transaction and profit histories are kept only in memory")
+		} else {
+			txStore, err = txs.Open(cfg.Db.Path)
+			if err != nil {
+				return errors.Wrap(err, "opening the transaction history")
+			}
+			profitStore, err = profit.Open(cfg.Db.Path)
+			if err != nil {
+				return errors.Wrap(err, "opening the profit history")
+			}
+		}
+
+		// The transactions left pending by the previous run when it didn't wait for these on shutdown.
+		if err := txStore.Resolve(ctx, client, nil); err != nil {
+			level.Error(logger).Log("msg", "resolving the pending transactions of the previous run", "err", err)
+		} else if pending, err := txStore.List(txs.Filter{Status: txs.StatusPending}); err == nil && len(pending) > 0 {
+			level.Warn(logger).Log("msg", "transactions of the previous run are still pending", "count", len(pending), "last", pending[0].Hash)
+		}
+		resolver := txs.NewResolver(logger, ctx, txStore, client, ethUSDPrice(aggregator))
+		g.Add(func() error {
+			resolver.Start()
+			level.Info(logger).Log("msg", "transactions resolver shutdown complete")
+			return nil
+		}, func(error) {
+			resolver.Stop()
+		})
+		srv.AddAPIHandler("/txs", "The history of the transactions sent by telliot, newest first.", txs.Handler(txStore),
+			api.Param{Name: "type", Type: "string", Description: "Only the transactions of this type, for example submit."},
+			api.Param{Name: "account", Type: "string", Description: "Only the transactions of this account."},
+			api.Param{Name: "status", Type: "string", Description: "Only the transactions with this status - pending, success, failed or dropped."},
+			api.Param{Name: "since", Type: "string", Description: "Only the transactions sent within this duration, for example 24h."},
+			api.Param{Name: "limit", Type: "integer", Description: "Maximum number of transactions. Defaults to 100."},
+		)
+
+		srv.AddAPIHandler("/profit", "The realized profit of each account in TRB, ETH and USD.", profit.Handler(profitStore),
+			api.Param{Name: "account", Type: "string", Description: "Only this account."},
+			api.Param{Name: "range", Type: "string", Description: "Only the latest range like 24h or 7d, with the trend from the range before it."},
+		)
+		srv.AddAPIHandler("/profit/export", "The profit events of a period as CSV or JSON.", profit.ExportHandler(profitStore),
+			api.Param{Name: "from", Type: "string", Description: "Start date like 2021-01-31 or RFC3339 time."},
+			api.Param{Name: "to", Type: "string", Description: "End date like 2021-01-31 or RFC3339 time, included."},
+			api.Param{Name: "account", Type: "string", Description: "Only this account."},
+			api.Param{Name: "format", Type: "string", Description: "csv or json. Defaults to csv."},
+		)
+
+		_netID, err := client.NetworkID(ctx)
 		if err != nil {
-			return errors.Wrap(err, "creating aggregator")
+			return errors.Wrap(err, "getting network ID")
+		}
+		netID := _netID.Int64()
+
+		if err := addTelemetry(logger, ctx, cfg, &g, netID, len(accounts)); err != nil {
+			return err
 		}
 
 		// Index tracker.
+		// Fetches new samples on demand when a value is stale right before a submit.
+		// It is available only when the index tracker runs in this process.
+		var fetcher tellor.Fetcher
+
 		// Run only when not using remote DB as it needs to write to the local db.
 		if cfg.Db.RemoteHost == "" {
 			_tsDB, ok := tsDB.(*tsdb.DB)
@@ -125,31 +321,29 @@ func (self mineCmd) Run() error {
 			}
 
 			// Index Tracker.
-			index, err := index.New(logger, ctx, cfg.IndexTracker, _tsDB, client)
+			appendable, err := addRetryQueue(logger, ctx, cfg, &g, _tsDB)
+			if err != nil {
+				return err
+			}
+			index, err := index.New(logger, ctx, cfg.IndexTracker, appendable, client)
 			if err != nil {
 				return errors.Wrapf(err, "creating index tracker")
 			}
 
-			g.Add(func() error {
+			supervisor.Add(&g, "indexTracker", func() error {
 				err := index.Run()
 				level.Info(logger).Log("msg", "index shutdown complete")
 				return err
-			}, func(error) {
-				index.Stop()
-			})
-
-			_netID, err := client.NetworkID(ctx)
-			if err != nil {
-				return errors.Wrap(err, "getting network ID")
-			}
-			netID := _netID.Int64()
+			}, index.Stop)
+			srv.AddReadinessCheck("indexTracker", index.Ready)
+			fetcher = index
 
 			// Dispute tracker.
 			// Run it only when not connected to a remote DB.
 			// A remote DB already runs a dispute tracker so no need to run another one.
 			// Also run and only for mainnet or rinkeby as the tellor oracle exists only on those networks.
 			if netID == 1 || netID == 4 {
-				contractTellor, err := contracts.NewITellor(client)
+				contractTellor, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 				if err != nil {
 					return errors.Wrap(err, "create tellor contract instance")
 				}
@@ -162,6 +356,7 @@ func (self mineCmd) Run() error {
 					client,
 					contractTellor,
 					psrTellor.New(logger, cfg.PsrTellor, aggregator),
+					notifier,
 				)
 				if err != nil {
 					return errors.Wrap(err, "creating profit tracker")
@@ -173,119 +368,283 @@ func (self mineCmd) Run() error {
 				}, func(error) {
 					disputeTracker.Stop()
 				})
+				reloader.OnReload(func(cfg *config.Config) {
+					disputeTracker.Reload(cfg.DisputeTracker)
+				})
+
+				// Tip tracker.
+				known := knownRequestIDs(logger, cfg, index)
+				tipTracker, err := tip.New(logger, ctx, cfg.TipTracker, _tsDB, client, contractTellor, known)
+				if err != nil {
+					return errors.Wrap(err, "creating tip tracker")
+				}
+				srv.AddAPIHandler("/tips/unknown", unknownTipsSummary, tipTracker.UnknownHandler())
+				g.Add(func() error {
+					tipTracker.Start()
+					level.Info(logger).Log("msg", "tip tracker shutdown complete")
+					return nil
+				}, func(error) {
+					tipTracker.Stop()
+				})
+
+				if err := addDeviationTracker(logger, ctx, cfg, &g, _tsDB, client, contractTellor, psrTellor.New(logger, cfg.PsrTellor, aggregator)); err != nil {
+					return err
+				}
 			}
 
 		}
 
-		gasPriceQuerier, err := gasStation.New(logger, cfg.GasStation, client)
+		gasPrices, err := gasStation.New(logger, cfg.GasStation, client)
 		if err != nil {
 			return errors.Wrap(err, "creating gas price tracker")
 		}
+		gasPriceQuerier := gasPrices.For(gasPrice.OpSubmit)
 
-		if cfg.SubmitterTellor.Enabled {
-			// Profit tracker.
-			var accountAddrs []common.Address
-			for _, acc := range accounts {
-				accountAddrs = append(accountAddrs, acc.Address)
-			}
-
-			contractTellor, err := contracts.NewITellor(client)
+		// Dispute voter.
+		// It only reads from the DB so it runs also when using a remote DB.
+		if netID == 1 || netID == 4 {
+			contractTellor, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 			if err != nil {
 				return errors.Wrap(err, "create tellor contract instance")
 			}
-
-			profitTracker, err := profit.NewProfitTracker(logger, ctx, cfg.ProfitTracker, client, contractTellor, accountAddrs)
+			voter, err := dispute.NewVoter(
+				logger,
+				ctx,
+				cfg.DisputeTracker,
+				client,
+				gasPrices.For(gasPrice.OpVote),
+				contractTellor,
+				psrTellor.New(logger, cfg.PsrTellor, aggregator),
+				accounts,
+				txStore,
+				notifier,
+				elector,
+			)
 			if err != nil {
-				return errors.Wrap(err, "creating profit tracker")
+				return errors.Wrap(err, "creating dispute voter")
 			}
 			g.Add(func() error {
-				err := profitTracker.Start()
-				level.Info(logger).Log("msg", "profit tracker shutdown complete")
-				return err
+				voter.Start()
+				level.Info(logger).Log("msg", "dispute voter shutdown complete")
+				return nil
 			}, func(error) {
-				profitTracker.Stop()
+				voter.Stop()
 			})
+			srv.AddStatusProvider("disputes", voter.Recommendations)
 
-			// Event tasker.
-			tasker, taskerChs, err := tasker.New(ctx, logger, cfg.Tasker, client, contractTellor, accounts)
+			var accountAddrs []common.Address
+			for _, acc := range accounts {
+				accountAddrs = append(accountAddrs, acc.Address)
+			}
+			feeTracker, err := dispute.NewFeeTracker(logger, ctx, cfg.DisputeTracker, contractTellor, accountAddrs)
 			if err != nil {
-				return errors.Wrap(err, "creating tasker")
+				return errors.Wrap(err, "creating dispute fee tracker")
 			}
 			g.Add(func() error {
-				err := tasker.Start()
-				level.Info(logger).Log("msg", "tasker shutdown complete")
-				return err
+				feeTracker.Start()
+				level.Info(logger).Log("msg", "dispute fee tracker shutdown complete")
+				return nil
 			}, func(error) {
-				tasker.Stop()
+				feeTracker.Stop()
 			})
+		}
 
-			// Create a submitter for each account.
-			for _, account := range accounts {
-				loggerWithAddr := log.With(logger, "addr", account.Address.String()[:6])
+		if cfg.SubmitterTellor.Enabled {
+			// Profit tracker.
+			accountAddrs, err := profitAddrs(cfg, accounts)
+			if err != nil {
+				return err
+			}
 
-				transactor, err := transactor.New(loggerWithAddr, cfg.Transactor, gasPriceQuerier, client, account)
-				if err != nil {
-					return errors.Wrap(err, "creating transactor")
-				}
+			contractTellor, err := contracts.NewITellor(logger, client, cfg.Ethereum)
+			if err != nil {
+				return errors.Wrap(err, "create tellor contract instance")
+			}
 
-				psr := psrTellor.New(loggerWithAddr, cfg.PsrTellor, aggregator)
+			// Fail before starting anything when a protocol upgrade changed the contracts.
+			compat, err := contracts.CheckCompatibility(ctx, client, contractTellor)
+			if err != nil {
+				return errors.Wrap(err, "checking the contracts compatibility")
+			}
+			if err := compat.Err(); err != nil {
+				if !cfg.Ethereum.IgnoreIncompatible {
+					return err
+				}
+				level.Error(logger).Log("msg", "IGNORING INCOMPATIBLE CONTRACTS, CALLS AND SUBMITS WILL LIKELY FAIL", "err", err)
+			} else {
+				level.Info(logger).Log("msg", "contracts are compatible", "version", compat.Version, "implementation", compat.Implementation.Hex(), "oracle", compat.Oracle.Hex())
+			}
 
-				// Get a channel on which it listens for new data to submit.
-				submitter, submitterCh, err := tellor.New(
-					ctx,
-					loggerWithAddr,
-					cfg.SubmitterTellor,
-					client,
-					contractTellor,
-					account,
-					reward.New(loggerWithAddr, aggregator, contractTellor),
-					transactor,
-					gasPriceQuerier,
-					psr,
-				)
+			// Without any addresses the event filters would match all reporters.
+			if len(accountAddrs) > 0 {
+				profitTracker, err := profit.NewProfitTracker(logger, ctx, cfg.ProfitTracker, client, contractTellor, accountAddrs, profitStore, txStore, usdPrice(aggregator), notifier)
 				if err != nil {
-					return errors.Wrap(err, "creating tellor submitter")
+					return errors.Wrap(err, "creating profit tracker")
 				}
-				g.Add(func() error {
-					err := submitter.Start()
-					level.Info(loggerWithAddr).Log("msg", "tellor submitter shutdown complete")
-					return err
-				}, func(error) {
-					submitter.Stop()
+				srv.AddAPIHandler("/profit/pending", "The submits sent but not mined yet with their projected reward and cost.", profitTracker.PendingHandler(),
+					api.Param{Name: "account", Type: "string", Description: "Only this account."},
+				)
+				reloader.OnReload(func(cfg *config.Config) {
+					profitTracker.Reload(cfg.ProfitTracker.Alerts)
 				})
+				supervisor.Add(&g, "profitTracker", func() error {
+					err := profitTracker.Start()
+					level.Info(logger).Log("msg", "profit tracker shutdown complete")
+					return err
+				}, profitTracker.Stop)
+			}
 
-				// Will be used to cancel pending submissions.
-				tasker.AddSubmitCanceler(submitter)
+			if !readOnly {
+				// Stake top up.
+				if cfg.StakeTopUp.Enabled {
+					topUp, err := stake.New(logger, ctx, cfg.StakeTopUp, client, gasPrices, contractTellor, accounts, txStore, notifier, elector)
+					if err != nil {
+						return errors.Wrap(err, "creating stake top up")
+					}
+					g.Add(func() error {
+						topUp.Start()
+						level.Info(logger).Log("msg", "stake top up shutdown complete")
+						return nil
+					}, func(error) {
+						topUp.Stop()
+					})
+					reloader.OnReload(func(cfg *config.Config) {
+						topUp.Reload(cfg.StakeTopUp)
+					})
+				}
 
-				// The Miner component.
-				miner, err := mining.NewMiningManager(loggerWithAddr, ctx, cfg.Mining, contractTellor, taskerChs[account.Address.String()], submitterCh, client)
+				// Event tasker.
+				tasker, taskerChs, err := tasker.New(ctx, logger, cfg.Tasker, client, contractTellor, accounts)
 				if err != nil {
-					return errors.Wrap(err, "creating miner")
+					return errors.Wrap(err, "creating tasker")
 				}
-				g.Add(func() error {
-					err := miner.Start()
-					level.Info(loggerWithAddr).Log("msg", "miner shutdown complete")
+				supervisor.Add(&g, "tasker", func() error {
+					err := tasker.Start()
+					level.Info(logger).Log("msg", "tasker shutdown complete")
 					return err
-				}, func(error) {
-					miner.Stop()
+				}, tasker.Stop)
+
+				oracle, err := contracts.NewOracle(ctx, client, contractTellor)
+				if err != nil {
+					return errors.Wrap(err, "create oracle contract instance")
+				}
+				level.Info(logger).Log("msg", "oracle contract", "version", oracle.Version(), "addr", oracle.Address().Hex())
+
+				// With the pool the challenges are solved by the remote workers instead of the local miners.
+				var coordinator *pool.Coordinator
+				if cfg.Pool.Enabled {
+					coordinator, err = pool.NewCoordinator(logger, ctx, cfg.Pool, os.Getenv(pool.SecretEnvName), func(ctx context.Context) (*big.Int, error) {
+						return oracle.TimeOfLastNewValue(&bind.CallOpts{Context: ctx})
+					})
+					if err != nil {
+						return errors.Wrap(err, "creating pool coordinator")
+					}
+				}
+
+				// Create a submitter for each account.
+				var submitters []*tellor.Submitter
+				srv.AddStatusProvider("accounts", func(ctx context.Context) (interface{}, error) {
+					statuses := make([]*tellor.Status, 0, len(submitters))
+					for _, submitter := range submitters {
+						status, err := submitter.Status(ctx)
+						if err != nil {
+							return nil, err
+						}
+						statuses = append(statuses, status)
+					}
+					return statuses, nil
 				})
+				for _, account := range accounts {
+					loggerWithAddr := log.With(logger, "account", account.Address.String())
+
+					transactor, err := transactor.New(loggerWithAddr, cfg.Transactor, gasPriceQuerier, client, account, txStore)
+					if err != nil {
+						return errors.Wrap(err, "creating transactor")
+					}
+					drainer.Add(transactor)
+
+					psr := psrTellor.New(loggerWithAddr, cfg.PsrTellor, aggregator)
+
+					// Get a channel on which it listens for new data to submit.
+					submitter, submitterCh, err := tellor.New(
+						ctx,
+						loggerWithAddr,
+						cfg.SubmitterTellor,
+						client,
+						oracle,
+						account,
+						reward.New(loggerWithAddr, aggregator, contractTellor),
+						transactor,
+						gasPriceQuerier,
+						psr,
+						aggregator,
+						fetcher,
+						notifier,
+						elector,
+					)
+					if err != nil {
+						return errors.Wrap(err, "creating tellor submitter")
+					}
+					g.Add(func() error {
+						err := submitter.Start()
+						level.Info(loggerWithAddr).Log("msg", "tellor submitter shutdown complete")
+						return err
+					}, func(error) {
+						submitter.Stop()
+					})
+					reloader.OnReload(func(cfg *config.Config) {
+						submitter.Reload(cfg.SubmitterTellor)
+					})
+
+					// Will be used to cancel pending submissions.
+					tasker.AddSubmitCanceler(submitter)
+					submitters = append(submitters, submitter)
+
+					if coordinator != nil {
+						coordinator.AddAccount(account.Address.String(), taskerChs[account.Address.String()], submitterCh)
+						continue
+					}
+
+					// The Miner component.
+					miner, err := mining.NewMiningManager(loggerWithAddr, ctx, cfg.Mining, contractTellor, taskerChs[account.Address.String()], submitterCh, client, notifier, submitter)
+					if err != nil {
+						return errors.Wrap(err, "creating miner")
+					}
+					g.Add(func() error {
+						err := miner.Start()
+						level.Info(loggerWithAddr).Log("msg", "miner shutdown complete")
+						return err
+					}, func(error) {
+						miner.Stop()
+					})
+				}
+				if coordinator != nil {
+					g.Add(func() error {
+						err := coordinator.Start()
+						level.Info(logger).Log("msg", "pool coordinator shutdown complete")
+						return err
+					}, func(error) {
+						coordinator.Stop()
+					})
+				}
 			}
 		}
 
 		if cfg.SubmitterTellorMesosphere.Enabled {
-			contract, err := contracts.NewITellorMesosphere(client)
+			contract, err := contracts.NewITellorMesosphere(client, cfg.Ethereum)
 			if err != nil {
 				return errors.Wrap(err, "create contract instance")
 			}
 
 			// Create a submitter for each account.
 			for _, account := range accounts {
-				loggerWithAddr := log.With(logger, "addr", account.Address.String()[:6])
+				loggerWithAddr := log.With(logger, "account", account.Address.String())
 				psr := psrTellorMesosphere.New(loggerWithAddr, cfg.PsrTellorMesosphere, aggregator)
-				transactor, err := transactor.New(loggerWithAddr, cfg.Transactor, gasPriceQuerier, client, account)
+				transactor, err := transactor.New(loggerWithAddr, cfg.Transactor, gasPriceQuerier, client, account, txStore)
 				if err != nil {
 					return errors.Wrap(err, "creating transactor")
 				}
+				drainer.Add(transactor)
 
 				submitter, err := tellorMesosphere.New(
 					ctx,
@@ -296,6 +655,7 @@ func (self mineCmd) Run() error {
 					account,
 					transactor,
 					psr,
+					elector,
 				)
 				if err != nil {
 					return errors.Wrap(err, "creating tellor mesosphere submitter")
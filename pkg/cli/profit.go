@@ -0,0 +1,225 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/aggregator"
+	"github.com/tellor-io/telliot/pkg/config"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/tracker/profit"
+)
+
+type profitExportCmd struct {
+	cfg
+	From    string `optional:"" help:"first day to export like 2021-01-31, exports from the start of the history when not set"`
+	To      string `optional:"" help:"last day to export like 2021-12-31, exports up to now when not set"`
+	Account string `optional:"" help:"export only the events of this address"`
+	Format  string `optional:"" default:"csv" enum:"csv,json" help:"output format: csv or json"`
+	Output  string `optional:"" help:"file to write to, prints to the standard output when not set"`
+}
+
+// Run exports the rewards and expenses recorded by the profit tracker in the given period.
+func (self profitExportCmd) Run() error {
+	logger := logging.NewLogger()
+
+	cfg, err := self.parse(logger)
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+
+	from, to, err := profit.ParsePeriod(self.From, self.To)
+	if err != nil {
+		return err
+	}
+
+	store, err := profit.Open(cfg.Db.Path)
+	if err != nil {
+		return err
+	}
+	events, err := store.List(profit.Filter{Account: self.Account, From: from, To: to})
+	if err != nil {
+		return err
+	}
+
+	if self.Output == "" {
+		return profit.Export(os.Stdout, self.Format, events)
+	}
+	f, err := os.Create(self.Output)
+	if err != nil {
+		return errors.Wrap(err, "creating the output file")
+	}
+	if err := profit.Export(f, self.Format, events); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "closing the output file")
+}
+
+type profitReportCmd struct {
+	cfg
+	Year    int    `required:"" help:"calendar year of the report in UTC"`
+	Method  string `optional:"" default:"fifo" enum:"fifo,lifo" help:"cost basis method to match the sent TRB with the rewards: fifo or lifo"`
+	Account string `optional:"" help:"report only this address"`
+	Format  string `optional:"" default:"text" enum:"text,json" help:"output format: text for the totals or json for all the events"`
+}
+
+// Run prints the yearly tax report from the profit history.
+func (self profitReportCmd) Run() error {
+	logger := logging.NewLogger()
+
+	cfg, err := self.parse(logger)
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+
+	store, err := profit.Open(cfg.Db.Path)
+	if err != nil {
+		return err
+	}
+	// All the events up to the end of the year are needed for the cost basis.
+	events, err := store.List(profit.Filter{Account: self.Account})
+	if err != nil {
+		return err
+	}
+	report, err := profit.NewReport(events, self.Year, self.Method)
+	if err != nil {
+		return err
+	}
+
+	if self.Format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	if len(report.Accounts) == 0 {
+		fmt.Printf("no events recorded in %v\n", self.Year)
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tREWARDS\tINCOME USD\tSUBMITS\tEXPENSES USD\tDISPOSALS\tGAIN USD\tUNMATCHED TRB")
+	for _, r := range report.Accounts {
+		var unmatched float64
+		for _, d := range r.Disposals {
+			unmatched += d.Unmatched
+		}
+		fmt.Fprintf(w, "%v\t%v\t%.2f\t%v\t%.2f\t%v\t%.2f\t%.4f\n",
+			r.Account,
+			len(r.Income),
+			r.IncomeUSD,
+			len(r.Expenses),
+			r.ExpenseUSD,
+			len(r.Disposals),
+			r.GainUSD,
+			unmatched,
+		)
+	}
+	return w.Flush()
+}
+
+type profitBackfillCmd struct {
+	cfg
+	FromBlock uint64 `required:"" help:"block from which to reconstruct the rewards and costs, for example the block of the first stake"`
+}
+
+// Run reconstructs the profit history of the accounts from the chain logs
+// so that the rewards and costs from before the first start of the miner are in the history.
+func (self profitBackfillCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	cfg, err := self.parse(logger)
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+
+	client, err := ethereum.NewClient(ctx, logger)
+	if err != nil {
+		return errors.Wrap(err, "creating ethereum client")
+	}
+	accounts, err := ethereum.GetAccounts()
+	if err != nil {
+		return errors.Wrap(err, "getting accounts")
+	}
+	addrs, err := profitAddrs(cfg, accounts)
+	if err != nil {
+		return err
+	}
+	// Without any addresses the event filters would match all reporters.
+	if len(addrs) == 0 {
+		return errors.New("no accounts to backfill, set the private keys or the profit tracker addresses")
+	}
+
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
+	if err != nil {
+		return errors.Wrap(err, "create tellor contract instance")
+	}
+
+	// The prices are read from the DB so the USD values are set only for the events within its retention.
+	tsDB, closeDB, err := openReadOnlyDB(logger, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+	aggr, err := aggregator.New(logger, ctx, cfg.Aggregator, tsDB)
+	if err != nil {
+		return errors.Wrap(err, "creating aggregator")
+	}
+
+	store, err := profit.Open(cfg.Db.Path)
+	if err != nil {
+		return err
+	}
+	before, err := store.List(profit.Filter{})
+	if err != nil {
+		return err
+	}
+
+	tracker, err := profit.NewProfitTracker(logger, ctx, cfg.ProfitTracker, client, contract, addrs, store, nil, usdPrice(aggr), nil)
+	if err != nil {
+		return errors.Wrap(err, "creating profit tracker")
+	}
+	if err := tracker.Backfill(self.FromBlock); err != nil {
+		return err
+	}
+
+	after, err := store.List(profit.Filter{})
+	if err != nil {
+		return err
+	}
+	var noPrice int
+	for _, e := range after {
+		if e.USD == 0 && e.Amount != 0 {
+			noPrice++
+		}
+	}
+	level.Info(logger).Log("msg", "backfilled the profit history", "added", len(after)-len(before), "withoutPrice", noPrice)
+	return nil
+}
+
+// profitAddrs returns the addresses tracked by the profit tracker,
+// the accounts of the private keys and the configured addresses.
+func profitAddrs(cfg *config.Config, accounts []*ethereum.Account) ([]common.Address, error) {
+	var addrs []common.Address
+	for _, acc := range accounts {
+		addrs = append(addrs, acc.Address)
+	}
+	for _, addr := range cfg.ProfitTracker.Addresses {
+		if !common.IsHexAddress(addr) {
+			return nil, errors.Errorf("invalid profit tracker address:%v", addr)
+		}
+		addrs = append(addrs, common.HexToAddress(addr))
+	}
+	return addrs, nil
+}
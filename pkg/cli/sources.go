@@ -0,0 +1,227 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/aggregator"
+	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/tracker/index"
+)
+
+type sourcesDoctorCmd struct {
+	cfg
+	Symbols []string      `optional:"" help:"check only these symbols of the index file"`
+	Timeout time.Duration `optional:"" default:"10s" help:"timeout of each get of the sources without a timeout in the index file"`
+	Verbose bool          `optional:"" help:"show the responses also of the working sources"`
+}
+
+// Run gets every source of the index file once and shows which symbols have working sources.
+// It returns an error when a symbol has no working source so it can be used in scripts.
+func (self sourcesDoctorCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	cfg, err := self.parse(logger) // Load the env file.
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+	if len(self.Symbols) > 0 {
+		cfg.IndexTracker.Symbols = self.Symbols
+	}
+
+	// The node is needed only for the on-chain sources and these fail without it.
+	var client *ethclient.Client
+	if os.Getenv(ethereum.NodeURLEnvName) != "" {
+		client, err = ethereum.NewClient(ctx, logger)
+		if err != nil {
+			level.Warn(logger).Log("msg", "the on-chain sources can't be checked", "err", err)
+		}
+	}
+
+	if cfg.IndexTracker.Registry != "" {
+		fmt.Printf("index file: %v\n", cfg.IndexTracker.Registry)
+	} else {
+		fmt.Printf("index file: %v\n", cfg.IndexTracker.IndexFile)
+	}
+	checks, problems, err := index.CheckSources(ctx, cfg.IndexTracker, client, self.Timeout)
+	for _, p := range problems {
+		fmt.Printf("  %v\n", p.String())
+	}
+	if err != nil {
+		return err
+	}
+	if err := problems.Err(); err != nil {
+		return errors.Wrapf(err, "invalid index file:%v", cfg.IndexTracker.IndexFile)
+	}
+
+	type health struct {
+		sources, ok int
+		failed      []string
+	}
+	symbols := make(map[string]*health)
+	for _, c := range checks {
+		h, ok := symbols[c.Symbol]
+		if !ok {
+			h = &health{}
+			symbols[c.Symbol] = h
+		}
+		h.sources++
+
+		result := "ok"
+		if c.Err != nil {
+			result = "FAIL"
+			h.failed = append(h.failed, c.Source)
+		} else {
+			h.ok++
+		}
+		fmt.Printf("\n[%v] %v %v\n", result, c.Symbol, c.Source)
+		if c.Status != 0 {
+			fmt.Printf("  status: %v %v\n", c.Status, http.StatusText(c.Status))
+		}
+		fmt.Printf("  took: %v\n", c.Duration.Round(time.Millisecond))
+		if c.Err != nil {
+			fmt.Printf("  error: %v\n", c.Err)
+		} else {
+			fmt.Printf("  value: %v at %v\n", c.Value, c.Time.UTC().Format(time.RFC3339))
+		}
+		if c.Body != "" && (c.Err != nil || self.Verbose) {
+			fmt.Printf("  response: %v\n", c.Body)
+		}
+	}
+
+	self.manual(cfg.Aggregator.ManualDataFile)
+
+	names := make([]string, 0, len(symbols))
+	for name := range symbols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println()
+	failing := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SYMBOL\tSOURCES\tWORKING\tHEALTH\tFAILED SOURCES")
+	for _, name := range names {
+		h := symbols[name]
+		state := "healthy"
+		switch {
+		case h.ok == 0:
+			state = "failing"
+			failing++
+		case h.ok < h.sources:
+			state = "degraded"
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", name, h.sources, h.ok, state, strings.Join(h.failed, " "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if failing > 0 {
+		return errors.Errorf("%v of %v symbols have no working source", failing, len(names))
+	}
+	return nil
+}
+
+// manual shows the values of the manual data file and whether these expired.
+func (self sourcesDoctorCmd) manual(path string) {
+	fmt.Printf("\nmanual data file: %v\n", path)
+	data, err := aggregator.LoadManualData(path)
+	if err != nil {
+		fmt.Printf("  error: %v\n", err)
+		return
+	}
+	oracles := make([]string, 0, len(data))
+	for oracle := range data {
+		oracles = append(oracles, oracle)
+	}
+	sort.Strings(oracles)
+	for _, oracle := range oracles {
+		ids := make([]string, 0, len(data[oracle]))
+		for id := range data[oracle] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			entry := data[oracle][id]
+			expires := time.Unix(int64(entry["DATE"]), 0)
+			state := "ok"
+			if time.Now().After(expires) {
+				state = "EXPIRED"
+			}
+			fmt.Printf("  [%v] %v request id %v value: %v expires: %v\n", state, oracle, id, entry["VALUE"], expires.UTC().Format(time.RFC3339))
+		}
+	}
+}
+
+type sourcesSignCmd struct {
+	cfgAddr
+	File    string        `optional:"" type:"existingfile" help:"index file to sign, the configured index file when not set"`
+	Version uint64        `optional:"" help:"version of the index file, the reporters reject an index file that isn't newer than the one they use. The current unix time when not set"`
+	Expires time.Duration `optional:"" default:"720h" help:"how long the reporters accept the index file, sign it again before it expires"`
+}
+
+// Run signs the index file with the account so that it can be served by a registry.
+// The signature is written next to the file with the signature suffix.
+func (self sourcesSignCmd) Run() error {
+	logger := logging.NewLogger()
+
+	cfg, err := self.parse(logger) // Load the env file.
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+	path := self.File
+	if path == "" {
+		path = cfg.IndexTracker.IndexFile
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "read index file path:%s", path)
+	}
+	// Not signing an index file that the reporters would reject.
+	_, problems, err := index.ParseIndex(b)
+	if err != nil {
+		return err
+	}
+	if err := problems.Err(); err != nil {
+		return errors.Wrapf(err, "invalid index file:%v", path)
+	}
+
+	account, err := ethereum.GetAccountByPubAddess(self.Addr)
+	if err != nil {
+		return err
+	}
+	version := self.Version
+	if version == 0 {
+		version = uint64(time.Now().Unix())
+	}
+	expires := time.Now().Add(self.Expires)
+	sig, err := index.Sign(b, version, expires, account.GetPrivateKey())
+	if err != nil {
+		return errors.Wrap(err, "signing the index file")
+	}
+	sigFile, err := json.MarshalIndent(sig, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "marshal the signature")
+	}
+	if err := ioutil.WriteFile(path+index.SignatureSuffix, sigFile, 0644); err != nil {
+		return errors.Wrap(err, "writing the signature")
+	}
+	level.Info(logger).Log("msg", "signed the index file", "signer", account.Address.Hex(), "version", version, "expires", expires.UTC().Format(time.RFC3339), "signature", path+index.SignatureSuffix)
+	return nil
+}
@@ -10,26 +10,33 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
-	"github.com/tellor-io/telliot/pkg/config"
 	"github.com/tellor-io/telliot/pkg/contracts"
 	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
 	"github.com/tellor-io/telliot/pkg/logging"
 	"github.com/tellor-io/telliot/pkg/math"
+	"github.com/tellor-io/telliot/pkg/stake"
+	"github.com/tellor-io/telliot/pkg/txs"
 )
 
 type depositCmd struct {
 	cfgGasAddr
+	NoWait bool `help:"don't wait for the deposit to be confirmed"`
 }
 
+// Run checks everything needed for the stake, deposits it and
+// waits until the account is staked printing the progress of each step.
+// The tellor contract holds the TRB balances itself so unlike
+// other staking contracts the deposit doesn't need an approve first.
 func (self depositCmd) Run() error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -42,16 +49,12 @@ func (self depositCmd) Run() error {
 	if err != nil {
 		return err
 	}
-	contract, err := contracts.NewITellor(client)
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
 
-	balance, err := contract.BalanceOf(&bind.CallOpts{Context: ctx}, account.Address)
-	if err != nil {
-		return errors.Wrap(err, "get TRB balance")
-	}
-
+	level.Info(logger).Log("msg", "step 1/4: checking the stake status")
 	status, startTime, err := contract.GetStakerInfo(&bind.CallOpts{Context: ctx}, account.Address)
 	if err != nil {
 		return errors.Wrap(err, "get stake status")
@@ -62,6 +65,12 @@ func (self depositCmd) Run() error {
 		return nil
 	}
 
+	level.Info(logger).Log("msg", "step 2/4: checking the TRB balance")
+	balance, err := contract.BalanceOf(&bind.CallOpts{Context: ctx}, account.Address)
+	if err != nil {
+		return errors.Wrap(err, "get TRB balance")
+	}
+
 	stakeAmt, err := contract.GetUintVar(nil, ethereum.Keccak256([]byte("_STAKE_AMOUNT")))
 	if err != nil {
 		return errors.Wrap(err, "fetching stake amount")
@@ -72,34 +81,58 @@ func (self depositCmd) Run() error {
 			math.BigInt18eToFloat(balance),
 			math.BigInt18eToFloat(stakeAmt))
 	}
+	level.Info(logger).Log("msg", "balance is enough for the stake", "balance", math.BigInt18eToFloat(balance), "stake", math.BigInt18eToFloat(stakeAmt))
 
-	var gasPrice *big.Int
-	if self.GasPrice > 0 {
-		gasPrice = big.NewInt(int64(self.GasPrice) * params.GWei)
+	price, err := gasPriceFor(ctx, logger, cfg, client, gasPrice.OpStake, self.GasPrice)
+	if err != nil {
+		return err
 	}
 
-	auth, err := ethereum.PrepareEthTransaction(ctx, client, account, gasPrice)
+	level.Info(logger).Log("msg", "step 3/4: sending the deposit transaction")
+	auth, release, err := ethereum.PrepareEthTransaction(ctx, client, account, price)
 	if err != nil {
 		return errors.Wrap(err, "prepare ethereum transaction")
 	}
 
 	tx, err := contract.DepositStake(auth)
 	if err != nil {
+		release()
 		return errors.Wrap(err, "contract failed")
 	}
-	level.Info(logger).Log("msg", "stake depositied", "tx", tx.Hash())
+	level.Info(logger).Log("msg", "deposit transaction sent", "tx", tx.Hash())
+	recordTx(logger, cfg, txs.TypeDeposit, account.Address, tx)
+
+	if self.NoWait {
+		return nil
+	}
+
+	level.Info(logger).Log("msg", "step 4/4: waiting for the deposit confirmation")
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return errors.Wrap(err, "waiting for the deposit transaction")
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return errors.Errorf("deposit transaction failed:%v", tx.Hash().Hex())
+	}
+
+	status, startTime, err = contract.GetStakerInfo(&bind.CallOpts{Context: ctx}, account.Address)
+	if err != nil {
+		return errors.Wrap(err, "get stake status")
+	}
+	printStakeStatus(logger, status, startTime)
 	return nil
 }
 
 type withdrawCmd struct {
 	cfgGasAddr
+	Wait bool `help:"wait until the stake is eligible to withdraw and then withdraw it"`
 }
 
 func (self withdrawCmd) Run() error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -113,7 +146,7 @@ func (self withdrawCmd) Run() error {
 	if err != nil {
 		return err
 	}
-	contract, err := contracts.NewITellor(client)
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
@@ -128,21 +161,33 @@ func (self withdrawCmd) Run() error {
 		return nil
 	}
 
-	var gasPrice *big.Int
-	if self.GasPrice > 0 {
-		gasPrice = big.NewInt(int64(self.GasPrice) * params.GWei)
+	if eligible := stake.WithdrawEligibleTime(startTime); time.Now().Before(eligible) {
+		if !self.Wait {
+			printStakeStatus(logger, status, startTime)
+			return errors.New("stake is not eligible to withdraw yet, use --wait to withdraw it automatically once eligible")
+		}
+		level.Info(logger).Log("msg", "waiting until the stake is eligible to withdraw", "eligible", eligible.UTC(), "delta", time.Until(eligible).Round(time.Second))
+		// Wait a bit longer as the contract checks against the block time.
+		time.Sleep(time.Until(eligible) + time.Minute)
 	}
 
-	auth, err := ethereum.PrepareEthTransaction(ctx, client, account, gasPrice)
+	price, err := gasPriceFor(ctx, logger, cfg, client, gasPrice.OpWithdraw, self.GasPrice)
+	if err != nil {
+		return err
+	}
+
+	auth, release, err := ethereum.PrepareEthTransaction(ctx, client, account, price)
 	if err != nil {
 		return errors.Wrap(err, "prepare ethereum transaction")
 	}
 
 	tx, err := contract.WithdrawStake(auth)
 	if err != nil {
+		release()
 		return errors.Wrap(err, "contract")
 	}
-	level.Info(logger).Log("msg", "withdrew stake", "txHash", tx.Hash().Hex())
+	level.Info(logger).Log("msg", "withdrew stake", "tx", tx.Hash().Hex())
+	recordTx(logger, cfg, txs.TypeWithdraw, account.Address, tx)
 
 	return nil
 }
@@ -155,7 +200,7 @@ func (self requestCmd) Run() error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -168,7 +213,7 @@ func (self requestCmd) Run() error {
 	if err != nil {
 		return err
 	}
-	contract, err := contracts.NewITellor(client)
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
@@ -182,22 +227,24 @@ func (self requestCmd) Run() error {
 		return nil
 	}
 
-	var gasPrice *big.Int
-	if self.GasPrice > 0 {
-		gasPrice = big.NewInt(int64(self.GasPrice) * params.GWei)
+	price, err := gasPriceFor(ctx, logger, cfg, client, gasPrice.OpWithdraw, self.GasPrice)
+	if err != nil {
+		return err
 	}
 
-	auth, err := ethereum.PrepareEthTransaction(ctx, client, account, gasPrice)
+	auth, release, err := ethereum.PrepareEthTransaction(ctx, client, account, price)
 	if err != nil {
 		return errors.Wrap(err, "prepare ethereum transaction")
 	}
 
 	tx, err := contract.RequestStakingWithdraw(auth)
 	if err != nil {
+		release()
 		return errors.Wrap(err, "contract")
 	}
 
-	level.Info(logger).Log("msg", "withdrawal request sent", "txHash", tx.Hash().Hex())
+	level.Info(logger).Log("msg", "withdrawal request sent", "tx", tx.Hash().Hex())
+	recordTx(logger, cfg, txs.TypeRequestWithdraw, account.Address, tx)
 
 	return nil
 }
@@ -210,7 +257,7 @@ func (self statusCmd) Run() error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -220,7 +267,7 @@ func (self statusCmd) Run() error {
 		return errors.Wrap(err, "creating ethereum client")
 	}
 
-	contract, err := contracts.NewITellor(client)
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
@@ -250,11 +297,7 @@ func printStakeStatus(logger log.Logger, bigStatus *big.Int, started *big.Int) {
 	case 1:
 		level.Info(logger).Log("msg", "staked in good standing since", "UTC", stakeTime.UTC())
 	case 2:
-		startedRound := started.Int64()
-		startedRound = ((startedRound + 86399) / 86400) * 86400
-		target := time.Unix(startedRound, 0)
-		timePassed := time.Since(target)
-		delta := timePassed - (time.Hour * 24 * 7)
+		delta := time.Since(stake.WithdrawEligibleTime(started))
 		if delta > 0 {
 			level.Info(logger).Log("msg", "stake has been eligbile to withdraw for", "delta", delta)
 		} else {
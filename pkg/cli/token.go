@@ -5,18 +5,17 @@ package cli
 
 import (
 	"context"
-	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
-	"github.com/tellor-io/telliot/pkg/config"
 	"github.com/tellor-io/telliot/pkg/contracts"
 	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
 	"github.com/tellor-io/telliot/pkg/logging"
 	"github.com/tellor-io/telliot/pkg/math"
+	"github.com/tellor-io/telliot/pkg/txs"
 )
 
 type tokenCmd struct {
@@ -32,7 +31,7 @@ func (self *transferCmd) Run() error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -48,7 +47,7 @@ func (self *transferCmd) Run() error {
 	}
 	from := common.HexToAddress(self.From)
 
-	contract, err := contracts.NewITellor(client)
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
@@ -69,28 +68,29 @@ func (self *transferCmd) Run() error {
 			math.BigInt18eToFloat(amount))
 	}
 
-	var gasPrice *big.Int
-	if self.GasPrice > 0 {
-		gasPrice = big.NewInt(int64(self.GasPrice) * params.GWei)
+	price, err := gasPriceFor(ctx, logger, cfg, client, gasPrice.OpTransfer, self.GasPrice)
+	if err != nil {
+		return err
 	}
 
 	acc, err := ethereum.GetAccountByPubAddess(self.From)
 	if err != nil {
 		return errors.Wrap(err, "getting auth account")
 	}
-	fromAuth, err := ethereum.PrepareEthTransaction(ctx, client, acc, gasPrice)
-	if err != nil {
-		return errors.Wrap(err, "preparing ethereum transaction")
-	}
-
 	valid = common.IsHexAddress(self.To)
 	if !valid {
 		return errors.Errorf("invalid etherum address:%v", self.From)
 	}
 	to := common.HexToAddress(self.To)
 
+	fromAuth, release, err := ethereum.PrepareEthTransaction(ctx, client, acc, price)
+	if err != nil {
+		return errors.Wrap(err, "preparing ethereum transaction")
+	}
+
 	tx, err := contract.Transfer(fromAuth, to, amount)
 	if err != nil {
+		release()
 		return errors.Wrap(err, "calling transfer")
 	}
 	level.Info(logger).Log(
@@ -99,6 +99,7 @@ func (self *transferCmd) Run() error {
 		"to", to.String()[:12],
 		"tx", tx.Hash(),
 	)
+	recordTx(logger, cfg, txs.TypeTransfer, acc.Address, tx)
 	return nil
 }
 
@@ -108,7 +109,7 @@ func (self *approveCmd) Run() error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -118,7 +119,7 @@ func (self *approveCmd) Run() error {
 		return errors.Wrap(err, "creating ethereum client")
 	}
 
-	contract, err := contracts.NewITellor(client)
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
@@ -144,9 +145,9 @@ func (self *approveCmd) Run() error {
 			math.BigInt18eToFloat(amount))
 	}
 
-	var gasPrice *big.Int
-	if self.GasPrice > 0 {
-		gasPrice = big.NewInt(int64(self.GasPrice) * params.GWei)
+	price, err := gasPriceFor(ctx, logger, cfg, client, gasPrice.OpTransfer, self.GasPrice)
+	if err != nil {
+		return err
 	}
 
 	acc, err := ethereum.GetAccountByPubAddess(self.From)
@@ -154,36 +155,38 @@ func (self *approveCmd) Run() error {
 		return errors.Wrap(err, "getting auth account")
 	}
 
-	fromAuth, err := ethereum.PrepareEthTransaction(ctx, client, acc, gasPrice)
-	if err != nil {
-		return errors.Wrap(err, "preparing ethereum transaction")
-	}
-
 	valid = common.IsHexAddress(self.To)
 	if !valid {
 		return errors.Errorf("invalid etherum address:%v", self.To)
 	}
 	spender := common.HexToAddress(self.To)
 
+	fromAuth, release, err := ethereum.PrepareEthTransaction(ctx, client, acc, price)
+	if err != nil {
+		return errors.Wrap(err, "preparing ethereum transaction")
+	}
+
 	tx, err := contract.Approve(fromAuth, spender, amount)
 	if err != nil {
+		release()
 		return errors.Wrap(err, "calling approve")
 	}
 	level.Info(logger).Log("msg", "approved", "amount", math.BigInt18eToFloat(amount), "spender", spender.String()[:12], "tx", tx.Hash())
+	recordTx(logger, cfg, txs.TypeApprove, acc.Address, tx)
 	return nil
 
 }
 
 type balanceCmd struct {
-	Config  configPath `type:"existingfile" help:"path to config file"`
-	Address string     `arg:"" optional:""`
+	cfg
+	Address string `arg:"" optional:""`
 }
 
 func (self *balanceCmd) Run() error {
 	logger := logging.NewLogger()
 	ctx := context.Background()
 
-	_, err := config.ParseConfig(logger, string(self.Config)) // Load the env file.
+	cfg, err := self.parse(logger) // Load the env file.
 	if err != nil {
 		return errors.Wrap(err, "creating config")
 	}
@@ -193,7 +196,7 @@ func (self *balanceCmd) Run() error {
 		return errors.Wrap(err, "creating ethereum client")
 	}
 
-	contract, err := contracts.NewITellor(client)
+	contract, err := contracts.NewITellor(logger, client, cfg.Ethereum)
 	if err != nil {
 		return errors.Wrap(err, "create tellor contract instance")
 	}
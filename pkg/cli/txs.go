@@ -0,0 +1,142 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/aggregator"
+	"github.com/tellor-io/telliot/pkg/config"
+	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/tracker/profit"
+	"github.com/tellor-io/telliot/pkg/txs"
+)
+
+type txsCmd struct {
+	cfg
+	Type    string        `optional:"" help:"show only the transactions of this type, for example submit, deposit, transfer or vote"`
+	Account string        `optional:"" help:"show only the transactions sent from this address"`
+	Status  string        `optional:"" help:"show only the transactions with this status: pending, success, failed or dropped"`
+	Since   time.Duration `optional:"" help:"show only the transactions sent within this duration, for example 24h"`
+	Limit   int           `optional:"" default:"50" help:"max number of transactions to show, 0 shows all"`
+}
+
+// Run updates the pending transactions and prints the history with the most recent first.
+func (self txsCmd) Run() error {
+	logger := logging.NewLogger()
+	ctx := context.Background()
+
+	cfg, err := self.parse(logger) // Load the env file.
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+
+	store, err := txs.Open(cfg.Db.Path)
+	if err != nil {
+		return err
+	}
+
+	client, err := ethereum.NewClient(ctx, logger)
+	if err != nil {
+		return errors.Wrap(err, "creating ethereum client")
+	}
+
+	// The USD cost is optional as the local DB might not have the ETH price.
+	var ethUSD txs.PriceFunc
+	tsDB, closeDB, err := openReadOnlyDB(logger, cfg)
+	if err != nil {
+		level.Warn(logger).Log("msg", "the USD cost won't be available", "err", err)
+	} else {
+		defer closeDB()
+		aggr, err := aggregator.New(logger, ctx, cfg.Aggregator, tsDB)
+		if err != nil {
+			return errors.Wrap(err, "creating aggregator")
+		}
+		ethUSD = ethUSDPrice(aggr)
+	}
+
+	if err := store.Resolve(ctx, client, ethUSD); err != nil {
+		level.Warn(logger).Log("msg", "updating the pending transactions", "err", err)
+	}
+
+	filter := txs.Filter{
+		Type:    txs.Type(self.Type),
+		Account: self.Account,
+		Status:  txs.Status(self.Status),
+		Limit:   self.Limit,
+	}
+	if self.Since > 0 {
+		filter.Since = time.Now().Add(-self.Since)
+	}
+	records, err := store.List(filter)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Println("no transactions recorded")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tTYPE\tACCOUNT\tHASH\tSTATUS\tGAS USED\tGAS PRICE GWEI\tCOST ETH\tCOST USD")
+	for _, r := range records {
+		costUSD := "-"
+		if r.CostUSD > 0 {
+			costUSD = fmt.Sprintf("%.2f", r.CostUSD)
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%.2f\t%.6f\t%v\n",
+			r.Time.UTC().Format(time.RFC3339),
+			r.Type,
+			r.Account,
+			r.Hash,
+			r.Status,
+			r.GasUsed,
+			r.GasPrice,
+			r.CostETH,
+			costUSD,
+		)
+	}
+	return w.Flush()
+}
+
+// recordTx adds a transaction sent by a cli command to the history.
+// Its status is updated when listing the history.
+// A failure is only logged as the transaction is already sent.
+func recordTx(logger log.Logger, cfg *config.Config, typ txs.Type, account common.Address, tx *types.Transaction) {
+	store, err := txs.Open(cfg.Db.Path)
+	if err == nil {
+		r := txs.NewRecord(typ, account, tx)
+		r.CLI = true
+		err = store.Add(r)
+	}
+	if err != nil {
+		level.Error(logger).Log("msg", "recording the transaction in the history", "tx", tx.Hash().Hex(), "err", err)
+	}
+}
+
+// usdPrice returns the USD price of a symbol from the local DB.
+func usdPrice(aggr *aggregator.Aggregator) profit.PriceFunc {
+	return func(symbol string, at time.Time) (float64, error) {
+		price, _, err := aggr.MedianAt(symbol, at)
+		return price, err
+	}
+}
+
+// ethUSDPrice returns the ETH/USD price from the local DB.
+func ethUSDPrice(aggr *aggregator.Aggregator) txs.PriceFunc {
+	return func(at time.Time) (float64, error) {
+		price, _, err := aggr.MedianAt("ETH/USD", at)
+		return price, err
+	}
+}
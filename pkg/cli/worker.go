@@ -0,0 +1,62 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package cli
+
+import (
+	"context"
+	"os"
+	"syscall"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/oklog/run"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/pool"
+)
+
+type workerCmd struct {
+	cfg
+	Coordinator string `required:"" help:"host:port of the pool coordinator"`
+	Transport   string `optional:"" default:"tcp" enum:"tcp,grpc" help:"protocol of the coordinator, tcp or grpc"`
+	Name        string `optional:"" help:"name of the worker in the coordinator logs, defaults to the hostname"`
+}
+
+// Run mines the challenges of a pool coordinator.
+// The worker needs only the pool secret and not the private keys or a node.
+func (self workerCmd) Run() error {
+	logger := logging.NewLogger()
+
+	cfg, err := self.parse(logger)
+	if err != nil {
+		return errors.Wrap(err, "creating config")
+	}
+
+	name := self.Name
+	if name == "" {
+		name, err = os.Hostname()
+		if err != nil {
+			return errors.Wrap(err, "getting hostname")
+		}
+	}
+
+	worker, err := pool.NewWorker(logger, context.Background(), cfg.Mining, self.Coordinator, self.Transport, name, os.Getenv(pool.SecretEnvName))
+	if err != nil {
+		return errors.Wrap(err, "creating pool worker")
+	}
+
+	var g run.Group
+	g.Add(run.SignalHandler(context.Background(), syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM))
+	g.Add(func() error {
+		err := worker.Start()
+		level.Info(logger).Log("msg", "pool worker shutdown complete")
+		return err
+	}, func(error) {
+		worker.Stop()
+	})
+	if err := g.Run(); err != nil {
+		level.Error(logger).Log("msg", "main exited with error", "err", err)
+		return err
+	}
+	return nil
+}
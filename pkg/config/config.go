@@ -4,10 +4,13 @@
 package config
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-kit/kit/log"
@@ -15,18 +18,32 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
 	"github.com/tellor-io/telliot/pkg/aggregator"
+	"github.com/tellor-io/telliot/pkg/alerting"
+	"github.com/tellor-io/telliot/pkg/contracts"
 	"github.com/tellor-io/telliot/pkg/db"
+	"github.com/tellor-io/telliot/pkg/ethereum"
 	"github.com/tellor-io/telliot/pkg/format"
 	"github.com/tellor-io/telliot/pkg/gasPrice/gasStation"
+	"github.com/tellor-io/telliot/pkg/leader"
+	"github.com/tellor-io/telliot/pkg/logging"
 	"github.com/tellor-io/telliot/pkg/mining"
+	"github.com/tellor-io/telliot/pkg/notify"
+	"github.com/tellor-io/telliot/pkg/pool"
 	psrTellor "github.com/tellor-io/telliot/pkg/psr/tellor"
 	psrTellorMesosphere "github.com/tellor-io/telliot/pkg/psr/tellorMesosphere"
+	"github.com/tellor-io/telliot/pkg/secrets"
+	"github.com/tellor-io/telliot/pkg/stake"
 	"github.com/tellor-io/telliot/pkg/submitter/tellor"
 	"github.com/tellor-io/telliot/pkg/submitter/tellorMesosphere"
+	"github.com/tellor-io/telliot/pkg/supervisor"
 	"github.com/tellor-io/telliot/pkg/tasker"
+	"github.com/tellor-io/telliot/pkg/telemetry"
+	"github.com/tellor-io/telliot/pkg/tracing"
+	"github.com/tellor-io/telliot/pkg/tracker/deviation"
 	"github.com/tellor-io/telliot/pkg/tracker/dispute"
 	"github.com/tellor-io/telliot/pkg/tracker/index"
 	"github.com/tellor-io/telliot/pkg/tracker/profit"
+	"github.com/tellor-io/telliot/pkg/tracker/tip"
 	"github.com/tellor-io/telliot/pkg/transactor"
 	"github.com/tellor-io/telliot/pkg/web"
 )
@@ -34,7 +51,9 @@ import (
 // Config is the top-level configuration that holds configs for all components.
 type Config struct {
 	Web                       web.Config
+	Logging                   logging.Config
 	Mining                    mining.Config
+	Pool                      pool.Config
 	SubmitterTellor           tellor.Config
 	SubmitterTellorMesosphere tellorMesosphere.Config
 	ProfitTracker             profit.Config
@@ -42,49 +61,172 @@ type Config struct {
 	Transactor                transactor.Config
 	IndexTracker              index.Config
 	DisputeTracker            dispute.Config
+	TipTracker                tip.Config
+	DeviationTracker          deviation.Config
 	Aggregator                aggregator.Config
 	PsrTellor                 psrTellor.Config
 	PsrTellorMesosphere       psrTellorMesosphere.Config
 	Db                        db.Config
 	GasStation                gasStation.Config
+	Notify                    notify.Config
+	Alerting                  alerting.Config
+	Tracing                   tracing.Config
+	StakeTopUp                stake.Config
+	Leader                    leader.Config
+	Supervisor                supervisor.Config
+	Telemetry                 telemetry.Config
+	Ethereum                  contracts.Config
 	// EnvFile location that include all private details like private key etc.
 	EnvFile string `json:"envFile"`
 }
 
 var DefaultConfig = Config{
 	Mining: mining.Config{
-		LogLevel:  "info",
-		Heartbeat: time.Minute,
+		LogLevel:      "info",
+		Heartbeat:     time.Minute,
+		NumProcessors: 1,
+		Kernel:        "optimized",
+		StallTimeout:  format.Duration{Duration: time.Minute},
+		PauseCheck:    format.Duration{Duration: 30 * time.Second},
 	},
 	Web: web.Config{
 		LogLevel:   "info",
 		ListenHost: "", // Listen on all addresses.
 		ListenPort: 9090,
+		// No CORS headers by default so that only the listed dashboards can call the API from a browser.
+		Cors: web.CorsConfig{
+			AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+			AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "Origin"},
+		},
+		QueryTimeout:       format.Duration{Duration: 10 * time.Second},
+		QueryMaxSamples:    100000,
+		QueryLookbackDelta: format.Duration{Duration: 5 * time.Minute},
+		QueryMaxSeries:     1000,
+		// The rate limit is off by default as the dashboards, like Grafana with many panels,
+		// poll the API from a single IP and would get rejected.
+		RateLimit: web.RateLimitConfig{
+			Burst:              50,
+			MaxRequestBodySize: 1 << 20,
+		},
 	},
 	Db: db.Config{
-		LogLevel:      "info",
-		Path:          "db",
-		RemoteTimeout: format.Duration{Duration: 5 * time.Second},
+		LogLevel:       "info",
+		Path:           "db",
+		RemoteTimeout:  format.Duration{Duration: 5 * time.Second},
+		RetryQueueSize: 10000,
+		RetryInterval:  format.Duration{Duration: 30 * time.Second},
 	},
 	Tasker: tasker.Config{
 		LogLevel: "info",
 	},
 	ProfitTracker: profit.Config{
 		LogLevel: "info",
+		Ranges:   []string{"24h", "7d", "30d"},
+		Alerts: profit.AlertsConfig{
+			Interval: format.Duration{Duration: 10 * time.Minute},
+			Window:   format.Duration{Duration: 24 * time.Hour},
+		},
 	},
 	DisputeTracker: dispute.Config{
+		LogLevel:       "info",
+		AlertThreshold: 10,
+	},
+	TipTracker: tip.Config{
+		LogLevel: "info",
+	},
+	DeviationTracker: deviation.Config{
+		LogLevel:        "info",
+		Interval:        format.Duration{Duration: time.Minute},
+		ChainlinkMaxAge: format.Duration{Duration: 3 * time.Hour},
+		Feeds: []deviation.Feed{
+			{RequestID: 1, Chainlink: deviation.ChainlinkETHUSDMainnet},
+			{RequestID: 2, Chainlink: deviation.ChainlinkBTCUSDMainnet},
+		},
+	},
+	Notify: notify.Config{
 		LogLevel: "info",
+		Telegram: notify.TelegramConfig{
+			MinSeverity: notify.SeverityInfo,
+		},
+		Discord: notify.DiscordConfig{
+			MinSeverity: notify.SeverityInfo,
+			RateLimit:   format.Duration{Duration: 10 * time.Minute},
+		},
+		Slack: notify.SlackConfig{
+			MinSeverity: notify.SeverityWarning,
+		},
+		Email: notify.EmailConfig{
+			Port:        587,
+			TLS:         notify.TLSStartTLS,
+			Subject:     "[telliot] {{.Title}}",
+			Body:        "{{.Message}}\n\n{{.Severity}} notification of {{.Component}} at {{.Time}}\n",
+			MinSeverity: notify.SeverityCritical,
+		},
+	},
+	Alerting: alerting.Config{
+		LogLevel:      "info",
+		Interval:      format.Duration{Duration: time.Minute},
+		StaleAfter:    format.Duration{Duration: 10 * time.Minute},
+		MinConfidence: 50,
+		NodeDownFor:   format.Duration{Duration: 5 * time.Minute},
+		Pager: alerting.PagerConfig{
+			MinSeverity: notify.SeverityCritical,
+		},
+	},
+	Logging: logging.Config{
+		Format: logging.FormatLogfmt,
+	},
+	Pool: pool.Config{
+		LogLevel:   "info",
+		ListenPort: 9095,
+		Transport:  "tcp",
+	},
+	Tracing: tracing.Config{
+		LogLevel:    "info",
+		Endpoint:    "http://localhost:4318",
+		SampleRatio: 1,
+		ServiceName: "telliot",
+	},
+	StakeTopUp: stake.Config{
+		LogLevel: "info",
+		Interval: format.Duration{Duration: 10 * time.Minute},
+	},
+	Leader: leader.Config{
+		LogLevel:      "info",
+		LeaseFile:     "leader.lease",
+		LeaseDuration: format.Duration{Duration: 10 * time.Second},
+		RenewInterval: format.Duration{Duration: 2 * time.Second},
+	},
+	Supervisor: supervisor.Config{
+		LogLevel:       "info",
+		Enabled:        true,
+		InitialBackoff: format.Duration{Duration: time.Second},
+		MaxBackoff:     format.Duration{Duration: 5 * time.Minute},
+		MaxRestarts:    10,
+	},
+	Telemetry: telemetry.Config{
+		LogLevel: "info",
+		Interval: format.Duration{Duration: 6 * time.Hour},
 	},
 	Transactor: transactor.Config{
 		LogLevel:      "info",
 		GasMax:        10,
 		GasMultiplier: 1,
+		BumpBlocks:    3,
+		BumpPercent:   20,
+		CancelOnClose: true,
+		DrainTimeout:  format.Duration{Duration: time.Minute},
+		PrivateRelay: transactor.PrivateRelayConfig{
+			URL:            "https://rpc.flashbots.net",
+			FallbackBlocks: 5,
+		},
 	},
 	SubmitterTellor: tellor.Config{
 		Enabled:  true,
 		LogLevel: "info",
 		// With a 1 second delay here as a workaround to prevent a race condition in the oracle contract check.
 		MinSubmitPeriod: format.Duration{Duration: 15*time.Minute + 1*time.Second},
+		MaxValueAge:     format.Duration{Duration: 2 * time.Minute},
 	},
 	SubmitterTellorMesosphere: tellorMesosphere.Config{
 		LogLevel:             "info",
@@ -99,31 +241,88 @@ var DefaultConfig = Config{
 		ManualDataFile: "configs/manualData.json",
 	},
 	GasStation: gasStation.Config{
-		TimeWait: format.Duration{Duration: time.Minute},
+		Strategy:         "standard",
+		Percentile:       60,
+		PercentileBlocks: 20,
+		Providers: []string{
+			gasStation.ProviderGasStation,
+			gasStation.ProviderEtherscan,
+			gasStation.ProviderBlocknative,
+			gasStation.ProviderNode,
+			gasStation.ProviderBlocks,
+		},
+		MaxDeviation: 50,
+	},
+	Ethereum: contracts.Config{
+		MaxNodeLag: format.Duration{Duration: ethereum.DefaultMaxNodeLag},
 	},
 	IndexTracker: index.Config{
-		LogLevel:  "info",
-		Interval:  format.Duration{Duration: 30 * time.Second},
-		IndexFile: "configs/index.json",
+		LogLevel:        "info",
+		Interval:        format.Duration{Duration: 30 * time.Second},
+		FetchTimeout:    format.Duration{Duration: 30 * time.Second},
+		IndexFile:       "configs/index.json",
+		RegistryRefresh: format.Duration{Duration: 10 * time.Minute},
 	},
 	EnvFile: "configs/.env",
 }
 
-func ParseConfig(logger log.Logger, path string) (*Config, error) {
+// ParseConfig loads the config with Load and applies the process wide settings.
+// When profile is not empty its section of the config file overrides the shared settings.
+// override changes the loaded config last, for example with the values of cli flags.
+func ParseConfig(logger log.Logger, path, profile string, override func(*Config) error) (*Config, error) {
+	cfg, err := Load(logger, path, profile)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil {
+		if err := override(cfg); err != nil {
+			return nil, errors.Wrap(err, "override config")
+		}
+	}
+
+	if err := secrets.ResolveEnv(logger, context.Background()); err != nil {
+		return nil, errors.Wrap(err, "resolving the secrets")
+	}
+	if err := secrets.DecryptKeys(logger, ethereum.PrivateKeysEnvName); err != nil {
+		return nil, errors.Wrap(err, "decrypting the private keys")
+	}
+
+	// The transactions are prepared in many places so the check is set for the whole process.
+	ethereum.SetMaxNodeLag(cfg.Ethereum.MaxNodeLag.Duration)
+	if err := logging.SetFormat(cfg.Logging.Format); err != nil {
+		return nil, errors.Wrap(err, "setting the log format")
+	}
+
+	return cfg, nil
+}
 
+// Load reads the config file and the env overrides
+// without applying the process wide settings like ParseConfig.
+func Load(logger log.Logger, path, profile string) (*Config, error) {
 	cfg := &Config{}
 
-	cfgI, err := DeеpCopy(logger, path, cfg, DefaultConfig)
+	cfgI, err := DeеpCopy(logger, path, profile, cfg, DefaultConfig)
+	if err != nil {
+		return nil, err
+	}
 	cfg = cfgI.(*Config)
 
+	// The env file location can be overridden as well so
+	// the overrides are applied before and after loading it.
+	if err := ApplyEnv(log.NewNopLogger(), cfg); err != nil {
+		return nil, errors.Wrap(err, "apply the env overrides")
+	}
 	if err := godotenv.Load(cfg.EnvFile); err != nil && !os.IsNotExist(err) {
 		return nil, errors.Wrap(err, "loading env vars from env file")
 	}
+	if err := ApplyEnv(logger, cfg); err != nil {
+		return nil, errors.Wrap(err, "apply the env overrides")
+	}
 
-	return cfg, err
+	return cfg, nil
 }
 
-func DeеpCopy(logger log.Logger, path string, cfg, cfgDefault interface{}) (interface{}, error) {
+func DeеpCopy(logger log.Logger, path, profile string, cfg, cfgDefault interface{}) (interface{}, error) {
 	if path == "" {
 		path = filepath.Join("configs", "config.json")
 	}
@@ -140,9 +339,8 @@ func DeеpCopy(logger log.Logger, path string, cfg, cfgDefault interface{}) (int
 		}
 	}
 
-	f, err := os.Open(path)
 	var noConfigFile bool
-	if err != nil {
+	if _, err := os.Stat(path); err != nil {
 		if !os.IsNotExist(err) {
 			return nil, errors.Wrap(err, "open config file")
 		}
@@ -150,17 +348,64 @@ func DeеpCopy(logger log.Logger, path string, cfg, cfgDefault interface{}) (int
 		level.Warn(logger).Log("msg", "no config file on disk so using defaults", "path", path)
 	}
 
+	if noConfigFile && profile != "" {
+		return nil, errors.Errorf("profile:%v selected without a config file", profile)
+	}
+
 	if !noConfigFile {
-		dec := json.NewDecoder(f)
-		dec.DisallowUnknownFields()
-		for {
-			// Override defaults with the custom configs.
-			if err := dec.Decode(cfg); err == io.EOF {
-				break
-			} else if err != nil {
-				return nil, errors.Wrap(err, "parse config")
+		files, err := readConfig(path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// Check the whole files first so that all problems are reported at once
+		// with their path instead of failing at the first one.
+		for _, file := range files {
+			problems, err := file.validate(cfgDefault)
+			if err != nil {
+				return nil, errors.Wrapf(err, "config file:%v", file.path)
+			}
+			for _, p := range problems.Warnings() {
+				level.Warn(logger).Log("msg", "config file", "path", file.path, "problem", p.String())
+			}
+			if err := problems.Err(); err != nil {
+				return nil, errors.Wrapf(err, "invalid config file:%v", file.path)
 			}
+		}
+
+		for _, file := range files {
+			dec := json.NewDecoder(bytes.NewReader(file.shared))
+			for {
+				// Override defaults with the custom configs.
+				if err := dec.Decode(cfg); err == io.EOF {
+					break
+				} else if err != nil {
+					return nil, errors.Wrapf(err, "parse config:%v", file.path)
+				}
+			}
+		}
 
+		if profile != "" {
+			var found bool
+			all := make(map[string]json.RawMessage)
+			for _, file := range files {
+				for name, p := range file.profiles {
+					all[name] = p
+				}
+				p, ok := file.profiles[profile]
+				if !ok {
+					continue
+				}
+				found = true
+				// Only the fields set in the profile override the shared settings.
+				if err := json.Unmarshal(p, cfg); err != nil {
+					return nil, errors.Wrapf(err, "parse config profile:%v of:%v", profile, file.path)
+				}
+			}
+			if !found {
+				return nil, errors.Errorf("profile:%v not found in the config file:%v, available profiles:%v", profile, path, strings.Join(profileNames(all), ", "))
+			}
+			level.Info(logger).Log("msg", "using config profile", "profile", profile)
 		}
 	}
 
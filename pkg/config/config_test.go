@@ -4,9 +4,18 @@
 package config
 
 import (
+	"bytes"
+	"context"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-kit/kit/log"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/mining"
 	"github.com/tellor-io/telliot/pkg/testutil"
 )
 
@@ -39,3 +48,175 @@ func TestConfig(t *testing.T) {
 	testutil.Assert(t, cfg.Transactor.GasMultiplier > 0, "GasMultiplier should have value")
 
 }
+
+func TestApplyEnv(t *testing.T) {
+	cfg := DefaultConfig
+	for name, value := range map[string]string{
+		"TELLIOT_WEB_LISTENPORT":          "9191",
+		"TELLIOT_INDEXTRACKER_INTERVAL":   "1m",
+		"TELLIOT_MINING_NONCERANGE_COUNT": "2",
+		"TELLIOT_GASSTATION_PROVIDERS":    `["node"]`,
+		"TELLIOT_ENVFILE":                 "/run/secrets/telliot.env",
+	} {
+		testutil.Setenv(t, name, value)
+	}
+
+	testutil.Ok(t, ApplyEnv(log.NewNopLogger(), &cfg))
+	testutil.Equals(t, uint(9191), cfg.Web.ListenPort)
+	testutil.Equals(t, time.Minute, cfg.IndexTracker.Interval.Duration)
+	testutil.Equals(t, 2, int(cfg.Mining.NonceRange.Count))
+	testutil.Equals(t, []string{"node"}, cfg.GasStation.Providers)
+	testutil.Equals(t, "/run/secrets/telliot.env", cfg.EnvFile)
+	testutil.Equals(t, "info", cfg.Web.LogLevel)
+
+	testutil.Setenv(t, "TELLIOT_WEB_LISTENPORT", "port")
+	testutil.NotOk(t, ApplyEnv(log.NewNopLogger(), &cfg))
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	testutil.Ok(t, ioutil.WriteFile(path, []byte(`{"SubmitterTellor": {"ProfitThreshold": 100}}`), 0600))
+	cfg, err := Load(log.NewNopLogger(), path, "")
+	testutil.Ok(t, err)
+
+	reloader := NewReloader(log.NewNopLogger(), context.Background(), path, "", cfg, nil)
+	var applied *Config
+	reloader.OnReload(func(cfg *Config) {
+		applied = cfg
+	})
+
+	// A change that needs a restart is left out.
+	testutil.Ok(t, ioutil.WriteFile(path, []byte(`{"SubmitterTellor": {"ProfitThreshold": 200}, "ProfitTracker": {"Alerts": {"MinProfit": 2}}, "Mining": {"LogLevel": "debug"}, "Web": {"ListenPort": 1234}}`), 0600))
+	testutil.Ok(t, reloader.Reload())
+	testutil.Equals(t, uint64(200), applied.SubmitterTellor.ProfitThreshold)
+	testutil.Equals(t, 2.0, applied.ProfitTracker.Alerts.MinProfit)
+	testutil.Equals(t, "debug", applied.Mining.LogLevel)
+	testutil.Equals(t, DefaultConfig.Web.ListenPort, applied.Web.ListenPort)
+
+	testutil.Ok(t, ioutil.WriteFile(path, []byte(`{"Mining": {"LogLevel": "verbose"}}`), 0600))
+	testutil.NotOk(t, reloader.Reload())
+
+	// A failed reload applies none of the changes.
+	_, err = logging.ApplyFilter(mining.ComponentName, "debug", log.NewNopLogger())
+	testutil.Ok(t, err)
+	testutil.Ok(t, ioutil.WriteFile(path, []byte(`{"SubmitterTellor": {"ProfitThreshold": 200}, "Mining": {"LogLevel": "error"}, "Logging": {"Format": "xml"}}`), 0600))
+	testutil.NotOk(t, reloader.Reload())
+	testutil.Equals(t, "debug", logging.Levels()[mining.ComponentName])
+}
+
+func TestGenerate(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.Ethereum.Network = "rinkeby"
+
+	var buf bytes.Buffer
+	testutil.Ok(t, Generate(&buf, &cfg))
+	testutil.Assert(t, strings.Contains(buf.String(), "\t\t// "), "the fields should have their help as a comment")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	testutil.Ok(t, ioutil.WriteFile(path, buf.Bytes(), 0600))
+	parsed, err := Load(log.NewNopLogger(), path, "")
+	testutil.Ok(t, err)
+	testutil.Equals(t, 0, len(Diff(&cfg, parsed)))
+}
+
+func TestStripComments(t *testing.T) {
+	in := "{\n\t// comment\n\t\"URL\": \"http://a//b\\\"//c\" // trailing\n}"
+	testutil.Equals(t, "{\n\t\n\t\"URL\": \"http://a//b\\\"//c\" \n}", string(stripComments([]byte(in))))
+}
+
+func TestProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	testutil.Ok(t, ioutil.WriteFile(path, []byte(`{
+		"Web": {"ListenPort": 1234},
+		"Ethereum": {"Network": "mainnet"},
+		"Profiles": {
+			// Comments are allowed in the profiles as well.
+			"rinkeby": {"Ethereum": {"Network": "rinkeby", "ContractAddress": "0x01"}, "envFile": "rinkeby.env"},
+			"staging": {"Web": {"LogLevel": "debug"}}
+		}
+	}`), 0600))
+
+	cfg, err := Load(log.NewNopLogger(), path, "")
+	testutil.Ok(t, err)
+	testutil.Equals(t, "mainnet", cfg.Ethereum.Network)
+	testutil.Equals(t, DefaultConfig.EnvFile, cfg.EnvFile)
+
+	cfg, err = Load(log.NewNopLogger(), path, "rinkeby")
+	testutil.Ok(t, err)
+	testutil.Equals(t, "rinkeby", cfg.Ethereum.Network)
+	testutil.Equals(t, "0x01", cfg.Ethereum.ContractAddress)
+	testutil.Equals(t, "rinkeby.env", cfg.EnvFile)
+	testutil.Equals(t, uint(1234), cfg.Web.ListenPort, "the shared settings should apply to all profiles")
+
+	cfg, err = Load(log.NewNopLogger(), path, "staging")
+	testutil.Ok(t, err)
+	testutil.Equals(t, "debug", cfg.Web.LogLevel)
+	testutil.Equals(t, uint(1234), cfg.Web.ListenPort, "a profile should override only its own fields")
+
+	_, err = Load(log.NewNopLogger(), path, "goerli")
+	testutil.NotOk(t, err)
+
+	// All profiles are validated even when not selected.
+	testutil.Ok(t, ioutil.WriteFile(path, []byte(`{"Profiles": {"rinkeby": {"Web": {"ListenPort": "port"}}}}`), 0600))
+	_, err = Load(log.NewNopLogger(), path, "")
+	testutil.NotOk(t, err)
+	testutil.Assert(t, strings.Contains(err.Error(), "Profiles.rinkeby.Web.ListenPort"), "the problem should include the profile path")
+}
+
+func TestSet(t *testing.T) {
+	cfg := DefaultConfig
+	testutil.Ok(t, Set(&cfg, "web.listenport", "9191"))
+	testutil.Equals(t, uint(9191), cfg.Web.ListenPort)
+	testutil.Ok(t, Set(&cfg, "Mining.NonceRange.Count", "2"))
+	testutil.Equals(t, 2, int(cfg.Mining.NonceRange.Count))
+	testutil.Ok(t, Set(&cfg, "IndexTracker.Interval", "1m"))
+	testutil.Equals(t, time.Minute, cfg.IndexTracker.Interval.Duration)
+	testutil.Ok(t, Set(&cfg, "envFile", "/run/secrets/telliot.env"))
+	testutil.Equals(t, "/run/secrets/telliot.env", cfg.EnvFile)
+
+	testutil.NotOk(t, Set(&cfg, "Web.ListenPrt", "9191"))
+	testutil.NotOk(t, Set(&cfg, "Web.ListenPort.Port", "9191"))
+	testutil.NotOk(t, Set(&cfg, "Web.ListenPort", "port"))
+
+	testutil.Ok(t, SetLogLevel(&cfg, "debug"))
+	testutil.Equals(t, "debug", cfg.Web.LogLevel)
+	testutil.Equals(t, "debug", cfg.DisputeTracker.LogLevel)
+	testutil.Equals(t, "info", DefaultConfig.Web.LogLevel)
+	testutil.NotOk(t, SetLogLevel(&cfg, "verbose"))
+}
+
+func TestInclude(t *testing.T) {
+	dir := t.TempDir()
+	testutil.Ok(t, os.Mkdir(filepath.Join(dir, "shared"), 0700))
+	testutil.Ok(t, ioutil.WriteFile(filepath.Join(dir, "shared", "base.json"), []byte(`{
+		"Web": {"ListenPort": 1234, "LogLevel": "debug"},
+		"GasStation": {"Providers": ["node", "blocks"]},
+		"Profiles": {"rinkeby": {"Ethereum": {"Network": "rinkeby"}}}
+	}`), 0600))
+	testutil.Ok(t, ioutil.WriteFile(filepath.Join(dir, "shared", "alerts.json"), []byte(`{"Alerting": {"Enabled": true}}`), 0600))
+	path := filepath.Join(dir, "host.json")
+	testutil.Ok(t, ioutil.WriteFile(path, []byte(`{
+		// Relative to the directory of this file.
+		"include": ["shared/base.json", "shared/alerts.json"],
+		"Web": {"ListenPort": 4321},
+		"GasStation": {"Providers": ["node"]},
+		"Profiles": {"rinkeby": {"envFile": "rinkeby.env"}}
+	}`), 0600))
+
+	cfg, err := Load(log.NewNopLogger(), path, "")
+	testutil.Ok(t, err)
+	testutil.Equals(t, uint(4321), cfg.Web.ListenPort, "the including file should override the included ones")
+	testutil.Equals(t, "debug", cfg.Web.LogLevel, "the sections should be merged")
+	testutil.Equals(t, []string{"node"}, cfg.GasStation.Providers, "the lists should be replaced")
+	testutil.Equals(t, true, cfg.Alerting.Enabled)
+
+	cfg, err = Load(log.NewNopLogger(), path, "rinkeby")
+	testutil.Ok(t, err)
+	testutil.Equals(t, "rinkeby", cfg.Ethereum.Network, "the profiles of all files should be applied")
+	testutil.Equals(t, "rinkeby.env", cfg.EnvFile)
+
+	testutil.Ok(t, ioutil.WriteFile(filepath.Join(dir, "shared", "alerts.json"), []byte(`{"include": ["../host.json"]}`), 0600))
+	_, err = Load(log.NewNopLogger(), path, "")
+	testutil.NotOk(t, err)
+	testutil.Assert(t, strings.Contains(err.Error(), "includes itself"), "a cycle should be reported")
+}
@@ -0,0 +1,94 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+)
+
+// EnvPrefix is the prefix of the env variables that override config fields.
+// The name of the variable is the path to the field in upper case joined with an underscore,
+// for example TELLIOT_WEB_LISTENPORT or TELLIOT_MINING_NONCERANGE_COUNT.
+const EnvPrefix = "TELLIOT"
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// ApplyEnv overrides the fields of cfg with the values of the matching env variables.
+// The values are parsed as JSON like in the config file, but strings and durations don't need quotes.
+// Env variables with the prefix that don't match any field are logged so that typos don't go unnoticed.
+func ApplyEnv(logger log.Logger, cfg interface{}) error {
+	known := make(map[string]bool)
+	if err := applyEnv(reflect.ValueOf(cfg).Elem(), EnvPrefix, known); err != nil {
+		return err
+	}
+
+	for _, env := range os.Environ() {
+		name := strings.SplitN(env, "=", 2)[0]
+		if strings.HasPrefix(name, EnvPrefix+"_") && !known[name] {
+			level.Warn(logger).Log("msg", "env variable doesn't match any config field", "name", name)
+		}
+	}
+	return nil
+}
+
+func applyEnv(v reflect.Value, prefix string, known map[string]bool) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldName := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+			fieldName = tag
+		}
+		name := prefix + "_" + strings.ToUpper(fieldName)
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct && !reflect.PtrTo(fv.Type()).Implements(unmarshalerType) {
+			if err := applyEnv(fv, name, known); err != nil {
+				return err
+			}
+			continue
+		}
+
+		known[name] = true
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setField(fv, value); err != nil {
+			return errors.Wrapf(err, "parse env variable:%v", name)
+		}
+	}
+	return nil
+}
+
+func setField(v reflect.Value, value string) error {
+	if v.Kind() == reflect.String {
+		v.SetString(value)
+		return nil
+	}
+	// The value replaces the default entries instead of merging with these.
+	if v.Kind() == reflect.Map || v.Kind() == reflect.Slice {
+		v.Set(reflect.Zero(v.Type()))
+	}
+	// Allows values like 30s without quotes.
+	if err := json.Unmarshal([]byte(value), v.Addr().Interface()); err != nil {
+		quoted, errQ := json.Marshal(value)
+		if errQ != nil {
+			return err
+		}
+		if errQ := json.Unmarshal(quoted, v.Addr().Interface()); errQ != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Generate writes the config as JSON with the help of every field as a comment above it.
+// The config parser ignores the comments so the output can be used as is.
+func Generate(w io.Writer, cfg *Config) error {
+	var buf bytes.Buffer
+	if err := generate(&buf, reflect.ValueOf(cfg).Elem(), 1); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func generate(buf *bytes.Buffer, v reflect.Value, depth int) error {
+	indent := strings.Repeat("\t", depth)
+	buf.WriteString("{\n")
+
+	var fields []reflect.StructField
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).PkgPath == "" {
+			fields = append(fields, v.Type().Field(i))
+		}
+	}
+	for i, field := range fields {
+		name := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+			name = tag
+		}
+		if help := field.Tag.Get("help"); help != "" {
+			fmt.Fprintf(buf, "%s// %s\n", indent, help)
+		}
+		fmt.Fprintf(buf, "%s%q: ", indent, name)
+
+		fv := v.FieldByIndex(field.Index)
+		if fv.Kind() == reflect.Struct && !reflect.PtrTo(fv.Type()).Implements(unmarshalerType) {
+			if err := generate(buf, fv, depth+1); err != nil {
+				return err
+			}
+		} else {
+			b, err := json.MarshalIndent(fv.Interface(), indent, "\t")
+			if err != nil {
+				return errors.Wrapf(err, "marshal field:%v", name)
+			}
+			buf.Write(b)
+		}
+		if i < len(fields)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString(strings.Repeat("\t", depth-1) + "}")
+	if depth == 1 {
+		buf.WriteString("\n")
+	}
+	return nil
+}
+
+// stripComments removes the // line comments outside of the JSON strings.
+func stripComments(b []byte) []byte {
+	var (
+		out      = make([]byte, 0, len(b))
+		inString bool
+		escaped  bool
+	)
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			out = append(out, c)
+			continue
+		}
+		if c == '/' && i+1 < len(b) && b[i+1] == '/' {
+			for i < len(b) && b[i] != '\n' {
+				i++
+			}
+			if i < len(b) {
+				out = append(out, '\n')
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+		}
+		out = append(out, c)
+	}
+	return out
+}
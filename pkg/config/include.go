@@ -0,0 +1,111 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/schema"
+)
+
+// IncludeKey is the config file option with the list of other config files to merge.
+// The included files are applied first in the listed order and the including file overrides these,
+// so the shared settings of a fleet can be kept in one file and the per host settings in another.
+// Relative paths are relative to the directory of the including file.
+const IncludeKey = "include"
+
+// configFile is a config file without its include and profiles sections.
+type configFile struct {
+	path     string
+	shared   []byte
+	profiles map[string]json.RawMessage
+}
+
+// readConfig reads the config file and all its includes
+// in the order these are applied.
+func readConfig(path string, including []string) ([]configFile, error) {
+	for _, p := range including {
+		if p == path {
+			return nil, errors.Errorf("config file:%v includes itself through:%v", path, strings.Join(including, " -> "))
+		}
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read config file")
+	}
+	// The comments like the ones of a generated config are allowed.
+	b, include, err := splitKey(stripComments(b), IncludeKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid config file:%v", path)
+	}
+	b, profiles, err := splitProfiles(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid config file:%v", path)
+	}
+
+	var files []configFile
+	if include != nil {
+		var includes []string
+		if err := json.Unmarshal(include, &includes); err != nil {
+			return nil, errors.Errorf("invalid config file:%v: %v: expected a list of paths, for example [\"shared.json\"], got %s", path, IncludeKey, include)
+		}
+		for _, p := range includes {
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(filepath.Dir(path), p)
+			}
+			included, err := readConfig(p, append(including, path))
+			if err != nil {
+				return nil, errors.Wrapf(err, "include of:%v", path)
+			}
+			files = append(files, included...)
+		}
+	}
+	return append(files, configFile{path: path, shared: b, profiles: profiles}), nil
+}
+
+// validate checks the shared settings and all profiles of the file.
+// All profiles are checked so that a typo doesn't wait until the profile is used.
+func (self configFile) validate(cfgDefault interface{}) (schema.Problems, error) {
+	problems, err := schema.Validate(self.shared, cfgDefault)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse config")
+	}
+	for _, name := range profileNames(self.profiles) {
+		profileProblems, err := schema.Validate(self.profiles[name], cfgDefault)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse config profile:%v", name)
+		}
+		for _, p := range profileProblems {
+			p.Path = ProfilesKey + "." + name + "." + p.Path
+			problems = append(problems, p)
+		}
+	}
+	return problems, nil
+}
+
+// splitKey removes the top level key from the JSON object and returns its value.
+// The key is matched case insensitive like when decoding into the config.
+func splitKey(b []byte, key string) ([]byte, json.RawMessage, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(b, &doc); err != nil {
+		// Reported by the schema validation with more details.
+		return b, nil, nil
+	}
+	for k, v := range doc {
+		if !strings.EqualFold(k, key) {
+			continue
+		}
+		delete(doc, k)
+		rest, err := json.Marshal(doc)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "marshal the config without:%v", key)
+		}
+		return rest, v, nil
+	}
+	return b, nil, nil
+}
@@ -0,0 +1,40 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ProfilesKey is the config file section with the named profiles.
+// A profile has the same structure as the config file and
+// its fields override the shared settings when selected with --profile.
+const ProfilesKey = "Profiles"
+
+// splitProfiles removes the profiles section from the config file
+// and returns the shared settings and all profiles by name.
+func splitProfiles(b []byte) ([]byte, map[string]json.RawMessage, error) {
+	shared, section, err := splitKey(b, ProfilesKey)
+	if err != nil || section == nil {
+		return shared, nil, err
+	}
+	var profiles map[string]json.RawMessage
+	if err := json.Unmarshal(section, &profiles); err != nil {
+		return nil, nil, errors.Wrapf(err, "parse the %v section, expected an object with the profiles by name", ProfilesKey)
+	}
+	return shared, profiles, nil
+}
+
+// profileNames returns the sorted names of the profiles.
+func profileNames(profiles map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
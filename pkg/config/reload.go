@@ -0,0 +1,330 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/aggregator"
+	"github.com/tellor-io/telliot/pkg/alerting"
+	"github.com/tellor-io/telliot/pkg/leader"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/mining"
+	"github.com/tellor-io/telliot/pkg/notify"
+	"github.com/tellor-io/telliot/pkg/pool"
+	"github.com/tellor-io/telliot/pkg/stake"
+	"github.com/tellor-io/telliot/pkg/submitter/tellor"
+	"github.com/tellor-io/telliot/pkg/submitter/tellorMesosphere"
+	"github.com/tellor-io/telliot/pkg/supervisor"
+	"github.com/tellor-io/telliot/pkg/tasker"
+	"github.com/tellor-io/telliot/pkg/telemetry"
+	"github.com/tellor-io/telliot/pkg/tracing"
+	"github.com/tellor-io/telliot/pkg/tracker/deviation"
+	"github.com/tellor-io/telliot/pkg/tracker/dispute"
+	"github.com/tellor-io/telliot/pkg/tracker/index"
+	"github.com/tellor-io/telliot/pkg/tracker/profit"
+	"github.com/tellor-io/telliot/pkg/tracker/tip"
+	"github.com/tellor-io/telliot/pkg/transactor"
+	"github.com/tellor-io/telliot/pkg/web"
+)
+
+const ComponentName = "config"
+
+// Reloadable are the config fields that running components apply without a restart.
+// The LogLevel of every section is reloadable as well.
+// Changes of all other fields are rejected on reload.
+var Reloadable = []string{
+	"Logging.Format",
+	"SubmitterTellor.ProfitThreshold",
+	"SubmitterTellor.MinSubmitPeriod",
+	"SubmitterTellor.SubmitDelay",
+	"SubmitterTellor.MaxValueAge",
+	"SubmitterTellor.MaxValueAgeSymbols",
+	"StakeTopUp.Interval",
+	"Alerting.Interval",
+	"Alerting.StaleAfter",
+	"Alerting.MinConfidence",
+	"Alerting.NodeDownFor",
+	"DisputeTracker.AlertThreshold",
+	"DisputeTracker.PrepareDispute",
+	"ProfitTracker.Alerts.Interval",
+	"ProfitTracker.Alerts.Window",
+	"ProfitTracker.Alerts.MinProfit",
+	"ProfitTracker.Alerts.MaxGasRatio",
+}
+
+// needsRestart explains why the intervals and whitelists that are not in Reloadable need a restart.
+// It is added to the warning about the rejected change.
+var needsRestart = map[string]string{
+	"IndexTracker.Interval":    "the index tracker schedules its sources at the start",
+	"IndexTracker.Symbols":     "the index tracker creates the sources of its symbols at the start",
+	"IndexTracker.RegistryEnv": "the index tracker substitutes the env variables of the registry at the start",
+	"ProfitTracker.Addresses":  "the profit tracker backfills the history of its accounts at the start",
+	"Web.Cors.AllowedOrigins":  "the web handlers are created at the start",
+	"Web.Cors.AllowedMethods":  "the web handlers are created at the start",
+	"Web.Cors.AllowedHeaders":  "the web handlers are created at the start",
+}
+
+// logComponents are the components that use the LogLevel of each config section.
+var logComponents = map[string][]string{
+	"Web":                       {web.ComponentName},
+	"Mining":                    {mining.ComponentName},
+	"Pool":                      {pool.ComponentName},
+	"SubmitterTellor":           {tellor.ComponentName},
+	"SubmitterTellorMesosphere": {tellorMesosphere.ComponentName},
+	"ProfitTracker":             {profit.ComponentName},
+	"Tasker":                    {tasker.ComponentName},
+	"Transactor":                {transactor.ComponentName},
+	"IndexTracker":              {index.ComponentName},
+	"DisputeTracker":            {dispute.ComponentName, dispute.VoterComponentName, dispute.FeeComponentName},
+	"TipTracker":                {tip.ComponentName},
+	"DeviationTracker":          {deviation.ComponentName},
+	"Aggregator":                {aggregator.ComponentName},
+	"Notify":                    {notify.ComponentName},
+	"Alerting":                  {alerting.ComponentName},
+	"Tracing":                   {tracing.ComponentName},
+	"StakeTopUp":                {stake.ComponentName},
+	"Leader":                    {leader.ComponentName},
+	"Supervisor":                {supervisor.ComponentName},
+	"Telemetry":                 {telemetry.ComponentName},
+}
+
+// Change is a config field with a different value after a reload.
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Diff returns all leaf fields that differ between the two configs.
+func Diff(old, new *Config) []Change {
+	var changes []Change
+	diff(reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem(), "", &changes)
+	return changes
+}
+
+func diff(old, new reflect.Value, path string, changes *[]Change) {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Name
+		if path != "" {
+			name = path + "." + name
+		}
+		o, n := old.Field(i), new.Field(i)
+		if o.Kind() == reflect.Struct && !reflect.PtrTo(o.Type()).Implements(unmarshalerType) {
+			diff(o, n, name, changes)
+			continue
+		}
+		if reflect.DeepEqual(o.Interface(), n.Interface()) {
+			continue
+		}
+		*changes = append(*changes, Change{Field: name, Old: formatValue(o), New: formatValue(n)})
+	}
+}
+
+func formatValue(v reflect.Value) string {
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+func reloadable(field string) bool {
+	if strings.HasSuffix(field, ".LogLevel") {
+		return true
+	}
+	for _, r := range Reloadable {
+		if r == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Reloader re-reads the config on SIGHUP and applies the reloadable changes.
+// The components register what they apply with OnReload.
+type Reloader struct {
+	logger   log.Logger
+	ctx      context.Context
+	close    context.CancelFunc
+	path     string
+	profile  string
+	override func(*Config) error
+
+	mtx      sync.Mutex
+	cfg      *Config
+	appliers []func(*Config)
+
+	reloads  *prometheus.CounterVec
+	rejected prometheus.Counter
+}
+
+// NewReloader creates a reloader for the config loaded from path with the given profile.
+// override is applied to every reloaded config, for example to keep the values set with cli flags.
+func NewReloader(logger log.Logger, ctx context.Context, path, profile string, cfg *Config, override func(*Config) error) *Reloader {
+	ctx, close := context.WithCancel(ctx)
+	return &Reloader{
+		logger:   log.With(logger, "component", ComponentName),
+		ctx:      ctx,
+		close:    close,
+		path:     path,
+		profile:  profile,
+		override: override,
+		cfg:      cfg,
+		reloads: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "reloads_total",
+			Help:      "The total number of config reloads by result",
+		}, []string{"result"}),
+		rejected: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "rejected_changes_total",
+			Help:      "The total number of changed config fields that need a restart",
+		}),
+	}
+}
+
+// OnReload registers a function that applies a reloaded config.
+// It gets the running config with only the reloadable changes.
+func (self *Reloader) OnReload(f func(*Config)) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.appliers = append(self.appliers, f)
+}
+
+func (self *Reloader) Start() error {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+
+	for {
+		select {
+		case <-self.ctx.Done():
+			return nil
+		case <-sig:
+		}
+		level.Info(self.logger).Log("msg", "reloading the config", "path", self.path)
+		if err := self.Reload(); err != nil {
+			self.reloads.With(prometheus.Labels{"result": "failure"}).Inc()
+			level.Error(self.logger).Log("msg", "reloading the config", "err", err)
+			continue
+		}
+		self.reloads.With(prometheus.Labels{"result": "success"}).Inc()
+	}
+}
+
+func (self *Reloader) Stop() {
+	self.close()
+}
+
+// Reload re-reads the config and applies the reloadable changes.
+// The changes that need a restart are logged and left out.
+func (self *Reloader) Reload() error {
+	newCfg, err := Load(self.logger, self.path, self.profile)
+	if err != nil {
+		return errors.Wrap(err, "load config")
+	}
+	if self.override != nil {
+		if err := self.override(newCfg); err != nil {
+			return errors.Wrap(err, "override config")
+		}
+	}
+
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.cfg.IndexTracker.IndexFile != "" {
+		level.Info(self.logger).Log("msg", "the index file is not re-read on reload, its sources and intervals change after a restart", "path", self.cfg.IndexTracker.IndexFile)
+	}
+
+	// Start from the running config so that the rejected changes don't reach the components.
+	applied := *self.cfg
+	var reloaded []Change
+	for _, c := range Diff(self.cfg, newCfg) {
+		if !reloadable(c.Field) {
+			self.rejected.Inc()
+			keyvals := []interface{}{"msg", "config change needs a restart, ignoring it", "field", c.Field, "old", c.Old, "new", c.New}
+			if reason, ok := needsRestart[c.Field]; ok {
+				keyvals = append(keyvals, "reason", reason)
+			}
+			level.Warn(self.logger).Log(keyvals...)
+			continue
+		}
+		field(&applied, c.Field).Set(field(newCfg, c.Field))
+		reloaded = append(reloaded, c)
+	}
+	if len(reloaded) == 0 {
+		level.Info(self.logger).Log("msg", "no reloadable config changes")
+		return nil
+	}
+
+	// Validate everything before applying anything so that
+	// a failed reload doesn't leave the changes partly applied.
+	if alerts := applied.ProfitTracker.Alerts; alerts.Enabled && (alerts.Interval.Duration <= 0 || alerts.Window.Duration <= 0) {
+		return errors.Errorf("invalid profit alerts interval:%v or window:%v", alerts.Interval, alerts.Window)
+	}
+	if !logging.ValidFormat(applied.Logging.Format) {
+		return errors.Errorf("unexpected log format:%v", applied.Logging.Format)
+	}
+	for _, c := range reloaded {
+		if section := strings.TrimSuffix(c.Field, ".LogLevel"); section != c.Field {
+			if lvl := field(&applied, c.Field).String(); !logging.ValidLevel(lvl) {
+				return errors.Errorf("unexpected log level:%v for:%v", lvl, section)
+			}
+		}
+	}
+
+	if err := logging.SetFormat(applied.Logging.Format); err != nil {
+		return errors.Wrap(err, "setting the log format")
+	}
+	running := logging.Levels()
+	for _, c := range reloaded {
+		level.Info(self.logger).Log("msg", "config changed", "field", c.Field, "old", c.Old, "new", c.New)
+		section := strings.TrimSuffix(c.Field, ".LogLevel")
+		if section == c.Field {
+			continue
+		}
+		for _, component := range logComponents[section] {
+			if _, ok := running[component]; !ok {
+				continue
+			}
+			// The level is validated above and the component is registered so this can't fail.
+			if err := logging.SetConfigLevel(component, field(&applied, c.Field).String()); err != nil {
+				level.Error(self.logger).Log("msg", "setting the log level", "component", component, "err", err)
+			}
+		}
+	}
+	for _, f := range self.appliers {
+		f(&applied)
+	}
+	self.cfg = &applied
+	return nil
+}
+
+// field returns the field of the config at the given dot separated path.
+func field(cfg *Config, path string) reflect.Value {
+	v := reflect.ValueOf(cfg).Elem()
+	for _, name := range strings.Split(path, ".") {
+		v = v.FieldByName(name)
+	}
+	return v
+}
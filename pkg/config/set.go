@@ -0,0 +1,75 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/logging"
+)
+
+// Set changes the config field at the dot separated path, for example Web.ListenPort.
+// The path is case insensitive and the value is parsed like the env variable overrides.
+func Set(cfg *Config, path, value string) error {
+	v := reflect.ValueOf(cfg).Elem()
+	var walked []string
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() != reflect.Struct || reflect.PtrTo(v.Type()).Implements(unmarshalerType) {
+			return errors.Errorf("field:%v has no field:%v", strings.Join(walked, "."), name)
+		}
+		f, ok := fieldByName(v, name)
+		if !ok {
+			return errors.Errorf("unknown field:%v, known fields are %v", strings.Join(append(walked, name), "."), strings.Join(fieldNames(v.Type()), ", "))
+		}
+		walked = append(walked, name)
+		v = f
+	}
+	if err := setField(v, value); err != nil {
+		return errors.Wrapf(err, "parse the value of:%v", path)
+	}
+	return nil
+}
+
+// SetLogLevel changes the LogLevel of every config section.
+func SetLogLevel(cfg *Config, lvl string) error {
+	if !logging.ValidLevel(lvl) {
+		return errors.Errorf("unexpected log level:%v", lvl)
+	}
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() != reflect.Struct {
+			continue
+		}
+		if f := v.Field(i).FieldByName("LogLevel"); f.IsValid() && f.Kind() == reflect.String {
+			f.SetString(lvl)
+		}
+	}
+	return nil
+}
+
+func fieldByName(v reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if strings.EqualFold(field.Name, name) || (tag != "" && tag != "-" && strings.EqualFold(tag, name)) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func fieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			names = append(names, t.Field(i).Name)
+		}
+	}
+	return names
+}
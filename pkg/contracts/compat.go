@@ -0,0 +1,108 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+)
+
+// Compatibility is the result of checking the deployed contracts against the bindings of this binary.
+type Compatibility struct {
+	Version OracleVersion
+	// Implementation is the logic contract of the master proxy which changes with every upgrade.
+	Implementation common.Address
+	Oracle         common.Address
+	// Failed are the calls of the bindings that the deployed contracts didn't answer.
+	Failed []string
+}
+
+// Err returns an error with the failed calls when the contracts don't match the bindings.
+func (self *Compatibility) Err() error {
+	if len(self.Failed) == 0 {
+		return nil
+	}
+	return errors.Errorf("the deployed %v contracts at implementation:%v don't match the bindings of this version, probably after a protocol upgrade, update telliot, failed calls:\n%v",
+		self.Version,
+		self.Implementation.Hex(),
+		strings.Join(self.Failed, "\n"),
+	)
+}
+
+// CheckCompatibility detects the oracle version on chain and calls the view methods of the bindings
+// that the miner uses for that version.
+// A contract upgrade that changes these methods makes the calls revert or return data that doesn't unpack
+// so this fails at the start instead of with ABI errors in the middle of a submit.
+func CheckCompatibility(ctx context.Context, client *ethclient.Client, master *ITellor) (*Compatibility, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	implementation, err := master.ITellor.GetAddressVars(opts, crypto.Keccak256Hash([]byte("_TELLOR_CONTRACT")))
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the implementation address of the tellor contract")
+	}
+	oracle, err := NewOracle(ctx, client, master)
+	if err != nil {
+		return nil, err
+	}
+	compat := &Compatibility{
+		Version:        oracle.Version(),
+		Implementation: implementation,
+		Oracle:         oracle.Address(),
+	}
+
+	call := func(name string, f func() error) {
+		if err := f(); err != nil {
+			compat.Failed = append(compat.Failed, fmt.Sprintf("%v: %v", name, err))
+		}
+	}
+	var zero common.Address
+	call("balanceOf", func() error {
+		_, err := master.BalanceOf(opts, zero)
+		return err
+	})
+	call("getStakerInfo", func() error {
+		_, _, err := oracle.GetStakerInfo(opts, zero)
+		return err
+	})
+	call("timeOfLastNewValue", func() error {
+		_, err := oracle.TimeOfLastNewValue(opts)
+		return err
+	})
+	call("reporterLastTimestamp", func() error {
+		_, err := oracle.ReporterLastTimestamp(opts, zero)
+		return err
+	})
+
+	switch o := oracle.(type) {
+	case *tellorOracle:
+		call("getNewCurrentVariables", func() error {
+			_, err := master.GetNewCurrentVariables(opts)
+			return err
+		})
+	case *tellorXOracle:
+		code, err := client.CodeAt(ctx, o.address, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting the oracle contract code")
+		}
+		if len(code) == 0 {
+			compat.Failed = append(compat.Failed, fmt.Sprintf("no oracle contract deployed at the registered address:%v", o.address.Hex()))
+			break
+		}
+		call("getReportingLock", func() error {
+			_, err := o.oracle.GetReportingLock(opts)
+			return err
+		})
+		call("getTimestampCountById", func() error {
+			_, err := o.oracle.GetTimestampCountById(opts, common.Hash{})
+			return err
+		})
+	}
+	return compat, nil
+}
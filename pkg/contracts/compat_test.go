@@ -0,0 +1,24 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package contracts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestCompatibilityErr(t *testing.T) {
+	compat := &Compatibility{Version: OracleTellorX}
+	testutil.Ok(t, compat.Err())
+
+	compat.Failed = []string{"getReportingLock: execution reverted", "getTimestampCountById: execution reverted"}
+	err := compat.Err()
+	testutil.NotOk(t, err)
+	testutil.Assert(t, strings.Contains(err.Error(), string(OracleTellorX)), "the error should name the detected version")
+	for _, f := range compat.Failed {
+		testutil.Assert(t, strings.Contains(err.Error(), f), "the error should list the failed call:%v", f)
+	}
+}
@@ -3,10 +3,15 @@ package contracts
 import (
 	"context"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
 	"github.com/tellor-io/telliot/pkg/contracts/balancer"
+	"github.com/tellor-io/telliot/pkg/contracts/governance"
 	"github.com/tellor-io/telliot/pkg/contracts/lens"
 	"github.com/tellor-io/telliot/pkg/contracts/tellor"
 	"github.com/tellor-io/telliot/pkg/contracts/tellorMesosphere"
@@ -50,8 +55,18 @@ type ITellor struct {
 	Address common.Address
 }
 
-func NewITellor(client *ethclient.Client) (*ITellor, error) {
-	conractAddr, err := GetTellorAddress(client)
+func NewITellor(logger log.Logger, client *ethclient.Client, cfg Config) (*ITellor, error) {
+	ctx := context.Background()
+	network, err := cfg.Select(ctx, client)
+	if err != nil {
+		return nil, errors.Wrap(err, "selecting network")
+	}
+	source := "network"
+	if cfg.ContractAddress != "" {
+		network.Tellor = cfg.ContractAddress
+		source = "config"
+	}
+	conractAddr, err := network.address("tellor", network.Tellor)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting contract address")
 	}
@@ -59,7 +74,10 @@ func NewITellor(client *ethclient.Client) (*ITellor, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "creating contract interface")
 	}
-	contractAddr, err := GetLensAddress(client)
+	if err := validateTellor(ctx, client, conractAddr, tellorInstance); err != nil {
+		return nil, errors.Wrapf(err, "validating the tellor contract at:%v", conractAddr.Hex())
+	}
+	contractAddr, err := network.address("lens", network.Lens)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting contract address")
 	}
@@ -69,70 +87,62 @@ func NewITellor(client *ethclient.Client) (*ITellor, error) {
 		return nil, errors.Wrap(err, "creating telllor interface")
 	}
 
-	return &ITellor{Address: common.HexToAddress(TellorAddress), ITellor: tellorInstance, Main: lensInstance}, nil
+	level.Info(logger).Log("msg", "using tellor contract", "addr", conractAddr.Hex(), "network", network.Name, "source", source)
+
+	return &ITellor{Address: conractAddr, ITellor: tellorInstance, Main: lensInstance}, nil
 }
 
-func NewITellorMesosphere(client *ethclient.Client) (*ITellorMesosphere, error) {
-	conractAddr, err := GetTellorMesosphereAddress(client)
+// validateTellor checks that a contract is deployed at the address and that it has the expected interface
+// so that a wrong address fails early instead of with confusing errors from the contract calls.
+func validateTellor(ctx context.Context, client *ethclient.Client, addr common.Address, instance *tellor.ITellor) error {
+	code, err := client.CodeAt(ctx, addr, nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting contract address")
+		return errors.Wrap(err, "getting contract code")
 	}
-	tellorInstance, err := tellorMesosphere.NewTellorMesosphere(conractAddr, client)
-	if err != nil {
-		return nil, errors.Wrap(err, "creating telllor interface")
+	if len(code) == 0 {
+		return errors.New("no contract deployed at the address")
 	}
+	if _, err := instance.GetNewCurrentVariables(&bind.CallOpts{Context: ctx}); err != nil {
+		return errors.Wrap(err, "the contract doesn't implement the tellor interface")
+	}
+	return nil
+}
 
-	return &ITellorMesosphere{Address: common.HexToAddress(TellorMesosphereAddress), TellorMesosphere: tellorInstance}, nil
+type Governance struct {
+	Address common.Address
+	*governance.Governance
 }
 
-func GetTellorMesosphereAddress(client *ethclient.Client) (common.Address, error) {
-	networkID, err := client.NetworkID(context.Background())
+// NewGovernance returns the TellorX governance contract
+// using the address registered in the tellor master contract.
+func NewGovernance(ctx context.Context, client *ethclient.Client, master *ITellor) (*Governance, error) {
+	addr, err := master.ITellor.GetAddressVars(&bind.CallOpts{Context: ctx}, crypto.Keccak256Hash([]byte("_GOVERNANCE_CONTRACT")))
 	if err != nil {
-		return common.Address{}, err
+		return nil, errors.Wrap(err, "getting governance contract address")
+	}
+	if addr == (common.Address{}) {
+		return nil, errors.New("no governance contract registered in the tellor contract, the network is not upgraded to TellorX yet")
 	}
-	switch netID := networkID.Int64(); netID {
-	case 421611:
-		return common.HexToAddress(TellorMesosphereAddressArbitrumTestnet), nil
-	case 4:
-		return common.HexToAddress(TellorMesosphereAddressRinkeby), nil
-	default:
-		return common.Address{}, errors.Errorf("contract address for current network id not found:%v", netID)
+	instance, err := governance.NewGovernance(addr, client)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating governance interface")
 	}
+	return &Governance{Address: addr, Governance: instance}, nil
 }
 
-func GetTellorAddress(client *ethclient.Client) (common.Address, error) {
-	networkID, err := client.NetworkID(context.Background())
+func NewITellorMesosphere(client *ethclient.Client, cfg Config) (*ITellorMesosphere, error) {
+	network, err := cfg.Select(context.Background(), client)
 	if err != nil {
-		return common.Address{}, err
+		return nil, errors.Wrap(err, "selecting network")
 	}
-
-	switch netID := networkID.Int64(); netID {
-	case 31337:
-		return common.HexToAddress(TellorAddressHardhat), nil
-	case 4: // Rinkeby has the same address as mainnet.
-		fallthrough
-	case 1:
-		return common.HexToAddress(TellorAddress), nil
-	case 5:
-		return common.HexToAddress(TellorAddressGoerli), nil
-	default:
-		return common.Address{}, errors.Errorf("network id not supported id:%v", netID)
+	conractAddr, err := network.address("tellorMesosphere", network.TellorMesosphere)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting contract address")
 	}
-}
-
-func GetLensAddress(client *ethclient.Client) (common.Address, error) {
-	networkID, err := client.NetworkID(context.Background())
+	tellorInstance, err := tellorMesosphere.NewTellorMesosphere(conractAddr, client)
 	if err != nil {
-		return common.Address{}, err
-	}
-	switch netID := networkID.Int64(); netID {
-	case 31337:
-		return common.HexToAddress(LensAddressHardhat), nil
-	case 1:
-		return common.HexToAddress(LensAddressMainnet), nil
-	case 4:
-		return common.HexToAddress(LensAddressRinkeby), nil
-	default:
-		return common.Address{}, errors.Errorf("contract address for current network id not found:%v", netID)
+		return nil, errors.Wrap(err, "creating telllor interface")
 	}
+
+	return &ITellorMesosphere{Address: conractAddr, TellorMesosphere: tellorInstance}, nil
 }
@@ -0,0 +1,902 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package governance
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// GovernanceABI is the input ABI used to generate the binding from.
+const GovernanceABI = "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_contract\",\"type\":\"address\"},{\"internalType\":\"bytes4\",\"name\":\"_function\",\"type\":\"bytes4\"},{\"internalType\":\"bytes\",\"name\":\"_data\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"_timestamp\",\"type\":\"uint256\"}],\"name\":\"proposeVote\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_disputeId\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"_supports\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"_invalidQuery\",\"type\":\"bool\"}],\"name\":\"vote\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_disputeId\",\"type\":\"uint256\"}],\"name\":\"tallyVotes\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_disputeId\",\"type\":\"uint256\"}],\"name\":\"executeVote\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getVoteCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_disputeId\",\"type\":\"uint256\"}],\"name\":\"getVoteInfo\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"},{\"internalType\":\"uint256[8]\",\"name\":\"\",\"type\":\"uint256[8]\"},{\"internalType\":\"bool[2]\",\"name\":\"\",\"type\":\"bool[2]\"},{\"internalType\":\"enumGovernance.VoteResult\",\"name\":\"\",\"type\":\"uint8\"},{\"internalType\":\"bytes\",\"name\":\"\",\"type\":\"bytes\"},{\"internalType\":\"bytes4\",\"name\":\"\",\"type\":\"bytes4\"},{\"internalType\":\"address[2]\",\"name\":\"\",\"type\":\"address[2]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_disputeId\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"_voter\",\"type\":\"address\"}],\"name\":\"didVote\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"address\",\"name\":\"_contract\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bytes4\",\"name\":\"_function\",\"type\":\"bytes4\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"_data\",\"type\":\"bytes\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"_disputeId\",\"type\":\"uint256\"}],\"name\":\"NewVote\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"_disputeId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"_supports\",\"type\":\"bool\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"_voter\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"_voteWeight\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"_invalidQuery\",\"type\":\"bool\"}],\"name\":\"Voted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"_disputeId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"enumGovernance.VoteResult\",\"name\":\"_result\",\"type\":\"uint8\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"_initiator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"_reporter\",\"type\":\"address\"}],\"name\":\"VoteTallied\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"_disputeId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"enumGovernance.VoteResult\",\"name\":\"_result\",\"type\":\"uint8\"}],\"name\":\"VoteExecuted\",\"type\":\"event\"}]"
+
+// Governance is an auto generated Go binding around an Ethereum contract.
+type Governance struct {
+	GovernanceCaller     // Read-only binding to the contract
+	GovernanceTransactor // Write-only binding to the contract
+	GovernanceFilterer   // Log filterer for contract events
+}
+
+// GovernanceCaller is an auto generated read-only Go binding around an Ethereum contract.
+type GovernanceCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// GovernanceTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type GovernanceTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// GovernanceFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type GovernanceFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// GovernanceSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type GovernanceSession struct {
+	Contract     *Governance       // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// GovernanceCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type GovernanceCallerSession struct {
+	Contract *GovernanceCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts     // Call options to use throughout this session
+}
+
+// GovernanceTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type GovernanceTransactorSession struct {
+	Contract     *GovernanceTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts     // Transaction auth options to use throughout this session
+}
+
+// GovernanceRaw is an auto generated low-level Go binding around an Ethereum contract.
+type GovernanceRaw struct {
+	Contract *Governance // Generic contract binding to access the raw methods on
+}
+
+// GovernanceCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type GovernanceCallerRaw struct {
+	Contract *GovernanceCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// GovernanceTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type GovernanceTransactorRaw struct {
+	Contract *GovernanceTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewGovernance creates a new instance of Governance, bound to a specific deployed contract.
+func NewGovernance(address common.Address, backend bind.ContractBackend) (*Governance, error) {
+	contract, err := bindGovernance(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Governance{GovernanceCaller: GovernanceCaller{contract: contract}, GovernanceTransactor: GovernanceTransactor{contract: contract}, GovernanceFilterer: GovernanceFilterer{contract: contract}}, nil
+}
+
+// NewGovernanceCaller creates a new read-only instance of Governance, bound to a specific deployed contract.
+func NewGovernanceCaller(address common.Address, caller bind.ContractCaller) (*GovernanceCaller, error) {
+	contract, err := bindGovernance(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GovernanceCaller{contract: contract}, nil
+}
+
+// NewGovernanceTransactor creates a new write-only instance of Governance, bound to a specific deployed contract.
+func NewGovernanceTransactor(address common.Address, transactor bind.ContractTransactor) (*GovernanceTransactor, error) {
+	contract, err := bindGovernance(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GovernanceTransactor{contract: contract}, nil
+}
+
+// NewGovernanceFilterer creates a new log filterer instance of Governance, bound to a specific deployed contract.
+func NewGovernanceFilterer(address common.Address, filterer bind.ContractFilterer) (*GovernanceFilterer, error) {
+	contract, err := bindGovernance(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &GovernanceFilterer{contract: contract}, nil
+}
+
+// bindGovernance binds a generic wrapper to an already deployed contract.
+func bindGovernance(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(GovernanceABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Governance *GovernanceRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Governance.Contract.GovernanceCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Governance *GovernanceRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Governance.Contract.GovernanceTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Governance *GovernanceRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Governance.Contract.GovernanceTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Governance *GovernanceCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Governance.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Governance *GovernanceTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Governance.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Governance *GovernanceTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Governance.Contract.contract.Transact(opts, method, params...)
+}
+
+// DidVote is a free data retrieval call binding the contract method 0xa7c438bc.
+//
+// Solidity: function didVote(uint256 _disputeId, address _voter) view returns(bool)
+func (_Governance *GovernanceCaller) DidVote(opts *bind.CallOpts, _disputeId *big.Int, _voter common.Address) (bool, error) {
+	var out []interface{}
+	err := _Governance.contract.Call(opts, &out, "didVote", _disputeId, _voter)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// DidVote is a free data retrieval call binding the contract method 0xa7c438bc.
+//
+// Solidity: function didVote(uint256 _disputeId, address _voter) view returns(bool)
+func (_Governance *GovernanceSession) DidVote(_disputeId *big.Int, _voter common.Address) (bool, error) {
+	return _Governance.Contract.DidVote(&_Governance.CallOpts, _disputeId, _voter)
+}
+
+// DidVote is a free data retrieval call binding the contract method 0xa7c438bc.
+//
+// Solidity: function didVote(uint256 _disputeId, address _voter) view returns(bool)
+func (_Governance *GovernanceCallerSession) DidVote(_disputeId *big.Int, _voter common.Address) (bool, error) {
+	return _Governance.Contract.DidVote(&_Governance.CallOpts, _disputeId, _voter)
+}
+
+// GetVoteCount is a free data retrieval call binding the contract method 0xe7b3387c.
+//
+// Solidity: function getVoteCount() view returns(uint256)
+func (_Governance *GovernanceCaller) GetVoteCount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Governance.contract.Call(opts, &out, "getVoteCount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetVoteCount is a free data retrieval call binding the contract method 0xe7b3387c.
+//
+// Solidity: function getVoteCount() view returns(uint256)
+func (_Governance *GovernanceSession) GetVoteCount() (*big.Int, error) {
+	return _Governance.Contract.GetVoteCount(&_Governance.CallOpts)
+}
+
+// GetVoteCount is a free data retrieval call binding the contract method 0xe7b3387c.
+//
+// Solidity: function getVoteCount() view returns(uint256)
+func (_Governance *GovernanceCallerSession) GetVoteCount() (*big.Int, error) {
+	return _Governance.Contract.GetVoteCount(&_Governance.CallOpts)
+}
+
+// GetVoteInfo is a free data retrieval call binding the contract method 0x8d824273.
+//
+// Solidity: function getVoteInfo(uint256 _disputeId) view returns(bytes32, uint256[8], bool[2], uint8, bytes, bytes4, address[2])
+func (_Governance *GovernanceCaller) GetVoteInfo(opts *bind.CallOpts, _disputeId *big.Int) ([32]byte, [8]*big.Int, [2]bool, uint8, []byte, [4]byte, [2]common.Address, error) {
+	var out []interface{}
+	err := _Governance.contract.Call(opts, &out, "getVoteInfo", _disputeId)
+
+	if err != nil {
+		return *new([32]byte), *new([8]*big.Int), *new([2]bool), *new(uint8), *new([]byte), *new([4]byte), *new([2]common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	out1 := *abi.ConvertType(out[1], new([8]*big.Int)).(*[8]*big.Int)
+	out2 := *abi.ConvertType(out[2], new([2]bool)).(*[2]bool)
+	out3 := *abi.ConvertType(out[3], new(uint8)).(*uint8)
+	out4 := *abi.ConvertType(out[4], new([]byte)).(*[]byte)
+	out5 := *abi.ConvertType(out[5], new([4]byte)).(*[4]byte)
+	out6 := *abi.ConvertType(out[6], new([2]common.Address)).(*[2]common.Address)
+
+	return out0, out1, out2, out3, out4, out5, out6, err
+
+}
+
+// GetVoteInfo is a free data retrieval call binding the contract method 0x8d824273.
+//
+// Solidity: function getVoteInfo(uint256 _disputeId) view returns(bytes32, uint256[8], bool[2], uint8, bytes, bytes4, address[2])
+func (_Governance *GovernanceSession) GetVoteInfo(_disputeId *big.Int) ([32]byte, [8]*big.Int, [2]bool, uint8, []byte, [4]byte, [2]common.Address, error) {
+	return _Governance.Contract.GetVoteInfo(&_Governance.CallOpts, _disputeId)
+}
+
+// GetVoteInfo is a free data retrieval call binding the contract method 0x8d824273.
+//
+// Solidity: function getVoteInfo(uint256 _disputeId) view returns(bytes32, uint256[8], bool[2], uint8, bytes, bytes4, address[2])
+func (_Governance *GovernanceCallerSession) GetVoteInfo(_disputeId *big.Int) ([32]byte, [8]*big.Int, [2]bool, uint8, []byte, [4]byte, [2]common.Address, error) {
+	return _Governance.Contract.GetVoteInfo(&_Governance.CallOpts, _disputeId)
+}
+
+// ExecuteVote is a paid mutator transaction binding the contract method 0xf98a4eca.
+//
+// Solidity: function executeVote(uint256 _disputeId) returns()
+func (_Governance *GovernanceTransactor) ExecuteVote(opts *bind.TransactOpts, _disputeId *big.Int) (*types.Transaction, error) {
+	return _Governance.contract.Transact(opts, "executeVote", _disputeId)
+}
+
+// ExecuteVote is a paid mutator transaction binding the contract method 0xf98a4eca.
+//
+// Solidity: function executeVote(uint256 _disputeId) returns()
+func (_Governance *GovernanceSession) ExecuteVote(_disputeId *big.Int) (*types.Transaction, error) {
+	return _Governance.Contract.ExecuteVote(&_Governance.TransactOpts, _disputeId)
+}
+
+// ExecuteVote is a paid mutator transaction binding the contract method 0xf98a4eca.
+//
+// Solidity: function executeVote(uint256 _disputeId) returns()
+func (_Governance *GovernanceTransactorSession) ExecuteVote(_disputeId *big.Int) (*types.Transaction, error) {
+	return _Governance.Contract.ExecuteVote(&_Governance.TransactOpts, _disputeId)
+}
+
+// ProposeVote is a paid mutator transaction binding the contract method 0x0b5e95c3.
+//
+// Solidity: function proposeVote(address _contract, bytes4 _function, bytes _data, uint256 _timestamp) returns()
+func (_Governance *GovernanceTransactor) ProposeVote(opts *bind.TransactOpts, _contract common.Address, _function [4]byte, _data []byte, _timestamp *big.Int) (*types.Transaction, error) {
+	return _Governance.contract.Transact(opts, "proposeVote", _contract, _function, _data, _timestamp)
+}
+
+// ProposeVote is a paid mutator transaction binding the contract method 0x0b5e95c3.
+//
+// Solidity: function proposeVote(address _contract, bytes4 _function, bytes _data, uint256 _timestamp) returns()
+func (_Governance *GovernanceSession) ProposeVote(_contract common.Address, _function [4]byte, _data []byte, _timestamp *big.Int) (*types.Transaction, error) {
+	return _Governance.Contract.ProposeVote(&_Governance.TransactOpts, _contract, _function, _data, _timestamp)
+}
+
+// ProposeVote is a paid mutator transaction binding the contract method 0x0b5e95c3.
+//
+// Solidity: function proposeVote(address _contract, bytes4 _function, bytes _data, uint256 _timestamp) returns()
+func (_Governance *GovernanceTransactorSession) ProposeVote(_contract common.Address, _function [4]byte, _data []byte, _timestamp *big.Int) (*types.Transaction, error) {
+	return _Governance.Contract.ProposeVote(&_Governance.TransactOpts, _contract, _function, _data, _timestamp)
+}
+
+// TallyVotes is a paid mutator transaction binding the contract method 0x4d318b0e.
+//
+// Solidity: function tallyVotes(uint256 _disputeId) returns()
+func (_Governance *GovernanceTransactor) TallyVotes(opts *bind.TransactOpts, _disputeId *big.Int) (*types.Transaction, error) {
+	return _Governance.contract.Transact(opts, "tallyVotes", _disputeId)
+}
+
+// TallyVotes is a paid mutator transaction binding the contract method 0x4d318b0e.
+//
+// Solidity: function tallyVotes(uint256 _disputeId) returns()
+func (_Governance *GovernanceSession) TallyVotes(_disputeId *big.Int) (*types.Transaction, error) {
+	return _Governance.Contract.TallyVotes(&_Governance.TransactOpts, _disputeId)
+}
+
+// TallyVotes is a paid mutator transaction binding the contract method 0x4d318b0e.
+//
+// Solidity: function tallyVotes(uint256 _disputeId) returns()
+func (_Governance *GovernanceTransactorSession) TallyVotes(_disputeId *big.Int) (*types.Transaction, error) {
+	return _Governance.Contract.TallyVotes(&_Governance.TransactOpts, _disputeId)
+}
+
+// Vote is a paid mutator transaction binding the contract method 0xdf133bca.
+//
+// Solidity: function vote(uint256 _disputeId, bool _supports, bool _invalidQuery) returns()
+func (_Governance *GovernanceTransactor) Vote(opts *bind.TransactOpts, _disputeId *big.Int, _supports bool, _invalidQuery bool) (*types.Transaction, error) {
+	return _Governance.contract.Transact(opts, "vote", _disputeId, _supports, _invalidQuery)
+}
+
+// Vote is a paid mutator transaction binding the contract method 0xdf133bca.
+//
+// Solidity: function vote(uint256 _disputeId, bool _supports, bool _invalidQuery) returns()
+func (_Governance *GovernanceSession) Vote(_disputeId *big.Int, _supports bool, _invalidQuery bool) (*types.Transaction, error) {
+	return _Governance.Contract.Vote(&_Governance.TransactOpts, _disputeId, _supports, _invalidQuery)
+}
+
+// Vote is a paid mutator transaction binding the contract method 0xdf133bca.
+//
+// Solidity: function vote(uint256 _disputeId, bool _supports, bool _invalidQuery) returns()
+func (_Governance *GovernanceTransactorSession) Vote(_disputeId *big.Int, _supports bool, _invalidQuery bool) (*types.Transaction, error) {
+	return _Governance.Contract.Vote(&_Governance.TransactOpts, _disputeId, _supports, _invalidQuery)
+}
+
+// GovernanceNewVoteIterator is returned from FilterNewVote and is used to iterate over the raw logs and unpacked data for NewVote events raised by the Governance contract.
+type GovernanceNewVoteIterator struct {
+	Event *GovernanceNewVote // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *GovernanceNewVoteIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(GovernanceNewVote)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(GovernanceNewVote)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *GovernanceNewVoteIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *GovernanceNewVoteIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// GovernanceNewVote represents a NewVote event raised by the Governance contract.
+type GovernanceNewVote struct {
+	Contract  common.Address
+	Function  [4]byte
+	Data      []byte
+	DisputeId *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterNewVote is a free log retrieval operation binding the contract event 0x03cd1db94c84fbf802bb289f9fec190fc43fcb105eee5554433e8d642ceab118.
+//
+// Solidity: event NewVote(address _contract, bytes4 _function, bytes _data, uint256 _disputeId)
+func (_Governance *GovernanceFilterer) FilterNewVote(opts *bind.FilterOpts) (*GovernanceNewVoteIterator, error) {
+
+	logs, sub, err := _Governance.contract.FilterLogs(opts, "NewVote")
+	if err != nil {
+		return nil, err
+	}
+	return &GovernanceNewVoteIterator{contract: _Governance.contract, event: "NewVote", logs: logs, sub: sub}, nil
+}
+
+// WatchNewVote is a free log subscription operation binding the contract event 0x03cd1db94c84fbf802bb289f9fec190fc43fcb105eee5554433e8d642ceab118.
+//
+// Solidity: event NewVote(address _contract, bytes4 _function, bytes _data, uint256 _disputeId)
+func (_Governance *GovernanceFilterer) WatchNewVote(opts *bind.WatchOpts, sink chan<- *GovernanceNewVote) (event.Subscription, error) {
+
+	logs, sub, err := _Governance.contract.WatchLogs(opts, "NewVote")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(GovernanceNewVote)
+				if err := _Governance.contract.UnpackLog(event, "NewVote", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseNewVote is a log parse operation binding the contract event 0x03cd1db94c84fbf802bb289f9fec190fc43fcb105eee5554433e8d642ceab118.
+//
+// Solidity: event NewVote(address _contract, bytes4 _function, bytes _data, uint256 _disputeId)
+func (_Governance *GovernanceFilterer) ParseNewVote(log types.Log) (*GovernanceNewVote, error) {
+	event := new(GovernanceNewVote)
+	if err := _Governance.contract.UnpackLog(event, "NewVote", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// GovernanceVoteExecutedIterator is returned from FilterVoteExecuted and is used to iterate over the raw logs and unpacked data for VoteExecuted events raised by the Governance contract.
+type GovernanceVoteExecutedIterator struct {
+	Event *GovernanceVoteExecuted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *GovernanceVoteExecutedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(GovernanceVoteExecuted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(GovernanceVoteExecuted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *GovernanceVoteExecutedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *GovernanceVoteExecutedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// GovernanceVoteExecuted represents a VoteExecuted event raised by the Governance contract.
+type GovernanceVoteExecuted struct {
+	DisputeId *big.Int
+	Result    uint8
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterVoteExecuted is a free log retrieval operation binding the contract event 0x40d231bf91823121de9e1c012d95f835ea5684dc1d93360d9510a30543345da4.
+//
+// Solidity: event VoteExecuted(uint256 _disputeId, uint8 _result)
+func (_Governance *GovernanceFilterer) FilterVoteExecuted(opts *bind.FilterOpts) (*GovernanceVoteExecutedIterator, error) {
+
+	logs, sub, err := _Governance.contract.FilterLogs(opts, "VoteExecuted")
+	if err != nil {
+		return nil, err
+	}
+	return &GovernanceVoteExecutedIterator{contract: _Governance.contract, event: "VoteExecuted", logs: logs, sub: sub}, nil
+}
+
+// WatchVoteExecuted is a free log subscription operation binding the contract event 0x40d231bf91823121de9e1c012d95f835ea5684dc1d93360d9510a30543345da4.
+//
+// Solidity: event VoteExecuted(uint256 _disputeId, uint8 _result)
+func (_Governance *GovernanceFilterer) WatchVoteExecuted(opts *bind.WatchOpts, sink chan<- *GovernanceVoteExecuted) (event.Subscription, error) {
+
+	logs, sub, err := _Governance.contract.WatchLogs(opts, "VoteExecuted")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(GovernanceVoteExecuted)
+				if err := _Governance.contract.UnpackLog(event, "VoteExecuted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseVoteExecuted is a log parse operation binding the contract event 0x40d231bf91823121de9e1c012d95f835ea5684dc1d93360d9510a30543345da4.
+//
+// Solidity: event VoteExecuted(uint256 _disputeId, uint8 _result)
+func (_Governance *GovernanceFilterer) ParseVoteExecuted(log types.Log) (*GovernanceVoteExecuted, error) {
+	event := new(GovernanceVoteExecuted)
+	if err := _Governance.contract.UnpackLog(event, "VoteExecuted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// GovernanceVoteTalliedIterator is returned from FilterVoteTallied and is used to iterate over the raw logs and unpacked data for VoteTallied events raised by the Governance contract.
+type GovernanceVoteTalliedIterator struct {
+	Event *GovernanceVoteTallied // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *GovernanceVoteTalliedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(GovernanceVoteTallied)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(GovernanceVoteTallied)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *GovernanceVoteTalliedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *GovernanceVoteTalliedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// GovernanceVoteTallied represents a VoteTallied event raised by the Governance contract.
+type GovernanceVoteTallied struct {
+	DisputeId *big.Int
+	Result    uint8
+	Initiator common.Address
+	Reporter  common.Address
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterVoteTallied is a free log retrieval operation binding the contract event 0xa2d4e500801849d40ad00f0f12ba92a5263f83ec68946e647be95cfbe581c7b6.
+//
+// Solidity: event VoteTallied(uint256 _disputeId, uint8 _result, address _initiator, address _reporter)
+func (_Governance *GovernanceFilterer) FilterVoteTallied(opts *bind.FilterOpts) (*GovernanceVoteTalliedIterator, error) {
+
+	logs, sub, err := _Governance.contract.FilterLogs(opts, "VoteTallied")
+	if err != nil {
+		return nil, err
+	}
+	return &GovernanceVoteTalliedIterator{contract: _Governance.contract, event: "VoteTallied", logs: logs, sub: sub}, nil
+}
+
+// WatchVoteTallied is a free log subscription operation binding the contract event 0xa2d4e500801849d40ad00f0f12ba92a5263f83ec68946e647be95cfbe581c7b6.
+//
+// Solidity: event VoteTallied(uint256 _disputeId, uint8 _result, address _initiator, address _reporter)
+func (_Governance *GovernanceFilterer) WatchVoteTallied(opts *bind.WatchOpts, sink chan<- *GovernanceVoteTallied) (event.Subscription, error) {
+
+	logs, sub, err := _Governance.contract.WatchLogs(opts, "VoteTallied")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(GovernanceVoteTallied)
+				if err := _Governance.contract.UnpackLog(event, "VoteTallied", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseVoteTallied is a log parse operation binding the contract event 0xa2d4e500801849d40ad00f0f12ba92a5263f83ec68946e647be95cfbe581c7b6.
+//
+// Solidity: event VoteTallied(uint256 _disputeId, uint8 _result, address _initiator, address _reporter)
+func (_Governance *GovernanceFilterer) ParseVoteTallied(log types.Log) (*GovernanceVoteTallied, error) {
+	event := new(GovernanceVoteTallied)
+	if err := _Governance.contract.UnpackLog(event, "VoteTallied", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// GovernanceVotedIterator is returned from FilterVoted and is used to iterate over the raw logs and unpacked data for Voted events raised by the Governance contract.
+type GovernanceVotedIterator struct {
+	Event *GovernanceVoted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *GovernanceVotedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(GovernanceVoted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(GovernanceVoted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *GovernanceVotedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *GovernanceVotedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// GovernanceVoted represents a Voted event raised by the Governance contract.
+type GovernanceVoted struct {
+	DisputeId    *big.Int
+	Supports     bool
+	Voter        common.Address
+	VoteWeight   *big.Int
+	InvalidQuery bool
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterVoted is a free log retrieval operation binding the contract event 0x687119431787025fac8e5b4b0fc31f4cf9701bd16535c717e286c568c1dc8356.
+//
+// Solidity: event Voted(uint256 _disputeId, bool _supports, address _voter, uint256 _voteWeight, bool _invalidQuery)
+func (_Governance *GovernanceFilterer) FilterVoted(opts *bind.FilterOpts) (*GovernanceVotedIterator, error) {
+
+	logs, sub, err := _Governance.contract.FilterLogs(opts, "Voted")
+	if err != nil {
+		return nil, err
+	}
+	return &GovernanceVotedIterator{contract: _Governance.contract, event: "Voted", logs: logs, sub: sub}, nil
+}
+
+// WatchVoted is a free log subscription operation binding the contract event 0x687119431787025fac8e5b4b0fc31f4cf9701bd16535c717e286c568c1dc8356.
+//
+// Solidity: event Voted(uint256 _disputeId, bool _supports, address _voter, uint256 _voteWeight, bool _invalidQuery)
+func (_Governance *GovernanceFilterer) WatchVoted(opts *bind.WatchOpts, sink chan<- *GovernanceVoted) (event.Subscription, error) {
+
+	logs, sub, err := _Governance.contract.WatchLogs(opts, "Voted")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(GovernanceVoted)
+				if err := _Governance.contract.UnpackLog(event, "Voted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseVoted is a log parse operation binding the contract event 0x687119431787025fac8e5b4b0fc31f4cf9701bd16535c717e286c568c1dc8356.
+//
+// Solidity: event Voted(uint256 _disputeId, bool _supports, address _voter, uint256 _voteWeight, bool _invalidQuery)
+func (_Governance *GovernanceFilterer) ParseVoted(log types.Log) (*GovernanceVoted, error) {
+	event := new(GovernanceVoted)
+	if err := _Governance.contract.UnpackLog(event, "Voted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
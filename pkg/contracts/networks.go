@@ -0,0 +1,142 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package contracts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/format"
+)
+
+// Network is a deployment of the Tellor contracts.
+// An empty address means that the contract is not deployed on the network.
+type Network struct {
+	Name             string
+	ChainID          int64
+	Tellor           string `json:",omitempty"`
+	Lens             string `json:",omitempty"`
+	TellorMesosphere string `json:",omitempty"`
+}
+
+// Networks is the registry of the known deployments.
+var Networks = []Network{
+	{
+		Name:    "mainnet",
+		ChainID: 1,
+		Tellor:  TellorAddress,
+		Lens:    LensAddressMainnet,
+	},
+	{
+		Name:             "rinkeby",
+		ChainID:          4,
+		Tellor:           TellorAddress, // Rinkeby has the same address as mainnet.
+		Lens:             LensAddressRinkeby,
+		TellorMesosphere: TellorMesosphereAddressRinkeby,
+	},
+	{
+		Name:    "goerli",
+		ChainID: 5,
+		Tellor:  TellorAddressGoerli,
+	},
+	{
+		Name:             "polygon",
+		ChainID:          137,
+		TellorMesosphere: TellorMesosphereAddress,
+	},
+	{
+		Name:             "arbitrumTestnet",
+		ChainID:          421611,
+		TellorMesosphere: TellorMesosphereAddressArbitrumTestnet,
+	},
+	{
+		Name:    "hardhat",
+		ChainID: 31337,
+		Tellor:  TellorAddressHardhat,
+		Lens:    LensAddressHardhat,
+	},
+}
+
+type Config struct {
+	MaxNodeLag         format.Duration `help:"Refuse to send transactions when the latest block of the node is older than this as values built on a lagging node get disputed. 0 disables the check."`
+	ContractAddress    string          `help:"Address of the Tellor oracle contract overriding the address of the network, for example after a proxy upgrade or when testing against a fork."`
+	Network            string          `help:"Name of the network from the registry - mainnet, rinkeby, goerli, polygon, arbitrumTestnet, hardhat or one of the custom networks. Empty selects the network by the chain id of the node."`
+	Networks           []Network       `help:"Custom networks added to the registry. A network with the name or chain id of a known network overrides its non empty addresses."`
+	IgnoreIncompatible bool            `help:"Only log an error instead of exiting when the deployed contracts don't answer the calls of the bindings of this version, for example right after a protocol upgrade."`
+}
+
+// Registry returns the known networks with the custom networks applied.
+func (self Config) Registry() []Network {
+	registry := append([]Network{}, Networks...)
+	for _, custom := range self.Networks {
+		found := false
+		for i, n := range registry {
+			if (custom.Name == "" || !strings.EqualFold(custom.Name, n.Name)) && (custom.ChainID == 0 || custom.ChainID != n.ChainID) {
+				continue
+			}
+			found = true
+			if custom.Tellor != "" {
+				registry[i].Tellor = custom.Tellor
+			}
+			if custom.Lens != "" {
+				registry[i].Lens = custom.Lens
+			}
+			if custom.TellorMesosphere != "" {
+				registry[i].TellorMesosphere = custom.TellorMesosphere
+			}
+		}
+		if !found {
+			registry = append(registry, custom)
+		}
+	}
+	return registry
+}
+
+func (self Config) find(match func(Network) bool) (Network, bool) {
+	for _, n := range self.Registry() {
+		if match(n) {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
+
+// Select returns the configured network or when not set the network with the chain id of the node.
+// The selected network must have the same chain id as the node
+// to prevent sending transactions to a contract on another network.
+func (self Config) Select(ctx context.Context, client *ethclient.Client) (Network, error) {
+	chainID, err := client.NetworkID(ctx)
+	if err != nil {
+		return Network{}, errors.Wrap(err, "getting network ID")
+	}
+	if self.Network == "" {
+		n, ok := self.find(func(n Network) bool { return n.ChainID == chainID.Int64() })
+		if !ok {
+			return Network{}, errors.Errorf("network id not supported id:%v", chainID)
+		}
+		return n, nil
+	}
+
+	n, ok := self.find(func(n Network) bool { return strings.EqualFold(n.Name, self.Network) })
+	if !ok {
+		return Network{}, errors.Errorf("network not in the registry:%v", self.Network)
+	}
+	if n.ChainID != 0 && n.ChainID != chainID.Int64() {
+		return Network{}, errors.Errorf("the node is on network id:%v while the configured network %v is on id:%v", chainID, n.Name, n.ChainID)
+	}
+	return n, nil
+}
+
+func (self Network) address(contract, addr string) (common.Address, error) {
+	if addr == "" {
+		return common.Address{}, errors.Errorf("no %v contract address for network:%v", contract, self.Name)
+	}
+	if !common.IsHexAddress(addr) {
+		return common.Address{}, errors.Errorf("invalid %v contract address for network %v:%v", contract, self.Name, addr)
+	}
+	return common.HexToAddress(addr), nil
+}
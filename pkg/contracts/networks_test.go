@@ -0,0 +1,39 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package contracts
+
+import (
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestRegistry(t *testing.T) {
+	const custom = "0x1111111111111111111111111111111111111111"
+	cfg := Config{
+		Networks: []Network{
+			{ChainID: 5, Lens: custom},
+			{Name: "Mainnet", Tellor: custom},
+			{Name: "fork", ChainID: 1337, Tellor: custom, Lens: custom},
+		},
+	}
+
+	find := func(name string) Network {
+		t.Helper()
+		n, ok := cfg.find(func(n Network) bool { return n.Name == name })
+		testutil.Assert(t, ok, "network not found:%v", name)
+		return n
+	}
+
+	// Overrides by chain id and by name keep the other addresses.
+	testutil.Equals(t, Network{Name: "goerli", ChainID: 5, Tellor: TellorAddressGoerli, Lens: custom}, find("goerli"))
+	testutil.Equals(t, Network{Name: "mainnet", ChainID: 1, Tellor: custom, Lens: LensAddressMainnet}, find("mainnet"))
+
+	// New networks are added.
+	testutil.Equals(t, cfg.Networks[2], find("fork"))
+	testutil.Equals(t, len(Networks)+1, len(cfg.Registry()))
+
+	// The package registry is not changed.
+	testutil.Equals(t, TellorAddress, Networks[0].Tellor)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package contracts
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/contracts/tellorX"
+)
+
+// OracleVersion is the version of the oracle contract.
+type OracleVersion string
+
+const (
+	// OracleTellor is the current contract where the values are submitted with a mining solution.
+	OracleTellor OracleVersion = "tellor"
+	// OracleTellorX is the upgraded contract where each value is submitted separately
+	// with its query id and query data.
+	OracleTellorX OracleVersion = "tellorX"
+)
+
+// Oracle is the part of the oracle contract used for submitting values
+// that doesn't change between the contract versions.
+type Oracle interface {
+	Version() OracleVersion
+	Address() common.Address
+	// Submit sends the values of the requests of a challenge.
+	// The nonce is the mining solution and is ignored by the versions without mining.
+	Submit(opts *bind.TransactOpts, nonce string, requestIDs [5]*big.Int, values [5]*big.Int) (*types.Transaction, error)
+	// TimeOfLastNewValue returns when the last value was added which starts a new reporting window.
+	TimeOfLastNewValue(opts *bind.CallOpts) (*big.Int, error)
+	// ReporterLastTimestamp returns when the reporter submitted the last time.
+	ReporterLastTimestamp(opts *bind.CallOpts, reporter common.Address) (*big.Int, error)
+	GetStakerInfo(opts *bind.CallOpts, staker common.Address) (*big.Int, *big.Int, error)
+}
+
+// NewOracle returns the oracle for the version of the contract deployed on chain.
+// The TellorX upgrade registers the new oracle contract in the master contract
+// so it is detected by its address var.
+func NewOracle(ctx context.Context, client *ethclient.Client, master *ITellor) (Oracle, error) {
+	addr, err := master.ITellor.GetAddressVars(&bind.CallOpts{Context: ctx}, crypto.Keccak256Hash([]byte("_ORACLE_CONTRACT")))
+	if err != nil {
+		return nil, errors.Wrap(err, "getting oracle contract address")
+	}
+	if addr == (common.Address{}) {
+		return &tellorOracle{master: master}, nil
+	}
+	instance, err := tellorX.NewOracle(addr, client)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating oracle interface")
+	}
+	return &tellorXOracle{master: master, address: addr, oracle: instance}, nil
+}
+
+type tellorOracle struct {
+	master *ITellor
+}
+
+func (self *tellorOracle) Version() OracleVersion {
+	return OracleTellor
+}
+
+func (self *tellorOracle) Address() common.Address {
+	return self.master.Address
+}
+
+func (self *tellorOracle) Submit(opts *bind.TransactOpts, nonce string, requestIDs [5]*big.Int, values [5]*big.Int) (*types.Transaction, error) {
+	return self.master.SubmitMiningSolution(opts, nonce, requestIDs, values)
+}
+
+func (self *tellorOracle) TimeOfLastNewValue(opts *bind.CallOpts) (*big.Int, error) {
+	return self.master.GetUintVar(opts, crypto.Keccak256Hash([]byte("_TIME_OF_LAST_NEW_VALUE")))
+}
+
+func (self *tellorOracle) ReporterLastTimestamp(opts *bind.CallOpts, reporter common.Address) (*big.Int, error) {
+	// The last submit time is kept under the hash of the reporter address padded to 32 bytes.
+	return self.master.GetUintVar(opts, crypto.Keccak256Hash(common.LeftPadBytes(reporter.Bytes(), 32)))
+}
+
+func (self *tellorOracle) GetStakerInfo(opts *bind.CallOpts, staker common.Address) (*big.Int, *big.Int, error) {
+	return self.master.GetStakerInfo(opts, staker)
+}
+
+type tellorXOracle struct {
+	master  *ITellor
+	address common.Address
+	oracle  *tellorX.Oracle
+}
+
+func (self *tellorXOracle) Version() OracleVersion {
+	return OracleTellorX
+}
+
+func (self *tellorXOracle) Address() common.Address {
+	return self.address
+}
+
+// Submit sends the value of the first request.
+// TellorX allows one value per reporting lock so only the first request can be set
+// and an error is returned when there are more.
+// The legacy request ids are valid query ids without query data.
+func (self *tellorXOracle) Submit(opts *bind.TransactOpts, nonce string, requestIDs [5]*big.Int, values [5]*big.Int) (*types.Transaction, error) {
+	if err := singleRequest(requestIDs, values); err != nil {
+		return nil, err
+	}
+	queryID := common.BigToHash(requestIDs[0])
+	count, err := self.oracle.GetTimestampCountById(&bind.CallOpts{Context: opts.Context}, queryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the report count")
+	}
+	return self.oracle.SubmitValue(opts, queryID, common.LeftPadBytes(values[0].Bytes(), 32), count, []byte{})
+}
+
+// singleRequest returns an error unless only the first request and its value are set.
+func singleRequest(requestIDs [5]*big.Int, values [5]*big.Int) error {
+	if requestIDs[0] == nil || values[0] == nil {
+		return errors.New("missing the request id or the value to submit")
+	}
+	for i := 1; i < len(requestIDs); i++ {
+		if requestIDs[i] != nil && requestIDs[i].Sign() != 0 {
+			return errors.Errorf("tellorX accepts one value per submit, got request ids:%v", requestIDs)
+		}
+	}
+	return nil
+}
+
+func (self *tellorXOracle) TimeOfLastNewValue(opts *bind.CallOpts) (*big.Int, error) {
+	return self.oracle.GetTimeOfLastNewValue(opts)
+}
+
+func (self *tellorXOracle) ReporterLastTimestamp(opts *bind.CallOpts, reporter common.Address) (*big.Int, error) {
+	return self.oracle.GetReporterLastTimestamp(opts, reporter)
+}
+
+func (self *tellorXOracle) GetStakerInfo(opts *bind.CallOpts, staker common.Address) (*big.Int, *big.Int, error) {
+	return self.master.GetStakerInfo(opts, staker)
+}
@@ -0,0 +1,24 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package contracts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestSingleRequest(t *testing.T) {
+	testutil.Ok(t, singleRequest([5]*big.Int{big.NewInt(1)}, [5]*big.Int{big.NewInt(100)}))
+	testutil.Ok(t, singleRequest(
+		[5]*big.Int{big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)},
+		[5]*big.Int{big.NewInt(100), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)},
+	))
+	testutil.NotOk(t, singleRequest([5]*big.Int{}, [5]*big.Int{}))
+	testutil.NotOk(t, singleRequest(
+		[5]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4), big.NewInt(5)},
+		[5]*big.Int{big.NewInt(100), big.NewInt(200), big.NewInt(300), big.NewInt(400), big.NewInt(500)},
+	), "the other values would be silently dropped")
+}
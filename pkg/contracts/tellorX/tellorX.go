@@ -0,0 +1,488 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package tellorX
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// OracleABI is the input ABI used to generate the binding from.
+const OracleABI = "[{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_queryId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes\",\"name\":\"_value\",\"type\":\"bytes\"},{\"internalType\":\"uint256\",\"name\":\"_nonce\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"_queryData\",\"type\":\"bytes\"}],\"name\":\"submitValue\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_queryId\",\"type\":\"bytes32\"}],\"name\":\"getTimestampCountById\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_reporter\",\"type\":\"address\"}],\"name\":\"getReporterLastTimestamp\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getTimeOfLastNewValue\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getReportingLock\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_queryId\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"_timestamp\",\"type\":\"uint256\"}],\"name\":\"getValueByTimestamp\",\"outputs\":[{\"internalType\":\"bytes\",\"name\":\"\",\"type\":\"bytes\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"_queryId\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"_time\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"_value\",\"type\":\"bytes\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"_reward\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"_nonce\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"_queryData\",\"type\":\"bytes\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"_reporter\",\"type\":\"address\"}],\"name\":\"NewReport\",\"type\":\"event\"}]"
+
+// Oracle is an auto generated Go binding around an Ethereum contract.
+type Oracle struct {
+	OracleCaller     // Read-only binding to the contract
+	OracleTransactor // Write-only binding to the contract
+	OracleFilterer   // Log filterer for contract events
+}
+
+// OracleCaller is an auto generated read-only Go binding around an Ethereum contract.
+type OracleCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// OracleTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type OracleTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// OracleFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type OracleFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// OracleSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type OracleSession struct {
+	Contract     *Oracle           // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// OracleCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type OracleCallerSession struct {
+	Contract *OracleCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// OracleTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type OracleTransactorSession struct {
+	Contract     *OracleTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// OracleRaw is an auto generated low-level Go binding around an Ethereum contract.
+type OracleRaw struct {
+	Contract *Oracle // Generic contract binding to access the raw methods on
+}
+
+// OracleCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type OracleCallerRaw struct {
+	Contract *OracleCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// OracleTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type OracleTransactorRaw struct {
+	Contract *OracleTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewOracle creates a new instance of Oracle, bound to a specific deployed contract.
+func NewOracle(address common.Address, backend bind.ContractBackend) (*Oracle, error) {
+	contract, err := bindOracle(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Oracle{OracleCaller: OracleCaller{contract: contract}, OracleTransactor: OracleTransactor{contract: contract}, OracleFilterer: OracleFilterer{contract: contract}}, nil
+}
+
+// NewOracleCaller creates a new read-only instance of Oracle, bound to a specific deployed contract.
+func NewOracleCaller(address common.Address, caller bind.ContractCaller) (*OracleCaller, error) {
+	contract, err := bindOracle(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OracleCaller{contract: contract}, nil
+}
+
+// NewOracleTransactor creates a new write-only instance of Oracle, bound to a specific deployed contract.
+func NewOracleTransactor(address common.Address, transactor bind.ContractTransactor) (*OracleTransactor, error) {
+	contract, err := bindOracle(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OracleTransactor{contract: contract}, nil
+}
+
+// NewOracleFilterer creates a new log filterer instance of Oracle, bound to a specific deployed contract.
+func NewOracleFilterer(address common.Address, filterer bind.ContractFilterer) (*OracleFilterer, error) {
+	contract, err := bindOracle(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &OracleFilterer{contract: contract}, nil
+}
+
+// bindOracle binds a generic wrapper to an already deployed contract.
+func bindOracle(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(OracleABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Oracle *OracleRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Oracle.Contract.OracleCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Oracle *OracleRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Oracle.Contract.OracleTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Oracle *OracleRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Oracle.Contract.OracleTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Oracle *OracleCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Oracle.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Oracle *OracleTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Oracle.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Oracle *OracleTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Oracle.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetReporterLastTimestamp is a free data retrieval call binding the contract method 0x50005b83.
+//
+// Solidity: function getReporterLastTimestamp(address _reporter) view returns(uint256)
+func (_Oracle *OracleCaller) GetReporterLastTimestamp(opts *bind.CallOpts, _reporter common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _Oracle.contract.Call(opts, &out, "getReporterLastTimestamp", _reporter)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetReporterLastTimestamp is a free data retrieval call binding the contract method 0x50005b83.
+//
+// Solidity: function getReporterLastTimestamp(address _reporter) view returns(uint256)
+func (_Oracle *OracleSession) GetReporterLastTimestamp(_reporter common.Address) (*big.Int, error) {
+	return _Oracle.Contract.GetReporterLastTimestamp(&_Oracle.CallOpts, _reporter)
+}
+
+// GetReporterLastTimestamp is a free data retrieval call binding the contract method 0x50005b83.
+//
+// Solidity: function getReporterLastTimestamp(address _reporter) view returns(uint256)
+func (_Oracle *OracleCallerSession) GetReporterLastTimestamp(_reporter common.Address) (*big.Int, error) {
+	return _Oracle.Contract.GetReporterLastTimestamp(&_Oracle.CallOpts, _reporter)
+}
+
+// GetReportingLock is a free data retrieval call binding the contract method 0x460c33a2.
+//
+// Solidity: function getReportingLock() view returns(uint256)
+func (_Oracle *OracleCaller) GetReportingLock(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Oracle.contract.Call(opts, &out, "getReportingLock")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetReportingLock is a free data retrieval call binding the contract method 0x460c33a2.
+//
+// Solidity: function getReportingLock() view returns(uint256)
+func (_Oracle *OracleSession) GetReportingLock() (*big.Int, error) {
+	return _Oracle.Contract.GetReportingLock(&_Oracle.CallOpts)
+}
+
+// GetReportingLock is a free data retrieval call binding the contract method 0x460c33a2.
+//
+// Solidity: function getReportingLock() view returns(uint256)
+func (_Oracle *OracleCallerSession) GetReportingLock() (*big.Int, error) {
+	return _Oracle.Contract.GetReportingLock(&_Oracle.CallOpts)
+}
+
+// GetTimeOfLastNewValue is a free data retrieval call binding the contract method 0xc0f95d52.
+//
+// Solidity: function getTimeOfLastNewValue() view returns(uint256)
+func (_Oracle *OracleCaller) GetTimeOfLastNewValue(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Oracle.contract.Call(opts, &out, "getTimeOfLastNewValue")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetTimeOfLastNewValue is a free data retrieval call binding the contract method 0xc0f95d52.
+//
+// Solidity: function getTimeOfLastNewValue() view returns(uint256)
+func (_Oracle *OracleSession) GetTimeOfLastNewValue() (*big.Int, error) {
+	return _Oracle.Contract.GetTimeOfLastNewValue(&_Oracle.CallOpts)
+}
+
+// GetTimeOfLastNewValue is a free data retrieval call binding the contract method 0xc0f95d52.
+//
+// Solidity: function getTimeOfLastNewValue() view returns(uint256)
+func (_Oracle *OracleCallerSession) GetTimeOfLastNewValue() (*big.Int, error) {
+	return _Oracle.Contract.GetTimeOfLastNewValue(&_Oracle.CallOpts)
+}
+
+// GetTimestampCountById is a free data retrieval call binding the contract method 0x35e72432.
+//
+// Solidity: function getTimestampCountById(bytes32 _queryId) view returns(uint256)
+func (_Oracle *OracleCaller) GetTimestampCountById(opts *bind.CallOpts, _queryId [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _Oracle.contract.Call(opts, &out, "getTimestampCountById", _queryId)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetTimestampCountById is a free data retrieval call binding the contract method 0x35e72432.
+//
+// Solidity: function getTimestampCountById(bytes32 _queryId) view returns(uint256)
+func (_Oracle *OracleSession) GetTimestampCountById(_queryId [32]byte) (*big.Int, error) {
+	return _Oracle.Contract.GetTimestampCountById(&_Oracle.CallOpts, _queryId)
+}
+
+// GetTimestampCountById is a free data retrieval call binding the contract method 0x35e72432.
+//
+// Solidity: function getTimestampCountById(bytes32 _queryId) view returns(uint256)
+func (_Oracle *OracleCallerSession) GetTimestampCountById(_queryId [32]byte) (*big.Int, error) {
+	return _Oracle.Contract.GetTimestampCountById(&_Oracle.CallOpts, _queryId)
+}
+
+// GetValueByTimestamp is a free data retrieval call binding the contract method 0x0b2d2b0d.
+//
+// Solidity: function getValueByTimestamp(bytes32 _queryId, uint256 _timestamp) view returns(bytes)
+func (_Oracle *OracleCaller) GetValueByTimestamp(opts *bind.CallOpts, _queryId [32]byte, _timestamp *big.Int) ([]byte, error) {
+	var out []interface{}
+	err := _Oracle.contract.Call(opts, &out, "getValueByTimestamp", _queryId, _timestamp)
+
+	if err != nil {
+		return *new([]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]byte)).(*[]byte)
+
+	return out0, err
+
+}
+
+// GetValueByTimestamp is a free data retrieval call binding the contract method 0x0b2d2b0d.
+//
+// Solidity: function getValueByTimestamp(bytes32 _queryId, uint256 _timestamp) view returns(bytes)
+func (_Oracle *OracleSession) GetValueByTimestamp(_queryId [32]byte, _timestamp *big.Int) ([]byte, error) {
+	return _Oracle.Contract.GetValueByTimestamp(&_Oracle.CallOpts, _queryId, _timestamp)
+}
+
+// GetValueByTimestamp is a free data retrieval call binding the contract method 0x0b2d2b0d.
+//
+// Solidity: function getValueByTimestamp(bytes32 _queryId, uint256 _timestamp) view returns(bytes)
+func (_Oracle *OracleCallerSession) GetValueByTimestamp(_queryId [32]byte, _timestamp *big.Int) ([]byte, error) {
+	return _Oracle.Contract.GetValueByTimestamp(&_Oracle.CallOpts, _queryId, _timestamp)
+}
+
+// SubmitValue is a paid mutator transaction binding the contract method 0x5eaa9ced.
+//
+// Solidity: function submitValue(bytes32 _queryId, bytes _value, uint256 _nonce, bytes _queryData) returns()
+func (_Oracle *OracleTransactor) SubmitValue(opts *bind.TransactOpts, _queryId [32]byte, _value []byte, _nonce *big.Int, _queryData []byte) (*types.Transaction, error) {
+	return _Oracle.contract.Transact(opts, "submitValue", _queryId, _value, _nonce, _queryData)
+}
+
+// SubmitValue is a paid mutator transaction binding the contract method 0x5eaa9ced.
+//
+// Solidity: function submitValue(bytes32 _queryId, bytes _value, uint256 _nonce, bytes _queryData) returns()
+func (_Oracle *OracleSession) SubmitValue(_queryId [32]byte, _value []byte, _nonce *big.Int, _queryData []byte) (*types.Transaction, error) {
+	return _Oracle.Contract.SubmitValue(&_Oracle.TransactOpts, _queryId, _value, _nonce, _queryData)
+}
+
+// SubmitValue is a paid mutator transaction binding the contract method 0x5eaa9ced.
+//
+// Solidity: function submitValue(bytes32 _queryId, bytes _value, uint256 _nonce, bytes _queryData) returns()
+func (_Oracle *OracleTransactorSession) SubmitValue(_queryId [32]byte, _value []byte, _nonce *big.Int, _queryData []byte) (*types.Transaction, error) {
+	return _Oracle.Contract.SubmitValue(&_Oracle.TransactOpts, _queryId, _value, _nonce, _queryData)
+}
+
+// OracleNewReportIterator is returned from FilterNewReport and is used to iterate over the raw logs and unpacked data for NewReport events raised by the Oracle contract.
+type OracleNewReportIterator struct {
+	Event *OracleNewReport // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *OracleNewReportIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(OracleNewReport)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(OracleNewReport)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *OracleNewReportIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *OracleNewReportIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// OracleNewReport represents a NewReport event raised by the Oracle contract.
+type OracleNewReport struct {
+	QueryId   [32]byte
+	Time      *big.Int
+	Value     []byte
+	Reward    *big.Int
+	Nonce     *big.Int
+	QueryData []byte
+	Reporter  common.Address
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterNewReport is a free log retrieval operation binding the contract event 0xab1d593f8e2ecb165106e30a39db6769078d35c3fdbb110f24932f0d7af68c29.
+//
+// Solidity: event NewReport(bytes32 _queryId, uint256 _time, bytes _value, uint256 _reward, uint256 _nonce, bytes _queryData, address _reporter)
+func (_Oracle *OracleFilterer) FilterNewReport(opts *bind.FilterOpts) (*OracleNewReportIterator, error) {
+
+	logs, sub, err := _Oracle.contract.FilterLogs(opts, "NewReport")
+	if err != nil {
+		return nil, err
+	}
+	return &OracleNewReportIterator{contract: _Oracle.contract, event: "NewReport", logs: logs, sub: sub}, nil
+}
+
+// WatchNewReport is a free log subscription operation binding the contract event 0xab1d593f8e2ecb165106e30a39db6769078d35c3fdbb110f24932f0d7af68c29.
+//
+// Solidity: event NewReport(bytes32 _queryId, uint256 _time, bytes _value, uint256 _reward, uint256 _nonce, bytes _queryData, address _reporter)
+func (_Oracle *OracleFilterer) WatchNewReport(opts *bind.WatchOpts, sink chan<- *OracleNewReport) (event.Subscription, error) {
+
+	logs, sub, err := _Oracle.contract.WatchLogs(opts, "NewReport")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(OracleNewReport)
+				if err := _Oracle.contract.UnpackLog(event, "NewReport", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseNewReport is a log parse operation binding the contract event 0xab1d593f8e2ecb165106e30a39db6769078d35c3fdbb110f24932f0d7af68c29.
+//
+// Solidity: event NewReport(bytes32 _queryId, uint256 _time, bytes _value, uint256 _reward, uint256 _nonce, bytes _queryData, address _reporter)
+func (_Oracle *OracleFilterer) ParseNewReport(log types.Log) (*OracleNewReport, error) {
+	event := new(OracleNewReport)
+	if err := _Oracle.contract.UnpackLog(event, "NewReport", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -24,6 +24,10 @@ type Config struct {
 	RemoteHost    string
 	RemotePort    uint
 	RemoteTimeout format.Duration
+	Stateless     bool `help:"Keep no state on the disk so the miner can be rescheduled anywhere. Requires a remote host, the transaction and profit histories are kept only in memory and are lost on a restart."`
+	// Retry the samples of the failed commits.
+	RetryQueueSize uint            `help:"The max number of samples kept on the disk for a retry when a commit to the db fails. 0 drops the samples of a failed commit."`
+	RetryInterval  format.Duration `help:"How often to retry writing the queued samples."`
 }
 
 func NewRemoteDB(cfg Config) (storage.SampleAndChunkQueryable, error) {
@@ -0,0 +1,337 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/tellor-io/telliot/pkg/logging"
+)
+
+// RetryQueueFileName is the name of the queue file in the db directory.
+const RetryQueueFileName = "retry-queue.jsonl"
+
+// Sample is a queued sample.
+type Sample struct {
+	Labels map[string]string `json:"labels"`
+	T      int64             `json:"t"`
+	V      float64           `json:"v"`
+}
+
+// RetryAppendable queues the samples of the failed commits in a file and writes them again later
+// so that a short storage failure doesn't leave gaps in the data.
+// While there are queued samples the new ones are queued after them
+// because the db doesn't accept samples older than the last one of the same series.
+type RetryAppendable struct {
+	logger     log.Logger
+	ctx        context.Context
+	close      context.CancelFunc
+	cfg        Config
+	appendable storage.Appendable
+	path       string
+
+	mtx     sync.Mutex
+	batches [][]Sample
+	queued  int
+
+	queuedSamples prometheus.Gauge
+	dropped       prometheus.Counter
+}
+
+func NewRetryAppendable(logger log.Logger, ctx context.Context, cfg Config, appendable storage.Appendable) (*RetryAppendable, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	if err := os.MkdirAll(cfg.Path, 0750); err != nil {
+		return nil, errors.Wrapf(err, "creating the db directory:%v", cfg.Path)
+	}
+	ctx, close := context.WithCancel(ctx)
+	self := &RetryAppendable{
+		logger:     log.With(logger, "component", ComponentName),
+		ctx:        ctx,
+		close:      close,
+		cfg:        cfg,
+		appendable: appendable,
+		path:       filepath.Join(cfg.Path, RetryQueueFileName),
+		queuedSamples: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "retry_queue_samples",
+			Help:      "The number of samples waiting in the retry queue",
+		}),
+		dropped: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "retry_dropped_samples_total",
+			Help:      "The total number of samples dropped from the retry queue because it was full or the db rejected them",
+		}),
+	}
+	// The samples queued before a restart.
+	if err := self.load(); err != nil {
+		return nil, errors.Wrap(err, "loading the retry queue")
+	}
+	if self.queued > 0 {
+		level.Info(self.logger).Log("msg", "loaded the retry queue", "samples", self.queued)
+	}
+	return self, nil
+}
+
+func (self *RetryAppendable) Appender(ctx context.Context) storage.Appender {
+	return &retryAppender{ctx: ctx, parent: self}
+}
+
+func (self *RetryAppendable) Start() error {
+	ticker := time.NewTicker(self.cfg.RetryInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-self.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		self.mtx.Lock()
+		self.flush(self.ctx)
+		self.mtx.Unlock()
+	}
+}
+
+func (self *RetryAppendable) Stop() {
+	self.close()
+}
+
+func (self *RetryAppendable) commit(ctx context.Context, batch []Sample) error {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	// The failure of the queued samples was already returned
+	// so these are only logged while waiting behind them.
+	if len(self.batches) > 0 {
+		self.enqueue(batch)
+		self.flush(ctx)
+		if len(self.batches) > 0 {
+			level.Debug(self.logger).Log("msg", "queued the samples behind the previously failed ones", "queued", self.queued)
+		}
+		return nil
+	}
+	err := self.write(ctx, batch)
+	if err == nil || rejected(err) {
+		return err
+	}
+	self.enqueue(batch)
+	return errors.Wrap(err, "queued for a retry")
+}
+
+// write appends the samples to the db in a single commit.
+func (self *RetryAppendable) write(ctx context.Context, batch []Sample) error {
+	appender := self.appendable.Appender(ctx)
+	for _, s := range batch {
+		if _, err := appender.Append(0, labels.FromMap(s.Labels), s.T, s.V); err != nil {
+			if errR := appender.Rollback(); errR != nil {
+				level.Error(self.logger).Log("msg", "db rollback failed", "err", errR)
+			}
+			return err
+		}
+	}
+	return appender.Commit()
+}
+
+// flush writes the queued samples in order and stops at the first failure.
+func (self *RetryAppendable) flush(ctx context.Context) {
+	if len(self.batches) == 0 {
+		return
+	}
+	written := 0
+	for _, batch := range self.batches {
+		if err := self.write(ctx, batch); err != nil && !rejected(err) {
+			level.Debug(self.logger).Log("msg", "retrying the queued samples", "err", err)
+			break
+		} else if err != nil {
+			level.Warn(self.logger).Log("msg", "the db rejected the queued samples", "samples", len(batch), "err", err)
+			self.dropped.Add(float64(len(batch)))
+		}
+		written++
+	}
+	if written == 0 {
+		return
+	}
+	self.batches = self.batches[written:]
+	self.count()
+	if err := self.save(); err != nil {
+		level.Error(self.logger).Log("msg", "saving the retry queue", "err", err)
+	}
+	if len(self.batches) == 0 {
+		level.Info(self.logger).Log("msg", "the retry queue is empty, all samples are written")
+	}
+}
+
+// enqueue adds the samples at the end of the queue and drops the oldest ones when it is full.
+func (self *RetryAppendable) enqueue(batch []Sample) {
+	if len(batch) > int(self.cfg.RetryQueueSize) {
+		self.dropped.Add(float64(len(batch)))
+		return
+	}
+	self.batches = append(self.batches, batch)
+	self.queued += len(batch)
+
+	dropped := 0
+	for self.queued > int(self.cfg.RetryQueueSize) {
+		self.queued -= len(self.batches[0])
+		dropped += len(self.batches[0])
+		self.batches = self.batches[1:]
+	}
+	self.queuedSamples.Set(float64(self.queued))
+	if dropped > 0 {
+		self.dropped.Add(float64(dropped))
+		level.Warn(self.logger).Log("msg", "the retry queue is full, dropped the oldest samples", "samples", dropped)
+		if err := self.save(); err != nil {
+			level.Error(self.logger).Log("msg", "saving the retry queue", "err", err)
+		}
+		return
+	}
+
+	// The queue is on the same storage that just failed so it can fail as well,
+	// the samples are still retried from memory.
+	b, err := json.Marshal(batch)
+	if err != nil {
+		level.Error(self.logger).Log("msg", "marshal the queued samples", "err", err)
+		return
+	}
+	f, err := os.OpenFile(self.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		level.Error(self.logger).Log("msg", "open the retry queue", "err", err)
+		return
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		level.Error(self.logger).Log("msg", "write to the retry queue", "err", err)
+	}
+	if err := f.Close(); err != nil {
+		level.Error(self.logger).Log("msg", "close the retry queue", "err", err)
+	}
+}
+
+func (self *RetryAppendable) count() {
+	self.queued = 0
+	for _, batch := range self.batches {
+		self.queued += len(batch)
+	}
+	self.queuedSamples.Set(float64(self.queued))
+}
+
+func (self *RetryAppendable) load() error {
+	f, err := os.Open(self.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		var batch []Sample
+		if err := json.Unmarshal(scanner.Bytes(), &batch); err != nil {
+			// Skip a line partially written when the process crashed.
+			continue
+		}
+		self.batches = append(self.batches, batch)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	self.count()
+	return nil
+}
+
+// save replaces the queue file with the current queue.
+func (self *RetryAppendable) save() error {
+	if len(self.batches) == 0 {
+		if err := os.Remove(self.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(self.path), RetryQueueFileName+".tmp")
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, batch := range self.batches {
+		b, err := json.Marshal(batch)
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), self.path)
+}
+
+// rejected returns whether the db refused the samples themselves so a retry won't help.
+func rejected(err error) bool {
+	switch errors.Cause(err) {
+	case storage.ErrOutOfOrderSample, storage.ErrOutOfBounds, storage.ErrDuplicateSampleForTimestamp:
+		return true
+	}
+	return false
+}
+
+// retryAppender collects the samples until the commit like the db appender.
+type retryAppender struct {
+	ctx     context.Context
+	parent  *RetryAppendable
+	samples []Sample
+}
+
+func (self *retryAppender) Append(ref uint64, l labels.Labels, t int64, v float64) (uint64, error) {
+	self.samples = append(self.samples, Sample{Labels: l.Map(), T: t, V: v})
+	return 0, nil
+}
+
+func (self *retryAppender) AppendExemplar(ref uint64, l labels.Labels, e exemplar.Exemplar) (uint64, error) {
+	return 0, errors.New("exemplars are not supported")
+}
+
+func (self *retryAppender) Commit() error {
+	if len(self.samples) == 0 {
+		return nil
+	}
+	samples := self.samples
+	self.samples = nil
+	return self.parent.commit(self.ctx, samples)
+}
+
+func (self *retryAppender) Rollback() error {
+	self.samples = nil
+	return nil
+}
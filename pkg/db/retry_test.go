@@ -0,0 +1,125 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package db
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+// flakyAppendable fails the commits while fail is set.
+type flakyAppendable struct {
+	memAppendable
+	pending []sample
+	fail    error
+}
+
+func (self *flakyAppendable) Appender(context.Context) storage.Appender { return self }
+
+func (self *flakyAppendable) Append(ref uint64, l labels.Labels, t int64, v float64) (uint64, error) {
+	self.pending = append(self.pending, sample{l, t, v})
+	return 0, nil
+}
+
+func (self *flakyAppendable) Commit() error {
+	defer func() { self.pending = nil }()
+	if self.fail != nil {
+		return self.fail
+	}
+	self.samples = append(self.samples, self.pending...)
+	return nil
+}
+
+func (self *flakyAppendable) Rollback() error {
+	self.pending = nil
+	return nil
+}
+
+func newRetry(t *testing.T, dir string, size uint, appendable storage.Appendable) *RetryAppendable {
+	retry := &RetryAppendable{
+		logger:        log.NewNopLogger(),
+		cfg:           Config{RetryQueueSize: size},
+		appendable:    appendable,
+		path:          filepath.Join(dir, RetryQueueFileName),
+		queuedSamples: prometheus.NewGauge(prometheus.GaugeOpts{Name: "retry_queue_samples"}),
+		dropped:       prometheus.NewCounter(prometheus.CounterOpts{Name: "retry_dropped_samples_total"}),
+	}
+	testutil.Ok(t, retry.load())
+	return retry
+}
+
+func commit(retry *RetryAppendable, t int64) error {
+	appender := retry.Appender(context.Background())
+	if _, err := appender.Append(0, labels.FromStrings("__name__", "value"), t, float64(t)); err != nil {
+		return err
+	}
+	return appender.Commit()
+}
+
+func TestRetryAppendable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "retry")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	appendable := &flakyAppendable{fail: errors.New("disk full")}
+	retry := newRetry(t, dir, 10, appendable)
+
+	// The first failure is returned and the later samples are queued behind it.
+	testutil.NotOk(t, commit(retry, 1))
+	testutil.Ok(t, commit(retry, 2))
+	testutil.Equals(t, 2, retry.queued)
+	testutil.Equals(t, 0, len(appendable.samples))
+
+	// The queue survives a restart.
+	retry = newRetry(t, dir, 10, appendable)
+	testutil.Equals(t, 2, retry.queued)
+
+	// The queued samples are written in order before the new ones.
+	appendable.fail = nil
+	testutil.Ok(t, commit(retry, 3))
+	testutil.Equals(t, 0, retry.queued)
+	testutil.Equals(t, 3, len(appendable.samples))
+	for i, s := range appendable.samples {
+		testutil.Equals(t, int64(i+1), s.t)
+	}
+	_, err = os.Stat(filepath.Join(dir, RetryQueueFileName))
+	testutil.Assert(t, os.IsNotExist(err), "the queue file should be removed when the queue is empty")
+
+	// Samples rejected by the db aren't queued.
+	appendable.fail = storage.ErrOutOfOrderSample
+	testutil.NotOk(t, commit(retry, 4))
+	testutil.Equals(t, 0, retry.queued)
+}
+
+func TestRetryAppendableFull(t *testing.T) {
+	dir, err := ioutil.TempDir("", "retry")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	appendable := &flakyAppendable{fail: errors.New("disk full")}
+	retry := newRetry(t, dir, 2, appendable)
+	for i := int64(1); i <= 3; i++ {
+		_ = commit(retry, i)
+	}
+	testutil.Equals(t, 2, retry.queued)
+
+	// The oldest sample is dropped also from the file.
+	retry = newRetry(t, dir, 2, appendable)
+	testutil.Equals(t, int64(2), retry.batches[0][0].T)
+
+	appendable.fail = nil
+	retry.flush(context.Background())
+	testutil.Equals(t, 2, len(appendable.samples))
+	testutil.Equals(t, int64(3), appendable.samples[1].t)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package db
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	promConfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/storage/remote"
+)
+
+// NewRemoteAppendable returns an appendable that sends the samples of every commit
+// to the remote write endpoint of a data server.
+// It is used by the index tracker shards that write to a shared data server.
+func NewRemoteAppendable(cfg Config, token string) (storage.Appendable, error) {
+	url, err := url.Parse("http://" + cfg.RemoteHost + ":" + strconv.Itoa(int(cfg.RemotePort)) + "/api/v1/write")
+	if err != nil {
+		return nil, err
+	}
+	client, err := remote.NewWriteClient("", &remote.ClientConfig{
+		URL:     &promConfig.URL{URL: url},
+		Timeout: model.Duration(cfg.RemoteTimeout.Duration),
+		HTTPClientConfig: promConfig.HTTPClientConfig{
+			FollowRedirects: true,
+			BearerToken:     promConfig.Secret(token),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteAppendable{client: client}, nil
+}
+
+type remoteAppendable struct {
+	client remote.WriteClient
+}
+
+func (self *remoteAppendable) Appender(ctx context.Context) storage.Appender {
+	return &remoteAppender{ctx: ctx, client: self.client}
+}
+
+// remoteAppender collects the samples until the commit
+// so that a commit is a single write request.
+type remoteAppender struct {
+	ctx    context.Context
+	client remote.WriteClient
+	mtx    sync.Mutex
+	series []prompb.TimeSeries
+}
+
+func (self *remoteAppender) Append(ref uint64, l labels.Labels, t int64, v float64) (uint64, error) {
+	lbls := make([]prompb.Label, 0, len(l))
+	for _, lbl := range l {
+		lbls = append(lbls, prompb.Label{Name: lbl.Name, Value: lbl.Value})
+	}
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.series = append(self.series, prompb.TimeSeries{
+		Labels:  lbls,
+		Samples: []prompb.Sample{{Timestamp: t, Value: v}},
+	})
+	return 0, nil
+}
+
+func (self *remoteAppender) AppendExemplar(ref uint64, l labels.Labels, e exemplar.Exemplar) (uint64, error) {
+	return 0, errors.New("exemplars are not supported")
+}
+
+func (self *remoteAppender) Commit() error {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	if len(self.series) == 0 {
+		return nil
+	}
+	req := &prompb.WriteRequest{Timeseries: self.series}
+	self.series = nil
+	b, err := req.Marshal()
+	if err != nil {
+		return errors.Wrap(err, "marshal the write request")
+	}
+	if err := self.client.Store(self.ctx, snappy.Encode(nil, b)); err != nil {
+		return errors.Wrap(err, "remote write")
+	}
+	return nil
+}
+
+func (self *remoteAppender) Rollback() error {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.series = nil
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package db
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+type sample struct {
+	lbls labels.Labels
+	t    int64
+	v    float64
+}
+
+type memAppendable struct {
+	samples []sample
+}
+
+func (self *memAppendable) Appender(context.Context) storage.Appender { return self }
+
+func (self *memAppendable) Append(ref uint64, l labels.Labels, t int64, v float64) (uint64, error) {
+	self.samples = append(self.samples, sample{l, t, v})
+	return 0, nil
+}
+
+func (self *memAppendable) AppendExemplar(uint64, labels.Labels, exemplar.Exemplar) (uint64, error) {
+	return 0, nil
+}
+func (self *memAppendable) Commit() error   { return nil }
+func (self *memAppendable) Rollback() error { return nil }
+
+func TestRemoteAppendable(t *testing.T) {
+	received := &memAppendable{}
+	handler := remote.NewWriteHandler(log.NewNopLogger(), received)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/write" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	testutil.Ok(t, err)
+	port, err := strconv.Atoi(u.Port())
+	testutil.Ok(t, err)
+	cfg := Config{RemoteHost: u.Hostname(), RemotePort: uint(port), RemoteTimeout: format.Duration{Duration: time.Second}}
+
+	appendable, err := NewRemoteAppendable(cfg, "token")
+	testutil.Ok(t, err)
+
+	lbls := labels.FromStrings("__name__", "indexTracker_value", "symbol", "ETH_USD")
+	app := appendable.Appender(context.Background())
+	_, err = app.Append(0, lbls, 1000, 1.5)
+	testutil.Ok(t, err)
+	_, err = app.Append(0, lbls, 2000, 2.5)
+	testutil.Ok(t, err)
+	testutil.Equals(t, 0, len(received.samples), "nothing is sent before the commit")
+	testutil.Ok(t, app.Commit())
+	testutil.Equals(t, []sample{{lbls, 1000, 1.5}, {lbls, 2000, 2.5}}, received.samples)
+
+	// Rolled back samples are never sent.
+	app = appendable.Appender(context.Background())
+	_, err = app.Append(0, lbls, 3000, 3.5)
+	testutil.Ok(t, err)
+	testutil.Ok(t, app.Rollback())
+	testutil.Ok(t, app.Commit())
+	testutil.Equals(t, 2, len(received.samples))
+
+	// A wrong token is rejected by the data server.
+	appendable, err = NewRemoteAppendable(cfg, "wrong")
+	testutil.Ok(t, err)
+	app = appendable.Appender(context.Background())
+	_, err = app.Append(0, lbls, 3000, 3.5)
+	testutil.Ok(t, err)
+	testutil.NotOk(t, app.Commit())
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -76,50 +77,67 @@ func DecodeHex(s string) []byte {
 	return b
 }
 
+// PrepareEthTransaction returns the options for sending a transaction from the account
+// with a nonce reserved in the nonce manager shared by the process.
+// The caller must call release when sending the transaction fails
+// so that the nonce is reused right away instead of leaving a gap
+// that blocks the later transactions of the account.
 func PrepareEthTransaction(
 	ctx context.Context,
 	client *ethclient.Client,
 	account *Account,
 	gasPrice *big.Int,
-) (*bind.TransactOpts, error) {
+) (_ *bind.TransactOpts, release func(), errFinal error) {
+	if err := CheckNodeSync(ctx, client); err != nil {
+		return nil, nil, err
+	}
 
-	nonce, err := client.PendingNonceAt(ctx, account.GetAddress())
+	nonces := Nonces(client, account.GetAddress())
+	nonce, err := nonces.Next(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting pending nonce")
+		return nil, nil, errors.Wrap(err, "getting nonce")
 	}
+	// Releasing twice could free the nonce after it was assigned to another transaction.
+	var once sync.Once
+	release = func() { once.Do(func() { nonces.Release(nonce) }) }
+	defer func() {
+		if errFinal != nil {
+			release()
+		}
+	}()
 
 	if gasPrice == nil {
 		gasPrice, err = client.SuggestGasPrice(ctx)
 		if err != nil {
-			return nil, errors.Wrap(err, "getting gas price")
+			return nil, nil, errors.Wrap(err, "getting gas price")
 		}
 	}
 
 	ethBalance, err := client.BalanceAt(ctx, account.GetAddress(), nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting balance")
+		return nil, nil, errors.Wrap(err, "getting balance")
 	}
 
 	cost := new(big.Int)
 	cost.Mul(gasPrice, big.NewInt(700000))
 	if ethBalance.Cmp(cost) < 0 {
-		return nil, errors.Errorf("insufficient ethereum to send a transaction: %v < %v", ethBalance, cost)
+		return nil, nil, errors.Errorf("insufficient ethereum to send a transaction: %v < %v", ethBalance, cost)
 	}
 
 	netID, err := client.NetworkID(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting network id")
+		return nil, nil, errors.Wrap(err, "getting network id")
 	}
 
 	auth, err := bind.NewKeyedTransactorWithChainID(account.GetPrivateKey(), netID)
 	if err != nil {
-		return nil, errors.Wrap(err, "creating transactor")
+		return nil, nil, errors.Wrap(err, "creating transactor")
 	}
 	auth.Nonce = big.NewInt(int64(nonce))
 	auth.Value = big.NewInt(0)        // in wei
 	auth.GasLimit = uint64(3_000_000) // in units
 	auth.GasPrice = gasPrice
-	return auth, nil
+	return auth, release, nil
 }
 
 func Keccak256(input []byte) [32]byte {
@@ -164,8 +182,12 @@ func GetAccounts() ([]*Account, error) {
 	privateKeys := strings.Split(_privateKeys, ",")
 
 	// Create an Account instance per private keys.
-	accounts := make([]*Account, len(privateKeys))
-	for i, pkey := range privateKeys {
+	// No private keys is not an error so that the read only commands run without them.
+	accounts := make([]*Account, 0, len(privateKeys))
+	for _, pkey := range privateKeys {
+		if strings.TrimSpace(pkey) == "" {
+			continue
+		}
 		privateKey, err := crypto.HexToECDSA(strings.TrimSpace(pkey))
 		if err != nil {
 			return nil, errors.Wrap(err, "getting private key to ECDSA")
@@ -178,17 +200,36 @@ func GetAccounts() ([]*Account, error) {
 		}
 
 		publicAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-		accounts[i] = &Account{Address: publicAddress, PrivateKey: privateKey}
+		accounts = append(accounts, &Account{Address: publicAddress, PrivateKey: privateKey})
 	}
 	return accounts, nil
 }
 
+// NewClient connects to the node set with the NODE_URL env variable.
+// With a comma separated list of urls the client fails over between them.
 func NewClient(ctx context.Context, logger log.Logger) (*ethclient.Client, error) {
 	nodeURL := os.Getenv(NodeURLEnvName)
 
-	client, err := ethclient.DialContext(ctx, nodeURL)
-	if err != nil {
-		return nil, errors.Wrap(err, "create rpc client instance")
+	var urls []string
+	for _, u := range strings.Split(nodeURL, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+
+	var client *ethclient.Client
+	if len(urls) > 1 {
+		rpcClient, err := newFailoverClient(ctx, logger, urls)
+		if err != nil {
+			return nil, errors.Wrap(err, "create failover rpc client instance")
+		}
+		client = ethclient.NewClient(rpcClient)
+	} else {
+		var err error
+		client, err = ethclient.DialContext(ctx, nodeURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "create rpc client instance")
+		}
 	}
 
 	if !strings.Contains(strings.ToLower(nodeURL), "arbitrum") { // Arbitrum nodes doesn't support sync checking.
@@ -211,3 +252,57 @@ func NewClient(ctx context.Context, logger log.Logger) (*ethclient.Client, error
 
 	return client, nil
 }
+
+// NodeReady returns an error when the node can't be reached or is still syncing with the network.
+func NodeReady(ctx context.Context, client *ethclient.Client) error {
+	if _, err := client.BlockNumber(ctx); err != nil {
+		return errors.Wrap(err, "get block number")
+	}
+	if strings.Contains(strings.ToLower(os.Getenv(NodeURLEnvName)), "arbitrum") { // Arbitrum nodes doesn't support sync checking.
+		return nil
+	}
+	s, err := client.SyncProgress(ctx)
+	if err != nil {
+		return errors.Wrap(err, "determining if Ethereum client is syncing")
+	}
+	if s != nil {
+		return errors.Errorf("ethereum node is still syncing, current block:%v highest block:%v", s.CurrentBlock, s.HighestBlock)
+	}
+	return nil
+}
+
+// NodeStatus is the sync state of the ethereum node.
+type NodeStatus struct {
+	NetworkID    int64  `json:"networkID"`
+	BlockNumber  uint64 `json:"blockNumber"`
+	Syncing      bool   `json:"syncing"`
+	HighestBlock uint64 `json:"highestBlock,omitempty"`
+}
+
+// GetNodeStatus returns the current network, block and sync state of the node.
+func GetNodeStatus(ctx context.Context, client *ethclient.Client) (*NodeStatus, error) {
+	id, err := client.NetworkID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get network ID")
+	}
+	block, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get block number")
+	}
+	status := &NodeStatus{
+		NetworkID:   id.Int64(),
+		BlockNumber: block,
+	}
+	if strings.Contains(strings.ToLower(os.Getenv(NodeURLEnvName)), "arbitrum") { // Arbitrum nodes doesn't support sync checking.
+		return status, nil
+	}
+	progress, err := client.SyncProgress(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "determining if Ethereum client is syncing")
+	}
+	if progress != nil {
+		status.Syncing = true
+		status.HighestBlock = progress.HighestBlock
+	}
+	return status, nil
+}
@@ -0,0 +1,572 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// failoverCheckInterval is how often to check the health of the node endpoints.
+	failoverCheckInterval = 15 * time.Second
+	// failoverCallTimeout is the timeout of a single request to a node endpoint.
+	failoverCallTimeout = 30 * time.Second
+	// failoverMaxHeadLag is how many blocks an endpoint can be behind the others before it is considered unhealthy.
+	failoverMaxHeadLag = 5
+)
+
+var (
+	activeEndpoint = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "active_endpoint",
+		Help:      "1 for the node endpoint currently in use and 0 for the others",
+	},
+		[]string{"endpoint"},
+	)
+	endpointHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "endpoint_healthy",
+		Help:      "1 when the node endpoint passed the last health check",
+	},
+		[]string{"endpoint"},
+	)
+	failovers = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "failovers_total",
+		Help:      "The total number of switches between the node endpoints",
+	})
+)
+
+// endpoint is a single node URL.
+type endpoint struct {
+	url   string
+	name  string // Only the scheme and host so that the API keys in the URL don't end up in the logs and metrics.
+	local bool
+
+	mtx     sync.Mutex
+	client  *rpc.Client
+	healthy bool
+	head    uint64
+}
+
+func newEndpoint(rawurl string) (*endpoint, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing node url")
+	}
+	host := u.Hostname()
+	ip := net.ParseIP(host)
+	return &endpoint{
+		url:   rawurl,
+		name:  u.Scheme + "://" + u.Host,
+		local: host == "localhost" || (ip != nil && ip.IsLoopback()),
+	}, nil
+}
+
+func (self *endpoint) dial(ctx context.Context) (*rpc.Client, error) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	if self.client == nil {
+		client, err := rpc.DialContext(ctx, self.url)
+		if err != nil {
+			return nil, errors.Wrapf(err, "connecting to node endpoint:%v", self.name)
+		}
+		self.client = client
+	}
+	return self.client, nil
+}
+
+// reset closes the connection so that the next request connects again.
+func (self *endpoint) reset() {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	if self.client != nil {
+		self.client.Close()
+		self.client = nil
+	}
+}
+
+// failover is an in process JSON-RPC server that forwards all requests to the active node endpoint.
+// The ethclient connects to it like to any other node so the components don't know about the failover.
+// A request that fails because of a connection error or a timeout is sent to the next healthy endpoint
+// and the subscriptions are moved to another endpoint when their connection drops.
+type failover struct {
+	logger    log.Logger
+	ctx       context.Context
+	endpoints []*endpoint // In the order of preference.
+
+	mtx    sync.Mutex
+	active *endpoint
+
+	writeMtx sync.Mutex
+	out      *json.Encoder
+
+	subsMtx sync.Mutex
+	subs    map[string]*subscription
+}
+
+// subscription is an upstream subscription of the client.
+type subscription struct {
+	ctx  context.Context
+	cncl context.CancelFunc
+	ch   chan json.RawMessage
+	sub  *rpc.ClientSubscription
+	e    *endpoint
+	args []interface{}
+}
+
+type jsonrpcMessage struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+}
+
+type jsonrpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+type subscriptionResult struct {
+	ID     string          `json:"subscription"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// newFailoverClient returns a client that fails over between the node urls.
+// The local nodes are preferred when healthy and otherwise the urls are used in the given order.
+func newFailoverClient(ctx context.Context, logger log.Logger, urls []string) (*rpc.Client, error) {
+	self := &failover{
+		logger: log.With(logger, "component", ComponentName),
+		ctx:    ctx,
+		subs:   make(map[string]*subscription),
+	}
+	var remote []*endpoint
+	for _, u := range urls {
+		e, err := newEndpoint(u)
+		if err != nil {
+			return nil, err
+		}
+		if e.local {
+			self.endpoints = append(self.endpoints, e)
+			continue
+		}
+		remote = append(remote, e)
+	}
+	self.endpoints = append(self.endpoints, remote...)
+
+	self.check()
+	if self.active == nil {
+		return nil, errors.New("none of the node endpoints is healthy")
+	}
+
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+	self.out = json.NewEncoder(respW)
+	go self.serve(reqR)
+	go self.run()
+	go func() {
+		<-ctx.Done()
+		reqW.Close()
+		respW.Close()
+	}()
+
+	return rpc.DialIO(ctx, respR, reqW)
+}
+
+// run checks the endpoints periodically.
+func (self *failover) run() {
+	ticker := time.NewTicker(failoverCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-ticker.C:
+			self.check()
+		}
+	}
+}
+
+// check marks the endpoints healthy when these respond and are not behind the others
+// and selects the most preferred healthy endpoint as the active one.
+func (self *failover) check() {
+	var wg sync.WaitGroup
+	errs := make([]error, len(self.endpoints))
+	for i, e := range self.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			ctx, cncl := context.WithTimeout(self.ctx, 5*time.Second)
+			defer cncl()
+			client, err := e.dial(ctx)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			var head hexutil.Uint64
+			if err := client.CallContext(ctx, &head, "eth_blockNumber"); err != nil {
+				e.reset()
+				errs[i] = errors.Wrap(err, "getting block number")
+				return
+			}
+			e.mtx.Lock()
+			e.head = uint64(head)
+			e.mtx.Unlock()
+		}(i, e)
+	}
+	wg.Wait()
+
+	var maxHead uint64
+	for i, e := range self.endpoints {
+		if errs[i] == nil && e.head > maxHead {
+			maxHead = e.head
+		}
+	}
+
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	for i, e := range self.endpoints {
+		err := errs[i]
+		if err == nil && e.head+failoverMaxHeadLag < maxHead {
+			err = errors.Errorf("behind the other endpoints head:%v, max head:%v", e.head, maxHead)
+		}
+		if err != nil && e.healthy {
+			level.Warn(self.logger).Log("msg", "node endpoint unhealthy", "endpoint", e.name, "err", err)
+		}
+		if err == nil && !e.healthy {
+			level.Info(self.logger).Log("msg", "node endpoint healthy", "endpoint", e.name, "head", e.head)
+		}
+		e.healthy = err == nil
+		endpointHealthy.With(prometheus.Labels{"endpoint": e.name}).Set(boolToFloat(e.healthy))
+	}
+	self.selectActive()
+}
+
+// selectActive sets the most preferred healthy endpoint as active.
+// When none is healthy it keeps the current one.
+// It should be called with the mutex locked.
+func (self *failover) selectActive() {
+	for _, e := range self.endpoints {
+		if !e.healthy {
+			continue
+		}
+		if e == self.active {
+			return
+		}
+		if self.active != nil {
+			level.Warn(self.logger).Log("msg", "switching node endpoint", "from", self.active.name, "to", e.name)
+			activeEndpoint.With(prometheus.Labels{"endpoint": self.active.name}).Set(0)
+			failovers.Inc()
+		} else {
+			level.Info(self.logger).Log("msg", "using node endpoint", "endpoint", e.name)
+		}
+		self.active = e
+		activeEndpoint.With(prometheus.Labels{"endpoint": e.name}).Set(1)
+		return
+	}
+}
+
+func (self *failover) markUnhealthy(e *endpoint, err error) {
+	level.Warn(self.logger).Log("msg", "node endpoint request failed", "endpoint", e.name, "err", err)
+	e.reset()
+
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	e.healthy = false
+	endpointHealthy.With(prometheus.Labels{"endpoint": e.name}).Set(0)
+	if self.active == e {
+		self.selectActive()
+	}
+}
+
+// next returns the endpoint to try for a request:
+// the active one, then the other healthy ones and last the unhealthy ones.
+func (self *failover) next(tried map[*endpoint]bool) *endpoint {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	if self.active != nil && !tried[self.active] {
+		return self.active
+	}
+	for _, healthy := range []bool{true, false} {
+		for _, e := range self.endpoints {
+			if e.healthy == healthy && !tried[e] {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// do runs the request on the endpoints until one of them responds.
+// Errors returned by the node itself, like a reverted call, are not retried.
+func (self *failover) do(fn func(context.Context, *rpc.Client) error) (*endpoint, error) {
+	tried := make(map[*endpoint]bool)
+	var lastErr error
+	for e := self.next(tried); e != nil; e = self.next(tried) {
+		tried[e] = true
+		client, err := e.dial(self.ctx)
+		if err == nil {
+			ctx, cncl := context.WithTimeout(self.ctx, failoverCallTimeout)
+			err = fn(ctx, client)
+			cncl()
+			if _, ok := err.(rpc.Error); err == nil || ok {
+				return e, err
+			}
+		}
+		lastErr = err
+		// HTTP endpoints don't support subscriptions so just try the next one.
+		if err == rpc.ErrNotificationsUnsupported {
+			continue
+		}
+		if self.ctx.Err() != nil {
+			return nil, self.ctx.Err()
+		}
+		self.markUnhealthy(e, err)
+	}
+	return nil, lastErr
+}
+
+// serve reads the requests of the client.
+func (self *failover) serve(in io.Reader) {
+	dec := json.NewDecoder(in)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if self.ctx.Err() == nil {
+				level.Error(self.logger).Log("msg", "reading node request", "err", err)
+			}
+			return
+		}
+		go self.handle(raw)
+	}
+}
+
+func (self *failover) handle(raw json.RawMessage) {
+	if raw = bytes.TrimSpace(raw); len(raw) > 0 && raw[0] == '[' {
+		var msgs []*jsonrpcMessage
+		if err := json.Unmarshal(raw, &msgs); err != nil {
+			self.write(errorResponse(nil, -32700, err.Error()))
+			return
+		}
+		resps := make([]*jsonrpcMessage, len(msgs))
+		for i, msg := range msgs {
+			resps[i] = self.handleMsg(msg)
+		}
+		self.write(resps)
+		for i, msg := range msgs {
+			self.startSubscription(msg, resps[i])
+		}
+		return
+	}
+
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		self.write(errorResponse(nil, -32700, err.Error()))
+		return
+	}
+	resp := self.handleMsg(&msg)
+	self.write(resp)
+	self.startSubscription(&msg, resp)
+}
+
+func (self *failover) handleMsg(msg *jsonrpcMessage) *jsonrpcMessage {
+	var params []json.RawMessage
+	if len(msg.Params) > 0 {
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return errorResponse(msg.ID, -32602, "invalid params:"+err.Error())
+		}
+	}
+	args := make([]interface{}, len(params))
+	for i, p := range params {
+		args[i] = p
+	}
+
+	switch msg.Method {
+	case "eth_subscribe":
+		return self.subscribe(msg.ID, args)
+	case "eth_unsubscribe":
+		return self.unsubscribe(msg.ID, params)
+	}
+
+	var result json.RawMessage
+	_, err := self.do(func(ctx context.Context, client *rpc.Client) error {
+		return client.CallContext(ctx, &result, msg.Method, args...)
+	})
+	if err != nil {
+		return errorFromErr(msg.ID, err)
+	}
+	if len(result) == 0 {
+		result = json.RawMessage("null")
+	}
+	return &jsonrpcMessage{Version: "2.0", ID: msg.ID, Result: result}
+}
+
+func (self *failover) subscribe(id json.RawMessage, args []interface{}) *jsonrpcMessage {
+	ch := make(chan json.RawMessage)
+	e, sub, err := self.subscribeUpstream(ch, args)
+	if err != nil {
+		return errorFromErr(id, err)
+	}
+
+	ctx, cncl := context.WithCancel(self.ctx)
+	subID := newSubscriptionID()
+	self.subsMtx.Lock()
+	self.subs[subID] = &subscription{ctx: ctx, cncl: cncl, ch: ch, sub: sub, e: e, args: args}
+	self.subsMtx.Unlock()
+
+	return &jsonrpcMessage{Version: "2.0", ID: id, Result: marshal(subID)}
+}
+
+func (self *failover) subscribeUpstream(ch chan json.RawMessage, args []interface{}) (*endpoint, *rpc.ClientSubscription, error) {
+	var sub *rpc.ClientSubscription
+	e, err := self.do(func(ctx context.Context, client *rpc.Client) error {
+		var err error
+		sub, err = client.EthSubscribe(ctx, ch, args...)
+		return err
+	})
+	return e, sub, err
+}
+
+// startSubscription starts forwarding the notifications of a new subscription
+// after its id is sent to the client.
+func (self *failover) startSubscription(msg, resp *jsonrpcMessage) {
+	if msg.Method != "eth_subscribe" || resp.Error != nil {
+		return
+	}
+	var id string
+	if err := json.Unmarshal(resp.Result, &id); err != nil {
+		return
+	}
+	self.subsMtx.Lock()
+	s, ok := self.subs[id]
+	self.subsMtx.Unlock()
+	if ok {
+		go self.forward(id, s)
+	}
+}
+
+// forward sends the notifications of the upstream subscription to the client
+// and subscribes again on another endpoint when the connection drops.
+// The events emitted while resubscribing are lost so the components should backfill them.
+func (self *failover) forward(id string, s *subscription) {
+	sub, e := s.sub, s.e
+	for {
+		select {
+		case <-s.ctx.Done():
+			sub.Unsubscribe()
+			return
+		case n := <-s.ch:
+			self.write(&jsonrpcMessage{
+				Version: "2.0",
+				Method:  "eth_subscription",
+				Params:  marshal(subscriptionResult{ID: id, Result: n}),
+			})
+		case err := <-sub.Err():
+			if err == nil {
+				return
+			}
+			self.markUnhealthy(e, err)
+			for {
+				var errSub error
+				e, sub, errSub = self.subscribeUpstream(s.ch, s.args)
+				if errSub == nil {
+					break
+				}
+				level.Error(self.logger).Log("msg", "resubscribing", "err", errSub)
+				select {
+				case <-s.ctx.Done():
+					return
+				case <-time.After(failoverCheckInterval):
+				}
+			}
+			level.Info(self.logger).Log("msg", "resubscribed", "endpoint", e.name)
+		}
+	}
+}
+
+func (self *failover) unsubscribe(id json.RawMessage, params []json.RawMessage) *jsonrpcMessage {
+	if len(params) != 1 {
+		return errorResponse(id, -32602, "invalid params")
+	}
+	var subID string
+	if err := json.Unmarshal(params[0], &subID); err != nil {
+		return errorResponse(id, -32602, "invalid params:"+err.Error())
+	}
+
+	self.subsMtx.Lock()
+	s, ok := self.subs[subID]
+	delete(self.subs, subID)
+	self.subsMtx.Unlock()
+	if ok {
+		// The forwarding unsubscribes from the upstream.
+		s.cncl()
+	}
+	return &jsonrpcMessage{Version: "2.0", ID: id, Result: marshal(ok)}
+}
+
+func (self *failover) write(v interface{}) {
+	self.writeMtx.Lock()
+	defer self.writeMtx.Unlock()
+	if err := self.out.Encode(v); err != nil && self.ctx.Err() == nil {
+		level.Error(self.logger).Log("msg", "writing node response", "err", err)
+	}
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *jsonrpcMessage {
+	return &jsonrpcMessage{Version: "2.0", ID: id, Error: &jsonrpcError{Code: code, Message: msg}}
+}
+
+// errorFromErr keeps the code and data of the errors returned by the node
+// so that for example the revert reasons still reach the client.
+func errorFromErr(id json.RawMessage, err error) *jsonrpcMessage {
+	resp := errorResponse(id, -32000, err.Error())
+	if rpcErr, ok := err.(rpc.Error); ok {
+		resp.Error.Code = rpcErr.ErrorCode()
+	}
+	if dataErr, ok := err.(rpc.DataError); ok {
+		resp.Error.Data = dataErr.ErrorData()
+	}
+	return resp
+}
+
+func newSubscriptionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hexutil.Encode(b)
+}
+
+// marshal encodes values that can't fail encoding like strings and bools.
+func marshal(v interface{}) json.RawMessage {
+	b, _ := json.Marshal(v)
+	return b
+}
+
+func boolToFloat(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
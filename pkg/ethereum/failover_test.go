@@ -0,0 +1,148 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package ethereum
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+type failoverTestService struct {
+	head uint64
+}
+
+func (self *failoverTestService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(self.head)
+}
+
+func (self *failoverTestService) Fail() error {
+	return errors.New("execution reverted")
+}
+
+// Heads sends the head of the node every 10ms.
+func (self *failoverTestService) Heads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	go func() {
+		for {
+			select {
+			case <-sub.Err():
+				return
+			case <-time.After(10 * time.Millisecond):
+				_ = notifier.Notify(sub.ID, self.head)
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func newFailoverTestNode(t *testing.T, head uint64) *httptest.Server {
+	srv := rpc.NewServer()
+	testutil.Ok(t, srv.RegisterName("eth", &failoverTestService{head: head}))
+	return httptest.NewServer(srv)
+}
+
+func TestFailover(t *testing.T) {
+	ctx, cncl := context.WithCancel(context.Background())
+	defer cncl()
+
+	node1 := newFailoverTestNode(t, 100)
+	node2 := newFailoverTestNode(t, 101)
+	defer node2.Close()
+
+	rpcClient, err := newFailoverClient(ctx, logging.NewLogger(), []string{node1.URL, node2.URL})
+	testutil.Ok(t, err)
+	client := ethclient.NewClient(rpcClient)
+
+	// The first healthy node is preferred.
+	head, err := client.BlockNumber(ctx)
+	testutil.Ok(t, err)
+	testutil.Equals(t, uint64(100), head)
+
+	// Errors returned by the node are not retried on the other nodes.
+	err = rpcClient.CallContext(ctx, nil, "eth_fail")
+	testutil.NotOk(t, err)
+	testutil.Equals(t, "execution reverted", err.Error())
+	head, err = client.BlockNumber(ctx)
+	testutil.Ok(t, err)
+	testutil.Equals(t, uint64(100), head)
+
+	// Fails over when the node goes down.
+	node1.CloseClientConnections()
+	node1.Close()
+	head, err = client.BlockNumber(ctx)
+	testutil.Ok(t, err)
+	testutil.Equals(t, uint64(101), head)
+}
+
+func TestFailoverHeadLag(t *testing.T) {
+	ctx, cncl := context.WithCancel(context.Background())
+	defer cncl()
+
+	node1 := newFailoverTestNode(t, 100)
+	defer node1.Close()
+	node2 := newFailoverTestNode(t, 100+failoverMaxHeadLag+1)
+	defer node2.Close()
+
+	rpcClient, err := newFailoverClient(ctx, logging.NewLogger(), []string{node1.URL, node2.URL})
+	testutil.Ok(t, err)
+
+	// The first node is behind so it isn't used.
+	head, err := ethclient.NewClient(rpcClient).BlockNumber(ctx)
+	testutil.Ok(t, err)
+	testutil.Equals(t, uint64(100+failoverMaxHeadLag+1), head)
+}
+
+func TestFailoverSubscription(t *testing.T) {
+	ctx, cncl := context.WithCancel(context.Background())
+	defer cncl()
+
+	var (
+		srvs  []*rpc.Server
+		nodes []string
+	)
+	for _, head := range []uint64{1, 2} {
+		srv := rpc.NewServer()
+		testutil.Ok(t, srv.RegisterName("eth", &failoverTestService{head: head}))
+		node := httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+		defer node.Close()
+		srvs = append(srvs, srv)
+		nodes = append(nodes, "ws"+strings.TrimPrefix(node.URL, "http"))
+	}
+
+	rpcClient, err := newFailoverClient(ctx, logging.NewLogger(), nodes)
+	testutil.Ok(t, err)
+
+	heads := make(chan uint64)
+	sub, err := rpcClient.EthSubscribe(ctx, heads, "heads")
+	testutil.Ok(t, err)
+	defer sub.Unsubscribe()
+	testutil.Equals(t, uint64(1), <-heads)
+
+	// The subscription moves to the other node when the connection drops.
+	srvs[0].Stop()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case head := <-heads:
+			if head == 2 {
+				return
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription error:%v", err)
+		case <-timeout:
+			t.Fatal("no heads from the other node")
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// nonceDropTimeout is how long an assigned nonce can stay unknown to the node before it is reused.
+// Transactions sent through a private relay are not in the public mempool so the node doesn't see them for a while.
+const nonceDropTimeout = 5 * time.Minute
+
+var (
+	nonceManagersMtx sync.Mutex
+	nonceManagers    = make(map[common.Address]*NonceManager)
+)
+
+// NonceClient is the part of the ethereum client needed by the nonce manager.
+type NonceClient interface {
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// Nonces returns the nonce manager of the account shared by all components in the process.
+func Nonces(client NonceClient, addr common.Address) *NonceManager {
+	nonceManagersMtx.Lock()
+	defer nonceManagersMtx.Unlock()
+
+	if m, ok := nonceManagers[addr]; ok {
+		return m
+	}
+	m := NewNonceManager(client, addr)
+	nonceManagers[addr] = m
+	return m
+}
+
+// NonceManager serializes the nonce assignment for the transactions of an account
+// so that concurrent transactions don't end up with the same nonce.
+// It also considers the pending transactions on the node
+// so that it doesn't collide with transactions sent by another process like a cli command.
+type NonceManager struct {
+	client NonceClient
+	addr   common.Address
+
+	mtx      sync.Mutex
+	next     uint64
+	inFlight map[uint64]time.Time
+	released map[uint64]struct{}
+}
+
+func NewNonceManager(client NonceClient, addr common.Address) *NonceManager {
+	return &NonceManager{
+		client:   client,
+		addr:     addr,
+		inFlight: make(map[uint64]time.Time),
+		released: make(map[uint64]struct{}),
+	}
+}
+
+// Next returns the nonce for a new transaction.
+// It reuses the nonces that were released or dropped by the node, for example after a reorg,
+// as a transaction with a higher nonce can't be mined until these gaps are filled.
+func (self *NonceManager) Next(ctx context.Context) (uint64, error) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	confirmed, err := self.client.NonceAt(ctx, self.addr, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting the confirmed nonce")
+	}
+	pending, err := self.client.PendingNonceAt(ctx, self.addr)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting the pending nonce")
+	}
+
+	// Forget the mined transactions.
+	for n := range self.inFlight {
+		if n < confirmed {
+			delete(self.inFlight, n)
+		}
+	}
+	for n := range self.released {
+		if n < confirmed {
+			delete(self.released, n)
+		}
+	}
+
+	// Transactions sent by another process.
+	if self.next < pending {
+		self.next = pending
+	}
+	if self.next < confirmed {
+		self.next = confirmed
+	}
+
+	// Find the gaps that the node doesn't know about.
+	for n := confirmed; n < self.next; n++ {
+		if n < pending {
+			continue
+		}
+		if assigned, ok := self.inFlight[n]; ok && time.Since(assigned) < nonceDropTimeout {
+			continue
+		}
+		delete(self.inFlight, n)
+		self.released[n] = struct{}{}
+	}
+
+	if len(self.released) > 0 {
+		gaps := make([]uint64, 0, len(self.released))
+		for n := range self.released {
+			gaps = append(gaps, n)
+		}
+		sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+		n := gaps[0]
+		delete(self.released, n)
+		self.inFlight[n] = time.Now()
+		return n, nil
+	}
+
+	n := self.next
+	self.next++
+	self.inFlight[n] = time.Now()
+	return n, nil
+}
+
+// Release returns a nonce that wasn't used because sending the transaction failed
+// so that it is assigned to the next transaction.
+func (self *NonceManager) Release(nonce uint64) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	delete(self.inFlight, nonce)
+	if nonce < self.next {
+		self.released[nonce] = struct{}{}
+	}
+}
+
+// InFlight returns the number of assigned nonces which are not mined yet.
+func (self *NonceManager) InFlight() int {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return len(self.inFlight)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+type nonceClient struct {
+	confirmed uint64
+	pending   uint64
+}
+
+func (self *nonceClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return self.confirmed, nil
+}
+
+func (self *nonceClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return self.pending, nil
+}
+
+func TestNonceManager(t *testing.T) {
+	ctx := context.Background()
+	client := &nonceClient{confirmed: 5, pending: 5}
+	m := NewNonceManager(client, common.Address{})
+
+	next := func(exp uint64) {
+		t.Helper()
+		n, err := m.Next(ctx)
+		testutil.Ok(t, err)
+		testutil.Equals(t, exp, n)
+	}
+
+	// Concurrent transactions get consecutive nonces.
+	next(5)
+	next(6)
+	testutil.Equals(t, 2, m.InFlight())
+
+	// A released nonce is reused.
+	m.Release(5)
+	next(5)
+	next(7)
+
+	// Transactions mined and sent by another process.
+	client.confirmed, client.pending = 8, 10
+	next(10)
+	testutil.Equals(t, 1, m.InFlight())
+
+	// A reorg dropped the transactions so the gap is filled first.
+	client.confirmed, client.pending = 9, 9
+	m.Release(10)
+	next(9)
+	next(10)
+	next(11)
+}
+
+// txTestService is a node that accepts no transactions.
+type txTestService struct{}
+
+func (self *txTestService) BlockNumber() hexutil.Uint64 { return 1 }
+
+func (self *txTestService) Syncing() bool { return false }
+
+func (self *txTestService) GetBalance(addr common.Address, block string) *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1e18))
+}
+
+func (self *txTestService) GetTransactionCount(addr common.Address, block string) hexutil.Uint64 {
+	return 3
+}
+
+func (self *txTestService) SendRawTransaction(tx hexutil.Bytes) error {
+	return errors.New("execution reverted")
+}
+
+type netTestService struct{}
+
+func (self *netTestService) Version() string { return strconv.Itoa(hardhatNetworkID) }
+
+func TestPrepareEthTransactionRelease(t *testing.T) {
+	ctx := context.Background()
+	srv := rpc.NewServer()
+	testutil.Ok(t, srv.RegisterName("eth", &txTestService{}))
+	testutil.Ok(t, srv.RegisterName("net", &netTestService{}))
+	node := httptest.NewServer(srv)
+	defer node.Close()
+	client, err := ethclient.Dial(node.URL)
+	testutil.Ok(t, err)
+
+	key, err := crypto.GenerateKey()
+	testutil.Ok(t, err)
+	account := &Account{Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key}
+
+	auth, release, err := PrepareEthTransaction(ctx, client, account, big.NewInt(1))
+	testutil.Ok(t, err)
+	testutil.Equals(t, uint64(3), auth.Nonce.Uint64())
+
+	// The contract call fails so the nonce is released for the next transaction.
+	contract := bind.NewBoundContract(common.Address{}, abi.ABI{}, client, client, client)
+	_, err = contract.Transfer(auth)
+	testutil.NotOk(t, err)
+	release()
+	nonces := Nonces(client, account.Address)
+	n, err := nonces.Next(ctx)
+	testutil.Ok(t, err)
+	testutil.Equals(t, uint64(3), n)
+
+	// Releasing again doesn't free the nonce after it was assigned to another transaction.
+	release()
+	n, err = nonces.Next(ctx)
+	testutil.Ok(t, err)
+	testutil.Equals(t, uint64(4), n)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package ethereum
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DefaultMaxNodeLag is how old the latest block of the node can be before the transactions are refused.
+const DefaultMaxNodeLag = 2 * time.Minute
+
+// hardhatNetworkID is the local development network which mines blocks only when there are transactions.
+const hardhatNetworkID = 31337
+
+var (
+	maxNodeLag int64 = int64(DefaultMaxNodeLag)
+
+	syncRefusals = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "sync_refusals_total",
+		Help:      "The total number of transactions refused because the node was syncing or behind the network",
+	},
+		[]string{"reason"},
+	)
+)
+
+// SetMaxNodeLag sets the max age of the latest block for all transactions in the process.
+// 0 disables the check.
+func SetMaxNodeLag(lag time.Duration) {
+	atomic.StoreInt64(&maxNodeLag, int64(lag))
+}
+
+// CheckNodeSync returns an error when the node is syncing or its latest block is too old.
+// Values built on the state of a lagging node are likely to be disputed
+// so no transactions are sent until the node catches up.
+func CheckNodeSync(ctx context.Context, client *ethclient.Client) error {
+	if err := NodeReady(ctx, client); err != nil {
+		syncRefusals.With(prometheus.Labels{"reason": "syncing"}).Inc()
+		return errors.Wrap(err, "node not ready for sending transactions")
+	}
+
+	maxLag := time.Duration(atomic.LoadInt64(&maxNodeLag))
+	if maxLag == 0 {
+		return nil
+	}
+	netID, err := client.NetworkID(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting network id")
+	}
+	if netID.Int64() == hardhatNetworkID {
+		return nil
+	}
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "getting the latest block")
+	}
+	if lag := time.Since(time.Unix(int64(header.Time), 0)); lag > maxLag {
+		syncRefusals.With(prometheus.Labels{"reason": "behind"}).Inc()
+		return errors.Errorf("node is behind the network, the latest block:%v is %v old, max:%v", header.Number, lag.Round(time.Second), maxLag)
+	}
+	return nil
+}
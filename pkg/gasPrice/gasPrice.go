@@ -6,8 +6,63 @@ package gasPrice
 import (
 	"context"
 	"math/big"
+
+	"github.com/pkg/errors"
 )
 
 type GasPriceQuerier interface {
 	Query(ctx context.Context) (*big.Int, error)
 }
+
+// Limiter is implemented by queriers with a hard max gas price.
+// Transactions should never be sent with a higher price than the returned max.
+type Limiter interface {
+	// Max returns the max gas price or nil when there is no max.
+	Max() *big.Int
+}
+
+// ErrAboveMax is returned when the gas price is above the max
+// so that the transaction is aborted instead of overpaying.
+var ErrAboveMax = errors.New("gas price is above the max")
+
+// Strategy is how fast a transaction should be mined.
+type Strategy string
+
+const (
+	StrategySlow     Strategy = "slow"
+	StrategyStandard Strategy = "standard"
+	StrategyFast     Strategy = "fast"
+	StrategyFastest  Strategy = "fastest"
+	// StrategyPercentile uses a percentile of the gas prices paid in the latest blocks.
+	StrategyPercentile Strategy = "percentile"
+)
+
+// Strategies lists all supported strategies.
+var Strategies = []Strategy{StrategySlow, StrategyStandard, StrategyFast, StrategyFastest, StrategyPercentile}
+
+// Operation is the type of a transaction which can use its own strategy.
+type Operation string
+
+const (
+	OpSubmit   Operation = "submit"
+	OpStake    Operation = "stake"
+	OpWithdraw Operation = "withdraw"
+	OpTransfer Operation = "transfer"
+	OpDispute  Operation = "dispute"
+	OpVote     Operation = "vote"
+)
+
+// ParseStrategy validates the strategy name.
+func ParseStrategy(name string) (Strategy, error) {
+	for _, s := range Strategies {
+		if string(s) == name {
+			return s, nil
+		}
+	}
+	return "", errors.Errorf("unknown gas price strategy:%v, supported:%v", name, Strategies)
+}
+
+// OperationQuerier returns the querier with the strategy of each operation.
+type OperationQuerier interface {
+	For(Operation) GasPriceQuerier
+}
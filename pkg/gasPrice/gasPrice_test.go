@@ -0,0 +1,23 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package gasPrice
+
+import (
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestParseStrategy(t *testing.T) {
+	for _, s := range Strategies {
+		parsed, err := ParseStrategy(string(s))
+		testutil.Ok(t, err)
+		testutil.Equals(t, s, parsed)
+	}
+
+	_, err := ParseStrategy("")
+	testutil.NotOk(t, err)
+	_, err = ParseStrategy("Fast")
+	testutil.NotOk(t, err)
+}
@@ -5,8 +5,9 @@ package gasStation
 
 import (
 	"context"
-	"encoding/json"
 	"math/big"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -14,31 +15,118 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
-	"github.com/tellor-io/telliot/pkg/format"
-	"github.com/tellor-io/telliot/pkg/web"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
 )
 
 const ComponentName = "gasPriceGasStation"
 
 type Config struct {
-	TimeWait format.Duration
+	Strategy         string     `help:"Default gas price strategy - slow, standard, fast, fastest or percentile."`
+	Percentile       int        `help:"Percentile of the gas prices paid in the latest blocks used by the percentile strategy."`
+	PercentileBlocks int        `help:"Number of latest blocks used by the percentile strategy."`
+	Max              uint       `help:"Hard max gas price in gwei. Transactions abort instead of overpaying when the price of their strategy is above it. 0 disables the max."`
+	Operations       Operations `help:"Strategies for specific operations. Empty uses the default strategy."`
+	Providers        []string   `help:"Providers combined for the slow, standard, fast and fastest strategies - gasStation, etherscan, blocknative, node and blocks. The external providers are used only on mainnet and etherscan and blocknative need the ETHERSCAN_API_KEY and BLOCKNATIVE_API_KEY env variables."`
+	MaxDeviation     int        `help:"Percent a provider price can deviate from the median of all providers before it is ignored."`
+	MinPrice         uint       `help:"Provider prices below this in gwei are ignored as invalid."`
+}
+
+// Operations sets the strategies for specific operations.
+type Operations struct {
+	Submit   string `help:"Strategy for submitting values."`
+	Stake    string `help:"Strategy for depositing a stake."`
+	Withdraw string `help:"Strategy for requesting a stake withdraw and withdrawing it."`
+	Transfer string `help:"Strategy for transferring and approving TRB."`
+	Dispute  string `help:"Strategy for starting, tallying and unlocking disputes."`
+	Vote     string `help:"Strategy for voting on disputes and governance votes."`
+}
+
+func (self Operations) strategy(op gasPrice.Operation) string {
+	switch op {
+	case gasPrice.OpSubmit:
+		return self.Submit
+	case gasPrice.OpStake:
+		return self.Stake
+	case gasPrice.OpWithdraw:
+		return self.Withdraw
+	case gasPrice.OpTransfer:
+		return self.Transfer
+	case gasPrice.OpDispute:
+		return self.Dispute
+	case gasPrice.OpVote:
+		return self.Vote
+	}
+	return ""
 }
 
 type GasStation struct {
-	netID  int64
-	cfg    Config
-	client *ethclient.Client
-	logger log.Logger
+	netID      int64
+	cfg        Config
+	client     *ethclient.Client
+	logger     log.Logger
+	strategy   gasPrice.Strategy
+	operations map[gasPrice.Operation]gasPrice.Strategy
+	max        *big.Int
+	providers  []provider
+
+	providerPrice  *prometheus.GaugeVec
+	providerErrors *prometheus.CounterVec
+	outliers       *prometheus.CounterVec
 }
 
 // GasStation is what ETHGasStation returns from queries. Not all fields are filled in.
 type GasStationModel struct {
+	SafeLow float32 `json:"safeLow"`
+	Average float32 `json:"average"`
 	Fast    float32 `json:"fast"`
 	Fastest float32 `json:"fastest"`
-	Average float32 `json:"average"`
 }
 
 func New(logger log.Logger, cfg Config, client *ethclient.Client) (*GasStation, error) {
+	strategy, err := gasPrice.ParseStrategy(cfg.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	usesPercentile := strategy == gasPrice.StrategyPercentile
+	operations := make(map[gasPrice.Operation]gasPrice.Strategy)
+	for _, op := range []gasPrice.Operation{
+		gasPrice.OpSubmit,
+		gasPrice.OpStake,
+		gasPrice.OpWithdraw,
+		gasPrice.OpTransfer,
+		gasPrice.OpDispute,
+		gasPrice.OpVote,
+	} {
+		name := cfg.Operations.strategy(op)
+		if name == "" {
+			continue
+		}
+		operations[op], err = gasPrice.ParseStrategy(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "operation:%v", op)
+		}
+		usesPercentile = usesPercentile || operations[op] == gasPrice.StrategyPercentile
+	}
+	if usesPercentile {
+		if cfg.Percentile < 1 || cfg.Percentile > 100 {
+			return nil, errors.Errorf("percentile should be between 1 and 100:%v", cfg.Percentile)
+		}
+		if cfg.PercentileBlocks < 1 {
+			return nil, errors.Errorf("percentile blocks should be at least 1:%v", cfg.PercentileBlocks)
+		}
+	}
+
+	if cfg.MaxDeviation < 1 {
+		return nil, errors.Errorf("max deviation should be at least 1 percent:%v", cfg.MaxDeviation)
+	}
+
+	var max *big.Int
+	if cfg.Max > 0 {
+		max = new(big.Int).Mul(big.NewInt(int64(cfg.Max)), big.NewInt(params.GWei))
+	}
+
 	ctx, cncl := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cncl()
 	netID, err := client.NetworkID(ctx)
@@ -46,59 +134,248 @@ func New(logger log.Logger, cfg Config, client *ethclient.Client) (*GasStation,
 		return nil, errors.Wrap(err, "get network id")
 	}
 
-	return &GasStation{
-		netID:  netID.Int64(),
-		cfg:    cfg,
-		client: client,
-		logger: log.With(logger, "component", ComponentName),
-	}, nil
-
-}
+	self := &GasStation{
+		netID:      netID.Int64(),
+		cfg:        cfg,
+		client:     client,
+		logger:     log.With(logger, "component", ComponentName),
+		strategy:   strategy,
+		operations: operations,
+		max:        max,
+		providerPrice: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "provider_price_gwei",
+			Help:      "The last gas price returned by a provider",
+		},
+			[]string{"provider", "strategy"},
+		),
+		providerErrors: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "provider_errors_total",
+			Help:      "The total number of failed queries to a provider",
+		},
+			[]string{"provider"},
+		),
+		outliers: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "provider_outliers_total",
+			Help:      "The total number of provider prices ignored for being out of the sanity bounds",
+		},
+			[]string{"provider"},
+		),
+	}
 
-func (self *GasStation) Query(ctx context.Context) (gasPriceFinal *big.Int, errFinal error) {
-	if self.netID != 1 {
-		gasPrice, err := self.client.SuggestGasPrice(ctx)
+	for _, name := range cfg.Providers {
+		p, err := self.newProvider(name)
 		if err != nil {
-			return nil, errors.Wrap(err, "getting suggested gas price")
+			return nil, err
+		}
+		if p == nil {
+			level.Debug(self.logger).Log("msg", "gas price provider not available", "provider", name)
+			continue
 		}
-		return gasPrice, nil
+		self.providers = append(self.providers, p)
+	}
+	// The node is always there as the last resort.
+	if len(self.providers) == 0 {
+		self.providers = append(self.providers, &nodeProvider{station: self})
+	}
+
+	return self, nil
+}
+
+// Query returns the gas price for the default strategy.
+func (self *GasStation) Query(ctx context.Context) (*big.Int, error) {
+	return self.QueryStrategy(ctx, self.strategy)
+}
+
+// Max implements the gasPrice.Limiter interface.
+func (self *GasStation) Max() *big.Int {
+	return self.max
+}
+
+// For returns a querier that uses the strategy configured for the operation.
+func (self *GasStation) For(op gasPrice.Operation) gasPrice.GasPriceQuerier {
+	strategy, ok := self.operations[op]
+	if !ok {
+		strategy = self.strategy
+	}
+	return &querier{station: self, strategy: strategy}
+}
+
+// QueryStrategy returns the gas price for the strategy
+// or an error wrapping gasPrice.ErrAboveMax when it is above the max.
+func (self *GasStation) QueryStrategy(ctx context.Context, strategy gasPrice.Strategy) (*big.Int, error) {
+	var (
+		price *big.Int
+		err   error
+	)
+	if strategy == gasPrice.StrategyPercentile {
+		price, err = self.percentile(ctx, self.cfg.Percentile)
+	} else {
+		price, err = self.combined(ctx, strategy)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if self.max != nil && price.Cmp(self.max) > 0 {
+		return nil, errors.Wrapf(gasPrice.ErrAboveMax, "strategy:%v price:%v gwei, max:%v gwei",
+			strategy,
+			new(big.Int).Div(price, big.NewInt(params.GWei)),
+			self.cfg.Max,
+		)
 	}
+	return price, nil
+}
+
+// combined queries all providers at the same time and returns the median of their prices
+// ignoring the failed providers and the prices out of the sanity bounds.
+func (self *GasStation) combined(ctx context.Context, strategy gasPrice.Strategy) (*big.Int, error) {
+	ctx, cncl := context.WithTimeout(ctx, 15*time.Second)
+	defer cncl()
 
-	defer func() {
-		if errFinal != nil {
-			level.Error(self.logger).Log("msg", "fetching eth gas price falling back to client suggested price", "err", errFinal)
-			gasPrice, err := self.client.SuggestGasPrice(ctx)
+	prices := make([]providerPrice, len(self.providers))
+	var wg sync.WaitGroup
+	for i, p := range self.providers {
+		wg.Add(1)
+		go func(i int, p provider) {
+			defer wg.Done()
+			price, err := p.query(ctx, strategy)
 			if err != nil {
-				errFinal = errors.Wrapf(errFinal, "failed to get price from chain client:%v", err)
+				level.Warn(self.logger).Log("msg", "querying gas price provider", "provider", p.name(), "err", err)
+				self.providerErrors.With(prometheus.Labels{"provider": p.name()}).Inc()
 				return
 			}
-			gasPriceFinal = gasPrice
+			prices[i] = providerPrice{provider: p.name(), price: price}
+			gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(price), big.NewFloat(params.GWei)).Float64()
+			self.providerPrice.With(prometheus.Labels{"provider": p.name(), "strategy": string(strategy)}).Set(gwei)
+		}(i, p)
+	}
+	wg.Wait()
+
+	var valid []providerPrice
+	for _, p := range prices {
+		if p.price != nil {
+			valid = append(valid, p)
 		}
-	}()
+	}
+	minPrice := new(big.Int).Mul(big.NewInt(int64(self.cfg.MinPrice)), big.NewInt(params.GWei))
+	price, outliers := combine(valid, minPrice, self.cfg.MaxDeviation)
+	for _, o := range outliers {
+		level.Warn(self.logger).Log("msg", "ignoring gas price out of the sanity bounds", "provider", o.provider, "price", o.price, "strategy", strategy)
+		self.outliers.With(prometheus.Labels{"provider": o.provider}).Inc()
+	}
+	if price == nil {
+		return nil, errors.Errorf("no valid gas price from the providers for strategy:%v", strategy)
+	}
+	return price, nil
+}
 
-	ctx, cncl := context.WithTimeout(ctx, 15*time.Second)
-	defer cncl()
-	resp, err := web.Get(ctx, "https://ethgasstation.info/json/ethgasAPI.json", nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "fetch price from provider")
+type providerPrice struct {
+	provider string
+	price    *big.Int
+}
+
+// combine returns the median of the prices that are above the min price
+// and within the max deviation percent of the median of all prices.
+// The prices are in the providers order of preference
+// so when no price is within the deviation the first one above the min price is used.
+func combine(prices []providerPrice, minPrice *big.Int, maxDeviation int) (*big.Int, []providerPrice) {
+	var (
+		valid    []providerPrice
+		outliers []providerPrice
+	)
+	for _, p := range prices {
+		if p.price.Sign() <= 0 || p.price.Cmp(minPrice) < 0 {
+			outliers = append(outliers, p)
+			continue
+		}
+		valid = append(valid, p)
+	}
+	if len(valid) == 0 {
+		return nil, outliers
 	}
 
-	gpModel := GasStationModel{}
-	err = json.Unmarshal(resp, &gpModel)
+	mid := median(valid)
+	var inBounds, deviated []providerPrice
+	for _, p := range valid {
+		// |price - median| * 100 <= median * maxDeviation
+		diff := new(big.Int).Abs(new(big.Int).Sub(p.price, mid))
+		if diff.Mul(diff, big.NewInt(100)).Cmp(new(big.Int).Mul(mid, big.NewInt(int64(maxDeviation)))) > 0 {
+			deviated = append(deviated, p)
+			continue
+		}
+		inBounds = append(inBounds, p)
+	}
+	if len(inBounds) == 0 {
+		return valid[0].price, append(outliers, valid[1:]...)
+	}
+	return median(inBounds), append(outliers, deviated...)
+}
+
+func median(prices []providerPrice) *big.Int {
+	sorted := make([]*big.Int, len(prices))
+	for i, p := range prices {
+		sorted[i] = p.price
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	if len(sorted)%2 == 1 {
+		return sorted[len(sorted)/2]
+	}
+	sum := new(big.Int).Add(sorted[len(sorted)/2-1], sorted[len(sorted)/2])
+	return sum.Div(sum, big.NewInt(2))
+}
+
+// percentile returns the percentile of the gas prices paid in the latest blocks.
+func (self *GasStation) percentile(ctx context.Context, percentile int) (*big.Int, error) {
+	head, err := self.client.BlockByNumber(ctx, nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "provider response json unmarshal")
+		return nil, errors.Wrap(err, "getting the latest block")
+	}
+
+	var prices []*big.Int
+	block := head
+	for i := 0; i < self.cfg.PercentileBlocks; i++ {
+		for _, tx := range block.Transactions() {
+			prices = append(prices, tx.GasPrice())
+		}
+		if block.NumberU64() == 0 || i == self.cfg.PercentileBlocks-1 {
+			break
+		}
+		parent := block.ParentHash()
+		block, err = self.client.BlockByHash(ctx, parent)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting block:%v", parent.Hex())
+		}
 	}
 
-	var gasPrice float32
-	switch t := self.cfg.TimeWait.Duration; {
-	case t < 5*time.Minute:
-		gasPrice = gpModel.Average
-	case t < 2*time.Minute:
-		gasPrice = gpModel.Fast
-	case t < 30*time.Second:
-		gasPrice = gpModel.Fastest
+	if len(prices) == 0 {
+		price, err := self.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "no transactions in the latest blocks, getting suggested gas price")
+		}
+		return price, nil
 	}
 
-	gasPriceB := big.NewInt(int64(gasPrice / 10))
-	return big.NewInt(0).Mul(gasPriceB, big.NewInt(params.GWei)), nil
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+	idx := (len(prices) - 1) * percentile / 100
+	return prices[idx], nil
+}
+
+// querier returns the gas price for a given strategy.
+type querier struct {
+	station  *GasStation
+	strategy gasPrice.Strategy
+}
+
+func (self *querier) Query(ctx context.Context) (*big.Int, error) {
+	return self.station.QueryStrategy(ctx, self.strategy)
+}
+
+func (self *querier) Max() *big.Int {
+	return self.station.Max()
 }
@@ -0,0 +1,79 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package gasStation
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestCombine(t *testing.T) {
+	gwei := func(v int64) *big.Int { return new(big.Int).Mul(big.NewInt(v), big.NewInt(1e9)) }
+
+	for _, tc := range []struct {
+		name     string
+		prices   []providerPrice
+		min      *big.Int
+		exp      *big.Int
+		outliers int
+	}{
+		{
+			name:   "no prices",
+			prices: nil,
+			min:    gwei(0),
+			exp:    nil,
+		},
+		{
+			name: "median of all",
+			prices: []providerPrice{
+				{provider: "a", price: gwei(30)},
+				{provider: "b", price: gwei(32)},
+				{provider: "c", price: gwei(36)},
+			},
+			min: gwei(0),
+			exp: gwei(32),
+		},
+		{
+			name: "ignores the outlier",
+			prices: []providerPrice{
+				{provider: "a", price: gwei(30)},
+				{provider: "b", price: gwei(34)},
+				{provider: "c", price: gwei(36)},
+				{provider: "d", price: gwei(1000)},
+			},
+			min:      gwei(0),
+			exp:      gwei(34),
+			outliers: 1,
+		},
+		{
+			name: "ignores the prices below the min",
+			prices: []providerPrice{
+				{provider: "a", price: gwei(0)},
+				{provider: "b", price: gwei(1)},
+				{provider: "c", price: gwei(40)},
+			},
+			min:      gwei(2),
+			exp:      gwei(40),
+			outliers: 2,
+		},
+		{
+			name: "uses the first provider when all disagree",
+			prices: []providerPrice{
+				{provider: "a", price: gwei(10)},
+				{provider: "b", price: gwei(100)},
+			},
+			min:      gwei(0),
+			exp:      gwei(10),
+			outliers: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			price, outliers := combine(tc.prices, tc.min, 50)
+			testutil.Equals(t, tc.exp, price)
+			testutil.Equals(t, tc.outliers, len(outliers))
+		})
+	}
+}
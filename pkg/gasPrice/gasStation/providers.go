@@ -0,0 +1,231 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package gasStation
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
+	"github.com/tellor-io/telliot/pkg/web"
+)
+
+// Names of the gas price providers.
+const (
+	ProviderGasStation  = "gasStation"
+	ProviderEtherscan   = "etherscan"
+	ProviderBlocknative = "blocknative"
+	ProviderNode        = "node"
+	ProviderBlocks      = "blocks"
+)
+
+const (
+	EtherscanAPIKeyEnvName   = "ETHERSCAN_API_KEY"
+	BlocknativeAPIKeyEnvName = "BLOCKNATIVE_API_KEY"
+)
+
+// blocksPercentiles are the percentiles of the gas prices paid in the latest blocks
+// used by the blocks provider for each strategy.
+var blocksPercentiles = map[gasPrice.Strategy]int{
+	gasPrice.StrategySlow:     25,
+	gasPrice.StrategyStandard: 50,
+	gasPrice.StrategyFast:     75,
+	gasPrice.StrategyFastest:  90,
+}
+
+// blocknativeConfidences are the probabilities of inclusion in the next block
+// used from the blocknative estimates for each strategy.
+var blocknativeConfidences = map[gasPrice.Strategy]int{
+	gasPrice.StrategySlow:     70,
+	gasPrice.StrategyStandard: 80,
+	gasPrice.StrategyFast:     95,
+	gasPrice.StrategyFastest:  99,
+}
+
+type provider interface {
+	name() string
+	query(ctx context.Context, strategy gasPrice.Strategy) (*big.Int, error)
+}
+
+// newProvider returns the provider with the given name
+// or nil when it is not available because of a missing API key or on a test network.
+func (self *GasStation) newProvider(name string) (provider, error) {
+	external := func() bool { return self.netID == 1 }
+	switch name {
+	case ProviderGasStation:
+		if !external() {
+			return nil, nil
+		}
+		return &gasStationProvider{}, nil
+	case ProviderEtherscan:
+		key := os.Getenv(EtherscanAPIKeyEnvName)
+		if key == "" || !external() {
+			return nil, nil
+		}
+		return &etherscanProvider{apiKey: key}, nil
+	case ProviderBlocknative:
+		key := os.Getenv(BlocknativeAPIKeyEnvName)
+		if key == "" || !external() {
+			return nil, nil
+		}
+		return &blocknativeProvider{apiKey: key}, nil
+	case ProviderNode:
+		return &nodeProvider{station: self}, nil
+	case ProviderBlocks:
+		return &blocksProvider{station: self}, nil
+	}
+	return nil, errors.Errorf("unknown gas price provider:%v", name)
+}
+
+func gweiToWei(gwei float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(params.GWei)).Int(nil)
+	return wei
+}
+
+// gasStationProvider uses the ETH Gas Station API.
+type gasStationProvider struct{}
+
+func (self *gasStationProvider) name() string { return ProviderGasStation }
+
+func (self *gasStationProvider) query(ctx context.Context, strategy gasPrice.Strategy) (*big.Int, error) {
+	resp, err := web.Get(ctx, "https://ethgasstation.info/json/ethgasAPI.json", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch price from provider")
+	}
+
+	gpModel := GasStationModel{}
+	if err := json.Unmarshal(resp, &gpModel); err != nil {
+		return nil, errors.Wrap(err, "provider response json unmarshal")
+	}
+
+	var price float32
+	switch strategy {
+	case gasPrice.StrategySlow:
+		price = gpModel.SafeLow
+	case gasPrice.StrategyFast:
+		price = gpModel.Fast
+	case gasPrice.StrategyFastest:
+		price = gpModel.Fastest
+	default:
+		price = gpModel.Average
+	}
+	// The provider returns the prices in tenths of a gwei.
+	return gweiToWei(float64(price) / 10), nil
+}
+
+// etherscanProvider uses the Etherscan gas tracker API.
+type etherscanProvider struct {
+	apiKey string
+}
+
+type etherscanModel struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Result  struct {
+		SafeGasPrice    string `json:"SafeGasPrice"`
+		ProposeGasPrice string `json:"ProposeGasPrice"`
+		FastGasPrice    string `json:"FastGasPrice"`
+	} `json:"result"`
+}
+
+func (self *etherscanProvider) name() string { return ProviderEtherscan }
+
+func (self *etherscanProvider) query(ctx context.Context, strategy gasPrice.Strategy) (*big.Int, error) {
+	resp, err := web.Get(ctx, "https://api.etherscan.io/api?module=gastracker&action=gasoracle&apikey="+self.apiKey, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch price from provider")
+	}
+
+	model := etherscanModel{}
+	if err := json.Unmarshal(resp, &model); err != nil {
+		return nil, errors.Wrap(err, "provider response json unmarshal")
+	}
+	if model.Status != "1" {
+		return nil, errors.Errorf("provider returned an error:%v", model.Message)
+	}
+
+	// The provider has no separate fastest price.
+	price := model.Result.ProposeGasPrice
+	switch strategy {
+	case gasPrice.StrategySlow:
+		price = model.Result.SafeGasPrice
+	case gasPrice.StrategyFast, gasPrice.StrategyFastest:
+		price = model.Result.FastGasPrice
+	}
+	gwei, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing price:%v", price)
+	}
+	return gweiToWei(gwei), nil
+}
+
+// blocknativeProvider uses the Blocknative gas estimator API.
+type blocknativeProvider struct {
+	apiKey string
+}
+
+type blocknativeModel struct {
+	BlockPrices []struct {
+		EstimatedPrices []struct {
+			Confidence int     `json:"confidence"`
+			Price      float64 `json:"price"`
+		} `json:"estimatedPrices"`
+	} `json:"blockPrices"`
+}
+
+func (self *blocknativeProvider) name() string { return ProviderBlocknative }
+
+func (self *blocknativeProvider) query(ctx context.Context, strategy gasPrice.Strategy) (*big.Int, error) {
+	resp, err := web.Get(ctx, "https://api.blocknative.com/gasprices/blockprices", map[string]string{"Authorization": self.apiKey})
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch price from provider")
+	}
+
+	model := blocknativeModel{}
+	if err := json.Unmarshal(resp, &model); err != nil {
+		return nil, errors.Wrap(err, "provider response json unmarshal")
+	}
+	if len(model.BlockPrices) == 0 {
+		return nil, errors.New("provider returned no estimates")
+	}
+
+	confidence := blocknativeConfidences[strategy]
+	for _, estimate := range model.BlockPrices[0].EstimatedPrices {
+		if estimate.Confidence == confidence {
+			return gweiToWei(estimate.Price), nil
+		}
+	}
+	return nil, errors.Errorf("provider returned no estimate with confidence:%v", confidence)
+}
+
+// nodeProvider uses the price suggested by the ethereum node.
+type nodeProvider struct {
+	station *GasStation
+}
+
+func (self *nodeProvider) name() string { return ProviderNode }
+
+func (self *nodeProvider) query(ctx context.Context, strategy gasPrice.Strategy) (*big.Int, error) {
+	price, err := self.station.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting suggested gas price")
+	}
+	return price, nil
+}
+
+// blocksProvider uses a percentile of the gas prices paid in the latest blocks.
+type blocksProvider struct {
+	station *GasStation
+}
+
+func (self *blocksProvider) name() string { return ProviderBlocks }
+
+func (self *blocksProvider) query(ctx context.Context, strategy gasPrice.Strategy) (*big.Int, error) {
+	return self.station.percentile(ctx, blocksPercentiles[strategy])
+}
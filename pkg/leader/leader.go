@@ -0,0 +1,236 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+// Package leader elects one active instance of a hot standby pair
+// that shares the same accounts so that only one of them sends transactions.
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/logging"
+)
+
+const ComponentName = "leader"
+
+type Config struct {
+	LogLevel      string
+	Enabled       bool            `help:"Run as one instance of a hot standby pair that shares the accounts. Only the elected leader submits, stakes and votes and the standby takes over when the leader stops renewing its lease."`
+	LeaseFile     string          `help:"File with the lease of the leader on a storage shared by both instances like NFS. The clocks of the hosts should be synced."`
+	LeaseDuration format.Duration `help:"How long the lease is valid after a renew. The standby takes over within this time after the leader fails."`
+	RenewInterval format.Duration `help:"How often the leader renews the lease and the standby checks whether it expired. Should be a fraction of the lease duration."`
+	ID            string          `help:"Name of this instance in the lease and the logs. Empty uses the hostname."`
+}
+
+// Lease is the content of the lease file.
+type Lease struct {
+	ID      string
+	Expires time.Time
+}
+
+// Elector holds the lease while it is the leader and takes it over when it expires.
+// A nil Elector is always the leader so that the components don't need to check
+// whether the election is enabled.
+type Elector struct {
+	logger      log.Logger
+	ctx         context.Context
+	close       context.CancelFunc
+	cfg         Config
+	id          string
+	leader      int32
+	isLeader    prometheus.Gauge
+	transitions prometheus.Counter
+}
+
+func New(logger log.Logger, ctx context.Context, cfg Config) (*Elector, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	logger = log.With(logger, "component", ComponentName)
+
+	if cfg.LeaseFile == "" {
+		return nil, errors.New("the lease file is required")
+	}
+	if cfg.RenewInterval.Duration <= 0 || cfg.RenewInterval.Duration >= cfg.LeaseDuration.Duration {
+		return nil, errors.Errorf("renew interval:%v should be more than 0 and less than the lease duration:%v", cfg.RenewInterval, cfg.LeaseDuration)
+	}
+	id := cfg.ID
+	if id == "" {
+		id, err = os.Hostname()
+		if err != nil {
+			return nil, errors.Wrap(err, "getting the hostname")
+		}
+	}
+
+	ctx, close := context.WithCancel(ctx)
+	return &Elector{
+		logger: logger,
+		ctx:    ctx,
+		close:  close,
+		cfg:    cfg,
+		id:     id,
+		isLeader: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace:   "telliot",
+			Subsystem:   ComponentName,
+			Name:        "is_leader",
+			Help:        "Whether this instance is the leader, 1 for the leader and 0 for the standby",
+			ConstLabels: prometheus.Labels{"id": id},
+		}),
+		transitions: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace:   "telliot",
+			Subsystem:   ComponentName,
+			Name:        "transitions_total",
+			Help:        "The total number of times this instance became the leader or the standby",
+			ConstLabels: prometheus.Labels{"id": id},
+		}),
+	}, nil
+}
+
+// IsLeader returns whether this instance should send transactions.
+func (self *Elector) IsLeader() bool {
+	if self == nil {
+		return true
+	}
+	return atomic.LoadInt32(&self.leader) == 1
+}
+
+func (self *Elector) Start() error {
+	level.Info(self.logger).Log("msg", "starting as standby", "id", self.id, "leaseFile", self.cfg.LeaseFile)
+	ticker := time.NewTicker(self.cfg.RenewInterval.Duration)
+	defer ticker.Stop()
+	for {
+		self.elect()
+		select {
+		case <-self.ctx.Done():
+			self.release()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *Elector) Stop() {
+	self.close()
+}
+
+// elect renews the lease when this instance holds it or takes it over when it expired.
+func (self *Elector) elect() {
+	lease, err := readLease(self.cfg.LeaseFile)
+	if err != nil {
+		// Without a valid lease the other instance can't be trusted to be the leader
+		// so step down and try again at the next interval.
+		level.Error(self.logger).Log("msg", "reading the lease", "err", err)
+		self.setLeader(false, "")
+		return
+	}
+	if lease != nil && lease.ID != self.id && time.Now().Before(lease.Expires) {
+		self.setLeader(false, lease.ID)
+		return
+	}
+	takeover := lease == nil || lease.ID != self.id
+
+	if err := writeLease(self.cfg.LeaseFile, Lease{ID: self.id, Expires: time.Now().Add(self.cfg.LeaseDuration.Duration)}); err != nil {
+		level.Error(self.logger).Log("msg", "writing the lease", "err", err)
+		self.setLeader(false, "")
+		return
+	}
+	// Both instances can see the same expired lease so
+	// wait for a concurrent write and the one whose write remains is the leader.
+	if takeover {
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-time.After(self.cfg.RenewInterval.Duration / 2):
+		}
+	}
+	lease, err = readLease(self.cfg.LeaseFile)
+	if err != nil || lease == nil || lease.ID != self.id {
+		self.setLeader(false, "")
+		return
+	}
+	self.setLeader(true, self.id)
+}
+
+func (self *Elector) setLeader(leader bool, holder string) {
+	var v int32
+	if leader {
+		v = 1
+	}
+	if atomic.SwapInt32(&self.leader, v) == v {
+		return
+	}
+	self.isLeader.Set(float64(v))
+	self.transitions.Inc()
+	if leader {
+		level.Info(self.logger).Log("msg", "became the leader", "id", self.id)
+		return
+	}
+	level.Warn(self.logger).Log("msg", "became the standby", "id", self.id, "leader", holder)
+}
+
+// release removes the lease of this instance so that the standby takes over without waiting for it to expire.
+func (self *Elector) release() {
+	if !self.IsLeader() {
+		return
+	}
+	self.setLeader(false, "")
+	lease, err := readLease(self.cfg.LeaseFile)
+	if err != nil || lease == nil || lease.ID != self.id {
+		return
+	}
+	if err := os.Remove(self.cfg.LeaseFile); err != nil {
+		level.Error(self.logger).Log("msg", "releasing the lease", "err", err)
+		return
+	}
+	level.Info(self.logger).Log("msg", "released the lease")
+}
+
+func readLease(path string) (*Lease, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lease := &Lease{}
+	if err := json.Unmarshal(b, lease); err != nil {
+		return nil, errors.Wrapf(err, "parsing the lease file:%v", path)
+	}
+	return lease, nil
+}
+
+// writeLease replaces the lease file atomically so that the other instance never reads a partial lease.
+func writeLease(path string, lease Lease) error {
+	b, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
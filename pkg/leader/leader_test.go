@@ -0,0 +1,52 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package leader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestElect(t *testing.T) {
+	cfg := Config{
+		LogLevel:      "info",
+		LeaseFile:     filepath.Join(t.TempDir(), "leader.lease"),
+		LeaseDuration: format.Duration{Duration: 200 * time.Millisecond},
+		RenewInterval: format.Duration{Duration: 20 * time.Millisecond},
+	}
+	newElector := func(id string) *Elector {
+		cfg.ID = id
+		e, err := New(log.NewNopLogger(), context.Background(), cfg)
+		testutil.Ok(t, err)
+		return e
+	}
+	a, b := newElector("a"), newElector("b")
+
+	a.elect()
+	b.elect()
+	testutil.Assert(t, a.IsLeader(), "the first instance should take the free lease")
+	testutil.Assert(t, !b.IsLeader(), "the second instance should be the standby")
+
+	// The leader stops renewing so the standby takes over after the lease expires.
+	time.Sleep(cfg.LeaseDuration.Duration)
+	b.elect()
+	testutil.Assert(t, b.IsLeader(), "the standby should take over the expired lease")
+	a.elect()
+	testutil.Assert(t, !a.IsLeader(), "the old leader should step down")
+
+	// A released lease is taken over without waiting for it to expire.
+	b.release()
+	testutil.Assert(t, !b.IsLeader(), "the released instance shouldn't be the leader")
+	a.elect()
+	testutil.Assert(t, a.IsLeader(), "the standby should take over the released lease")
+
+	var disabled *Elector
+	testutil.Assert(t, disabled.IsLeader(), "without election the instance is always the leader")
+}
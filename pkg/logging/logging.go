@@ -4,7 +4,10 @@
 package logging
 
 import (
+	"io"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/kit/log"
@@ -12,27 +15,207 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	FormatLogfmt = "logfmt"
+	FormatJSON   = "json"
+)
+
+type Config struct {
+	Format string `help:"The log output format - logfmt or json. The json format prints one object per line."`
+}
+
+var output = newFormatLogger(log.NewSyncWriter(os.Stderr))
+
 // NewLogger create a new logger.
+// All loggers share the output format which can be changed with SetFormat.
 func NewLogger() log.Logger {
-	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
-	return log.With(logger, "ts", log.TimestampFormat(func() time.Time { return time.Now().UTC() }, "jan 02 15:04:05.00"), "caller", log.Caller(5))
+	return log.With(output, "ts", log.TimestampFormat(func() time.Time { return time.Now().UTC() }, "jan 02 15:04:05.00"), "caller", log.Caller(5))
+}
+
+// SetFormat changes the output format of all loggers created with NewLogger.
+// The loggers are created before the config is loaded so
+// the format is switched in place instead of when creating a logger.
+func SetFormat(format string) error {
+	return output.setFormat(format)
 }
 
+type formatLogger struct {
+	mtx    sync.RWMutex
+	logfmt log.Logger
+	json   log.Logger
+	format string
+}
+
+func newFormatLogger(w io.Writer) *formatLogger {
+	return &formatLogger{
+		logfmt: log.NewLogfmtLogger(w),
+		json:   log.NewJSONLogger(w),
+		format: FormatLogfmt,
+	}
+}
+
+// ValidFormat returns whether format is one of the supported log formats.
+// An empty format is the default logfmt.
+func ValidFormat(format string) bool {
+	switch format {
+	case "", FormatLogfmt, FormatJSON:
+		return true
+	}
+	return false
+}
+
+func (self *formatLogger) setFormat(format string) error {
+	if !ValidFormat(format) {
+		return errors.Errorf("unexpected log format:%v", format)
+	}
+	if format == "" {
+		format = FormatLogfmt
+	}
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.format = format
+	return nil
+}
+
+func (self *formatLogger) Log(keyvals ...interface{}) error {
+	self.mtx.RLock()
+	logger := self.logfmt
+	if self.format == FormatJSON {
+		logger = self.json
+	}
+	self.mtx.RUnlock()
+	return logger.Log(keyvals...)
+}
+
+// levels orders the log levels so that a level allows itself and all levels after it.
+var levels = map[string]int{
+	"debug": 0,
+	"info":  1,
+	"warn":  2,
+	"error": 3,
+}
+
+var registry = &filters{components: make(map[string]*componentLevel)}
+
 // ApplyFilter applies a filter to logger based on component name.
-func ApplyFilter(configLevel string, logger log.Logger) (log.Logger, error) {
-	var lvl level.Option
-	switch configLevel {
-	case "error":
-		lvl = level.AllowError()
-	case "warn":
-		lvl = level.AllowWarn()
-	case "info":
-		lvl = level.AllowInfo()
-	case "debug":
-		lvl = level.AllowDebug()
-	default:
+// The level of the component can be changed at runtime with SetLevel.
+// All loggers of the same component share the same level.
+func ApplyFilter(component, configLevel string, logger log.Logger) (log.Logger, error) {
+	lvl, ok := levels[configLevel]
+	if !ok {
 		return nil, errors.Errorf("unexpected log level:%v", configLevel)
 	}
+	return &filter{next: logger, level: registry.register(component, configLevel, lvl)}, nil
+}
+
+// ValidLevel returns whether lvl is one of the supported log levels.
+func ValidLevel(lvl string) bool {
+	_, ok := levels[lvl]
+	return ok
+}
+
+// Levels returns the current log level of all components.
+func Levels() map[string]string {
+	return registry.levels()
+}
+
+// SetLevel changes the log level of a component.
+// When revertAfter is not 0 the level reverts to the configured level after that duration
+// so that a debug level doesn't remain enabled when forgotten.
+func SetLevel(component, lvl string, revertAfter time.Duration) error {
+	return registry.set(component, lvl, revertAfter)
+}
+
+// SetConfigLevel changes the configured log level of a component, for example after a config reload.
+// It also cancels a pending revert of a level set with SetLevel.
+func SetConfigLevel(component, lvl string) error {
+	if err := registry.set(component, lvl, 0); err != nil {
+		return err
+	}
+	registry.mtx.Lock()
+	defer registry.mtx.Unlock()
+	registry.components[component].configLevel = lvl
+	return nil
+}
+
+type componentLevel struct {
+	level       int32
+	configLevel string
+	revert      *time.Timer
+}
 
-	return level.NewFilter(logger, lvl), nil
+type filters struct {
+	mtx        sync.Mutex
+	components map[string]*componentLevel
+}
+
+func (self *filters) register(component, configLevel string, lvl int) *componentLevel {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	c, ok := self.components[component]
+	if !ok {
+		c = &componentLevel{}
+		self.components[component] = c
+	}
+	c.configLevel = configLevel
+	atomic.StoreInt32(&c.level, int32(lvl))
+	return c
+}
+
+func (self *filters) levels() map[string]string {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	lvls := make(map[string]string, len(self.components))
+	for component, c := range self.components {
+		lvl := atomic.LoadInt32(&c.level)
+		for name, l := range levels {
+			if int32(l) == lvl {
+				lvls[component] = name
+			}
+		}
+	}
+	return lvls
+}
+
+func (self *filters) set(component, lvl string, revertAfter time.Duration) error {
+	l, ok := levels[lvl]
+	if !ok {
+		return errors.Errorf("unexpected log level:%v", lvl)
+	}
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	c, ok := self.components[component]
+	if !ok {
+		return errors.Errorf("unknown component:%v", component)
+	}
+	if c.revert != nil {
+		c.revert.Stop()
+		c.revert = nil
+	}
+	atomic.StoreInt32(&c.level, int32(l))
+	if revertAfter > 0 {
+		configLevel := levels[c.configLevel]
+		c.revert = time.AfterFunc(revertAfter, func() {
+			atomic.StoreInt32(&c.level, int32(configLevel))
+		})
+	}
+	return nil
+}
+
+// filter drops the logs below the current level of its component.
+// It calls the next logger directly so that the caller depth is the same as with level.NewFilter.
+type filter struct {
+	next  log.Logger
+	level *componentLevel
+}
+
+func (self *filter) Log(keyvals ...interface{}) error {
+	for i := 1; i < len(keyvals); i += 2 {
+		if v, ok := keyvals[i].(level.Value); ok {
+			if l, ok := levels[v.String()]; ok && int32(l) < atomic.LoadInt32(&self.level.level) {
+				return nil
+			}
+		}
+	}
+	return self.next.Log(keyvals...)
 }
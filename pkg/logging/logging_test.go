@@ -0,0 +1,63 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestFormat(t *testing.T) {
+	var buf bytes.Buffer
+	output := newFormatLogger(&buf)
+	logger := log.With(output, "component", "submitter", "account", "0xA")
+
+	testutil.Ok(t, logger.Log("msg", "submitted", "tx", "0xB"))
+	testutil.Equals(t, "component=submitter account=0xA msg=submitted tx=0xB\n", buf.String())
+
+	buf.Reset()
+	testutil.Ok(t, output.setFormat(FormatJSON))
+	testutil.Ok(t, logger.Log("msg", "submitted", "tx", "0xB"))
+	var fields map[string]string
+	testutil.Ok(t, json.Unmarshal(buf.Bytes(), &fields))
+	testutil.Equals(t, map[string]string{
+		"component": "submitter",
+		"account":   "0xA",
+		"msg":       "submitted",
+		"tx":        "0xB",
+	}, fields)
+
+	testutil.NotOk(t, output.setFormat("xml"))
+}
+
+func TestSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := ApplyFilter("testComponent", "info", log.NewLogfmtLogger(&buf))
+	testutil.Ok(t, err)
+
+	level.Debug(logger).Log("msg", "hidden")
+	level.Info(logger).Log("msg", "shown")
+	testutil.Equals(t, "level=info msg=shown\n", buf.String())
+	testutil.Equals(t, "info", Levels()["testComponent"])
+
+	buf.Reset()
+	testutil.Ok(t, SetLevel("testComponent", "debug", 0))
+	level.Debug(logger).Log("msg", "shown")
+	testutil.Equals(t, "level=debug msg=shown\n", buf.String())
+
+	testutil.NotOk(t, SetLevel("testComponent", "verbose", 0))
+	testutil.NotOk(t, SetLevel("unknownComponent", "debug", 0))
+
+	// A temporary level reverts to the configured level.
+	testutil.Ok(t, SetLevel("testComponent", "error", 10*time.Millisecond))
+	testutil.Equals(t, "error", Levels()["testComponent"])
+	time.Sleep(50 * time.Millisecond)
+	testutil.Equals(t, "info", Levels()["testComponent"])
+}
@@ -0,0 +1,91 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package mining
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// benchmarkChunk is the number of hashes checked between the checks of the benchmark deadline.
+const benchmarkChunk = 1000
+
+// BenchmarkResult is the hashrate of a benchmark run.
+type BenchmarkResult struct {
+	Workers  int
+	Hashes   uint64
+	Duration time.Duration
+}
+
+// HashRate returns the hashes per second of all workers.
+func (self BenchmarkResult) HashRate() float64 {
+	return float64(self.Hashes) / self.Duration.Seconds()
+}
+
+// FormatHashRate formats a hashrate the same way as the mining logs.
+func FormatHashRate(rate float64) string {
+	return formatHashRate(rate)
+}
+
+// Benchmark runs the CPU miners from the config for the given duration
+// with a difficulty that is practically never solved so that
+// every worker checks hashes for the whole duration.
+func Benchmark(ctx context.Context, cfg Config, duration time.Duration) (BenchmarkResult, error) {
+	difficulty := new(big.Int).Lsh(big.NewInt(1), 255)
+	hash := &HashSettings{
+		prefix:     make([]byte, 52),
+		difficulty: difficulty,
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mtx   sync.Mutex
+		total uint64
+		errs  []error
+	)
+	started := time.Now()
+	deadline := started.Add(duration)
+	for i, hasher := range newCpuMiners(cfg) {
+		wg.Add(1)
+		// Each worker checks its own range so that the workers don't check the same hashes.
+		go func(hasher Hasher, start uint64) {
+			defer wg.Done()
+			var n uint64
+			for time.Now().Before(deadline) && ctx.Err() == nil {
+				sol, checked, err := hasher.CheckRange(context.Background(), hash, start+n, benchmarkChunk)
+				if err != nil {
+					mtx.Lock()
+					errs = append(errs, errors.Wrapf(err, "hasher:%v", hasher.Name()))
+					mtx.Unlock()
+					return
+				}
+				n += checked
+				if sol != "" {
+					// Practically impossible, but the rest of the chunk is not counted.
+					break
+				}
+			}
+			mtx.Lock()
+			total += n
+			mtx.Unlock()
+		}(hasher, uint64(i)<<48)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return BenchmarkResult{}, errs[0]
+	}
+	if err := ctx.Err(); err != nil {
+		return BenchmarkResult{}, err
+	}
+	return BenchmarkResult{
+		Workers:  cfg.NumProcessors,
+		Hashes:   total,
+		Duration: time.Since(started),
+	}, nil
+}
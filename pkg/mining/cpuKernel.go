@@ -0,0 +1,190 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package mining
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/cpu"
+
+	// nolint:staticcheck
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// KernelOptimized reuses the hash states and buffers between the nonces
+	// and hashes 4 nonces at a time with AVX2 when the CPU supports it.
+	KernelOptimized = "optimized"
+	// KernelGeneric is the reference implementation.
+	KernelGeneric = "generic"
+)
+
+func validateKernel(kernel string) error {
+	switch kernel {
+	case "", KernelOptimized, KernelGeneric:
+		return nil
+	}
+	return errors.Errorf("invalid mining kernel:%v, supported:%v, %v", kernel, KernelOptimized, KernelGeneric)
+}
+
+// cpuFeatures returns the CPU features that select the assembly implementations
+// of the keccak-f of 4 nonces with AVX2, of sha256 which uses AVX2 with BMI2 on amd64
+// and the SHA2 instructions on arm64.
+func cpuFeatures() []interface{} {
+	return []interface{}{"keccak4x", hasKeccak4x, "avx2", cpu.X86.HasAVX2 && cpu.X86.HasBMI2, "arm64sha2", cpu.ARM64.HasSHA2}
+}
+
+// keccakRate is the size of a keccak256 block.
+const keccakRate = 136
+
+// keccak4 hashes 4 inputs of a single block at a time with the AVX2 keccak-f.
+type keccak4 struct {
+	state   [25][4]uint64
+	scratch [25][4]uint64
+	block   [keccakRate]byte
+}
+
+// sum sets the keccak256 of the inputs in the sums.
+// The inputs must be shorter than the keccak rate.
+func (self *keccak4) sum(inputs *[4][]byte, sums *[4][32]byte) {
+	for j, input := range inputs {
+		n := copy(self.block[:], input)
+		for k := n; k < keccakRate; k++ {
+			self.block[k] = 0
+		}
+		// The padding of the legacy keccak256.
+		self.block[n] = 0x01
+		self.block[keccakRate-1] |= 0x80
+		for i := 0; i < keccakRate/8; i++ {
+			self.state[i][j] = binary.LittleEndian.Uint64(self.block[i*8:])
+		}
+		for i := keccakRate / 8; i < len(self.state); i++ {
+			self.state[i][j] = 0
+		}
+	}
+	keccakF1600x4(&self.state, &self.scratch)
+	for j := range sums {
+		for i := 0; i < 4; i++ {
+			binary.LittleEndian.PutUint64(sums[j][i*8:], self.state[i][j])
+		}
+	}
+}
+
+// kernel checks nonces with the hash states and buffers reused
+// so that checking a nonce doesn't allocate.
+type kernel struct {
+	keccak    crypto.KeccakState
+	ripemd    hash.Hash
+	input     []byte
+	keccakSum []byte
+	ripemdSum []byte
+	num       *big.Int
+	quo       *big.Int
+	rem       *big.Int
+	// x4 is nil when the CPU doesn't support the keccak-f of 4 nonces.
+	x4     *keccak4
+	inputs [4][]byte
+	sums   [4][32]byte
+}
+
+func newKernel() *kernel {
+	k := &kernel{
+		keccak:    crypto.NewKeccakState(),
+		ripemd:    ripemd160.New(),
+		keccakSum: make([]byte, 32),
+		ripemdSum: make([]byte, 0, ripemd160.Size),
+		num:       new(big.Int),
+		quo:       new(big.Int),
+		rem:       new(big.Int),
+	}
+	if hasKeccak4x {
+		k.x4 = &keccak4{}
+	}
+	return k
+}
+
+func (self *kernel) checkRange(anySolution context.Context, hash *HashSettings, start uint64, n uint64) (string, uint64, error) {
+	// The longest nonce has 20 digits.
+	if self.x4 != nil && len(hash.prefix)+20 < keccakRate {
+		return self.checkRange4(anySolution, hash, start, n)
+	}
+	baseLen := len(hash.prefix)
+	self.input = append(self.input[:0], hash.prefix...)
+
+	for i := start; i < (start + n); i++ {
+		// Checking the context on every nonce is a large part of the work.
+		if (i-start)%1024 == 0 {
+			select {
+			case <-anySolution.Done():
+				return "any", n, nil
+			default:
+			}
+		}
+		self.input = strconv.AppendUint(self.input[:baseLen], i, 10)
+
+		// Read doesn't copy the state like Sum.
+		self.keccak.Reset()
+		self.keccak.Write(self.input)
+		if _, err := self.keccak.Read(self.keccakSum); err != nil {
+			return "", 0, err
+		}
+
+		if self.solves(self.keccakSum, hash.difficulty) {
+			return string(self.input[baseLen:]), (i - start) + 1, nil
+		}
+	}
+	return "", n, nil
+}
+
+// checkRange4 checks the nonces in groups of 4 with the keccak-f of 4 nonces.
+func (self *kernel) checkRange4(anySolution context.Context, hash *HashSettings, start uint64, n uint64) (string, uint64, error) {
+	baseLen := len(hash.prefix)
+	for j := range self.inputs {
+		self.inputs[j] = append(self.inputs[j][:0], hash.prefix...)
+	}
+
+	for done := uint64(0); done < n; done += 4 {
+		if done%1024 == 0 {
+			select {
+			case <-anySolution.Done():
+				return "any", n, nil
+			default:
+			}
+		}
+		m := uint64(4)
+		if n-done < m {
+			m = n - done
+		}
+		for j := uint64(0); j < m; j++ {
+			self.inputs[j] = strconv.AppendUint(self.inputs[j][:baseLen], start+done+j, 10)
+		}
+		self.x4.sum(&self.inputs, &self.sums)
+		for j := uint64(0); j < m; j++ {
+			if self.solves(self.sums[j][:], hash.difficulty) {
+				return string(self.inputs[j][baseLen:]), done + j + 1, nil
+			}
+		}
+	}
+	return "", n, nil
+}
+
+// solves returns whether the keccak256 of a nonce is a solution for the difficulty.
+func (self *kernel) solves(keccakSum []byte, difficulty *big.Int) bool {
+	self.ripemd.Reset()
+	self.ripemd.Write(keccakSum)
+	self.ripemdSum = self.ripemd.Sum(self.ripemdSum[:0])
+
+	sum := sha256.Sum256(self.ripemdSum)
+	self.num.SetBytes(sum[:])
+	// DivMod reuses the quotient which Mod allocates. Both are the same for positive numbers.
+	self.quo.DivMod(self.num, difficulty, self.rem)
+	return self.rem.Sign() == 0
+}
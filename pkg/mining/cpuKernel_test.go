@@ -0,0 +1,116 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package mining
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestKernel(t *testing.T) {
+	challenge := &MiningChallenge{Challenge: make([]byte, 32), Difficulty: big.NewInt(1000)}
+	hash := NewHashSettings(challenge, "0x0000000000000000000000000000000000000001")
+
+	generic := &CpuMiner{cpu: -1}
+	optimized := NewCpuMiner(0)
+	for _, start := range []uint64{0, 5000, 1 << 40} {
+		expNonce, expN, err := generic.CheckRange(context.Background(), hash, start, 100000)
+		testutil.Ok(t, err)
+		nonce, n, err := optimized.CheckRange(context.Background(), hash, start, 100000)
+		testutil.Ok(t, err)
+		testutil.Equals(t, expNonce, nonce)
+		testutil.Equals(t, expN, n)
+
+		// Without the keccak-f of 4 nonces.
+		scalar := newKernel()
+		scalar.x4 = nil
+		nonce, n, err = scalar.checkRange(context.Background(), hash, start, 100000)
+		testutil.Ok(t, err)
+		testutil.Equals(t, expNonce, nonce)
+		testutil.Equals(t, expN, n)
+	}
+
+	// Ranges that aren't a multiple of 4 and the end of the nonces.
+	for _, r := range [][2]uint64{{0, 1}, {3, 6}, {997, 3}, {math.MaxUint64 - 6, 6}} {
+		expNonce, expN, err := generic.CheckRange(context.Background(), hash, r[0], r[1])
+		testutil.Ok(t, err)
+		nonce, n, err := optimized.CheckRange(context.Background(), hash, r[0], r[1])
+		testutil.Ok(t, err)
+		testutil.Equals(t, expNonce, nonce, "start:%v n:%v", r[0], r[1])
+		testutil.Equals(t, expN, n, "start:%v n:%v", r[0], r[1])
+	}
+}
+
+func TestKeccak4(t *testing.T) {
+	if !hasKeccak4x {
+		t.Skip("the CPU doesn't support the keccak-f of 4 nonces")
+	}
+	var (
+		k      keccak4
+		inputs [4][]byte
+		sums   [4][32]byte
+	)
+	for _, lengths := range [][4]int{{0, 1, 2, 3}, {52, 53, 71, 72}, {100, 8, 134, 135}} {
+		for j, l := range lengths {
+			inputs[j] = bytes.Repeat([]byte{byte(l + j)}, l)
+		}
+		k.sum(&inputs, &sums)
+		for j := range inputs {
+			testutil.Equals(t, crypto.Keccak256(inputs[j]), sums[j][:], "length:%v", lengths[j])
+		}
+	}
+}
+
+func TestKernelConcurrentRanges(t *testing.T) {
+	challenge := &MiningChallenge{Challenge: make([]byte, 32), Difficulty: big.NewInt(1000)}
+	hash := NewHashSettings(challenge, "0x0000000000000000000000000000000000000001")
+
+	expNonce, _, err := (&CpuMiner{cpu: -1}).CheckRange(context.Background(), hash, 0, 100000)
+	testutil.Ok(t, err)
+
+	// A restarted hasher checks a range while its stalled range still runs.
+	miner := NewCpuMiner(0)
+	nonces := make(chan string, 2)
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			nonce, _, err := miner.CheckRange(context.Background(), hash, 0, 100000)
+			nonces <- nonce
+			errs <- err
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		testutil.Ok(t, <-errs)
+		testutil.Equals(t, expNonce, <-nonces)
+	}
+}
+
+func BenchmarkKernel(b *testing.B) {
+	challenge := &MiningChallenge{Challenge: make([]byte, 32), Difficulty: new(big.Int).Lsh(big.NewInt(1), 255)}
+	hash := NewHashSettings(challenge, "0x0000000000000000000000000000000000000001")
+
+	for name, miner := range map[string]*CpuMiner{
+		KernelGeneric:   {cpu: -1},
+		KernelOptimized: NewCpuMiner(0),
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			_, _, err := miner.CheckRange(context.Background(), hash, 0, uint64(b.N))
+			testutil.Ok(b, err)
+		})
+	}
+	b.Run(KernelOptimized+"-scalar", func(b *testing.B) {
+		b.ReportAllocs()
+		scalar := newKernel()
+		scalar.x4 = nil
+		_, _, err := scalar.checkRange(context.Background(), hash, 0, uint64(b.N))
+		testutil.Ok(b, err)
+	})
+}
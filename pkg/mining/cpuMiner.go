@@ -8,7 +8,9 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"math/big"
+	"runtime"
 	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 
@@ -16,11 +18,37 @@ import (
 	"golang.org/x/crypto/ripemd160"
 )
 
-type CpuMiner int64
+type CpuMiner struct {
+	id   int64
+	cpu  int
+	nice int
+	// optimized selects the kernel instead of the generic implementation.
+	optimized bool
+}
 
 func NewCpuMiner(id int64) *CpuMiner {
-	x := CpuMiner(id)
-	return &x
+	return &CpuMiner{id: id, cpu: -1, optimized: true}
+}
+
+// newCpuMiners creates the CPU miners with the thread settings from the config.
+// The affinity CPUs are assigned to the miners in order and
+// wrap around when there are more miners than CPUs.
+func newCpuMiners(cfg Config) []Hasher {
+	var hashers []Hasher
+	for i := 0; i < cfg.NumProcessors; i++ {
+		miner := NewCpuMiner(int64(i))
+		if cfg.Kernel == KernelGeneric {
+			miner.optimized = false
+		}
+		if threadSettingsSupported {
+			if len(cfg.CPUAffinity) > 0 {
+				miner.cpu = cfg.CPUAffinity[i%len(cfg.CPUAffinity)]
+			}
+			miner.nice = cfg.Nice
+		}
+		hashers = append(hashers, miner)
+	}
+	return hashers
 }
 
 func (c *CpuMiner) StepSize() uint64 {
@@ -28,10 +56,32 @@ func (c *CpuMiner) StepSize() uint64 {
 }
 
 func (c *CpuMiner) Name() string {
-	return fmt.Sprintf("CPU %d", c)
+	return fmt.Sprintf("CPU %d", c.id)
 }
 
 func (c *CpuMiner) CheckRange(anySolution context.Context, hash *HashSettings, start uint64, n uint64) (string, uint64, error) {
+	if c.cpu >= 0 || c.nice != 0 {
+		// The settings are per thread so the goroutine keeps its thread while hashing
+		// and sets back the previous settings before handing the thread over to other goroutines.
+		// A lower nice level can't be set back without privileges and then the thread stays locked
+		// which makes the runtime discard it when the goroutine exits.
+		// Each range runs in its own goroutine so this is called only by the mining group.
+		runtime.LockOSThread()
+		restore, err := setThread(c.cpu, c.nice)
+		defer func() {
+			if restore != nil && restore() {
+				runtime.UnlockOSThread()
+			}
+		}()
+		if err != nil {
+			return "", 0, err
+		}
+	}
+	if c.optimized {
+		// The watchdog can start a range while a stalled one still runs
+		// so each range gets its own kernel.
+		return newKernel().checkRange(anySolution, hash, start, n)
+	}
 	baseLen := len(hash.prefix)
 	hashInput := make([]byte, len(hash.prefix))
 	copy(hashInput, hash.prefix)
@@ -59,6 +109,21 @@ func (c *CpuMiner) CheckRange(anySolution context.Context, hash *HashSettings, s
 	return "", n, nil
 }
 
+// CheckSolution returns whether the nonce solves the challenge for the address.
+// After the submit window any nonce is accepted.
+func CheckSolution(challenge *MiningChallenge, publicAddr string, nonce string, timeOfLastNewValue *big.Int) (bool, error) {
+	if timeOfLastNewValue != nil && time.Since(time.Unix(timeOfLastNewValue.Int64(), 0)) >= submitWindow {
+		return true, nil
+	}
+	hash := NewHashSettings(challenge, publicAddr)
+	input := append(append([]byte{}, hash.prefix...), []byte(nonce)...)
+	numHash, err := hashFn(input)
+	if err != nil {
+		return false, err
+	}
+	return new(big.Int).Mod(numHash, hash.difficulty).Sign() == 0, nil
+}
+
 func hashFn(input []byte) (*big.Int, error) {
 	hash := crypto.Keccak256(input)
 	hasher := ripemd160.New()
@@ -0,0 +1,49 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+//go:build linux
+// +build linux
+
+package mining
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const threadSettingsSupported = true
+
+// setThread pins the calling thread to a CPU and sets its nice level.
+// On Linux both apply only to the calling thread and not to the whole process.
+// The returned restore sets back the previous settings of the thread and reports whether it could.
+func setThread(cpu int, nice int) (restore func() bool, err error) {
+	var prevSet unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &prevSet); err != nil {
+		return nil, errors.Wrap(err, "getting the affinity")
+	}
+	// The raw syscall returns 20 - nice.
+	prio, err := unix.Getpriority(unix.PRIO_PROCESS, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the nice level")
+	}
+	restore = func() bool {
+		if unix.SchedSetaffinity(0, &prevSet) != nil {
+			return false
+		}
+		return unix.Setpriority(unix.PRIO_PROCESS, 0, 20-prio) == nil
+	}
+
+	if cpu >= 0 {
+		var set unix.CPUSet
+		set.Set(cpu)
+		if err := unix.SchedSetaffinity(0, &set); err != nil {
+			return restore, errors.Wrapf(err, "setting the affinity to cpu:%v", cpu)
+		}
+	}
+	if nice != 0 {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, 0, nice); err != nil {
+			return restore, errors.Wrapf(err, "setting the nice level:%v", nice)
+		}
+	}
+	return restore, nil
+}
@@ -0,0 +1,15 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+//go:build !linux
+// +build !linux
+
+package mining
+
+import "github.com/pkg/errors"
+
+const threadSettingsSupported = false
+
+func setThread(cpu int, nice int) (func() bool, error) {
+	return nil, errors.New("the cpu affinity and nice level are supported only on linux")
+}
@@ -0,0 +1,20 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+//go:build amd64
+// +build amd64
+
+package mining
+
+import "golang.org/x/sys/cpu"
+
+//go:generate go run ../../scripts/keccakgen keccak4x_amd64.s
+
+// hasKeccak4x is whether the CPU supports the AVX2 keccak-f of 4 states.
+var hasKeccak4x = cpu.X86.HasAVX2
+
+// keccakF1600x4 applies the keccak-f[1600] permutation to the 4 states interleaved in a,
+// the lane i of the state j is a[i][j]. b is used as scratch space.
+//
+//go:noescape
+func keccakF1600x4(a *[25][4]uint64, b *[25][4]uint64)
@@ -0,0 +1,345 @@
+// Code generated by scripts/keccakgen. DO NOT EDIT.
+
+//go:build amd64
+// +build amd64
+
+#include "textflag.h"
+
+DATA rc<>+0x00(SB)/8, $0x0000000000000001
+DATA rc<>+0x08(SB)/8, $0x0000000000008082
+DATA rc<>+0x10(SB)/8, $0x800000000000808a
+DATA rc<>+0x18(SB)/8, $0x8000000080008000
+DATA rc<>+0x20(SB)/8, $0x000000000000808b
+DATA rc<>+0x28(SB)/8, $0x0000000080000001
+DATA rc<>+0x30(SB)/8, $0x8000000080008081
+DATA rc<>+0x38(SB)/8, $0x8000000000008009
+DATA rc<>+0x40(SB)/8, $0x000000000000008a
+DATA rc<>+0x48(SB)/8, $0x0000000000000088
+DATA rc<>+0x50(SB)/8, $0x0000000080008009
+DATA rc<>+0x58(SB)/8, $0x000000008000000a
+DATA rc<>+0x60(SB)/8, $0x000000008000808b
+DATA rc<>+0x68(SB)/8, $0x800000000000008b
+DATA rc<>+0x70(SB)/8, $0x8000000000008089
+DATA rc<>+0x78(SB)/8, $0x8000000000008003
+DATA rc<>+0x80(SB)/8, $0x8000000000008002
+DATA rc<>+0x88(SB)/8, $0x8000000000000080
+DATA rc<>+0x90(SB)/8, $0x000000000000800a
+DATA rc<>+0x98(SB)/8, $0x800000008000000a
+DATA rc<>+0xa0(SB)/8, $0x8000000080008081
+DATA rc<>+0xa8(SB)/8, $0x8000000000008080
+DATA rc<>+0xb0(SB)/8, $0x0000000080000001
+DATA rc<>+0xb8(SB)/8, $0x8000000080008008
+GLOBL rc<>(SB), RODATA|NOPTR, $192
+
+// func keccakF1600x4(a *[25][4]uint64, b *[25][4]uint64)
+TEXT ·keccakF1600x4(SB), NOSPLIT, $0-16
+	MOVQ a+0(FP), DI
+	MOVQ b+8(FP), SI
+	LEAQ rc<>(SB), R8
+	MOVQ $24, CX
+
+round:
+	// Theta, the parities of the columns in Y0-Y4.
+	VMOVDQU 0(DI), Y0
+	VPXOR 160(DI), Y0, Y0
+	VPXOR 320(DI), Y0, Y0
+	VPXOR 480(DI), Y0, Y0
+	VPXOR 640(DI), Y0, Y0
+	VMOVDQU 32(DI), Y1
+	VPXOR 192(DI), Y1, Y1
+	VPXOR 352(DI), Y1, Y1
+	VPXOR 512(DI), Y1, Y1
+	VPXOR 672(DI), Y1, Y1
+	VMOVDQU 64(DI), Y2
+	VPXOR 224(DI), Y2, Y2
+	VPXOR 384(DI), Y2, Y2
+	VPXOR 544(DI), Y2, Y2
+	VPXOR 704(DI), Y2, Y2
+	VMOVDQU 96(DI), Y3
+	VPXOR 256(DI), Y3, Y3
+	VPXOR 416(DI), Y3, Y3
+	VPXOR 576(DI), Y3, Y3
+	VPXOR 736(DI), Y3, Y3
+	VMOVDQU 128(DI), Y4
+	VPXOR 288(DI), Y4, Y4
+	VPXOR 448(DI), Y4, Y4
+	VPXOR 608(DI), Y4, Y4
+	VPXOR 768(DI), Y4, Y4
+	// D[x] = C[x-1] ^ rot(C[x+1], 1) in Y5-Y9.
+	VPSLLQ $1, Y1, Y10
+	VPSRLQ $63, Y1, Y11
+	VPOR Y10, Y11, Y11
+	VPXOR Y4, Y11, Y5
+	VPSLLQ $1, Y2, Y10
+	VPSRLQ $63, Y2, Y11
+	VPOR Y10, Y11, Y11
+	VPXOR Y0, Y11, Y6
+	VPSLLQ $1, Y3, Y10
+	VPSRLQ $63, Y3, Y11
+	VPOR Y10, Y11, Y11
+	VPXOR Y1, Y11, Y7
+	VPSLLQ $1, Y4, Y10
+	VPSRLQ $63, Y4, Y11
+	VPOR Y10, Y11, Y11
+	VPXOR Y2, Y11, Y8
+	VPSLLQ $1, Y0, Y10
+	VPSRLQ $63, Y0, Y11
+	VPOR Y10, Y11, Y11
+	VPXOR Y3, Y11, Y9
+	// Theta, rho and pi into b.
+	VMOVDQU 0(DI), Y0
+	VPXOR Y5, Y0, Y0
+	VMOVDQU Y0, 0(SI)
+	VMOVDQU 32(DI), Y0
+	VPXOR Y6, Y0, Y0
+	VPSLLQ $1, Y0, Y1
+	VPSRLQ $63, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 320(SI)
+	VMOVDQU 64(DI), Y0
+	VPXOR Y7, Y0, Y0
+	VPSLLQ $62, Y0, Y1
+	VPSRLQ $2, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 640(SI)
+	VMOVDQU 96(DI), Y0
+	VPXOR Y8, Y0, Y0
+	VPSLLQ $28, Y0, Y1
+	VPSRLQ $36, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 160(SI)
+	VMOVDQU 128(DI), Y0
+	VPXOR Y9, Y0, Y0
+	VPSLLQ $27, Y0, Y1
+	VPSRLQ $37, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 480(SI)
+	VMOVDQU 160(DI), Y0
+	VPXOR Y5, Y0, Y0
+	VPSLLQ $36, Y0, Y1
+	VPSRLQ $28, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 512(SI)
+	VMOVDQU 192(DI), Y0
+	VPXOR Y6, Y0, Y0
+	VPSLLQ $44, Y0, Y1
+	VPSRLQ $20, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 32(SI)
+	VMOVDQU 224(DI), Y0
+	VPXOR Y7, Y0, Y0
+	VPSLLQ $6, Y0, Y1
+	VPSRLQ $58, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 352(SI)
+	VMOVDQU 256(DI), Y0
+	VPXOR Y8, Y0, Y0
+	VPSLLQ $55, Y0, Y1
+	VPSRLQ $9, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 672(SI)
+	VMOVDQU 288(DI), Y0
+	VPXOR Y9, Y0, Y0
+	VPSLLQ $20, Y0, Y1
+	VPSRLQ $44, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 192(SI)
+	VMOVDQU 320(DI), Y0
+	VPXOR Y5, Y0, Y0
+	VPSLLQ $3, Y0, Y1
+	VPSRLQ $61, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 224(SI)
+	VMOVDQU 352(DI), Y0
+	VPXOR Y6, Y0, Y0
+	VPSLLQ $10, Y0, Y1
+	VPSRLQ $54, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 544(SI)
+	VMOVDQU 384(DI), Y0
+	VPXOR Y7, Y0, Y0
+	VPSLLQ $43, Y0, Y1
+	VPSRLQ $21, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 64(SI)
+	VMOVDQU 416(DI), Y0
+	VPXOR Y8, Y0, Y0
+	VPSLLQ $25, Y0, Y1
+	VPSRLQ $39, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 384(SI)
+	VMOVDQU 448(DI), Y0
+	VPXOR Y9, Y0, Y0
+	VPSLLQ $39, Y0, Y1
+	VPSRLQ $25, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 704(SI)
+	VMOVDQU 480(DI), Y0
+	VPXOR Y5, Y0, Y0
+	VPSLLQ $41, Y0, Y1
+	VPSRLQ $23, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 736(SI)
+	VMOVDQU 512(DI), Y0
+	VPXOR Y6, Y0, Y0
+	VPSLLQ $45, Y0, Y1
+	VPSRLQ $19, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 256(SI)
+	VMOVDQU 544(DI), Y0
+	VPXOR Y7, Y0, Y0
+	VPSLLQ $15, Y0, Y1
+	VPSRLQ $49, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 576(SI)
+	VMOVDQU 576(DI), Y0
+	VPXOR Y8, Y0, Y0
+	VPSLLQ $21, Y0, Y1
+	VPSRLQ $43, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 96(SI)
+	VMOVDQU 608(DI), Y0
+	VPXOR Y9, Y0, Y0
+	VPSLLQ $8, Y0, Y1
+	VPSRLQ $56, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 416(SI)
+	VMOVDQU 640(DI), Y0
+	VPXOR Y5, Y0, Y0
+	VPSLLQ $18, Y0, Y1
+	VPSRLQ $46, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 448(SI)
+	VMOVDQU 672(DI), Y0
+	VPXOR Y6, Y0, Y0
+	VPSLLQ $2, Y0, Y1
+	VPSRLQ $62, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 768(SI)
+	VMOVDQU 704(DI), Y0
+	VPXOR Y7, Y0, Y0
+	VPSLLQ $61, Y0, Y1
+	VPSRLQ $3, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 288(SI)
+	VMOVDQU 736(DI), Y0
+	VPXOR Y8, Y0, Y0
+	VPSLLQ $56, Y0, Y1
+	VPSRLQ $8, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 608(SI)
+	VMOVDQU 768(DI), Y0
+	VPXOR Y9, Y0, Y0
+	VPSLLQ $14, Y0, Y1
+	VPSRLQ $50, Y0, Y0
+	VPOR Y1, Y0, Y0
+	VMOVDQU Y0, 128(SI)
+	// Chi and iota back into a.
+	VMOVDQU 0(SI), Y0
+	VMOVDQU 32(SI), Y1
+	VMOVDQU 64(SI), Y2
+	VMOVDQU 96(SI), Y3
+	VMOVDQU 128(SI), Y4
+	VPANDN Y2, Y1, Y10
+	VPXOR Y0, Y10, Y10
+	VPBROADCASTQ (R8), Y11
+	VPXOR Y11, Y10, Y10
+	VMOVDQU Y10, 0(DI)
+	VPANDN Y3, Y2, Y10
+	VPXOR Y1, Y10, Y10
+	VMOVDQU Y10, 32(DI)
+	VPANDN Y4, Y3, Y10
+	VPXOR Y2, Y10, Y10
+	VMOVDQU Y10, 64(DI)
+	VPANDN Y0, Y4, Y10
+	VPXOR Y3, Y10, Y10
+	VMOVDQU Y10, 96(DI)
+	VPANDN Y1, Y0, Y10
+	VPXOR Y4, Y10, Y10
+	VMOVDQU Y10, 128(DI)
+	VMOVDQU 160(SI), Y0
+	VMOVDQU 192(SI), Y1
+	VMOVDQU 224(SI), Y2
+	VMOVDQU 256(SI), Y3
+	VMOVDQU 288(SI), Y4
+	VPANDN Y2, Y1, Y10
+	VPXOR Y0, Y10, Y10
+	VMOVDQU Y10, 160(DI)
+	VPANDN Y3, Y2, Y10
+	VPXOR Y1, Y10, Y10
+	VMOVDQU Y10, 192(DI)
+	VPANDN Y4, Y3, Y10
+	VPXOR Y2, Y10, Y10
+	VMOVDQU Y10, 224(DI)
+	VPANDN Y0, Y4, Y10
+	VPXOR Y3, Y10, Y10
+	VMOVDQU Y10, 256(DI)
+	VPANDN Y1, Y0, Y10
+	VPXOR Y4, Y10, Y10
+	VMOVDQU Y10, 288(DI)
+	VMOVDQU 320(SI), Y0
+	VMOVDQU 352(SI), Y1
+	VMOVDQU 384(SI), Y2
+	VMOVDQU 416(SI), Y3
+	VMOVDQU 448(SI), Y4
+	VPANDN Y2, Y1, Y10
+	VPXOR Y0, Y10, Y10
+	VMOVDQU Y10, 320(DI)
+	VPANDN Y3, Y2, Y10
+	VPXOR Y1, Y10, Y10
+	VMOVDQU Y10, 352(DI)
+	VPANDN Y4, Y3, Y10
+	VPXOR Y2, Y10, Y10
+	VMOVDQU Y10, 384(DI)
+	VPANDN Y0, Y4, Y10
+	VPXOR Y3, Y10, Y10
+	VMOVDQU Y10, 416(DI)
+	VPANDN Y1, Y0, Y10
+	VPXOR Y4, Y10, Y10
+	VMOVDQU Y10, 448(DI)
+	VMOVDQU 480(SI), Y0
+	VMOVDQU 512(SI), Y1
+	VMOVDQU 544(SI), Y2
+	VMOVDQU 576(SI), Y3
+	VMOVDQU 608(SI), Y4
+	VPANDN Y2, Y1, Y10
+	VPXOR Y0, Y10, Y10
+	VMOVDQU Y10, 480(DI)
+	VPANDN Y3, Y2, Y10
+	VPXOR Y1, Y10, Y10
+	VMOVDQU Y10, 512(DI)
+	VPANDN Y4, Y3, Y10
+	VPXOR Y2, Y10, Y10
+	VMOVDQU Y10, 544(DI)
+	VPANDN Y0, Y4, Y10
+	VPXOR Y3, Y10, Y10
+	VMOVDQU Y10, 576(DI)
+	VPANDN Y1, Y0, Y10
+	VPXOR Y4, Y10, Y10
+	VMOVDQU Y10, 608(DI)
+	VMOVDQU 640(SI), Y0
+	VMOVDQU 672(SI), Y1
+	VMOVDQU 704(SI), Y2
+	VMOVDQU 736(SI), Y3
+	VMOVDQU 768(SI), Y4
+	VPANDN Y2, Y1, Y10
+	VPXOR Y0, Y10, Y10
+	VMOVDQU Y10, 640(DI)
+	VPANDN Y3, Y2, Y10
+	VPXOR Y1, Y10, Y10
+	VMOVDQU Y10, 672(DI)
+	VPANDN Y4, Y3, Y10
+	VPXOR Y2, Y10, Y10
+	VMOVDQU Y10, 704(DI)
+	VPANDN Y0, Y4, Y10
+	VPXOR Y3, Y10, Y10
+	VMOVDQU Y10, 736(DI)
+	VPANDN Y1, Y0, Y10
+	VPXOR Y4, Y10, Y10
+	VMOVDQU Y10, 768(DI)
+
+	ADDQ $8, R8
+	DECQ CX
+	JNZ round
+	VZEROUPPER
+	RET
@@ -0,0 +1,13 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+//go:build !amd64
+// +build !amd64
+
+package mining
+
+const hasKeccak4x = false
+
+func keccakF1600x4(a *[25][4]uint64, b *[25][4]uint64) {
+	panic("the keccak-f of 4 states is supported only on amd64")
+}
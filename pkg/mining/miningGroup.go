@@ -8,14 +8,19 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/tellor-io/telliot/pkg/contracts"
 	"github.com/tellor-io/telliot/pkg/ethereum"
 	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/notify"
 )
 
 const ComponentName = "miner"
@@ -43,6 +48,11 @@ type Backend struct {
 	TotalHashes      uint64
 	HashSincePrint   uint64
 	HashRateEstimate float64
+
+	// chunk is the range the hasher is working on.
+	chunk *backendResult
+	// noProgressSince is when the hasher started to return chunks without checking any hashes.
+	noProgressSince time.Time
 }
 
 // MiningChallenge holds information about a PoW challenge.
@@ -69,6 +79,62 @@ const targetChunkTime = 200 * time.Millisecond
 
 const rateInitialGuess = 100e3
 
+// submitWindow is the time after the last new value when any solution is accepted.
+const submitWindow = 15 * time.Minute
+
+// The metrics are shared by the mining groups of all accounts
+// so the per group series are labeled with the group id.
+var (
+	groups int32
+
+	hashRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "hash_rate",
+		Help:      "The estimated hashes per second of each hasher",
+	},
+		[]string{"group", "worker"},
+	)
+	hashes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "hashes_total",
+		Help:      "The total number of hashes checked by each hasher",
+	},
+		[]string{"group", "worker"},
+	)
+	difficulty = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "difficulty",
+		Help:      "The difficulty of the current challenge",
+	},
+		[]string{"group"},
+	)
+	solutionTime = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "solution_seconds",
+		Help:      "The time from receiving a challenge to finding its solution",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	})
+	stalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "stalls_total",
+		Help:      "The total number of hashers restarted by the watchdog",
+	},
+		[]string{"group", "worker"},
+	)
+	windowShare = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "solution_window_share",
+		Help:      "The share of the 15 minutes submit window passed since the last new value when the solution was found",
+		Buckets:   prometheus.LinearBuckets(0.1, 0.1, 10),
+	})
+)
+
 type MiningGroup struct {
 	cfg              Config
 	ctx              context.Context
@@ -76,10 +142,12 @@ type MiningGroup struct {
 	LastPrinted      time.Time
 	logger           log.Logger
 	contractInstance *contracts.ITellor
+	notifier         *notify.Notifier
+	id               string
 }
 
-func NewMiningGroup(logger log.Logger, ctx context.Context, cfg Config, hashers []Hasher, contractInstance *contracts.ITellor) (*MiningGroup, error) {
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+func NewMiningGroup(logger log.Logger, ctx context.Context, cfg Config, hashers []Hasher, contractInstance *contracts.ITellor, notifier *notify.Notifier) (*MiningGroup, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "apply filter logger")
 	}
@@ -89,6 +157,8 @@ func NewMiningGroup(logger log.Logger, ctx context.Context, cfg Config, hashers
 		Backends:         make([]*Backend, len(hashers)),
 		logger:           log.With(logger, "component", ComponentName),
 		contractInstance: contractInstance,
+		notifier:         notifier,
+		id:               strconv.Itoa(int(atomic.AddInt32(&groups, 1) - 1)),
 	}
 	for i, hasher := range hashers {
 		//start with a small estimate for hash rate, much faster to increase the gusses rather than decrease
@@ -103,8 +173,10 @@ type backendResult struct {
 	err      error
 	started  time.Time
 	finished time.Time
+	start    uint64
 	n        uint64
 	backend  *Backend
+	cancel   context.CancelFunc
 }
 
 // do some work and write the result back to a channel.
@@ -113,7 +185,7 @@ func (b *Backend) doWork(anySolution context.Context, close context.CancelFunc,
 	timeStarted := time.Now()
 	sol, nchecked, err := b.CheckRange(anySolution, hash, start, n)
 	if err != nil {
-		resultCh <- &backendResult{err: err}
+		resultCh <- &backendResult{hash: hash, err: err, start: start, n: n, backend: b}
 		return
 	}
 	resultCh <- &backendResult{
@@ -121,6 +193,7 @@ func (b *Backend) doWork(anySolution context.Context, close context.CancelFunc,
 		nonce:    sol,
 		started:  timeStarted,
 		finished: time.Now(),
+		start:    start,
 		n:        nchecked,
 		backend:  b,
 	}
@@ -184,6 +257,9 @@ type Work struct {
 	PublicAddr string
 	Start      uint64
 	N          uint64
+	// TimeOfLastNewValue is set for the work received from a pool coordinator.
+	// Otherwise it is read from the contract.
+	TimeOfLastNewValue *big.Int
 }
 
 type Result struct {
@@ -200,13 +276,72 @@ func (b *Backend) dispatchWork(parentCtx context.Context, timeOfLastNewValue *bi
 		nsteps = 1
 	}
 	n := nsteps * step
+	b.dispatchRange(parentCtx, timeOfLastNewValue, hash, start, n, resultCh)
+	return n
+}
+
+func (b *Backend) dispatchRange(parentCtx context.Context, timeOfLastNewValue *big.Int, hash *HashSettings, start, n uint64, resultCh chan *backendResult) {
 	tm := time.Unix(timeOfLastNewValue.Int64(), 0)
-	anySolution, close := context.WithDeadline(parentCtx, tm.Add(15*time.Minute))
+	anySolution, close := context.WithDeadline(parentCtx, tm.Add(submitWindow))
+	b.chunk = &backendResult{hash: hash, start: start, n: n, started: time.Now(), cancel: close}
 	go b.doWork(anySolution, close, hash, start, n, resultCh)
-	return n
 }
 
-func (g *MiningGroup) getTimeOfLastNewValue() *big.Int {
+// stalled returns whether the hasher didn't make any progress for the timeout.
+func (b *Backend) stalled(timeout time.Duration) bool {
+	if b.chunk != nil && time.Since(b.chunk.started) > timeout {
+		return true
+	}
+	return !b.noProgressSince.IsZero() && time.Since(b.noProgressSince) > timeout
+}
+
+func (g *MiningGroup) has(backend *Backend) bool {
+	for _, b := range g.Backends {
+		if b == backend {
+			return true
+		}
+	}
+	return false
+}
+
+// restart replaces a stalled backend with a new one for the same hasher.
+// The stalled chunk is canceled and its result, if it ever arrives, is ignored.
+// A hasher that doesn't return can't be stopped so its goroutine is left behind.
+func (g *MiningGroup) restart(backend *Backend) *Backend {
+	level.Error(g.logger).Log("msg", "hasher stalled, restarting it", "name", backend.Name(), "timeout", g.cfg.StallTimeout)
+	stalls.WithLabelValues(g.id, backend.Name()).Inc()
+	if g.notifier != nil {
+		g.notifier.Notify(notify.Event{
+			Component: ComponentName,
+			Severity:  notify.SeverityCritical,
+			Title:     "Miner stalled",
+			Message:   fmt.Sprintf("The hasher %v didn't check any hashes for %v and was restarted.", backend.Name(), g.cfg.StallTimeout),
+		})
+	}
+	if backend.chunk != nil {
+		backend.chunk.cancel()
+	}
+	g.remove(backend)
+	restarted := &Backend{Hasher: backend.Hasher, HashRateEstimate: rateInitialGuess}
+	g.Backends = append(g.Backends, restarted)
+	return restarted
+}
+
+// remove drops a failed backend from the group.
+func (g *MiningGroup) remove(backend *Backend) {
+	for i, b := range g.Backends {
+		if b == backend {
+			g.Backends = append(g.Backends[:i], g.Backends[i+1:]...)
+			hashRate.DeleteLabelValues(g.id, backend.Name())
+			return
+		}
+	}
+}
+
+func (g *MiningGroup) getTimeOfLastNewValue(work *Work) *big.Int {
+	if work.TimeOfLastNewValue != nil {
+		return work.TimeOfLastNewValue
+	}
 	var err error
 	var timeOfLastNewValue *big.Int
 	for {
@@ -229,10 +364,16 @@ func (g *MiningGroup) Mine(ctx context.Context, input chan *Work, output chan *R
 	timeStarted := time.Now()
 	g.LastPrinted = timeStarted
 
-	resultChannel := make(chan *backendResult, len(g.Backends)*2)
+	// Room for the CPU miners added when all other backends fail.
+	capacity := len(g.Backends) + g.cfg.NumProcessors
+
+	resultChannel := make(chan *backendResult, capacity*2)
 
 	// queue of miners waiting for work.
-	idleWorkers := make(chan *Backend, len(g.Backends))
+	idleWorkers := make(chan *Backend, capacity)
+
+	// Ranges of the failed chunks which are dispatched again to the remaining backends.
+	var failed []*backendResult
 
 	// add all available miners to the idleWorkers queue.
 	for _, b := range g.Backends {
@@ -242,8 +383,18 @@ func (g *MiningGroup) Mine(ctx context.Context, input chan *Work, output chan *R
 
 	nextHeartbeat := g.cfg.Heartbeat
 
+	// The watchdog restarts the hashers that stopped making progress.
+	var watchdog <-chan time.Time
+	if g.cfg.StallTimeout.Duration > 0 {
+		ticker := time.NewTicker(g.cfg.StallTimeout.Duration / 2)
+		defer ticker.Stop()
+		watchdog = ticker.C
+	}
+
 	var currHashSettings *HashSettings
 	var currWork *Work
+	var workStarted time.Time
+	var timeOfLastNewValue *big.Int
 
 	// Mine until context is done.
 	// Each time a hasher finishes a chunk, give it a new one to work on.
@@ -263,20 +414,70 @@ func (g *MiningGroup) Mine(ctx context.Context, input chan *Work, output chan *R
 		case work := <-input:
 			sent = 0
 			recv = 0
+			// No work pauses the mining, the results of the chunks in flight are ignored.
+			if work == nil {
+				currWork = nil
+				currHashSettings = nil
+				break
+			}
 			currWork = work
 			currHashSettings = NewHashSettings(work.Challenge, work.PublicAddr)
+			workStarted = time.Now()
+			diff, _ := new(big.Float).SetInt(work.Challenge.Difficulty).Float64()
+			difficulty.WithLabelValues(g.id).Set(diff)
+
+		case <-watchdog:
+			// The idle hashers are checked when they return their chunks.
+			for _, b := range append([]*Backend{}, g.Backends...) {
+				if b.chunk == nil || !b.stalled(g.cfg.StallTimeout.Duration) {
+					continue
+				}
+				if b.chunk.hash == currHashSettings {
+					failed = append(failed, b.chunk)
+				}
+				idleWorkers <- g.restart(b)
+			}
 
 		// Read in a result from one of the miners.
 		case result := <-resultChannel:
+			// The result of a restarted hasher.
+			if !g.has(result.backend) {
+				break
+			}
+			result.backend.chunk = nil
 			if result.err != nil {
-				level.Error(g.logger).Log("msg", "hasher failed", "err", result.err)
-				return
+				level.Error(g.logger).Log("msg", "hasher failed, removing it from the mining group", "name", result.backend.Name(), "err", result.err)
+				g.remove(result.backend)
+				if result.hash == currHashSettings {
+					failed = append(failed, result)
+				}
+				if len(g.Backends) == 0 {
+					level.Warn(g.logger).Log("msg", "no hashers left, falling back to CPU mining", "threads", g.cfg.NumProcessors)
+					for _, h := range newCpuMiners(g.cfg) {
+						b := &Backend{Hasher: h, HashRateEstimate: rateInitialGuess}
+						g.Backends = append(g.Backends, b)
+						idleWorkers <- b
+					}
+				}
+				break
+			}
+			if result.n == 0 && result.nonce == "" {
+				if result.backend.noProgressSince.IsZero() {
+					result.backend.noProgressSince = time.Now()
+				}
+			} else {
+				result.backend.noProgressSince = time.Time{}
+			}
+			if g.cfg.StallTimeout.Duration > 0 && result.backend.stalled(g.cfg.StallTimeout.Duration) {
+				idleWorkers <- g.restart(result.backend)
+			} else {
+				idleWorkers <- result.backend
 			}
-			idleWorkers <- result.backend
 
 			// Update the backend statistics no matter what.
 			result.backend.TotalHashes += result.n
 			result.backend.HashSincePrint += result.n
+			hashes.WithLabelValues(g.id, result.backend.Name()).Add(float64(result.n))
 
 			// Only update the hashRateEstimate if we didn't find a solution - otherwise the rate could be wrong
 			// due to returning early.
@@ -289,6 +490,7 @@ func (g *MiningGroup) Mine(ctx context.Context, input chan *Work, output chan *R
 					result.backend.HashRateEstimate *= 1 - memory
 					result.backend.HashRateEstimate += memory * newEst
 				}
+				hashRate.WithLabelValues(g.id, result.backend.Name()).Set(result.backend.HashRateEstimate)
 			}
 
 			// Ignore out of date results.
@@ -298,6 +500,12 @@ func (g *MiningGroup) Mine(ctx context.Context, input chan *Work, output chan *R
 
 			// Did it finish the job?
 			recv += result.n
+			if result.nonce != "" {
+				solutionTime.Observe(time.Since(workStarted).Seconds())
+				if timeOfLastNewValue != nil {
+					windowShare.Observe(time.Since(time.Unix(timeOfLastNewValue.Int64(), 0)).Seconds() / submitWindow.Seconds())
+				}
+			}
 			if result.nonce != "" || recv >= currWork.N {
 				level.Info(g.logger).Log("msg", "found solution and sending the result",
 					"challenge", fmt.Sprintf("%x", currWork.Challenge.Challenge),
@@ -317,9 +525,19 @@ func (g *MiningGroup) Mine(ctx context.Context, input chan *Work, output chan *R
 			}
 		}
 		if currWork != nil {
+			for len(failed) > 0 && len(idleWorkers) > 0 {
+				chunk := failed[0]
+				failed = failed[1:]
+				if chunk.hash != currHashSettings {
+					continue
+				}
+				worker := <-idleWorkers
+				timeOfLastNewValue = g.getTimeOfLastNewValue(currWork)
+				worker.dispatchRange(ctx, timeOfLastNewValue, currHashSettings, chunk.start, chunk.n, resultChannel)
+			}
 			for sent < currWork.N && len(idleWorkers) > 0 {
 				worker := <-idleWorkers
-				timeOfLastNewValue := g.getTimeOfLastNewValue()
+				timeOfLastNewValue = g.getTimeOfLastNewValue(currWork)
 				sent += worker.dispatchWork(ctx, timeOfLastNewValue, currHashSettings, currWork.Start+sent, resultChannel)
 			}
 		}
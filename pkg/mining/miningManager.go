@@ -6,6 +6,7 @@ package mining
 import (
 	"context"
 	"fmt"
+	"runtime"
 	"time"
 
 	"github.com/ethereum/go-ethereum/core/types"
@@ -13,28 +14,72 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/format"
 	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/notify"
 )
 
 type Config struct {
-	LogLevel  string
-	Heartbeat time.Duration
+	LogLevel      string
+	Heartbeat     time.Duration
+	NumProcessors int    `help:"Number of CPU mining threads."`
+	Kernel        string `help:"Hashing implementation of the CPU mining threads. optimized reuses the hash states between the nonces and hashes 4 nonces at a time with AVX2 when the CPU supports it, generic is the reference implementation."`
+	CPUAffinity   []int  `help:"CPUs to pin the mining threads to, assigned in order. Empty lets the OS schedule the threads. Linux only."`
+	Nice          int    `help:"Nice level of the mining threads from -20 to 19 where a higher level is a lower priority. Levels below 0 need root. Linux only."`
+	NonceRange    NonceRangeConfig
+	StallTimeout  format.Duration `help:"Restart the hashers that don't check any hashes for this long and send an alert. 0 disables the watchdog."`
+	PauseCheck    format.Duration `help:"How often to check whether a solution would be submitted. The mining pauses while it wouldn't be because of the profit threshold, the min submit period or the stake status. 0 disables the pausing."`
 }
 
 type SolutionSink interface {
 	Submit(context.Context, *Result) (*types.Transaction, error)
 }
 
-const NumProcessors = 1
+// Gate returns an error when a solution found now wouldn't be submitted.
+type Gate interface {
+	CanMine(context.Context) error
+}
+
+var (
+	paused = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "paused",
+		Help:      "The number of accounts with paused mining",
+	})
+	pausedSeconds = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "paused_seconds_total",
+		Help:      "The total time the mining of the accounts was paused",
+	})
+)
 
-func SetupMiningGroup(logger log.Logger, ctx context.Context, cfg Config, contractInstance *contracts.ITellor) (*MiningGroup, error) {
-	var hashers []Hasher
-	level.Info(logger).Log("msg", "starting CPU mining", "threads", NumProcessors)
-	for i := 0; i < NumProcessors; i++ {
-		hashers = append(hashers, NewCpuMiner(int64(i)))
+func SetupMiningGroup(logger log.Logger, ctx context.Context, cfg Config, contractInstance *contracts.ITellor, notifier *notify.Notifier) (*MiningGroup, error) {
+	if cfg.NumProcessors < 1 {
+		return nil, errors.Errorf("invalid number of mining threads:%v", cfg.NumProcessors)
+	}
+	if err := validateKernel(cfg.Kernel); err != nil {
+		return nil, err
+	}
+	if cfg.Nice < -20 || cfg.Nice > 19 {
+		return nil, errors.Errorf("invalid nice level:%v", cfg.Nice)
+	}
+	for _, cpu := range cfg.CPUAffinity {
+		if cpu < 0 || cpu >= runtime.NumCPU() {
+			return nil, errors.Errorf("invalid affinity cpu:%v, available cpus:%v", cpu, runtime.NumCPU())
+		}
+	}
+	if !threadSettingsSupported && (len(cfg.CPUAffinity) > 0 || cfg.Nice != 0) {
+		level.Warn(logger).Log("msg", "the cpu affinity and nice level are supported only on linux, ignoring")
 	}
-	miningGrp, err := NewMiningGroup(logger, ctx, cfg, hashers, contractInstance)
+
+	level.Info(logger).Log(append([]interface{}{"msg", "starting CPU mining", "threads", cfg.NumProcessors, "kernel", cfg.Kernel, "affinity", fmt.Sprintf("%v", cfg.CPUAffinity), "nice", cfg.Nice}, cpuFeatures()...)...)
+	hashers := newCpuMiners(cfg)
+	miningGrp, err := NewMiningGroup(logger, ctx, cfg, hashers, contractInstance, notifier)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating new mining group")
 	}
@@ -48,6 +93,7 @@ func SetupMiningGroup(logger log.Logger, ctx context.Context, cfg Config, contra
 // Transaction cost for submitting in each slot might be different so because of this
 // the manager needs to complete few transaction to gather the tx cost for each slot.
 type MiningMgr struct {
+	cfg              Config
 	ctx              context.Context
 	close            context.CancelFunc
 	logger           log.Logger
@@ -58,6 +104,7 @@ type MiningMgr struct {
 	contractInstance *contracts.ITellor
 	toMineInput      chan *Work
 	solutionOutput   chan *Result
+	gate             Gate
 }
 
 // NewMiningManager is the MiningMgr constructor.
@@ -69,21 +116,28 @@ func NewMiningManager(
 	taskerCh chan *Work,
 	submitterCh chan *Result,
 	client *ethclient.Client,
+	notifier *notify.Notifier,
+	gate Gate,
 ) (*MiningMgr, error) {
 
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "apply filter logger")
 	}
 	logger = log.With(logger, "component", ComponentName)
 
-	group, err := SetupMiningGroup(logger, ctx, cfg, contractInstance)
+	if err := cfg.NonceRange.validate(); err != nil {
+		return nil, err
+	}
+
+	group, err := SetupMiningGroup(logger, ctx, cfg, contractInstance, notifier)
 	if err != nil {
 		return nil, errors.Wrap(err, "setup MiningGroup")
 	}
 
 	ctx, close := context.WithCancel(ctx)
 	mng := &MiningMgr{
+		cfg:              cfg,
 		ctx:              ctx,
 		close:            close,
 		logger:           logger,
@@ -94,6 +148,7 @@ func NewMiningManager(
 		ethClient:        client,
 		toMineInput:      make(chan *Work),
 		solutionOutput:   make(chan *Result),
+		gate:             gate,
 	}
 	return mng, nil
 }
@@ -103,6 +158,23 @@ func (mgr *MiningMgr) Start() error {
 	// Start the mining group.
 	go mgr.group.Mine(mgr.ctx, mgr.toMineInput, mgr.solutionOutput)
 
+	var gateCheck <-chan time.Time
+	if mgr.gate != nil && mgr.cfg.PauseCheck.Duration > 0 {
+		ticker := time.NewTicker(mgr.cfg.PauseCheck.Duration)
+		defer ticker.Stop()
+		gateCheck = ticker.C
+	}
+	var (
+		pausedAt time.Time
+		lastWork *Work
+	)
+	defer func() {
+		if !pausedAt.IsZero() {
+			paused.Dec()
+			pausedSeconds.Add(time.Since(pausedAt).Seconds())
+		}
+	}()
+
 	for {
 		select {
 
@@ -117,12 +189,39 @@ func (mgr *MiningMgr) Start() error {
 
 		// Listen for new work from the tasker and send for mining.
 		case work := <-mgr.taskerCh:
+			work = mgr.cfg.NonceRange.assign(work)
+			lastWork = work
+			if !pausedAt.IsZero() {
+				level.Info(mgr.logger).Log("msg", "received new challenge while the mining is paused",
+					"challenge", fmt.Sprintf("%x", work.Challenge.Challenge),
+				)
+				break
+			}
 			mgr.toMineInput <- work
 			level.Info(mgr.logger).Log("msg", "sent new challenge to the mining group",
 				"challenge", fmt.Sprintf("%x", work.Challenge.Challenge),
+				"start", work.Start,
 				"difficulty", work.Challenge.Difficulty,
 				"requestIDs", fmt.Sprintf("%+v", work.Challenge.RequestIDs),
 			)
+
+		// Pause the mining while the solutions wouldn't be submitted.
+		case <-gateCheck:
+			err := mgr.gate.CanMine(mgr.ctx)
+			if err != nil && pausedAt.IsZero() {
+				level.Warn(mgr.logger).Log("msg", "pausing the mining as a solution wouldn't be submitted", "reason", err)
+				pausedAt = time.Now()
+				paused.Inc()
+				mgr.toMineInput <- nil
+			} else if err == nil && !pausedAt.IsZero() {
+				level.Info(mgr.logger).Log("msg", "resuming the mining", "paused", time.Since(pausedAt).Round(time.Second))
+				pausedSeconds.Add(time.Since(pausedAt).Seconds())
+				paused.Dec()
+				pausedAt = time.Time{}
+				if lastWork != nil {
+					mgr.toMineInput <- lastWork
+				}
+			}
 		}
 	}
 
@@ -0,0 +1,75 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package mining
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+type testGate struct {
+	closed int32
+}
+
+func (self *testGate) CanMine(context.Context) error {
+	if atomic.LoadInt32(&self.closed) == 1 {
+		return errors.New("closed")
+	}
+	return nil
+}
+
+func TestPause(t *testing.T) {
+	cfg := Config{LogLevel: "info", Heartbeat: time.Minute, NumProcessors: 1, PauseCheck: format.Duration{Duration: 20 * time.Millisecond}}
+	gate := &testGate{closed: 1}
+	taskerCh := make(chan *Work)
+	submitterCh := make(chan *Result)
+	ctx, cncl := context.WithCancel(context.Background())
+	defer cncl()
+
+	mgr, err := NewMiningManager(logging.NewLogger(), ctx, cfg, nil, taskerCh, submitterCh, nil, nil, gate)
+	testutil.Ok(t, err)
+	// The error is checked in the test goroutine as testutil can't fail the test from another one.
+	errs := make(chan error, 1)
+	go func() {
+		errs <- mgr.Start()
+	}()
+
+	// Wait for the first check to pause the mining.
+	time.Sleep(100 * time.Millisecond)
+	taskerCh <- &Work{
+		Challenge: &MiningChallenge{
+			Challenge:  make([]byte, 32),
+			Difficulty: big.NewInt(100),
+		},
+		PublicAddr:         "0x0000000000000000000000000000000000000001",
+		N:                  math.MaxInt64,
+		TimeOfLastNewValue: big.NewInt(time.Now().Unix()),
+	}
+	select {
+	case <-submitterCh:
+		t.Fatal("mined while paused")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// The last challenge is mined after resuming.
+	atomic.StoreInt32(&gate.closed, 0)
+	select {
+	case result := <-submitterCh:
+		testutil.Assert(t, result.Nonce != "", "empty solution")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no solution after resuming")
+	}
+
+	cncl()
+	testutil.Equals(t, context.Canceled, <-errs)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package mining
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// NonceRangeConfig splits the nonces between the machines mining for the same reporter.
+type NonceRangeConfig struct {
+	Count int `help:"Number of machines mining for the same reporter. The nonces are split into this many disjoint ranges. 0 or 1 doesn't split these."`
+	Index int `help:"The range of this machine from 0 to Count-1. Each machine must have a different index."`
+}
+
+func (self NonceRangeConfig) validate() error {
+	if self.Count < 0 {
+		return errors.Errorf("invalid nonce range count:%v", self.Count)
+	}
+	if self.Count > 1 && (self.Index < 0 || self.Index >= self.Count) {
+		return errors.Errorf("nonce range index:%v must be between 0 and %v", self.Index, self.Count-1)
+	}
+	return nil
+}
+
+// assign limits the work to the range of this machine.
+// The ranges don't depend on the challenge
+// so the machines don't need to coordinate.
+func (self NonceRangeConfig) assign(work *Work) *Work {
+	if self.Count <= 1 {
+		return work
+	}
+	size := math.MaxUint64 / uint64(self.Count)
+	w := *work
+	w.Start = uint64(self.Index) * size
+	w.N = size
+	return &w
+}
@@ -0,0 +1,34 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package mining
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestNonceRange(t *testing.T) {
+	work := &Work{Start: 12345, N: math.MaxInt64}
+
+	// Without splitting the work is not changed.
+	testutil.Equals(t, work, NonceRangeConfig{}.assign(work))
+
+	const count = 3
+	var end uint64
+	for i := 0; i < count; i++ {
+		cfg := NonceRangeConfig{Count: count, Index: i}
+		testutil.Ok(t, cfg.validate())
+		w := cfg.assign(work)
+		// The ranges are consecutive and don't overflow.
+		testutil.Equals(t, end, w.Start)
+		testutil.Assert(t, w.Start+w.N > w.Start, "range %v overflows", i)
+		end = w.Start + w.N
+	}
+	testutil.Equals(t, uint64(12345), work.Start)
+
+	testutil.NotOk(t, NonceRangeConfig{Count: count, Index: count}.validate())
+	testutil.NotOk(t, NonceRangeConfig{Count: -1}.validate())
+}
@@ -0,0 +1,68 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package mining
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+// stallingHasher blocks on its first range until the range is canceled.
+type stallingHasher struct {
+	*CpuMiner
+	calls int32
+}
+
+func (self *stallingHasher) CheckRange(anySolution context.Context, hash *HashSettings, start uint64, n uint64) (string, uint64, error) {
+	if atomic.AddInt32(&self.calls, 1) == 1 {
+		<-anySolution.Done()
+		return "any", n, nil
+	}
+	return self.CpuMiner.CheckRange(anySolution, hash, start, n)
+}
+
+func TestWatchdog(t *testing.T) {
+	cfg := Config{LogLevel: "info", Heartbeat: time.Minute, NumProcessors: 1, StallTimeout: format.Duration{Duration: 200 * time.Millisecond}}
+	hasher := &stallingHasher{CpuMiner: NewCpuMiner(0)}
+	group, err := NewMiningGroup(logging.NewLogger(), context.Background(), cfg, []Hasher{hasher}, nil, nil)
+	testutil.Ok(t, err)
+
+	ctx, cncl := context.WithCancel(context.Background())
+	defer cncl()
+	input := make(chan *Work)
+	output := make(chan *Result)
+	go group.Mine(ctx, input, output)
+
+	work := &Work{
+		Challenge: &MiningChallenge{
+			Challenge:  make([]byte, 32),
+			Difficulty: big.NewInt(100),
+		},
+		PublicAddr:         "0x0000000000000000000000000000000000000001",
+		N:                  math.MaxInt64,
+		TimeOfLastNewValue: big.NewInt(time.Now().Unix()),
+	}
+	input <- work
+
+	select {
+	case result := <-output:
+		// The canceled range of the stalled hasher is not accepted as a solution.
+		testutil.Assert(t, result.Nonce != "any", "the solution is from the stalled range")
+		valid, err := CheckSolution(work.Challenge, work.PublicAddr, result.Nonce, nil)
+		testutil.Ok(t, err)
+		testutil.Assert(t, valid, "invalid solution:%v", result.Nonce)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no solution after restarting the stalled hasher")
+	}
+	testutil.Equals(t, 1, len(group.Backends))
+	testutil.Assert(t, group.Backends[0].Hasher == hasher, "the hasher is not restarted")
+}
@@ -0,0 +1,74 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package notify
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tellor-io/telliot/pkg/format"
+)
+
+// DiscordWebhookEnvName is the env variable with the url of the Discord webhook.
+const DiscordWebhookEnvName = "DISCORD_WEBHOOK_URL"
+
+// discordMaxDescription is the maximum length of an embed description.
+const discordMaxDescription = 4096
+
+type DiscordConfig struct {
+	Enabled     bool            `help:"Send the notifications to the Discord webhook of the DISCORD_WEBHOOK_URL env variable."`
+	MinSeverity Severity        `help:"Send only the events with at least this severity - info, warning or critical."`
+	Components  map[string]bool `help:"Enable or disable the events of a component, for example {\"submitterTellor\": false}. The components that are not listed are enabled."`
+	RateLimit   format.Duration `help:"Send the same event at most once in this period so that a flapping check doesn't spam the channel. 0 disables the rate limiting."`
+}
+
+// Discord sends the events as embeds to a Discord webhook.
+type Discord struct {
+	url    string
+	client *http.Client
+}
+
+func NewDiscord(url string) *Discord {
+	return &Discord{
+		url:    url,
+		client: &http.Client{},
+	}
+}
+
+func (self *Discord) Send(ctx context.Context, event Event) error {
+	description := event.Message
+	if len(description) > discordMaxDescription {
+		description = description[:discordMaxDescription-3] + "..."
+	}
+	type footer struct {
+		Text string `json:"text"`
+	}
+	type embed struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Color       int    `json:"color"`
+		Timestamp   string `json:"timestamp"`
+		Footer      footer `json:"footer"`
+	}
+	return postJSON(ctx, self.client, self.url, map[string]interface{}{
+		"username": "telliot",
+		"embeds": []embed{{
+			Title:       event.Title,
+			Description: description,
+			Color:       discordColor(event.Severity),
+			Timestamp:   event.Time.UTC().Format("2006-01-02T15:04:05Z"),
+			Footer:      footer{Text: event.Component},
+		}},
+	})
+}
+
+func discordColor(severity Severity) int {
+	switch severity {
+	case SeverityWarning:
+		return 0xf2a600
+	case SeverityCritical:
+		return 0xd6312b
+	}
+	return 0x2f81f7
+}
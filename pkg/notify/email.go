@@ -0,0 +1,157 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SMTPPasswordEnvName is the env variable with the password of the SMTP user.
+const SMTPPasswordEnvName = "SMTP_PASSWORD"
+
+// TLS modes of the SMTP connection.
+const (
+	TLSNone     = "none"
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+)
+
+type EmailConfig struct {
+	Enabled     bool     `help:"Send the notifications by email."`
+	Host        string   `help:"SMTP server host."`
+	Port        int      `help:"SMTP server port, usually 587 for starttls and 465 for tls."`
+	TLS         string   `help:"Encryption of the SMTP connection - starttls, tls or none."`
+	Username    string   `help:"SMTP user with the password in the SMTP_PASSWORD env variable. Empty sends without authentication."`
+	From        string   `help:"Sender address."`
+	To          []string `help:"Recipient addresses."`
+	Subject     string   `help:"Template of the subject with the fields of the event like {{.Title}}, {{.Message}}, {{.Severity}}, {{.Component}} and {{.Time}}."`
+	Body        string   `help:"Template of the body with the same fields as the subject."`
+	MinSeverity Severity `help:"Send only the events with at least this severity - info, warning or critical."`
+}
+
+// Email sends the events as plain text emails through an SMTP server.
+type Email struct {
+	cfg      EmailConfig
+	password string
+	subject  *template.Template
+	body     *template.Template
+}
+
+func NewEmail(cfg EmailConfig, password string) (*Email, error) {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("the email notifications need a host, a sender and recipients")
+	}
+	switch cfg.TLS {
+	case TLSNone, TLSStartTLS, TLSImplicit:
+	default:
+		return nil, errors.Errorf("invalid smtp tls mode:%v", cfg.TLS)
+	}
+	subject, err := template.New("subject").Parse(cfg.Subject)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse subject template")
+	}
+	body, err := template.New("body").Parse(cfg.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse body template")
+	}
+	return &Email{
+		cfg:      cfg,
+		password: password,
+		subject:  subject,
+		body:     body,
+	}, nil
+}
+
+func (self *Email) Send(ctx context.Context, event Event) error {
+	msg, err := self.message(event)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(self.cfg.Host, strconv.Itoa(self.cfg.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return errors.Wrap(err, "dial smtp server")
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return errors.Wrap(err, "set deadline")
+		}
+	}
+	tlsCfg := &tls.Config{ServerName: self.cfg.Host}
+	if self.cfg.TLS == TLSImplicit {
+		conn = tls.Client(conn, tlsCfg)
+	}
+	c, err := smtp.NewClient(conn, self.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return errors.Wrap(err, "create smtp client")
+	}
+	defer c.Close()
+
+	if self.cfg.TLS == TLSStartTLS {
+		if err := c.StartTLS(tlsCfg); err != nil {
+			return errors.Wrap(err, "starttls")
+		}
+	}
+	if self.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", self.cfg.Username, self.password, self.cfg.Host)); err != nil {
+			return errors.Wrap(err, "authenticate")
+		}
+	}
+	if err := c.Mail(self.cfg.From); err != nil {
+		return errors.Wrap(err, "set sender")
+	}
+	for _, to := range self.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return errors.Wrapf(err, "set recipient:%v", to)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return errors.Wrap(err, "start data")
+	}
+	if _, err := w.Write(msg); err != nil {
+		return errors.Wrap(err, "write message")
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "send message")
+	}
+	return c.Quit()
+}
+
+// message renders the templates into the headers and the body of the email.
+func (self *Email) message(event Event) ([]byte, error) {
+	var subject, body bytes.Buffer
+	if err := self.subject.Execute(&subject, event); err != nil {
+		return nil, errors.Wrap(err, "execute subject template")
+	}
+	if err := self.body.Execute(&body, event); err != nil {
+		return nil, errors.Wrap(err, "execute body template")
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %v\r\n", self.cfg.From)
+	fmt.Fprintf(&msg, "To: %v\r\n", strings.Join(self.cfg.To, ", "))
+	// Line breaks in the subject would start new headers.
+	fmt.Fprintf(&msg, "Subject: %v\r\n", strings.Join(strings.Fields(subject.String()), " "))
+	fmt.Fprintf(&msg, "Date: %v\r\n", event.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+	return msg.Bytes(), nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// postJSON posts the payload as json and fails for a non 2xx response.
+func postJSON(ctx context.Context, client *http.Client, addr string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshal payload")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, "create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The url often includes a secret token so it is left out of the error.
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return errors.Wrap(err, "post")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("response status code not OK code:%v, payload:%v", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package notify
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/logging"
+)
+
+const ComponentName = "notify"
+
+// sendTimeout is the maximum time a sink can take to deliver a single event.
+const sendTimeout = 30 * time.Second
+
+// queueSize is how many events can wait for delivery before new ones are dropped.
+const queueSize = 100
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// AtLeast returns whether the severity is the same or higher than the other one.
+func (self Severity) AtLeast(other Severity) bool {
+	return self.rank() >= other.rank()
+}
+
+func (self Severity) rank() int {
+	switch self {
+	case SeverityWarning:
+		return 1
+	case SeverityCritical:
+		return 2
+	}
+	return 0
+}
+
+// Event is a notification raised by a component for the operator.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Component string    `json:"component"`
+	Severity  Severity  `json:"severity"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+}
+
+// Sink delivers events to a notification channel.
+type Sink interface {
+	Send(ctx context.Context, event Event) error
+}
+
+// SinkFunc is an adapter to allow the use of ordinary functions as sinks.
+type SinkFunc func(ctx context.Context, event Event) error
+
+func (self SinkFunc) Send(ctx context.Context, event Event) error {
+	return self(ctx, event)
+}
+
+type Config struct {
+	LogLevel string
+	Telegram TelegramConfig
+	Discord  DiscordConfig
+	Slack    SlackConfig
+	Email    EmailConfig
+}
+
+// MinSeverity wraps a sink to drop the events below the severity.
+func MinSeverity(sink Sink, severity Severity) Sink {
+	return SinkFunc(func(ctx context.Context, event Event) error {
+		if !event.Severity.AtLeast(severity) {
+			return nil
+		}
+		return sink.Send(ctx, event)
+	})
+}
+
+// Components wraps a sink to drop the events of the disabled components.
+// The components that are not in the map are enabled.
+func Components(sink Sink, enabled map[string]bool) Sink {
+	return SinkFunc(func(ctx context.Context, event Event) error {
+		if e, ok := enabled[event.Component]; ok && !e {
+			return nil
+		}
+		return sink.Send(ctx, event)
+	})
+}
+
+// ErrRateLimited is returned by a rate limited sink for the events that it drops.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimit wraps a sink to send the same event, by component and title,
+// at most once in the period.
+func RateLimit(sink Sink, period time.Duration) Sink {
+	var mtx sync.Mutex
+	last := make(map[string]time.Time)
+	return SinkFunc(func(ctx context.Context, event Event) error {
+		key := event.Component + "/" + event.Title
+		mtx.Lock()
+		if t, ok := last[key]; ok && event.Time.Sub(t) < period {
+			mtx.Unlock()
+			return ErrRateLimited
+		}
+		last[key] = event.Time
+		// Forget the events that can't be limited anymore.
+		for k, t := range last {
+			if event.Time.Sub(t) >= period {
+				delete(last, k)
+			}
+		}
+		mtx.Unlock()
+		return sink.Send(ctx, event)
+	})
+}
+
+// Notifier fans out events to all registered sinks.
+// Events are always logged so that they are visible even without any sink configured.
+type Notifier struct {
+	logger log.Logger
+	ctx    context.Context
+	close  context.CancelFunc
+	cfg    Config
+	queue  chan Event
+
+	mtx   sync.Mutex
+	names []string
+	sinks []Sink
+
+	sent    *prometheus.CounterVec
+	failed  *prometheus.CounterVec
+	limited *prometheus.CounterVec
+	dropped prometheus.Counter
+}
+
+func New(logger log.Logger, ctx context.Context, cfg Config) (*Notifier, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	logger = log.With(logger, "component", ComponentName)
+	ctx, close := context.WithCancel(ctx)
+
+	notifier := &Notifier{
+		logger: logger,
+		ctx:    ctx,
+		close:  close,
+		cfg:    cfg,
+		queue:  make(chan Event, queueSize),
+		sent: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "sent_total",
+			Help:      "The total number of notifications delivered by sink",
+		}, []string{"sink"}),
+		failed: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "failed_total",
+			Help:      "The total number of notifications that failed to be delivered by sink",
+		}, []string{"sink"}),
+		limited: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "rate_limited_total",
+			Help:      "The total number of notifications not sent because of the rate limit by sink",
+		}, []string{"sink"}),
+		dropped: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "dropped_total",
+			Help:      "The total number of notifications dropped because the queue was full",
+		}),
+	}
+
+	if cfg.Telegram.ChatID != "" {
+		token := os.Getenv(TelegramTokenEnvName)
+		if token == "" {
+			return nil, errors.Errorf("the telegram chat id is set without a bot token in the %v env variable", TelegramTokenEnvName)
+		}
+		notifier.AddSink("telegram", MinSeverity(NewTelegram(token, cfg.Telegram.ChatID), cfg.Telegram.MinSeverity))
+		level.Info(logger).Log("msg", "sending notifications to telegram", "chatID", cfg.Telegram.ChatID)
+	}
+
+	if cfg.Discord.Enabled {
+		url := os.Getenv(DiscordWebhookEnvName)
+		if url == "" {
+			return nil, errors.Errorf("discord is enabled without a webhook url in the %v env variable", DiscordWebhookEnvName)
+		}
+		var sink Sink = NewDiscord(url)
+		if cfg.Discord.RateLimit.Duration > 0 {
+			sink = RateLimit(sink, cfg.Discord.RateLimit.Duration)
+		}
+		sink = MinSeverity(Components(sink, cfg.Discord.Components), cfg.Discord.MinSeverity)
+		notifier.AddSink("discord", sink)
+		level.Info(logger).Log("msg", "sending notifications to discord")
+	}
+
+	if cfg.Slack.Enabled {
+		url := os.Getenv(SlackWebhookEnvName)
+		if url == "" {
+			return nil, errors.Errorf("slack is enabled without a webhook url in the %v env variable", SlackWebhookEnvName)
+		}
+		notifier.AddSink("slack", MinSeverity(NewSlack(url, cfg.Slack.Channel), cfg.Slack.MinSeverity))
+		level.Info(logger).Log("msg", "sending notifications to slack", "channel", cfg.Slack.Channel)
+	}
+
+	if cfg.Email.Enabled {
+		email, err := NewEmail(cfg.Email, os.Getenv(SMTPPasswordEnvName))
+		if err != nil {
+			return nil, errors.Wrap(err, "creating the email notifications")
+		}
+		notifier.AddSink("email", MinSeverity(email, cfg.Email.MinSeverity))
+		level.Info(logger).Log("msg", "sending notifications by email", "host", cfg.Email.Host, "to", strings.Join(cfg.Email.To, ","))
+	}
+
+	return notifier, nil
+}
+
+// AddSink registers a sink that receives all events raised after the call.
+func (self *Notifier) AddSink(name string, sink Sink) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.names = append(self.names, name)
+	self.sinks = append(self.sinks, sink)
+}
+
+// Notify queues an event for delivery without blocking the caller.
+func (self *Notifier) Notify(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Severity == "" {
+		event.Severity = SeverityInfo
+	}
+
+	l := level.Info
+	switch event.Severity {
+	case SeverityWarning:
+		l = level.Warn
+	case SeverityCritical:
+		l = level.Error
+	}
+	l(self.logger).Log("msg", "notification", "source", event.Component, "title", event.Title, "message", event.Message)
+
+	select {
+	case self.queue <- event:
+	default:
+		self.dropped.Inc()
+		level.Error(self.logger).Log("msg", "notification queue is full, dropping event", "title", event.Title)
+	}
+}
+
+func (self *Notifier) Start() {
+	for {
+		select {
+		case <-self.ctx.Done():
+			return
+		case event := <-self.queue:
+			self.send(event)
+		}
+	}
+}
+
+func (self *Notifier) Stop() {
+	self.close()
+}
+
+func (self *Notifier) send(event Event) {
+	self.mtx.Lock()
+	names := append([]string(nil), self.names...)
+	sinks := append([]Sink(nil), self.sinks...)
+	self.mtx.Unlock()
+
+	var wg sync.WaitGroup
+	for i := range sinks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(self.ctx, sendTimeout)
+			defer cancel()
+			if err := sinks[i].Send(ctx, event); err != nil {
+				if errors.Cause(err) == ErrRateLimited {
+					self.limited.With(prometheus.Labels{"sink": names[i]}).Inc()
+					level.Debug(self.logger).Log("msg", "notification rate limited", "sink", names[i], "title", event.Title)
+					return
+				}
+				self.failed.With(prometheus.Labels{"sink": names[i]}).Inc()
+				level.Error(self.logger).Log("msg", "sending notification", "sink", names[i], "title", event.Title, "err", err)
+				return
+			}
+			self.sent.With(prometheus.Labels{"sink": names[i]}).Inc()
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,154 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestMinSeverity(t *testing.T) {
+	var sent []string
+	sink := MinSeverity(SinkFunc(func(ctx context.Context, event Event) error {
+		sent = append(sent, event.Title)
+		return nil
+	}), SeverityWarning)
+
+	for _, e := range []Event{
+		{Title: "info", Severity: SeverityInfo},
+		{Title: "warning", Severity: SeverityWarning},
+		{Title: "critical", Severity: SeverityCritical},
+	} {
+		testutil.Ok(t, sink.Send(context.Background(), e))
+	}
+	testutil.Equals(t, []string{"warning", "critical"}, sent)
+}
+
+func TestTelegram(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testutil.Equals(t, "/botTOKEN/sendMessage", r.URL.Path)
+		testutil.Ok(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	sink := NewTelegram("TOKEN", "42")
+	sink.url = srv.URL + "/botTOKEN/sendMessage"
+	testutil.Ok(t, sink.Send(context.Background(), Event{
+		Component: "profitTracker",
+		Severity:  SeverityCritical,
+		Title:     "Account slashed",
+		Message:   "0xA was slashed 500 TRB",
+	}))
+	testutil.Equals(t, "42", got["chat_id"])
+	testutil.Equals(t, "[CRITICAL] Account slashed\n0xA was slashed 500 TRB\nprofitTracker", got["text"])
+}
+
+func TestRateLimitAndComponents(t *testing.T) {
+	var sent []string
+	sink := Components(RateLimit(SinkFunc(func(ctx context.Context, event Event) error {
+		sent = append(sent, event.Title)
+		return nil
+	}), time.Minute), map[string]bool{"submitterTellor": false, "alerting": true})
+
+	now := time.Unix(1600000000, 0)
+	send := func(component, title string, at time.Time) error {
+		return sink.Send(context.Background(), Event{Component: component, Title: title, Time: at})
+	}
+	testutil.Ok(t, send("alerting", "NodeDisconnected firing", now))
+	testutil.Equals(t, ErrRateLimited, send("alerting", "NodeDisconnected firing", now.Add(30*time.Second)))
+	testutil.Ok(t, send("alerting", "NodeDisconnected resolved", now.Add(30*time.Second)))
+	testutil.Ok(t, send("alerting", "NodeDisconnected firing", now.Add(time.Minute)))
+	testutil.Ok(t, send("submitterTellor", "Submitted", now))
+	testutil.Ok(t, send("profitTracker", "Account slashed", now))
+
+	testutil.Equals(t, []string{
+		"NodeDisconnected firing",
+		"NodeDisconnected resolved",
+		"NodeDisconnected firing",
+		"Account slashed",
+	}, sent)
+}
+
+func TestDiscord(t *testing.T) {
+	var got struct {
+		Embeds []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Color       int    `json:"color"`
+		} `json:"embeds"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testutil.Ok(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	testutil.Ok(t, NewDiscord(srv.URL).Send(context.Background(), Event{
+		Time:      time.Unix(1600000000, 0),
+		Component: "submitterTellor",
+		Severity:  SeverityWarning,
+		Title:     "Submit failed",
+		Message:   "reverted",
+	}))
+	testutil.Equals(t, 1, len(got.Embeds))
+	testutil.Equals(t, "Submit failed", got.Embeds[0].Title)
+	testutil.Equals(t, "reverted", got.Embeds[0].Description)
+	testutil.Equals(t, 0xf2a600, got.Embeds[0].Color)
+}
+
+func TestSlack(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testutil.Ok(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	testutil.Ok(t, NewSlack(srv.URL, "#telliot").Send(context.Background(), Event{
+		Time:      time.Unix(1600000000, 0),
+		Component: "profitTracker",
+		Severity:  SeverityCritical,
+		Title:     "Account slashed",
+		Message:   "0xA was slashed 500 TRB",
+	}))
+	testutil.Equals(t, "#telliot", got["channel"])
+	attachment := got["attachments"].([]interface{})[0].(map[string]interface{})
+	testutil.Equals(t, "danger", attachment["color"])
+	testutil.Equals(t, "Account slashed", attachment["title"])
+	testutil.Equals(t, float64(1600000000), attachment["ts"])
+}
+
+func TestEmailMessage(t *testing.T) {
+	email, err := NewEmail(EmailConfig{
+		Host:    "smtp.example.com",
+		TLS:     TLSStartTLS,
+		From:    "telliot@example.com",
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "[telliot] {{.Title}}",
+		Body:    "{{.Message}}\n{{.Component}}",
+	}, "")
+	testutil.Ok(t, err)
+
+	msg, err := email.message(Event{
+		Time:      time.Unix(1600000000, 0).UTC(),
+		Component: "disputeVoter",
+		Title:     "dispute\nagainst account",
+		Message:   "0xB disputed the value of 0xA",
+	})
+	testutil.Ok(t, err)
+	headers := strings.SplitN(string(msg), "\r\n\r\n", 2)
+	testutil.Assert(t, strings.Contains(headers[0], "To: a@example.com, b@example.com\r\n"), "missing recipients")
+	testutil.Assert(t, strings.Contains(headers[0], "Subject: [telliot] dispute against account\r\n"), "the subject should be a single line")
+	testutil.Equals(t, "0xB disputed the value of 0xA\r\ndisputeVoter", headers[1])
+
+	_, err = NewEmail(EmailConfig{Host: "smtp.example.com", TLS: "ssl", From: "a@example.com", To: []string{"b@example.com"}}, "")
+	testutil.NotOk(t, err)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package notify
+
+import (
+	"context"
+	"net/http"
+)
+
+// SlackWebhookEnvName is the env variable with the url of the Slack incoming webhook.
+const SlackWebhookEnvName = "SLACK_WEBHOOK_URL"
+
+type SlackConfig struct {
+	Enabled     bool     `help:"Send the notifications to the Slack incoming webhook of the SLACK_WEBHOOK_URL env variable."`
+	Channel     string   `help:"Channel like #telliot to post to instead of the default channel of the webhook."`
+	MinSeverity Severity `help:"Send only the events with at least this severity - info, warning or critical."`
+}
+
+// Slack sends the events as attachments to a Slack incoming webhook.
+type Slack struct {
+	url     string
+	channel string
+	client  *http.Client
+}
+
+func NewSlack(url, channel string) *Slack {
+	return &Slack{
+		url:     url,
+		channel: channel,
+		client:  &http.Client{},
+	}
+}
+
+func (self *Slack) Send(ctx context.Context, event Event) error {
+	type attachment struct {
+		Fallback string `json:"fallback"`
+		Color    string `json:"color"`
+		Title    string `json:"title"`
+		Text     string `json:"text"`
+		Footer   string `json:"footer"`
+		Ts       int64  `json:"ts"`
+	}
+	payload := map[string]interface{}{
+		"username": "telliot",
+		"attachments": []attachment{{
+			Fallback: event.Title + ": " + event.Message,
+			Color:    slackColor(event.Severity),
+			Title:    event.Title,
+			Text:     event.Message,
+			Footer:   event.Component,
+			Ts:       event.Time.Unix(),
+		}},
+	}
+	if self.channel != "" {
+		payload["channel"] = self.channel
+	}
+	return postJSON(ctx, self.client, self.url, payload)
+}
+
+func slackColor(severity Severity) string {
+	switch severity {
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "danger"
+	}
+	return "good"
+}
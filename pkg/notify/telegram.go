@@ -0,0 +1,48 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TelegramTokenEnvName is the env variable with the token of the Telegram bot.
+const TelegramTokenEnvName = "TELEGRAM_TOKEN"
+
+const telegramURL = "https://api.telegram.org"
+
+type TelegramConfig struct {
+	ChatID      string   `help:"Chat to message through the bot of the TELEGRAM_TOKEN env variable. Empty disables the Telegram notifications."`
+	MinSeverity Severity `help:"Send only the events with at least this severity - info, warning or critical."`
+}
+
+// Telegram sends the events as messages of a Telegram bot.
+type Telegram struct {
+	url    string
+	chatID string
+	client *http.Client
+}
+
+func NewTelegram(token, chatID string) *Telegram {
+	return &Telegram{
+		url:    telegramURL + "/bot" + token + "/sendMessage",
+		chatID: chatID,
+		client: &http.Client{},
+	}
+}
+
+func (self *Telegram) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, self.client, self.url, map[string]interface{}{
+		"chat_id":                  self.chatID,
+		"text":                     telegramText(event),
+		"disable_web_page_preview": true,
+	})
+}
+
+func telegramText(event Event) string {
+	return fmt.Sprintf("[%v] %v\n%v\n%v", strings.ToUpper(string(event.Severity)), event.Title, event.Message, event.Component)
+}
@@ -0,0 +1,401 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package pool
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/mining"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+const authTimeout = 10 * time.Second
+
+// TimeOfLastNewValueFunc returns when the last value was added to the oracle.
+type TimeOfLastNewValueFunc func(ctx context.Context) (*big.Int, error)
+
+type account struct {
+	addr  string
+	work  <-chan *mining.Work
+	sink  chan<- *mining.Result
+	index int
+}
+
+type poolWork struct {
+	id      uint64
+	account *account
+	work    *mining.Work
+}
+
+type workerConn struct {
+	transport
+	slot   int
+	name   string
+	logger log.Logger
+}
+
+// Coordinator sends the work of its accounts to the connected workers
+// and forwards the verified solutions to the submitters.
+// The workers are split between the accounts and the work of an account is split
+// into disjoint nonce ranges between its workers.
+type Coordinator struct {
+	logger             log.Logger
+	ctx                context.Context
+	stop               context.CancelFunc
+	cfg                Config
+	secret             string
+	timeOfLastNewValue TimeOfLastNewValueFunc
+	listener           net.Listener
+	grpcServer         *grpc.Server
+
+	mtx      sync.Mutex
+	accounts []*account
+	current  map[*account]*poolWork
+	works    map[uint64]*poolWork
+	workers  map[int]*workerConn
+	lastID   uint64
+}
+
+var (
+	connected = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "workers",
+		Help:      "The number of connected workers",
+	})
+	solutions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "telliot",
+		Subsystem: ComponentName,
+		Name:      "solutions_total",
+		Help:      "The total number of solutions received from the workers",
+	},
+		[]string{"result"},
+	)
+)
+
+func NewCoordinator(logger log.Logger, ctx context.Context, cfg Config, secret string, timeOfLastNewValue TimeOfLastNewValueFunc) (*Coordinator, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	if secret == "" {
+		return nil, errors.Errorf("the pool needs a secret in the %v env variable", SecretEnvName)
+	}
+	if err := validateTransport(cfg.Transport); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(cfg.ListenHost, strconv.Itoa(int(cfg.ListenPort))))
+	if err != nil {
+		return nil, errors.Wrap(err, "listening for workers")
+	}
+	ctx, stop := context.WithCancel(ctx)
+	self := &Coordinator{
+		listener:           listener,
+		logger:             log.With(logger, "component", ComponentName),
+		ctx:                ctx,
+		stop:               stop,
+		cfg:                cfg,
+		secret:             secret,
+		timeOfLastNewValue: timeOfLastNewValue,
+		current:            make(map[*account]*poolWork),
+		works:              make(map[uint64]*poolWork),
+		workers:            make(map[int]*workerConn),
+	}
+	if cfg.Transport == TransportGRPC {
+		self.grpcServer = grpc.NewServer()
+		self.grpcServer.RegisterService(&serviceDesc, self)
+	}
+	return self, nil
+}
+
+// AddAccount registers the work and the solution channels of an account.
+// All accounts must be added before starting the coordinator.
+func (self *Coordinator) AddAccount(addr string, work <-chan *mining.Work, sink chan<- *mining.Result) {
+	self.accounts = append(self.accounts, &account{addr: addr, work: work, sink: sink, index: len(self.accounts)})
+}
+
+func (self *Coordinator) Start() error {
+	if len(self.accounts) == 0 {
+		return errors.New("no accounts to mine for")
+	}
+	level.Info(self.logger).Log("msg", "listening for workers", "addr", self.listener.Addr(), "transport", self.cfg.Transport)
+
+	for _, acc := range self.accounts {
+		go self.receiveWork(acc)
+	}
+
+	if self.grpcServer != nil {
+		return errors.Wrap(self.grpcServer.Serve(self.listener), "serving workers")
+	}
+
+	for {
+		c, err := self.listener.Accept()
+		if err != nil {
+			if self.ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "accepting worker connection")
+		}
+		go func() {
+			defer c.Close()
+			self.serve(newConn(c), c.RemoteAddr().String())
+		}()
+	}
+}
+
+// serveStream serves a worker connected with gRPC until the worker or the coordinator closes the stream.
+func (self *Coordinator) serveStream(stream grpc.ServerStream) error {
+	remote := "unknown"
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		remote = p.Addr.String()
+	}
+	// The stream ends when the handler returns so closing the transport returns from the handler.
+	closed := make(chan struct{})
+	var once sync.Once
+	t := &streamConn{stream: stream, close: func() error {
+		once.Do(func() { close(closed) })
+		return nil
+	}}
+	go func() {
+		self.serve(t, remote)
+		t.Close()
+	}()
+	select {
+	case <-closed:
+	case <-stream.Context().Done():
+	}
+	return nil
+}
+
+// Addr returns the address the coordinator listens on.
+func (self *Coordinator) Addr() net.Addr {
+	return self.listener.Addr()
+}
+
+func (self *Coordinator) Stop() {
+	self.stop()
+	if self.grpcServer != nil {
+		self.grpcServer.Stop()
+	}
+	self.listener.Close()
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	for _, w := range self.workers {
+		w.Close()
+	}
+}
+
+func (self *Coordinator) receiveWork(acc *account) {
+	for {
+		select {
+		case <-self.ctx.Done():
+			return
+		case work := <-acc.work:
+			if work.TimeOfLastNewValue == nil {
+				t, err := self.timeOfLastNewValue(self.ctx)
+				if err != nil {
+					level.Error(self.logger).Log("msg", "getting time of last new value, the workers find only solutions that solve the challenge", "err", err)
+				}
+				work.TimeOfLastNewValue = t
+			}
+
+			self.mtx.Lock()
+			self.lastID++
+			pw := &poolWork{id: self.lastID, account: acc, work: work}
+			if prev, ok := self.current[acc]; ok {
+				delete(self.works, prev.id)
+			}
+			self.current[acc] = pw
+			self.works[pw.id] = pw
+			var workers []*workerConn
+			for _, w := range self.workers {
+				if self.accountOf(w) == acc {
+					workers = append(workers, w)
+				}
+			}
+			self.mtx.Unlock()
+
+			level.Info(self.logger).Log("msg", "sending new challenge to the workers",
+				"addr", acc.addr,
+				"challenge", fmt.Sprintf("%x", work.Challenge.Challenge),
+				"workers", len(workers),
+			)
+			for _, w := range workers {
+				self.sendWork(w, pw)
+			}
+		}
+	}
+}
+
+// accountOf returns the account a worker mines for.
+func (self *Coordinator) accountOf(w *workerConn) *account {
+	return self.accounts[w.slot%len(self.accounts)]
+}
+
+func (self *Coordinator) sendWork(w *workerConn, pw *poolWork) {
+	work := pw.work
+	// Each worker of the account gets its own part of the nonces.
+	size := work.N / maxWorkers
+	sub := uint64(w.slot / len(self.accounts))
+
+	params := workParams{
+		ID:         pw.id,
+		Challenge:  hex.EncodeToString(work.Challenge.Challenge),
+		Difficulty: work.Challenge.Difficulty.String(),
+		PublicAddr: work.PublicAddr,
+		Start:      work.Start + sub*size,
+		N:          size,
+	}
+	for i, id := range work.Challenge.RequestIDs {
+		if id != nil {
+			params.RequestIDs[i] = id.String()
+		}
+	}
+	if work.TimeOfLastNewValue != nil {
+		params.TimeOfLastNewValue = work.TimeOfLastNewValue.Int64()
+	}
+	if err := w.write(methodWork, params); err != nil {
+		level.Error(w.logger).Log("msg", "sending work", "err", err)
+		w.Close()
+	}
+}
+
+// serve authenticates a worker and then receives its solutions until it disconnects.
+func (self *Coordinator) serve(t transport, remote string) {
+	logger := log.With(self.logger, "remote", remote)
+	w := &workerConn{transport: t, logger: logger}
+
+	name, err := self.auth(w)
+	if err != nil {
+		level.Error(logger).Log("msg", "worker auth failed", "err", err)
+		return
+	}
+	w.name = name
+	w.logger = log.With(logger, "worker", name)
+
+	if err := self.register(w); err != nil {
+		level.Error(w.logger).Log("msg", "registering worker", "err", err)
+		return
+	}
+	defer self.unregister(w)
+
+	for {
+		var solution solutionParams
+		if err := w.read(methodSolution, &solution); err != nil {
+			if self.ctx.Err() == nil {
+				level.Info(w.logger).Log("msg", "worker disconnected", "err", err)
+			}
+			return
+		}
+		self.submit(w, solution)
+	}
+}
+
+func (self *Coordinator) auth(w *workerConn) (string, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Wrap(err, "creating salt")
+	}
+	// A worker that doesn't answer is disconnected.
+	timeout := time.AfterFunc(authTimeout, func() { w.Close() })
+	defer timeout.Stop()
+	if err := w.write(methodAuth, authRequest{Salt: hex.EncodeToString(salt)}); err != nil {
+		return "", errors.Wrap(err, "sending auth request")
+	}
+	var resp authResponse
+	if err := w.read(methodAuth, &resp); err != nil {
+		return "", errors.Wrap(err, "reading auth response")
+	}
+	if !hmac.Equal([]byte(resp.MAC), []byte(mac(self.secret, salt))) {
+		return "", errors.New("invalid secret")
+	}
+	return resp.Name, nil
+}
+
+// register assigns the first free slot to the worker and sends it the current work of its account.
+func (self *Coordinator) register(w *workerConn) error {
+	self.mtx.Lock()
+	slot := -1
+	for i := 0; i < maxWorkers*len(self.accounts); i++ {
+		if _, ok := self.workers[i]; !ok {
+			slot = i
+			break
+		}
+	}
+	if slot == -1 {
+		self.mtx.Unlock()
+		return errors.Errorf("no free slots, max workers per account:%v", maxWorkers)
+	}
+	w.slot = slot
+	self.workers[slot] = w
+	pw := self.current[self.accountOf(w)]
+	self.mtx.Unlock()
+
+	connected.Inc()
+	level.Info(w.logger).Log("msg", "worker connected", "slot", slot, "addr", self.accountOf(w).addr)
+	if pw != nil {
+		self.sendWork(w, pw)
+	}
+	return nil
+}
+
+func (self *Coordinator) unregister(w *workerConn) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	delete(self.workers, w.slot)
+	connected.Dec()
+}
+
+// submit verifies a solution before sending it to the submitter
+// so that a faulty worker can't make the account send failing transactions.
+func (self *Coordinator) submit(w *workerConn, solution solutionParams) {
+	self.mtx.Lock()
+	pw, ok := self.works[solution.ID]
+	if ok && self.current[pw.account] == pw {
+		// Only the first solution of a challenge is submitted.
+		delete(self.works, pw.id)
+	}
+	self.mtx.Unlock()
+	if !ok {
+		solutions.With(prometheus.Labels{"result": "stale"}).Inc()
+		level.Debug(w.logger).Log("msg", "ignoring solution for old work", "id", solution.ID)
+		return
+	}
+
+	valid, err := mining.CheckSolution(pw.work.Challenge, pw.work.PublicAddr, solution.Nonce, pw.work.TimeOfLastNewValue)
+	if err != nil || !valid {
+		solutions.With(prometheus.Labels{"result": "invalid"}).Inc()
+		level.Error(w.logger).Log("msg", "invalid solution from worker", "nonce", solution.Nonce, "err", err)
+		// Put the work back so that the other workers can still solve it.
+		self.mtx.Lock()
+		if self.current[pw.account] == pw {
+			self.works[pw.id] = pw
+		}
+		self.mtx.Unlock()
+		return
+	}
+
+	solutions.With(prometheus.Labels{"result": "valid"}).Inc()
+	level.Info(w.logger).Log("msg", "received solution", "addr", pw.account.addr, "nonce", solution.Nonce)
+	select {
+	case pw.account.sink <- &mining.Result{Work: pw.work, Nonce: solution.Nonce}:
+	case <-self.ctx.Done():
+	}
+}
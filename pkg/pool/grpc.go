@@ -0,0 +1,103 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// The gRPC transport sends the same messages as the TCP transport
+// in a single bidirectional stream. The messages are encoded with JSON
+// so the service is defined here without protobuf definitions.
+
+const codecName = "json"
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+const mineMethod = "/telliot.pool.Pool/Mine"
+
+type poolServer interface {
+	serveStream(grpc.ServerStream) error
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "telliot.pool.Pool",
+	HandlerType: (*poolServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "Mine",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(poolServer).serveStream(stream)
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+// streamConn is the gRPC transport.
+type streamConn struct {
+	stream grpc.Stream
+	mtx    sync.Mutex
+	close  func() error
+}
+
+func (self *streamConn) write(method string, params interface{}) error {
+	msg, err := newMessage(method, params)
+	if err != nil {
+		return err
+	}
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return self.stream.SendMsg(msg)
+}
+
+func (self *streamConn) read(method string, params interface{}) error {
+	var msg message
+	if err := self.stream.RecvMsg(&msg); err != nil {
+		return err
+	}
+	return msg.decode(method, params)
+}
+
+func (self *streamConn) Close() error {
+	return self.close()
+}
+
+// dialGRPC opens the stream to the coordinator.
+// Closing the transport closes the client connection which ends the stream.
+func dialGRPC(ctx context.Context, addr string) (transport, error) {
+	cc, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)))
+	if err != nil {
+		return nil, errors.Wrap(err, "dialing coordinator")
+	}
+	stream, err := cc.NewStream(ctx, &serviceDesc.Streams[0], mineMethod)
+	if err != nil {
+		cc.Close()
+		return nil, errors.Wrap(err, "opening stream")
+	}
+	return &streamConn{stream: stream, close: cc.Close}, nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+// Package pool lets remote workers mine for the accounts of a coordinator
+// which keeps the private keys and submits the solutions.
+//
+// The coordinator and the workers exchange JSON messages as newline delimited lines over TCP
+// or over a bidirectional gRPC stream.
+// The coordinator starts with an auth message with a random salt and the worker
+// answers with the HMAC-SHA256 of the salt with the shared secret.
+// Then the coordinator sends work messages and the worker answers with solution messages.
+// The messages don't include anything secret and a solution is valid only
+// for the address of the coordinator account so the connection is not encrypted.
+package pool
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ComponentName = "pool"
+	// SecretEnvName is the env variable with the secret shared by the coordinator and its workers.
+	SecretEnvName = "POOL_SECRET"
+
+	methodAuth     = "auth"
+	methodWork     = "work"
+	methodSolution = "solution"
+
+	// maxWorkers is the number of nonce ranges the work of each account is split into.
+	maxWorkers = 256
+
+	TransportTCP  = "tcp"
+	TransportGRPC = "grpc"
+)
+
+type Config struct {
+	LogLevel   string
+	Enabled    bool   `help:"Send the challenges to remote workers started with the worker command instead of mining locally."`
+	ListenHost string `help:"Host to listen on for the workers."`
+	ListenPort uint   `help:"Port to listen on for the workers."`
+	Transport  string `help:"Protocol of the workers, tcp for JSON lines over TCP or grpc for a gRPC stream. The workers must use the same."`
+}
+
+func validateTransport(transport string) error {
+	switch transport {
+	case TransportTCP, TransportGRPC:
+		return nil
+	}
+	return errors.Errorf("invalid pool transport:%v, supported:%v, %v", transport, TransportTCP, TransportGRPC)
+}
+
+type message struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type authRequest struct {
+	Salt string `json:"salt"`
+}
+
+type authResponse struct {
+	Name string `json:"name"`
+	MAC  string `json:"mac"`
+}
+
+type workParams struct {
+	ID                 uint64    `json:"id"`
+	Challenge          string    `json:"challenge"`
+	Difficulty         string    `json:"difficulty"`
+	RequestIDs         [5]string `json:"requestIDs"`
+	PublicAddr         string    `json:"publicAddr"`
+	Start              uint64    `json:"start"`
+	N                  uint64    `json:"n"`
+	TimeOfLastNewValue int64     `json:"timeOfLastNewValue"`
+}
+
+type solutionParams struct {
+	ID    uint64 `json:"id"`
+	Nonce string `json:"nonce"`
+}
+
+func mac(secret string, salt []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(salt)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// transport reads and writes the messages between the coordinator and a worker.
+// The writes are safe for concurrent use.
+type transport interface {
+	write(method string, params interface{}) error
+	// read returns the next message and decodes its params when it has the expected method.
+	read(method string, params interface{}) error
+	Close() error
+}
+
+func newMessage(method string, params interface{}) (*message, error) {
+	p, err := json.Marshal(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding params")
+	}
+	return &message{Method: method, Params: p}, nil
+}
+
+func (self *message) decode(method string, params interface{}) error {
+	if self.Method != method {
+		return errors.Errorf("unexpected message method:%v, expected:%v", self.Method, method)
+	}
+	return errors.Wrapf(json.Unmarshal(self.Params, params), "decoding %v params", method)
+}
+
+// conn is the TCP transport with a message per line.
+type conn struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+	mtx     sync.Mutex
+}
+
+func newConn(c net.Conn) *conn {
+	return &conn{conn: c, scanner: bufio.NewScanner(c)}
+}
+
+func (self *conn) write(method string, params interface{}) error {
+	m, err := newMessage(method, params)
+	if err != nil {
+		return err
+	}
+	msg, err := json.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, "encoding message")
+	}
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	_, err = self.conn.Write(append(msg, '\n'))
+	return err
+}
+
+func (self *conn) read(method string, params interface{}) error {
+	if !self.scanner.Scan() {
+		if err := self.scanner.Err(); err != nil {
+			return err
+		}
+		return errors.New("connection closed")
+	}
+	var msg message
+	if err := json.Unmarshal(self.scanner.Bytes(), &msg); err != nil {
+		return errors.Wrap(err, "decoding message")
+	}
+	return msg.decode(method, params)
+}
+
+func (self *conn) Close() error {
+	return self.conn.Close()
+}
@@ -0,0 +1,161 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package pool
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/mining"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestPool(t *testing.T) {
+	for _, transport := range []string{TransportTCP, TransportGRPC} {
+		t.Run(transport, func(t *testing.T) {
+			testPool(t, transport)
+		})
+	}
+}
+
+func testPool(t *testing.T, transport string) {
+	const secret = "secret"
+	logger := logging.NewLogger()
+	ctx, cncl := context.WithCancel(context.Background())
+	defer cncl()
+
+	coordinator, err := NewCoordinator(logger, ctx, Config{LogLevel: "info", ListenHost: "127.0.0.1", Transport: transport}, secret, nil)
+	testutil.Ok(t, err)
+	workCh := make(chan *mining.Work)
+	solutionCh := make(chan *mining.Result)
+	coordinator.AddAccount("0x0000000000000000000000000000000000000001", workCh, solutionCh)
+	// The goroutines send their errors to the test as testutil can't fail the test outside of its goroutine.
+	coordinatorErr := make(chan error, 1)
+	go func() {
+		coordinatorErr <- coordinator.Start()
+	}()
+	defer func() {
+		coordinator.Stop()
+		testutil.Ok(t, <-coordinatorErr)
+	}()
+
+	// A worker with a wrong secret is disconnected.
+	w := &Worker{ctx: ctx, coordinator: coordinator.Addr().String(), transport: transport}
+	conn, err := w.dial()
+	testutil.Ok(t, err)
+	var req authRequest
+	testutil.Ok(t, conn.read(methodAuth, &req))
+	testutil.Ok(t, conn.write(methodAuth, authResponse{Name: "bad", MAC: mac("wrong", []byte(req.Salt))}))
+	testutil.NotOk(t, conn.read(methodWork, &workParams{}))
+	conn.Close()
+
+	worker, err := NewWorker(logger, ctx, mining.Config{LogLevel: "info", Heartbeat: time.Minute, NumProcessors: 1}, coordinator.Addr().String(), transport, "test", secret)
+	testutil.Ok(t, err)
+	workerErr := make(chan error, 1)
+	go func() {
+		workerErr <- worker.Start()
+	}()
+	defer func() {
+		worker.Stop()
+		testutil.Ok(t, <-workerErr)
+	}()
+
+	work := &mining.Work{
+		Challenge: &mining.MiningChallenge{
+			Challenge:  make([]byte, 32),
+			Difficulty: big.NewInt(100),
+			RequestIDs: [5]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4), big.NewInt(5)},
+		},
+		PublicAddr:         "0x0000000000000000000000000000000000000001",
+		Start:              0,
+		N:                  math.MaxInt64,
+		TimeOfLastNewValue: big.NewInt(time.Now().Unix()),
+	}
+	// Wait for the worker to connect.
+	for i := 0; ; i++ {
+		coordinator.mtx.Lock()
+		connected := len(coordinator.workers)
+		coordinator.mtx.Unlock()
+		if connected == 1 {
+			break
+		}
+		testutil.Assert(t, i < 100, "worker not connected")
+		time.Sleep(100 * time.Millisecond)
+	}
+	workCh <- work
+
+	select {
+	case result := <-solutionCh:
+		testutil.Equals(t, work, result.Work)
+		valid, err := mining.CheckSolution(work.Challenge, work.PublicAddr, result.Nonce, nil)
+		testutil.Ok(t, err)
+		testutil.Assert(t, valid, "invalid solution:%v", result.Nonce)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no solution received")
+	}
+}
+
+func TestWorkerReconnect(t *testing.T) {
+	ctx, cncl := context.WithCancel(context.Background())
+	defer cncl()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.Ok(t, err)
+	defer l.Close()
+
+	// The coordinator sends an invalid work and another one right after it
+	// so that the reader of the worker holds the second one when the worker disconnects.
+	coordinatorErr := make(chan error, 1)
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn := newConn(c)
+			if err := conn.write(methodAuth, authRequest{Salt: "00"}); err != nil {
+				coordinatorErr <- err
+				return
+			}
+			var resp authResponse
+			if err := conn.read(methodAuth, &resp); err != nil {
+				coordinatorErr <- err
+				return
+			}
+			for _, challenge := range []string{"invalid", "00"} {
+				if err := conn.write(methodWork, workParams{Challenge: challenge, Difficulty: "100"}); err != nil {
+					coordinatorErr <- err
+					return
+				}
+			}
+			// Keep the connection open so that only the worker disconnects.
+			defer conn.Close()
+		}
+	}()
+
+	worker := &Worker{logger: log.NewNopLogger(), ctx: ctx, coordinator: l.Addr().String(), transport: TransportTCP, name: "test", secret: "secret"}
+	for i := 0; i < 3; i++ {
+		// run waits for its reader so it returns only when the reader has exited.
+		errs := make(chan error, 1)
+		go func() {
+			errs <- worker.run(make(chan *mining.Work), make(chan *mining.Result))
+		}()
+		select {
+		case err := <-errs:
+			testutil.NotOk(t, err)
+			testutil.Assert(t, strings.Contains(err.Error(), "decoding work"), "unexpected error:%v", err)
+		case err := <-coordinatorErr:
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the reader of the connection didn't exit")
+		}
+	}
+}
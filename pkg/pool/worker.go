@@ -0,0 +1,230 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package pool
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/mining"
+)
+
+const reconnectDelay = 5 * time.Second
+
+// Worker mines the work received from a coordinator with the local hashers
+// and sends back the solutions. It reconnects when the connection is lost.
+type Worker struct {
+	logger      log.Logger
+	ctx         context.Context
+	stop        context.CancelFunc
+	coordinator string
+	transport   string
+	name        string
+	secret      string
+	group       *mining.MiningGroup
+}
+
+func NewWorker(logger log.Logger, ctx context.Context, cfg mining.Config, coordinator, transport, name, secret string) (*Worker, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	if secret == "" {
+		return nil, errors.Errorf("the worker needs the secret of the pool in the %v env variable", SecretEnvName)
+	}
+	if err := validateTransport(transport); err != nil {
+		return nil, err
+	}
+	logger = log.With(logger, "component", ComponentName)
+
+	// The work includes the time of the last new value so the group doesn't need the contract.
+	group, err := mining.SetupMiningGroup(logger, ctx, cfg, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "setup MiningGroup")
+	}
+	ctx, stop := context.WithCancel(ctx)
+	return &Worker{
+		logger:      logger,
+		ctx:         ctx,
+		stop:        stop,
+		coordinator: coordinator,
+		transport:   transport,
+		name:        name,
+		secret:      secret,
+		group:       group,
+	}, nil
+}
+
+func (self *Worker) Start() error {
+	input := make(chan *mining.Work)
+	output := make(chan *mining.Result)
+	go self.group.Mine(self.ctx, input, output)
+
+	for {
+		err := self.run(input, output)
+		if self.ctx.Err() != nil {
+			return nil
+		}
+		level.Error(self.logger).Log("msg", "coordinator connection failed, reconnecting", "delay", reconnectDelay, "err", err)
+		select {
+		case <-self.ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (self *Worker) Stop() {
+	self.stop()
+}
+
+func (self *Worker) run(input chan<- *mining.Work, output <-chan *mining.Result) error {
+	conn, err := self.dial()
+	if err != nil {
+		return err
+	}
+	// The reader is waited for after closing the connection
+	// so that it doesn't outlive the connection and leak on every reconnect.
+	var reader sync.WaitGroup
+	defer reader.Wait()
+	defer conn.Close()
+	// Unblock the reads when stopping.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-self.ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	var req authRequest
+	if err := conn.read(methodAuth, &req); err != nil {
+		return errors.Wrap(err, "reading auth request")
+	}
+	salt, err := hex.DecodeString(req.Salt)
+	if err != nil {
+		return errors.Wrap(err, "decoding salt")
+	}
+	if err := conn.write(methodAuth, authResponse{Name: self.name, MAC: mac(self.secret, salt)}); err != nil {
+		return errors.Wrap(err, "sending auth response")
+	}
+	level.Info(self.logger).Log("msg", "connected to coordinator", "addr", self.coordinator)
+
+	works := make(chan workParams)
+	errs := make(chan error, 1)
+	reader.Add(1)
+	go func() {
+		defer reader.Done()
+		for {
+			var params workParams
+			if err := conn.read(methodWork, &params); err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case works <- params:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var (
+		pending *mining.Work
+		id      uint64
+		current *mining.Work
+	)
+	for {
+		// The group sends the results and receives the work from the same loop
+		// so the work is sent only when the group is ready without blocking the results.
+		var send chan<- *mining.Work
+		if pending != nil {
+			send = input
+		}
+		select {
+		case <-self.ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case params := <-works:
+			work, err := params.work()
+			if err != nil {
+				return errors.Wrap(err, "decoding work")
+			}
+			level.Info(self.logger).Log("msg", "received new challenge", "id", params.ID, "challenge", params.Challenge)
+			pending = work
+			id = params.ID
+		case send <- pending:
+			current = pending
+			pending = nil
+		case result := <-output:
+			// Only the solution of the last work is sent.
+			// The group sends an empty nonce when it checked the whole range without a solution.
+			if result.Work != current || pending != nil || result.Nonce == "" {
+				continue
+			}
+			level.Info(self.logger).Log("msg", "sending solution", "id", id, "nonce", result.Nonce)
+			if err := conn.write(methodSolution, solutionParams{ID: id, Nonce: result.Nonce}); err != nil {
+				return errors.Wrap(err, "sending solution")
+			}
+		}
+	}
+}
+
+func (self *Worker) dial() (transport, error) {
+	if self.transport == TransportGRPC {
+		return dialGRPC(self.ctx, self.coordinator)
+	}
+	var d net.Dialer
+	c, err := d.DialContext(self.ctx, "tcp", self.coordinator)
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to coordinator")
+	}
+	return newConn(c), nil
+}
+
+func (self workParams) work() (*mining.Work, error) {
+	challenge, err := hex.DecodeString(self.Challenge)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding challenge")
+	}
+	difficulty, ok := new(big.Int).SetString(self.Difficulty, 10)
+	if !ok || difficulty.Sign() <= 0 {
+		return nil, errors.Errorf("invalid difficulty:%v", self.Difficulty)
+	}
+	var requestIDs [5]*big.Int
+	for i, id := range self.RequestIDs {
+		requestIDs[i], ok = new(big.Int).SetString(id, 10)
+		if !ok {
+			requestIDs[i] = big.NewInt(0)
+		}
+	}
+	// Without the time the work can't be solved with any nonce
+	// after the submit window so it waits the whole window from now.
+	timeOfLastNewValue := self.TimeOfLastNewValue
+	if timeOfLastNewValue == 0 {
+		timeOfLastNewValue = time.Now().Unix()
+	}
+	return &mining.Work{
+		Challenge: &mining.MiningChallenge{
+			Challenge:  challenge,
+			Difficulty: difficulty,
+			RequestIDs: requestIDs,
+		},
+		PublicAddr:         self.PublicAddr,
+		Start:              self.Start,
+		N:                  self.N,
+		TimeOfLastNewValue: big.NewInt(timeOfLastNewValue),
+	}, nil
+}
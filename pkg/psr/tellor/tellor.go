@@ -36,6 +36,113 @@ type Psr struct {
 	cfg        Config
 }
 
+// request is how the value of a request id is calculated.
+type request struct {
+	// symbol is empty for the request ids with only manual values.
+	symbol string
+	value  func(a *aggregator.Aggregator, symbol string, ts time.Time) (float64, float64, error)
+}
+
+func median(a *aggregator.Aggregator, symbol string, ts time.Time) (float64, float64, error) {
+	return a.MedianAt(symbol, ts)
+}
+
+func mean(a *aggregator.Aggregator, symbol string, ts time.Time) (float64, float64, error) {
+	return a.MeanAt(symbol, ts)
+}
+
+func medianEOD(a *aggregator.Aggregator, symbol string, ts time.Time) (float64, float64, error) {
+	return a.MedianAtEOD(symbol, ts)
+}
+
+func timeWeightedAvg(period time.Duration) func(a *aggregator.Aggregator, symbol string, ts time.Time) (float64, float64, error) {
+	return func(a *aggregator.Aggregator, symbol string, ts time.Time) (float64, float64, error) {
+		return a.TimeWeightedAvg(symbol, ts, period)
+	}
+}
+
+// volumeWeightedAvg24h is the volume weighted average of the last 24h.
+// For more details see https://docs.google.com/document/d/1RFCApk1PznMhSRVhiyFl_vBDPA4mP2n1dTmfqjvuTNw/edit
+func volumeWeightedAvg24h(a *aggregator.Aggregator, symbol string, ts time.Time) (float64, float64, error) {
+	return a.VolumWeightedAvg(symbol, time.Now().Add(-(24 * time.Hour)), time.Now(), 10*time.Minute)
+}
+
+// requests are the symbols and the calculations of the values of all request ids.
+var requests = map[int64]request{
+	1:  {"ETH/USD", median},
+	2:  {"BTC/USD", median},
+	3:  {"BNB/USD", median},
+	4:  {"BTC/USD", timeWeightedAvg(24 * time.Hour)},
+	5:  {"ETH/BTC", median},
+	6:  {"BNB/BTC", median},
+	7:  {"BNB/ETH", median},
+	8:  {"ETH/USD", timeWeightedAvg(24 * time.Hour)},
+	9:  {"ETH/USD", medianEOD},
+	10: {"AMPL/USD", volumeWeightedAvg24h},
+	11: {"ZEC/ETH", median},
+	12: {"TRX/ETH", median},
+	13: {"XRP/USD", median},
+	14: {"XMR/ETH", median},
+	15: {"ATOM/USD", median},
+	16: {"LTC/USD", median},
+	17: {"WAVES/BTC", median},
+	18: {"REP/BTC", median},
+	19: {"TUSD/ETH", median},
+	20: {"EOS/USD", median},
+	21: {"IOTA/USD", median},
+	22: {"ETC/USD", median},
+	23: {"ETH/PAX", median},
+	24: {"ETH/BTC", timeWeightedAvg(time.Hour)},
+	25: {"USDC/USDT", median},
+	26: {"XTZ/USD", median},
+	27: {"LINK/USD", median},
+	28: {"ZRX/BNB", median},
+	29: {"ZEC/USD", median},
+	30: {"XAU/USD", median},
+	31: {"MATIC/USD", median},
+	32: {"BAT/USD", median},
+	33: {"ALGO/USD", median},
+	34: {"ZRX/USD", median},
+	35: {"COS/USD", median},
+	36: {"BCH/USD", median},
+	37: {"REP/USD", median},
+	38: {"GNO/USD", median},
+	39: {"DAI/USD", median},
+	40: {"STEEM/BTC", median},
+	// ID 41 is always manual.
+	// It is three month average for US PCE (monthly levels): https://www.bea.gov/data/personal-consumption-expenditures-price-index-excluding-food-and-energy
+	41: {},
+	42: {"BTC/USD", medianEOD},
+	43: {"TRB/ETH", median},
+	44: {"BTC/USD", timeWeightedAvg(time.Hour)},
+	45: {"TRB/USD", medianEOD},
+	46: {"ETH/USD", timeWeightedAvg(time.Hour)},
+	47: {"BSV/USD", median},
+	48: {"MAKER/USD", median},
+	49: {"BCH/USD", timeWeightedAvg(24 * time.Hour)},
+	50: {"TRB/USD", median},
+	51: {"XMR/USD", median},
+	52: {"XFT/USD", median},
+	53: {"BTCDOMINANCE", median},
+	54: {"WAVES/USD", median},
+	55: {"OGN/USD", median},
+	56: {"VIXEOD", median},
+	57: {"DEFITVL", median},
+	58: {"DEFIMCAP", mean},
+}
+
+// Symbol returns the symbol used to calculate the value of a request id.
+func Symbol(reqID int64) (string, error) {
+	req, ok := requests[reqID]
+	if !ok {
+		return "", errors.Errorf("no symbol for request ID:%v", reqID)
+	}
+	if req.symbol == "" {
+		return "", errors.Errorf("request ID:%v has only manual values", reqID)
+	}
+	return req.symbol, nil
+}
+
 func (self *Psr) GetValue(reqID int64, ts time.Time) (int64, error) {
 	val, err := self.getValue(reqID, ts)
 	return int64(math.Round(val * DefaultGranularity)), err
@@ -51,130 +158,14 @@ func (self *Psr) getValue(reqID int64, ts time.Time) (float64, error) {
 		return val, nil
 	}
 
-	var conf float64
-	switch reqID {
-	case 1:
-		val, conf, err = self.aggregator.MedianAt("ETH/USD", ts)
-	case 2:
-		val, conf, err = self.aggregator.MedianAt("BTC/USD", ts)
-	case 3:
-		val, conf, err = self.aggregator.MedianAt("BNB/USD", ts)
-	case 4:
-		val, conf, err = self.aggregator.TimeWeightedAvg("BTC/USD", ts, 24*time.Hour)
-	case 5:
-		val, conf, err = self.aggregator.MedianAt("ETH/BTC", ts)
-	case 6:
-		val, conf, err = self.aggregator.MedianAt("BNB/BTC", ts)
-	case 7:
-		val, conf, err = self.aggregator.MedianAt("BNB/ETH", ts)
-	case 8:
-		val, conf, err = self.aggregator.TimeWeightedAvg("ETH/USD", ts, 24*time.Hour)
-	case 9:
-		val, conf, err = self.aggregator.MedianAtEOD("ETH/USD", ts)
-	case 10: // For more details see https://docs.google.com/document/d/1RFCApk1PznMhSRVhiyFl_vBDPA4mP2n1dTmfqjvuTNw/edit
-		val, conf, err = self.aggregator.VolumWeightedAvg("AMPL/USD", time.Now().Add(-(24 * time.Hour)), time.Now(), 10*time.Minute)
-	case 11:
-		val, conf, err = self.aggregator.MedianAt("ZEC/ETH", ts)
-	case 12:
-		val, conf, err = self.aggregator.MedianAt("TRX/ETH", ts)
-	case 13:
-		val, conf, err = self.aggregator.MedianAt("XRP/USD", ts)
-	case 14:
-		val, conf, err = self.aggregator.MedianAt("XMR/ETH", ts)
-	case 15:
-		val, conf, err = self.aggregator.MedianAt("ATOM/USD", ts)
-	case 16:
-		val, conf, err = self.aggregator.MedianAt("LTC/USD", ts)
-	case 17:
-		val, conf, err = self.aggregator.MedianAt("WAVES/BTC", ts)
-	case 18:
-		val, conf, err = self.aggregator.MedianAt("REP/BTC", ts)
-	case 19:
-		val, conf, err = self.aggregator.MedianAt("TUSD/ETH", ts)
-	case 20:
-		val, conf, err = self.aggregator.MedianAt("EOS/USD", ts)
-	case 21:
-		val, conf, err = self.aggregator.MedianAt("IOTA/USD", ts)
-	case 22:
-		val, conf, err = self.aggregator.MedianAt("ETC/USD", ts)
-	case 23:
-		val, conf, err = self.aggregator.MedianAt("ETH/PAX", ts)
-	case 24:
-		val, conf, err = self.aggregator.TimeWeightedAvg("ETH/BTC", ts, time.Hour)
-	case 25:
-		val, conf, err = self.aggregator.MedianAt("USDC/USDT", ts)
-	case 26:
-		val, conf, err = self.aggregator.MedianAt("XTZ/USD", ts)
-	case 27:
-		val, conf, err = self.aggregator.MedianAt("LINK/USD", ts)
-	case 28:
-		val, conf, err = self.aggregator.MedianAt("ZRX/BNB", ts)
-	case 29:
-		val, conf, err = self.aggregator.MedianAt("ZEC/USD", ts)
-	case 30:
-		val, conf, err = self.aggregator.MedianAt("XAU/USD", ts)
-	case 31:
-		val, conf, err = self.aggregator.MedianAt("MATIC/USD", ts)
-	case 32:
-		val, conf, err = self.aggregator.MedianAt("BAT/USD", ts)
-	case 33:
-		val, conf, err = self.aggregator.MedianAt("ALGO/USD", ts)
-	case 34:
-		val, conf, err = self.aggregator.MedianAt("ZRX/USD", ts)
-	case 35:
-		val, conf, err = self.aggregator.MedianAt("COS/USD", ts)
-	case 36:
-		val, conf, err = self.aggregator.MedianAt("BCH/USD", ts)
-	case 37:
-		val, conf, err = self.aggregator.MedianAt("REP/USD", ts)
-	case 38:
-		val, conf, err = self.aggregator.MedianAt("GNO/USD", ts)
-	case 39:
-		val, conf, err = self.aggregator.MedianAt("DAI/USD", ts)
-	case 40:
-		val, conf, err = self.aggregator.MedianAt("STEEM/BTC", ts)
-	case 41:
-		// ID 41 is always manual so it sholud never get here.
-		// It is three month average for US PCE (monthly levels): https://www.bea.gov/data/personal-consumption-expenditures-price-index-excluding-food-and-energy
-		return 0, errors.New("no manual entry for request ID 41")
-	case 42:
-		val, conf, err = self.aggregator.MedianAtEOD("BTC/USD", ts)
-	case 43:
-		val, conf, err = self.aggregator.MedianAt("TRB/ETH", ts)
-	case 44:
-		val, conf, err = self.aggregator.TimeWeightedAvg("BTC/USD", ts, time.Hour)
-	case 45:
-		val, conf, err = self.aggregator.MedianAtEOD("TRB/USD", ts)
-	case 46:
-		val, conf, err = self.aggregator.TimeWeightedAvg("ETH/USD", ts, time.Hour)
-	case 47:
-		val, conf, err = self.aggregator.MedianAt("BSV/USD", ts)
-	case 48:
-		val, conf, err = self.aggregator.MedianAt("MAKER/USD", ts)
-	case 49:
-		val, conf, err = self.aggregator.TimeWeightedAvg("BCH/USD", ts, 24*time.Hour)
-	case 50:
-		val, conf, err = self.aggregator.MedianAt("TRB/USD", ts)
-	case 51:
-		val, conf, err = self.aggregator.MedianAt("XMR/USD", ts)
-	case 52:
-		val, conf, err = self.aggregator.MedianAt("XFT/USD", ts)
-	case 53:
-		val, conf, err = self.aggregator.MedianAt("BTCDOMINANCE", ts)
-	case 54:
-		val, conf, err = self.aggregator.MedianAt("WAVES/USD", ts)
-	case 55:
-		val, conf, err = self.aggregator.MedianAt("OGN/USD", ts)
-	case 56:
-		val, conf, err = self.aggregator.MedianAt("VIXEOD", ts)
-	case 57:
-		val, conf, err = self.aggregator.MedianAt("DEFITVL", ts)
-	case 58:
-		val, conf, err = self.aggregator.MeanAt("DEFIMCAP", ts)
-	default:
+	req, ok := requests[reqID]
+	if !ok {
 		return 0, errors.Errorf("undeclared request ID:%v", reqID)
 	}
-
+	if req.value == nil {
+		return 0, errors.Errorf("no manual entry for request ID:%v", reqID)
+	}
+	val, conf, err := req.value(self.aggregator, req.symbol, ts)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,25 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package tellor
+
+import (
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestSymbol(t *testing.T) {
+	symbol, err := Symbol(42)
+	testutil.Ok(t, err)
+	testutil.Equals(t, "BTC/USD", symbol)
+
+	_, err = Symbol(41)
+	testutil.NotOk(t, err, "the manual request ids have no symbol")
+	_, err = Symbol(59)
+	testutil.NotOk(t, err)
+
+	for id, req := range requests {
+		testutil.Assert(t, (req.symbol == "") == (req.value == nil), "request ID:%v should have both a symbol and a value or none", id)
+	}
+}
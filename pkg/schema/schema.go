@@ -0,0 +1,299 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+// Package schema checks JSON files against the Go types they are decoded into
+// so that all problems are reported at once with the path of the value and an example.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Problem is a value that doesn't match the schema.
+type Problem struct {
+	Path     string
+	Expected string
+	Example  string
+	Got      string
+	// Unknown problems are for keys without a matching field.
+	// These are ignored when decoding so are only warnings.
+	Unknown bool
+}
+
+func (self Problem) String() string {
+	if self.Unknown {
+		return fmt.Sprintf("%v: unknown field, %v", self.Path, self.Expected)
+	}
+	msg := fmt.Sprintf("%v: expected %v", self.Path, self.Expected)
+	if self.Example != "" {
+		msg += ", for example " + self.Example
+	}
+	if self.Got != "" {
+		msg += ", got " + self.Got
+	}
+	return msg
+}
+
+type Problems []Problem
+
+// Errors returns the problems that fail the decoding.
+func (self Problems) Errors() Problems {
+	var errs Problems
+	for _, p := range self {
+		if !p.Unknown {
+			errs = append(errs, p)
+		}
+	}
+	return errs
+}
+
+// Warnings returns the unknown fields.
+func (self Problems) Warnings() Problems {
+	var warns Problems
+	for _, p := range self {
+		if p.Unknown {
+			warns = append(warns, p)
+		}
+	}
+	return warns
+}
+
+// Err returns an error listing all errors or nil when there are none.
+func (self Problems) Err() error {
+	errs := self.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(errs))
+	for _, p := range errs {
+		lines = append(lines, "  "+p.String())
+	}
+	return errors.Errorf("%v problems found:\n%v", len(errs), strings.Join(lines, "\n"))
+}
+
+// Validate checks the JSON data against the type of v.
+// The values of v are used as the examples so v is best set to the defaults.
+func Validate(data []byte, v interface{}) (Problems, error) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "invalid json")
+	}
+	var problems Problems
+	validate(doc, reflect.ValueOf(v), "", &problems)
+	return problems, nil
+}
+
+func validate(doc interface{}, v reflect.Value, path string, problems *Problems) {
+	for v.Kind() == reflect.Ptr {
+		if doc == nil {
+			return
+		}
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+	if doc == nil {
+		// A null resets the value which is valid for all types.
+		return
+	}
+	problem := func(expected, example string) {
+		*problems = append(*problems, Problem{Path: root(path), Expected: expected, Example: example, Got: got(doc)})
+	}
+
+	t := v.Type()
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		b, _ := json.Marshal(doc)
+		if err := json.Unmarshal(b, reflect.New(t).Interface()); err != nil {
+			problem(typeName(t), example(v))
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			problem("an object", "")
+			return
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			i, ok := fieldIndex(t, k)
+			if !ok {
+				*problems = append(*problems, Problem{Path: join(path, k), Expected: "known fields are " + strings.Join(fieldNames(t), ", "), Unknown: true})
+				continue
+			}
+			// The path uses the key as written in the file so it is easy to find.
+			validate(obj[k], v.Field(i), join(path, k), problems)
+		}
+	case reflect.Map:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			problem("an object", example(v))
+			return
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		elem := reflect.New(t.Elem()).Elem()
+		for _, k := range keys {
+			validate(obj[k], elem, join(path, k), problems)
+		}
+	case reflect.Slice, reflect.Array:
+		list, ok := doc.([]interface{})
+		if !ok {
+			problem("a list", example(v))
+			return
+		}
+		for i, item := range list {
+			elem := reflect.New(t.Elem()).Elem()
+			if i < v.Len() {
+				elem = v.Index(i)
+			}
+			validate(item, elem, fmt.Sprintf("%v[%d]", root(path), i), problems)
+		}
+	case reflect.String:
+		if _, ok := doc.(string); !ok {
+			problem("a string", example(v))
+		}
+	case reflect.Bool:
+		if _, ok := doc.(bool); !ok {
+			problem("true or false", "")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := number(doc); !ok || n != math.Trunc(n) {
+			problem("an integer", example(v))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := number(doc); !ok || n != math.Trunc(n) || n < 0 {
+			problem("a positive integer", example(v))
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := number(doc); !ok {
+			problem("a number", example(v))
+		}
+	}
+}
+
+// fieldIndex finds the field for a key the same way as encoding/json with a case insensitive match.
+func fieldIndex(t reflect.Type, key string) (int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" || t.Field(i).Tag.Get("json") == "-" {
+			continue
+		}
+		if fieldName(t.Field(i)) == key {
+			return i, true
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" || t.Field(i).Tag.Get("json") == "-" {
+			continue
+		}
+		if strings.EqualFold(fieldName(t.Field(i)), key) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func fieldName(f reflect.StructField) string {
+	if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" {
+		return tag
+	}
+	return f.Name
+}
+
+func fieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" && t.Field(i).Tag.Get("json") != "-" {
+			names = append(names, fieldName(t.Field(i)))
+		}
+	}
+	return names
+}
+
+func typeName(t reflect.Type) string {
+	if strings.HasSuffix(t.Name(), "Duration") {
+		return "a duration"
+	}
+	return "a " + t.Name()
+}
+
+// example returns the value as JSON or a generic example for empty values.
+func example(v reflect.Value) string {
+	if !v.IsZero() {
+		if b, err := json.Marshal(v.Interface()); err == nil && len(b) < 80 {
+			return string(b)
+		}
+	}
+	if strings.HasSuffix(v.Type().Name(), "Duration") {
+		return `"30s"`
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return `"text"`
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "10"
+	case reflect.Float32, reflect.Float64:
+		return "1.5"
+	case reflect.Slice, reflect.Array:
+		return "[" + example(reflect.New(v.Type().Elem()).Elem()) + "]"
+	case reflect.Map:
+		return `{"key": ` + example(reflect.New(v.Type().Elem()).Elem()) + "}"
+	}
+	return ""
+}
+
+func number(doc interface{}) (float64, bool) {
+	n, ok := doc.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func got(doc interface{}) string {
+	switch doc.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	}
+	b, _ := json.Marshal(doc)
+	return string(b)
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func root(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,52 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+type testConfig struct {
+	Web struct {
+		ListenPort uint
+		Hosts      []string
+	}
+	Interval  format.Duration
+	Threshold float64
+	Enabled   bool
+}
+
+func TestValidate(t *testing.T) {
+	defaults := testConfig{Interval: format.Duration{Duration: time.Minute}, Threshold: 10}
+
+	problems, err := Validate([]byte(`{"web": {"ListenPort": 5000, "Hosts": ["a"]}, "Interval": "30s", "Enabled": true}`), defaults)
+	testutil.Ok(t, err)
+	testutil.Equals(t, 0, len(problems))
+
+	problems, err = Validate([]byte(`{
+		"Web": {"ListenPort": -1, "Hosts": "a", "Prot": 1},
+		"Interval": "1x",
+		"Threshold": "high",
+		"Enabled": "yes"
+	}`), defaults)
+	testutil.Ok(t, err)
+	testutil.Equals(t, Problems{
+		{Path: "Enabled", Expected: "true or false", Got: `"yes"`},
+		{Path: "Interval", Expected: "a duration", Example: `"1m0s"`, Got: `"1x"`},
+		{Path: "Threshold", Expected: "a number", Example: "10", Got: `"high"`},
+		{Path: "Web.Hosts", Expected: "a list", Example: `["text"]`, Got: `"a"`},
+		{Path: "Web.ListenPort", Expected: "a positive integer", Example: "10", Got: "-1"},
+		{Path: "Web.Prot", Expected: "known fields are ListenPort, Hosts", Unknown: true},
+	}, problems)
+	testutil.Equals(t, 5, len(problems.Errors()))
+	testutil.Equals(t, 1, len(problems.Warnings()))
+	testutil.NotOk(t, problems.Err())
+
+	_, err = Validate([]byte(`{"Web": `), defaults)
+	testutil.NotOk(t, err)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/pkg/errors"
+)
+
+// AWS reads the secrets from AWS Secrets Manager.
+// The references are in the format secretID#key, for example telliot/prod#privateKeys.
+// The key selects a value of a secret stored as JSON and without it the whole secret is used.
+// The credentials and the region are taken from the usual AWS env variables, shared config or instance role.
+type AWS struct {
+	client *secretsmanager.SecretsManager
+}
+
+func NewAWS() (*AWS, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "create aws session")
+	}
+	return &AWS{client: secretsmanager.New(sess)}, nil
+}
+
+func (self *AWS) Read(ctx context.Context, ref string) (string, error) {
+	id, key := splitRef(ref)
+	out, err := self.client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(id),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "get secret")
+	}
+	if out.SecretString == nil {
+		return "", errors.Errorf("secret:%v has no string value", id)
+	}
+	if key == "" {
+		return *out.SecretString, nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(*out.SecretString), &data); err != nil {
+		return "", errors.Wrapf(err, "decode secret:%v as json to get key:%v", id, key)
+	}
+	return value(data, key)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package secrets
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+const (
+	// SchemeKeystore references an encrypted keystore file in a list of private keys,
+	// for example ETH_PRIVATE_KEYS=keystore://configs/UTC--2021-06-01T00-00-00.000Z--0x...
+	// The files are in the Web3 secret storage format written by geth and most wallets.
+	SchemeKeystore = "keystore://"
+
+	// The sources of the passphrase of the keystore files in the order these are tried.
+	PassphraseEnvName     = "KEYSTORE_PASSPHRASE"
+	PassphraseFileEnvName = "KEYSTORE_PASSPHRASE_FILE"
+	PassphraseFDEnvName   = "KEYSTORE_PASSPHRASE_FD"
+	// PassphraseCredential is the name of the systemd credential with the passphrase
+	// set with LoadCredential=keystore-passphrase:/path/to/file in the unit.
+	PassphraseCredential = "keystore-passphrase"
+)
+
+// DecryptKeys replaces the keystore references in the comma separated list of keys
+// of the env variable with the decrypted private keys.
+// The passphrase is read only when there is a reference and
+// the same passphrase is used for all files.
+func DecryptKeys(logger log.Logger, name string) error {
+	logger = log.With(logger, "component", ComponentName)
+
+	keys := strings.Split(os.Getenv(name), ",")
+	var passphrase *string
+	for i, key := range keys {
+		path := strings.TrimSpace(key)
+		if !strings.HasPrefix(path, SchemeKeystore) {
+			continue
+		}
+		path = strings.TrimPrefix(path, SchemeKeystore)
+
+		if passphrase == nil {
+			p, err := readPassphrase()
+			if err != nil {
+				return errors.Wrap(err, "reading the keystore passphrase")
+			}
+			passphrase = &p
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrap(err, "reading the keystore file")
+		}
+		k, err := keystore.DecryptKey(b, *passphrase)
+		if err != nil {
+			return errors.Wrapf(err, "decrypting the keystore file:%v", path)
+		}
+		keys[i] = hex.EncodeToString(crypto.FromECDSA(k.PrivateKey))
+		level.Info(logger).Log("msg", "decrypted keystore", "name", name, "path", path, "address", k.Address.Hex())
+	}
+	if passphrase == nil {
+		return nil
+	}
+	if err := os.Setenv(name, strings.Join(keys, ",")); err != nil {
+		return errors.Wrapf(err, "setting env variable:%v", name)
+	}
+	return nil
+}
+
+// readPassphrase reads the passphrase from the env, a file, a file descriptor or a systemd credential
+// and when none are set asks for it on the terminal.
+func readPassphrase() (string, error) {
+	if p, ok := os.LookupEnv(PassphraseEnvName); ok {
+		// Not needed anymore so don't leave it to the child processes.
+		os.Unsetenv(PassphraseEnvName)
+		return p, nil
+	}
+	if path := os.Getenv(PassphraseFileEnvName); path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "reading the passphrase file of env variable:%v", PassphraseFileEnvName)
+		}
+		return trimNewline(string(b)), nil
+	}
+	if fd := os.Getenv(PassphraseFDEnvName); fd != "" {
+		n, err := strconv.ParseUint(fd, 10, 32)
+		if err != nil {
+			return "", errors.Wrapf(err, "parsing the file descriptor of env variable:%v", PassphraseFDEnvName)
+		}
+		f := os.NewFile(uintptr(n), "passphrase")
+		defer f.Close()
+		b, err := ioutil.ReadAll(f)
+		if err != nil {
+			return "", errors.Wrapf(err, "reading the passphrase from file descriptor:%v", n)
+		}
+		return trimNewline(string(b)), nil
+	}
+	if dir := os.Getenv("CREDENTIALS_DIRECTORY"); dir != "" {
+		b, err := ioutil.ReadFile(filepath.Join(dir, PassphraseCredential))
+		if err == nil {
+			return trimNewline(string(b)), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", errors.Wrap(err, "reading the systemd credential")
+		}
+	}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprint(os.Stderr, "Passphrase of the keystore files: ")
+		b, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", errors.Wrap(err, "reading the passphrase from the terminal")
+		}
+		return string(b), nil
+	}
+	return "", errors.Errorf("no passphrase, set one of the %v, %v or %v env variables or run in a terminal", PassphraseEnvName, PassphraseFileEnvName, PassphraseFDEnvName)
+}
+
+func trimNewline(s string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(s, "\n"), "\r")
+}
@@ -0,0 +1,59 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package secrets
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-kit/kit/log"
+	"github.com/google/uuid"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestDecryptKeys(t *testing.T) {
+	const plain = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	privateKey, err := crypto.GenerateKey()
+	testutil.Ok(t, err)
+	b, err := keystore.EncryptKey(&keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	testutil.Ok(t, err)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "key.json")
+	testutil.Ok(t, ioutil.WriteFile(path, b, 0600))
+	expected := plain + "," + hex.EncodeToString(crypto.FromECDSA(privateKey))
+
+	testutil.Setenv(t, "TEST_PRIVATE_KEYS", plain+",keystore://"+path)
+	testutil.Setenv(t, PassphraseEnvName, "secret")
+	testutil.Ok(t, DecryptKeys(log.NewNopLogger(), "TEST_PRIVATE_KEYS"))
+	testutil.Equals(t, expected, os.Getenv("TEST_PRIVATE_KEYS"))
+	_, ok := os.LookupEnv(PassphraseEnvName)
+	testutil.Assert(t, !ok, "the passphrase shouldn't be kept in the env")
+
+	passFile := filepath.Join(dir, "passphrase")
+	testutil.Ok(t, ioutil.WriteFile(passFile, []byte("secret\n"), 0600))
+	testutil.Setenv(t, "TEST_PRIVATE_KEYS", plain+",keystore://"+path)
+	testutil.Setenv(t, PassphraseFileEnvName, passFile)
+	testutil.Ok(t, DecryptKeys(log.NewNopLogger(), "TEST_PRIVATE_KEYS"))
+	testutil.Equals(t, expected, os.Getenv("TEST_PRIVATE_KEYS"))
+
+	r, w, err := os.Pipe()
+	testutil.Ok(t, err)
+	_, err = w.Write([]byte("wrong"))
+	testutil.Ok(t, err)
+	testutil.Ok(t, w.Close())
+	testutil.Setenv(t, "TEST_PRIVATE_KEYS", "keystore://"+path)
+	testutil.Setenv(t, PassphraseFileEnvName, "")
+	testutil.Setenv(t, PassphraseFDEnvName, fmt.Sprint(r.Fd()))
+	testutil.NotOk(t, DecryptKeys(log.NewNopLogger(), "TEST_PRIVATE_KEYS"))
+}
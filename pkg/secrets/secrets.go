@@ -0,0 +1,97 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package secrets
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+)
+
+const (
+	ComponentName = "secrets"
+
+	// The schemes of the env variable values that reference a secret.
+	SchemeVault = "vault://"
+	SchemeAWS   = "aws-sm://"
+
+	resolveTimeout = 30 * time.Second
+)
+
+// Backend reads a secret by its reference without the scheme.
+type Backend interface {
+	Read(ctx context.Context, ref string) (string, error)
+}
+
+// ResolveEnv replaces the values of the env variables that reference a secret,
+// for example ETH_PRIVATE_KEYS=vault://secret/data/telliot#privateKeys,
+// with the value of the secret so that the secrets aren't kept in the config or env files.
+// The backends are created only when a variable references them.
+func ResolveEnv(logger log.Logger, ctx context.Context) error {
+	logger = log.With(logger, "component", ComponentName)
+	ctx, cncl := context.WithTimeout(ctx, resolveTimeout)
+	defer cncl()
+
+	backends := make(map[string]Backend)
+	for _, env := range os.Environ() {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		name, value := kv[0], kv[1]
+
+		var scheme string
+		for _, s := range []string{SchemeVault, SchemeAWS} {
+			if strings.HasPrefix(value, s) {
+				scheme = s
+			}
+		}
+		if scheme == "" {
+			continue
+		}
+
+		backend, ok := backends[scheme]
+		if !ok {
+			var err error
+			backend, err = newBackend(scheme)
+			if err != nil {
+				return errors.Wrapf(err, "creating the secrets backend for env variable:%v", name)
+			}
+			backends[scheme] = backend
+		}
+
+		secret, err := backend.Read(ctx, strings.TrimPrefix(value, scheme))
+		if err != nil {
+			return errors.Wrapf(err, "reading the secret of env variable:%v", name)
+		}
+		if err := os.Setenv(name, secret); err != nil {
+			return errors.Wrapf(err, "setting env variable:%v", name)
+		}
+		level.Info(logger).Log("msg", "resolved secret", "name", name, "ref", value)
+	}
+	return nil
+}
+
+func newBackend(scheme string) (Backend, error) {
+	switch scheme {
+	case SchemeVault:
+		return NewVault(os.Getenv(VaultAddrEnvName), os.Getenv(VaultTokenEnvName))
+	case SchemeAWS:
+		return NewAWS()
+	}
+	return nil, errors.Errorf("unknown scheme:%v", scheme)
+}
+
+// splitRef splits a reference into the secret path and the key of the value in the secret.
+func splitRef(ref string) (path, key string) {
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
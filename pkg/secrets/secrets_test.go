@@ -0,0 +1,53 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestResolveEnvVault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "TOKEN" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/telliot": // KV version 2.
+			w.Write([]byte(`{"data": {"data": {"privateKeys": "0xKEY", "nodeURL": "wss://node"}, "metadata": {"version": 1}}}`))
+		case "/v1/kv/telliot": // KV version 1.
+			w.Write([]byte(`{"data": {"token": "API"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	testutil.Setenv(t, VaultAddrEnvName, srv.URL)
+	testutil.Setenv(t, VaultTokenEnvName, "TOKEN")
+	testutil.Setenv(t, "TEST_PRIVATE_KEYS", "vault://secret/data/telliot#privateKeys")
+	testutil.Setenv(t, "TEST_NODE_URL", "vault://secret/data/telliot#nodeURL")
+	testutil.Setenv(t, "TEST_API_TOKEN", "vault://kv/telliot")
+	testutil.Setenv(t, "TEST_PLAIN", "plain")
+
+	testutil.Ok(t, ResolveEnv(log.NewNopLogger(), context.Background()))
+	testutil.Equals(t, "0xKEY", os.Getenv("TEST_PRIVATE_KEYS"))
+	testutil.Equals(t, "wss://node", os.Getenv("TEST_NODE_URL"))
+	testutil.Equals(t, "API", os.Getenv("TEST_API_TOKEN"))
+	testutil.Equals(t, "plain", os.Getenv("TEST_PLAIN"))
+
+	// Without a key the secret must have a single value.
+	testutil.Setenv(t, "TEST_PRIVATE_KEYS", "vault://secret/data/telliot")
+	testutil.NotOk(t, ResolveEnv(log.NewNopLogger(), context.Background()))
+
+	testutil.Setenv(t, "TEST_PRIVATE_KEYS", "vault://secret/data/missing#privateKeys")
+	testutil.NotOk(t, ResolveEnv(log.NewNopLogger(), context.Background()))
+}
@@ -0,0 +1,105 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// The same env variables as used by the vault cli.
+	VaultAddrEnvName  = "VAULT_ADDR"
+	VaultTokenEnvName = "VAULT_TOKEN"
+)
+
+// Vault reads the secrets from the KV secrets engine of HashiCorp Vault.
+// The references are in the format path#key, for example secret/data/telliot#privateKeys.
+type Vault struct {
+	addr   string
+	token  string
+	client *http.Client
+}
+
+func NewVault(addr, token string) (*Vault, error) {
+	if addr == "" || token == "" {
+		return nil, errors.Errorf("the %v and %v env variables are required for the vault secrets", VaultAddrEnvName, VaultTokenEnvName)
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse vault address")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("vault address without http or https scheme:%v", addr)
+	}
+	return &Vault{
+		addr:   strings.TrimSuffix(u.String(), "/"),
+		token:  token,
+		client: &http.Client{},
+	}, nil
+}
+
+func (self *Vault) Read(ctx context.Context, ref string) (string, error) {
+	path, key := splitRef(ref)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, self.addr+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "create request")
+	}
+	req.Header.Set("X-Vault-Token", self.token)
+
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "get secret")
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("response status code not OK code:%v, payload:%v", resp.StatusCode, string(body))
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", errors.Wrap(err, "decode secret")
+	}
+	data := secret.Data
+	// The KV version 2 engine nests the values together with their metadata.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	return value(data, key)
+}
+
+// value returns the value of the key or the only value when the key is empty.
+func value(data map[string]interface{}, key string) (string, error) {
+	if key == "" {
+		if len(data) != 1 {
+			return "", errors.Errorf("the secret has %v values, add the key of one as #key", len(data))
+		}
+		for k := range data {
+			key = k
+		}
+	}
+	v, ok := data[key]
+	if !ok {
+		return "", errors.Errorf("the secret has no key:%v", key)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(v), nil
+}
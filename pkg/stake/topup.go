@@ -0,0 +1,340 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package stake
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
+	"github.com/tellor-io/telliot/pkg/leader"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/math"
+	"github.com/tellor-io/telliot/pkg/notify"
+	"github.com/tellor-io/telliot/pkg/txs"
+)
+
+const ComponentName = "stakeTopUp"
+
+// Stake statuses as returned by the contract.
+const (
+	StatusNotStaked         = 0
+	StatusStaked            = 1
+	StatusLockedForWithdraw = 2
+	StatusOnDispute         = 3
+)
+
+type Config struct {
+	LogLevel       string
+	Enabled        bool            `help:"Deposit the stake automatically for accounts that are not staked, for example after being slashed or when a new account is added."`
+	Interval       format.Duration `help:"How often to check the stake status and balance of the accounts."`
+	FundingAddress string          `help:"Account that transfers the missing TRB to accounts without enough balance for the stake. It should be one of the configured accounts. Leave empty to deposit only from the account's own balance."`
+	Budget         float64         `help:"Maximum TRB to transfer from the funding account since the start. 0 disables the transfers."`
+}
+
+// TopUp keeps all accounts staked.
+// Accounts that deliberately requested a withdraw are left alone.
+type TopUp struct {
+	logger    log.Logger
+	ctx       context.Context
+	close     context.CancelFunc
+	cfgMtx    sync.Mutex
+	cfg       Config
+	client    *ethclient.Client
+	gasPrices gasPrice.OperationQuerier
+	contract  *contracts.ITellor
+	accounts  []*ethereum.Account
+	funding   *ethereum.Account
+	txs       *txs.Store
+	notifier  *notify.Notifier
+	budget    *big.Int
+	actions   *prometheus.CounterVec
+	// withdrawable are the accounts already notified that their stake can be withdrawn.
+	withdrawable map[string]bool
+	intervalCh   chan time.Duration
+	leader       *leader.Elector
+}
+
+func New(
+	logger log.Logger,
+	ctx context.Context,
+	cfg Config,
+	client *ethclient.Client,
+	gasPrices gasPrice.OperationQuerier,
+	contract *contracts.ITellor,
+	accounts []*ethereum.Account,
+	txStore *txs.Store,
+	notifier *notify.Notifier,
+	leader *leader.Elector,
+) (*TopUp, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	logger = log.With(logger, "component", ComponentName)
+
+	var funding *ethereum.Account
+	if cfg.FundingAddress != "" {
+		for _, acc := range accounts {
+			if strings.EqualFold(acc.Address.Hex(), cfg.FundingAddress) {
+				funding = acc
+			}
+		}
+		if funding == nil {
+			return nil, errors.Errorf("funding address:%v is not one of the configured accounts", cfg.FundingAddress)
+		}
+	}
+
+	budget, err := math.FloatToBigInt18e(cfg.Budget)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing budget")
+	}
+
+	ctx, close := context.WithCancel(ctx)
+
+	return &TopUp{
+		logger:       logger,
+		ctx:          ctx,
+		close:        close,
+		cfg:          cfg,
+		client:       client,
+		gasPrices:    gasPrices,
+		contract:     contract,
+		accounts:     accounts,
+		funding:      funding,
+		txs:          txStore,
+		notifier:     notifier,
+		budget:       budget,
+		withdrawable: make(map[string]bool),
+		intervalCh:   make(chan time.Duration, 1),
+		leader:       leader,
+		actions: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "actions_total",
+			Help:      "The total number of automatic stake transactions by type",
+		}, []string{"type", "addr"}),
+	}, nil
+}
+
+func (self *TopUp) Start() {
+	self.cfgMtx.Lock()
+	ticker := time.NewTicker(self.cfg.Interval.Duration)
+	self.cfgMtx.Unlock()
+	defer ticker.Stop()
+
+	for {
+		for _, account := range self.accounts {
+			if !self.leader.IsLeader() {
+				level.Debug(self.logger).Log("msg", "standby instance, only the leader stakes")
+				break
+			}
+			if err := self.check(account); err != nil {
+				level.Error(self.logger).Log("msg", "checking stake", "addr", account.Address.String(), "err", err)
+				self.notify(notify.SeverityCritical, "automatic stake failed", fmt.Sprintf("account %v: %v", account.Address.String(), err))
+			}
+		}
+		if !self.wait(ticker) {
+			return
+		}
+	}
+}
+
+// wait blocks until the next check and returns false when the top up is stopped.
+func (self *TopUp) wait(ticker *time.Ticker) bool {
+	for {
+		select {
+		case <-self.ctx.Done():
+			return false
+		case interval := <-self.intervalCh:
+			ticker.Reset(interval)
+		case <-ticker.C:
+			return true
+		}
+	}
+}
+
+// Reload applies the check interval of a new config without a restart.
+func (self *TopUp) Reload(cfg Config) {
+	self.cfgMtx.Lock()
+	defer self.cfgMtx.Unlock()
+	if cfg.Interval == self.cfg.Interval {
+		return
+	}
+	self.cfg.Interval = cfg.Interval
+	select {
+	case <-self.intervalCh:
+	default:
+	}
+	self.intervalCh <- cfg.Interval.Duration
+}
+
+func (self *TopUp) Stop() {
+	self.close()
+}
+
+func (self *TopUp) check(account *ethereum.Account) error {
+	opts := &bind.CallOpts{Context: self.ctx}
+	status, started, err := self.contract.GetStakerInfo(opts, account.Address)
+	if err != nil {
+		return errors.Wrap(err, "get stake status")
+	}
+	if status.Int64() == StatusLockedForWithdraw {
+		self.checkWithdrawable(account, started)
+		return nil
+	}
+	delete(self.withdrawable, account.Address.String())
+	if status.Int64() != StatusNotStaked {
+		return nil
+	}
+
+	stakeAmt, err := self.contract.GetUintVar(opts, ethereum.Keccak256([]byte("_STAKE_AMOUNT")))
+	if err != nil {
+		return errors.Wrap(err, "fetching stake amount")
+	}
+	balance, err := self.contract.BalanceOf(opts, account.Address)
+	if err != nil {
+		return errors.Wrap(err, "get TRB balance")
+	}
+
+	if balance.Cmp(stakeAmt) < 0 {
+		missing := new(big.Int).Sub(stakeAmt, balance)
+		if err := self.transfer(account, missing); err != nil {
+			return errors.Wrapf(err, "insufficient TRB balance actual:%v, required:%v",
+				math.BigInt18eToFloat(balance),
+				math.BigInt18eToFloat(stakeAmt),
+			)
+		}
+	}
+
+	auth, release, err := self.prepareTransaction(account, gasPrice.OpStake)
+	if err != nil {
+		return errors.Wrap(err, "prepare ethereum transaction")
+	}
+	tx, err := self.contract.DepositStake(auth)
+	if err != nil {
+		release()
+		return errors.Wrap(err, "deposit stake")
+	}
+	if err := self.waitMined(txs.TypeDeposit, account, tx); err != nil {
+		return errors.Wrap(err, "deposit stake")
+	}
+	self.actions.With(prometheus.Labels{"type": "deposit", "addr": account.Address.String()}).Inc()
+	self.notify(notify.SeverityWarning, "stake deposited", fmt.Sprintf("account %v was not staked and deposited the stake, tx:%v", account.Address.String(), tx.Hash().String()))
+	return nil
+}
+
+// transfer sends TRB from the funding account when it is within the budget.
+func (self *TopUp) transfer(account *ethereum.Account, amount *big.Int) error {
+	if self.funding == nil {
+		return errors.New("no funding account")
+	}
+	if self.funding.Address == account.Address {
+		return errors.New("the funding account itself doesn't have enough balance")
+	}
+	if self.budget.Cmp(amount) < 0 {
+		return errors.Errorf("transfer exceeds the remaining budget amount:%v, budget:%v",
+			math.BigInt18eToFloat(amount),
+			math.BigInt18eToFloat(self.budget),
+		)
+	}
+
+	auth, release, err := self.prepareTransaction(self.funding, gasPrice.OpTransfer)
+	if err != nil {
+		return errors.Wrap(err, "prepare ethereum transaction")
+	}
+	tx, err := self.contract.Transfer(auth, account.Address, amount)
+	if err != nil {
+		release()
+		return errors.Wrap(err, "transfer TRB")
+	}
+	// Reserve the budget as soon as the transaction is sent
+	// so a failed wait doesn't allow spending it again.
+	self.budget.Sub(self.budget, amount)
+	if err := self.waitMined(txs.TypeTransfer, self.funding, tx); err != nil {
+		return errors.Wrap(err, "transfer TRB")
+	}
+	self.actions.With(prometheus.Labels{"type": "transfer", "addr": account.Address.String()}).Inc()
+	self.notify(notify.SeverityWarning, "stake funded", fmt.Sprintf(
+		"transferred %v TRB from %v to %v for the stake, remaining budget:%v TRB, tx:%v",
+		math.BigInt18eToFloat(amount),
+		self.funding.Address.String(),
+		account.Address.String(),
+		math.BigInt18eToFloat(self.budget),
+		tx.Hash().String(),
+	))
+	return nil
+}
+
+// prepareTransaction returns the options of a transaction and the func to release its nonce when sending it fails.
+func (self *TopUp) prepareTransaction(account *ethereum.Account, op gasPrice.Operation) (*bind.TransactOpts, func(), error) {
+	price, err := self.gasPrices.For(op).Query(self.ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "getting gas price")
+	}
+	return ethereum.PrepareEthTransaction(self.ctx, self.client, account, price)
+}
+
+func (self *TopUp) waitMined(typ txs.Type, account *ethereum.Account, tx *types.Transaction) error {
+	record := txs.NewRecord(typ, account.Address, tx)
+	self.record(record)
+	receipt, err := bind.WaitMined(self.ctx, self.client, tx)
+	if err != nil {
+		return errors.Wrapf(err, "waiting for tx:%v", tx.Hash().String())
+	}
+	record.SetReceipt(receipt)
+	self.record(record)
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return errors.Errorf("tx failed:%v", tx.Hash().String())
+	}
+	return nil
+}
+
+func (self *TopUp) record(r txs.Record) {
+	if err := self.txs.Add(r); err != nil {
+		level.Error(self.logger).Log("msg", "recording the transaction in the history", "tx", r.Hash, "err", err)
+	}
+}
+
+// checkWithdrawable notifies once when the stake locked for withdraw can be withdrawn.
+func (self *TopUp) checkWithdrawable(account *ethereum.Account, started *big.Int) {
+	addr := account.Address.String()
+	if self.withdrawable[addr] || time.Now().Before(WithdrawEligibleTime(started)) {
+		return
+	}
+	self.withdrawable[addr] = true
+	self.notify(notify.SeverityCritical, "stake withdrawal available", fmt.Sprintf("the stake of account %v can be withdrawn with the stake withdraw command", addr))
+}
+
+// WithdrawEligibleTime returns when a stake locked for withdraw can be withdrawn.
+// The lock period is 7 days counted from the start of the day after the withdraw request.
+func WithdrawEligibleTime(started *big.Int) time.Time {
+	startedRound := started.Int64()
+	startedRound = ((startedRound + 86399) / 86400) * 86400
+	return time.Unix(startedRound, 0).Add(time.Hour * 24 * 7)
+}
+
+func (self *TopUp) notify(severity notify.Severity, title, msg string) {
+	self.notifier.Notify(notify.Event{
+		Component: ComponentName,
+		Severity:  severity,
+		Title:     title,
+		Message:   msg,
+	})
+}
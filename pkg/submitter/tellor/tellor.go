@@ -5,13 +5,12 @@ package tellor
 
 import (
 	"context"
-	"encoding/hex"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/go-kit/kit/log"
@@ -19,29 +18,42 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/contracts"
 	"github.com/tellor-io/telliot/pkg/ethereum"
 	"github.com/tellor-io/telliot/pkg/format"
 	"github.com/tellor-io/telliot/pkg/gasPrice"
+	"github.com/tellor-io/telliot/pkg/leader"
 	"github.com/tellor-io/telliot/pkg/logging"
 	"github.com/tellor-io/telliot/pkg/mining"
+	"github.com/tellor-io/telliot/pkg/notify"
 	psr "github.com/tellor-io/telliot/pkg/psr/tellor"
 	"github.com/tellor-io/telliot/pkg/reward"
+	"github.com/tellor-io/telliot/pkg/tracing"
 	"github.com/tellor-io/telliot/pkg/transactor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const ComponentName = "submitterTellor"
 
-type ContractCaller interface {
-	GetUintVar(opts *bind.CallOpts, _data [32]byte) (*big.Int, error)
-	SubmitMiningSolution(opts *bind.TransactOpts, _nonce string, _requestId [5]*big.Int, _value [5]*big.Int) (*types.Transaction, error)
-	GetStakerInfo(opts *bind.CallOpts, _staker common.Address) (*big.Int, *big.Int, error)
+// Samples returns when the values of a symbol were last updated.
+type Samples interface {
+	LastUpdate(symbol string, at time.Time) (time.Time, time.Duration, error)
+}
+
+// Fetcher gets new values of a symbol on demand.
+type Fetcher interface {
+	Fetch(ctx context.Context, symbol string) error
 }
 
 type Config struct {
-	Enabled         bool
-	LogLevel        string
-	ProfitThreshold uint64          `help:"Minimum percent of profit when submitting a solution. For example if the tx cost is 0.01 ETH and current reward is 0.02 ETH a ProfitThreshold of 200% or more will wait until the reward is increased or the gas cost is lowered a ProfitThreshold of 199% or less will submit."`
-	MinSubmitPeriod format.Duration `help:"The time limit between each submit for a staked miner."`
+	Enabled            bool
+	LogLevel           string
+	ProfitThreshold    uint64                     `help:"Minimum percent of profit when submitting a solution. For example if the tx cost is 0.01 ETH and current reward is 0.02 ETH a ProfitThreshold of 200% or more will wait until the reward is increased or the gas cost is lowered a ProfitThreshold of 199% or less will submit."`
+	MinSubmitPeriod    format.Duration            `help:"The time limit between each submit for a staked miner."`
+	SubmitDelay        format.Duration            `help:"Wait until this long after the current challenge started before submitting. The reward grows with the time since the last value so submitting late in the window can be more profitable. The profit and the value freshness are checked again after the wait. 0 submits right away."`
+	MaxValueAge        format.Duration            `help:"Don't submit values based on samples older than this or than twice the interval of the symbol's data sources when it is longer. When the index tracker runs in the same process it fetches new samples before giving up. 0 disables the check."`
+	MaxValueAgeSymbols map[string]format.Duration `help:"Max value age for specific symbols, for example {\"ETH/USD\": \"1m\"}."`
 }
 
 /**
@@ -54,10 +66,11 @@ type Submitter struct {
 	ctx             context.Context
 	close           context.CancelFunc
 	logger          log.Logger
+	cfgMtx          sync.Mutex
 	cfg             Config
 	account         *ethereum.Account
 	client          *ethclient.Client
-	contract        ContractCaller
+	contract        contracts.Oracle
 	resultCh        chan *mining.Result
 	submitCount     prometheus.Counter
 	submitFailCount prometheus.Counter
@@ -67,6 +80,11 @@ type Submitter struct {
 	reward          *reward.Reward
 	gasPriceQuerier gasPrice.GasPriceQuerier
 	psr             *psr.Psr
+	samples         Samples
+	fetcher         Fetcher
+	staleCount      *prometheus.CounterVec
+	notifier        *notify.Notifier
+	leader          *leader.Elector
 }
 
 func New(
@@ -74,14 +92,18 @@ func New(
 	logger log.Logger,
 	cfg Config,
 	client *ethclient.Client,
-	contract ContractCaller,
+	contract contracts.Oracle,
 	account *ethereum.Account,
 	reward *reward.Reward,
 	transactor transactor.Transactor,
 	gasPriceQuerier gasPrice.GasPriceQuerier,
 	psr *psr.Psr,
+	samples Samples,
+	fetcher Fetcher,
+	notifier *notify.Notifier,
+	leader *leader.Elector,
 ) (*Submitter, chan *mining.Result, error) {
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "apply filter logger")
 	}
@@ -100,6 +122,10 @@ func New(
 		transactor:      transactor,
 		gasPriceQuerier: gasPriceQuerier,
 		psr:             psr,
+		samples:         samples,
+		fetcher:         fetcher,
+		notifier:        notifier,
+		leader:          leader,
 		submitCount: promauto.NewCounter(prometheus.CounterOpts{
 			Namespace:   "telliot",
 			Subsystem:   ComponentName,
@@ -123,6 +149,15 @@ func New(
 		},
 			[]string{"id"},
 		),
+		staleCount: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "telliot",
+			Subsystem:   ComponentName,
+			Name:        "stale_values_total",
+			Help:        "The total number of submits delayed because the value of the symbol was stale",
+			ConstLabels: prometheus.Labels{"account": account.Address.String()},
+		},
+			[]string{"symbol"},
+		),
 	}
 
 	return submitter, submitter.resultCh, nil
@@ -160,7 +195,25 @@ func (self *Submitter) Stop() {
 	self.close()
 }
 
+// Reload applies the profit and timing settings of a new config without a restart.
+func (self *Submitter) Reload(cfg Config) {
+	self.cfgMtx.Lock()
+	defer self.cfgMtx.Unlock()
+	self.cfg.ProfitThreshold = cfg.ProfitThreshold
+	self.cfg.MinSubmitPeriod = cfg.MinSubmitPeriod
+	self.cfg.SubmitDelay = cfg.SubmitDelay
+	self.cfg.MaxValueAge = cfg.MaxValueAge
+	self.cfg.MaxValueAgeSymbols = cfg.MaxValueAgeSymbols
+}
+
+func (self *Submitter) config() Config {
+	self.cfgMtx.Lock()
+	defer self.cfgMtx.Unlock()
+	return self.cfg
+}
+
 func (self *Submitter) blockUntilTimeToSubmit(newChallengeReplace context.Context) {
+	cfg := self.config()
 	var (
 		lastSubmit time.Duration
 		timestamp  *time.Time
@@ -180,14 +233,14 @@ func (self *Submitter) blockUntilTimeToSubmit(newChallengeReplace context.Contex
 		}
 		break
 	}
-	if lastSubmit < self.cfg.MinSubmitPeriod.Duration {
+	if lastSubmit < cfg.MinSubmitPeriod.Duration {
 		level.Info(self.logger).Log("msg", "min transaction submit threshold hasn't passed",
-			"nextSubmit", time.Duration(self.cfg.MinSubmitPeriod.Nanoseconds())-lastSubmit,
+			"nextSubmit", time.Duration(cfg.MinSubmitPeriod.Nanoseconds())-lastSubmit,
 			"lastSubmit", lastSubmit,
 			"lastSubmitTimestamp", timestamp.Format("2006-01-02 15:04:05.000000"),
-			"minSubmitPeriod", self.cfg.MinSubmitPeriod,
+			"minSubmitPeriod", cfg.MinSubmitPeriod,
 		)
-		timeToSubmit, cncl := context.WithDeadline(newChallengeReplace, timestamp.Add(self.cfg.MinSubmitPeriod.Duration))
+		timeToSubmit, cncl := context.WithDeadline(newChallengeReplace, timestamp.Add(cfg.MinSubmitPeriod.Duration))
 		defer cncl()
 		select {
 		case <-newChallengeReplace.Done():
@@ -197,15 +250,53 @@ func (self *Submitter) blockUntilTimeToSubmit(newChallengeReplace context.Contex
 	}
 }
 
+// blockUntilSubmitDelay waits until the configured delay since the start of the current challenge has passed.
+func (self *Submitter) blockUntilSubmitDelay(newChallengeReplace context.Context) error {
+	cfg := self.config()
+	if cfg.SubmitDelay.Duration == 0 {
+		return nil
+	}
+	var started *big.Int
+	for {
+		var err error
+		started, err = self.contract.TimeOfLastNewValue(&bind.CallOpts{Context: newChallengeReplace})
+		if err == nil {
+			break
+		}
+		level.Debug(self.logger).Log("msg", "getting the challenge start time", "err", err)
+		select {
+		case <-newChallengeReplace.Done():
+			return newChallengeReplace.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	submitAt := time.Unix(started.Int64(), 0).Add(cfg.SubmitDelay.Duration)
+	if time.Now().After(submitAt) {
+		return nil
+	}
+	level.Info(self.logger).Log("msg", "waiting to submit later in the challenge window", "submitAt", submitAt.Format("2006-01-02 15:04:05"), "delay", time.Until(submitAt).Round(time.Second))
+	select {
+	case <-newChallengeReplace.Done():
+		return newChallengeReplace.Err()
+	case <-time.After(time.Until(submitAt)):
+	}
+	return nil
+}
+
 func (self *Submitter) canSubmit() error {
-	if self.cfg.ProfitThreshold > 0 { // Profit check is enabled.
+	if !self.leader.IsLeader() {
+		return errors.New("standby instance, only the leader submits")
+	}
+	cfg := self.config()
+	if cfg.ProfitThreshold > 0 { // Profit check is enabled.
 		profitPercent, err := self.profitPercent()
 		if _, ok := errors.Cause(err).(reward.ErrNoDataForSlot); ok {
 			level.Warn(self.logger).Log("msg", "skipping profit check when the slot has no record for how much gas it uses", "err", err)
 		} else if err != nil {
 			return errors.Wrapf(err, "submit solution profit check")
-		} else if profitPercent < int64(self.cfg.ProfitThreshold) {
-			return errors.Errorf("profit:%v lower then the profit threshold:%v", profitPercent, self.cfg.ProfitThreshold)
+		} else if profitPercent < int64(cfg.ProfitThreshold) {
+			return errors.Errorf("profit:%v lower then the profit threshold:%v", profitPercent, cfg.ProfitThreshold)
 		}
 	}
 
@@ -220,6 +311,37 @@ func (self *Submitter) canSubmit() error {
 	return nil
 }
 
+// CanMine returns an error when a solution found now wouldn't be submitted
+// so that the miner doesn't spend the CPU on it.
+// Only the known reasons are returned and the errors of the checks are ignored
+// so that a node issue doesn't pause the mining.
+func (self *Submitter) CanMine(ctx context.Context) error {
+	if !self.leader.IsLeader() {
+		return errors.New("standby instance, only the leader submits")
+	}
+	cfg := self.config()
+	if lastSubmit, _, err := self.lastSubmit(); err != nil {
+		level.Debug(self.logger).Log("msg", "checking last submit time", "err", err)
+	} else if lastSubmit < cfg.MinSubmitPeriod.Duration {
+		return errors.Errorf("min submit period hasn't passed, next submit in:%v", (cfg.MinSubmitPeriod.Duration - lastSubmit).Round(time.Second))
+	}
+
+	if statusID, err := self.minerStatus(); err != nil {
+		level.Debug(self.logger).Log("msg", "getting miner status", "err", err)
+	} else if statusID != 1 {
+		return errors.Errorf("miner is not in a status that can submit:%v", minerStatusName(statusID))
+	}
+
+	if cfg.ProfitThreshold > 0 {
+		if profitPercent, err := self.profitPercent(); err != nil {
+			level.Debug(self.logger).Log("msg", "checking profit", "err", err)
+		} else if profitPercent < int64(cfg.ProfitThreshold) {
+			return errors.Errorf("profit:%v lower then the profit threshold:%v", profitPercent, cfg.ProfitThreshold)
+		}
+	}
+	return nil
+}
+
 func (self *Submitter) profitPercent() (int64, error) {
 	slot, err := self.reward.Slot()
 	if err != nil {
@@ -255,6 +377,10 @@ func (self *Submitter) Submit(newChallengeReplace context.Context, result *minin
 			}
 
 			self.blockUntilTimeToSubmit(newChallengeReplace)
+			if err := self.blockUntilSubmitDelay(newChallengeReplace); err != nil {
+				level.Info(self.logger).Log("msg", "pending submit canceled", "reason", err)
+				return
+			}
 			if err := self.canSubmit(); err != nil {
 				level.Info(self.logger).Log("msg", "can't submit and will retry later", "reason", err)
 				<-ticker.C
@@ -268,9 +394,21 @@ func (self *Submitter) Submit(newChallengeReplace context.Context, result *minin
 				default:
 				}
 
-				reqVals, err := self.requestVals(result.Work.Challenge.RequestIDs)
+				// Every attempt is a trace so that it shows where the time until the transaction is spent.
+				ctx, span := tracing.Tracer(ComponentName).Start(newChallengeReplace, "submit", trace.WithAttributes(
+					attribute.String("account", self.account.Address.String()),
+					attribute.String("requestIDs", fmt.Sprintf("%v", result.Work.Challenge.RequestIDs)),
+				))
+				if err := self.checkFreshness(ctx, result.Work.Challenge.RequestIDs); err != nil {
+					level.Warn(self.logger).Log("msg", "values are stale, retrying", "err", err)
+					tracing.End(span, err)
+					<-ticker.C
+					continue
+				}
+				reqVals, err := self.requestVals(ctx, result.Work.Challenge.RequestIDs)
 				if err != nil {
 					level.Error(self.logger).Log("msg", "adding the request ids, retrying", "err", err)
+					tracing.End(span, err)
 					<-ticker.C
 					continue
 				}
@@ -280,29 +418,41 @@ func (self *Submitter) Submit(newChallengeReplace context.Context, result *minin
 					"IDs", fmt.Sprintf("%+v", result.Work.Challenge.RequestIDs),
 					"vals", fmt.Sprintf("%+v", reqVals),
 				)
+				reqIDs := result.Work.Challenge.RequestIDs
+				// TellorX accepts only one value per reporting lock.
+				if self.contract.Version() == contracts.OracleTellorX {
+					reqIDs, reqVals = [5]*big.Int{reqIDs[0]}, [5]*big.Int{reqVals[0]}
+				}
 				f := func(auth *bind.TransactOpts) (*types.Transaction, error) {
-					return self.contract.SubmitMiningSolution(auth, result.Nonce, result.Work.Challenge.RequestIDs, reqVals)
+					return self.contract.Submit(auth, result.Nonce, reqIDs, reqVals)
 				}
-				tx, recieipt, err := self.transactor.Transact(newChallengeReplace, f)
+				txCtx, txSpan := tracing.Tracer(ComponentName).Start(ctx, "transact")
+				tx, recieipt, err := self.transactor.Transact(txCtx, f)
+				tracing.End(txSpan, err)
 				select {
 				case <-newChallengeReplace.Done():
 					level.Info(self.logger).Log("msg", "pending submit canceled")
+					tracing.End(span, newChallengeReplace.Err())
 					return
 				default:
 				}
 				if err != nil {
 					self.submitFailCount.Inc()
 					level.Error(self.logger).Log("msg", "submiting a solution", "err", err)
+					self.notify(notify.SeverityWarning, "Submit failed", fmt.Sprintf("%v failed to submit for request ids %v: %v", self.account.Address.String(), result.Work.Challenge.RequestIDs, err))
+					tracing.End(span, err)
 					return
 				}
 
 				if recieipt.Status != types.ReceiptStatusSuccessful {
 					self.submitFailCount.Inc()
-					level.Error(self.logger).Log("msg", "submiting solution status not success", "status", recieipt.Status, "hash", tx.Hash())
+					level.Error(self.logger).Log("msg", "submiting solution status not success", "status", recieipt.Status, "tx", tx.Hash().String())
+					self.notify(notify.SeverityWarning, "Submit failed", fmt.Sprintf("%v submit for request ids %v reverted, tx:%v", self.account.Address.String(), result.Work.Challenge.RequestIDs, tx.Hash().String()))
+					tracing.End(span, errors.Errorf("transaction reverted tx:%v", tx.Hash().String()))
 					return
 				}
 				level.Info(self.logger).Log("msg", "successfully submited solution",
-					"txHash", tx.Hash().String(),
+					"tx", tx.Hash().String(),
 					"nonce", tx.Nonce(),
 					"gasPrice", tx.GasPrice(),
 					"gasUsed", recieipt.GasUsed,
@@ -310,6 +460,9 @@ func (self *Submitter) Submit(newChallengeReplace context.Context, result *minin
 					"data", fmt.Sprintf("%x", tx.Data()),
 				)
 				self.submitCount.Inc()
+				span.SetAttributes(attribute.String("tx", tx.Hash().String()))
+				tracing.End(span, nil)
+				self.notify(notify.SeverityInfo, "Submitted", fmt.Sprintf("%v submitted %v for request ids %v, tx:%v", self.account.Address.String(), reqVals, result.Work.Challenge.RequestIDs, tx.Hash().String()))
 
 				for i, id := range result.Work.Challenge.RequestIDs {
 					self.submitValue.With(
@@ -332,10 +485,20 @@ func (self *Submitter) Submit(newChallengeReplace context.Context, result *minin
 	}(newChallengeReplace, result)
 }
 
-func (self *Submitter) requestVals(requestIDs [5]*big.Int) ([5]*big.Int, error) {
+// notify sends a notification when a notifier is configured.
+func (self *Submitter) notify(severity notify.Severity, title, msg string) {
+	if self.notifier == nil {
+		return
+	}
+	self.notifier.Notify(notify.Event{Component: ComponentName, Severity: severity, Title: title, Message: msg})
+}
+
+func (self *Submitter) requestVals(ctx context.Context, requestIDs [5]*big.Int) ([5]*big.Int, error) {
 	var currentValues [5]*big.Int
 	for i, reqID := range requestIDs {
+		_, span := tracing.Tracer(ComponentName).Start(ctx, "aggregate", trace.WithAttributes(attribute.Int64("requestID", reqID.Int64())))
 		val, err := self.psr.GetValue(reqID.Int64(), time.Now())
+		tracing.End(span, err)
 		if err != nil {
 			return currentValues, errors.Wrapf(err, "getting value for request ID:%v", reqID)
 		}
@@ -344,6 +507,64 @@ func (self *Submitter) requestVals(requestIDs [5]*big.Int) ([5]*big.Int, error)
 	return currentValues, nil
 }
 
+// checkFreshness makes sure the values of all request ids are based on recent samples.
+// When a value is stale it fetches new samples before giving up.
+func (self *Submitter) checkFreshness(ctx context.Context, requestIDs [5]*big.Int) error {
+	cfg := self.config()
+	if cfg.MaxValueAge.Duration == 0 {
+		return nil
+	}
+	for _, reqID := range requestIDs {
+		symbol, err := psr.Symbol(reqID.Int64())
+		if err != nil {
+			// Request ids with manual or computed values don't have samples to check.
+			continue
+		}
+		age, maxAge, err := self.valueAge(symbol)
+		if err != nil {
+			return errors.Wrapf(err, "getting the last update of symbol:%v", symbol)
+		}
+		if age <= maxAge {
+			continue
+		}
+
+		self.staleCount.With(prometheus.Labels{"symbol": symbol}).Inc()
+		if self.fetcher == nil {
+			return errors.Errorf("value of symbol:%v is stale age:%v, max:%v", symbol, age.Round(time.Second), maxAge)
+		}
+		level.Info(self.logger).Log("msg", "value is stale, fetching new samples", "symbol", symbol, "age", age.Round(time.Second), "max", maxAge)
+		if err := self.fetcher.Fetch(ctx, symbol); err != nil {
+			return errors.Wrapf(err, "value of symbol:%v is stale age:%v, max:%v and fetching new samples failed", symbol, age.Round(time.Second), maxAge)
+		}
+		if age, maxAge, err = self.valueAge(symbol); err != nil {
+			return errors.Wrapf(err, "getting the last update of symbol:%v", symbol)
+		}
+		if age > maxAge {
+			return errors.Errorf("value of symbol:%v is still stale after fetching age:%v, max:%v", symbol, age.Round(time.Second), maxAge)
+		}
+	}
+	return nil
+}
+
+// valueAge returns the age of the most recent sample of the symbol and the max allowed age.
+func (self *Submitter) valueAge(symbol string) (time.Duration, time.Duration, error) {
+	cfg := self.config()
+	now := time.Now()
+	last, interval, err := self.samples.LastUpdate(symbol, now)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxAge, ok := cfg.MaxValueAgeSymbols[symbol]
+	if ok {
+		return now.Sub(last), maxAge.Duration, nil
+	}
+	max := cfg.MaxValueAge.Duration
+	if 2*interval > max {
+		max = 2 * interval
+	}
+	return now.Sub(last), max, nil
+}
+
 func (self *Submitter) minerStatus() (int64, error) {
 	// Check if the staked account is in dispute before sending a transaction.
 	statusID, _, err := self.contract.GetStakerInfo(&bind.CallOpts{}, self.account.Address)
@@ -354,12 +575,7 @@ func (self *Submitter) minerStatus() (int64, error) {
 }
 
 func (self *Submitter) lastSubmit() (time.Duration, *time.Time, error) {
-	address := "000000000000000000000000" + self.account.Address.Hex()[2:]
-	decoded, err := hex.DecodeString(address)
-	if err != nil {
-		return 0, nil, errors.Wrapf(err, "decoding address")
-	}
-	last, err := self.contract.GetUintVar(nil, ethereum.Keccak256(decoded))
+	last, err := self.contract.ReporterLastTimestamp(nil, self.account.Address)
 
 	if err != nil {
 		return 0, nil, errors.Wrapf(err, "getting last submit time for:%v", self.account.Address.String())
@@ -381,6 +597,30 @@ func (self *Submitter) lastSubmit() (time.Duration, *time.Time, error) {
 	return lastSubmit, &tm, nil
 }
 
+// Status is the staking and submit state of the submitter account.
+type Status struct {
+	Account     string    `json:"account"`
+	StakeStatus string    `json:"stakeStatus"`
+	LastSubmit  time.Time `json:"lastSubmit"`
+}
+
+// Status returns the current stake status and the time of the last submit of the account.
+func (self *Submitter) Status(ctx context.Context) (*Status, error) {
+	statusID, err := self.minerStatus()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting miner status")
+	}
+	_, lastSubmit, err := self.lastSubmit()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting last submit time")
+	}
+	return &Status{
+		Account:     self.account.Address.String(),
+		StakeStatus: minerStatusName(statusID),
+		LastSubmit:  *lastSubmit,
+	}, nil
+}
+
 func minerStatusName(statusID int64) string {
 	// From https://github.com/tellor-io/tellor3/blob/7c2f38a0e3f96631fb0f96e0d0a9f73e7b355766/contracts/TellorStorage.sol#L41
 	switch statusID {
@@ -22,6 +22,7 @@ import (
 	"github.com/tellor-io/telliot/pkg/contracts"
 	"github.com/tellor-io/telliot/pkg/ethereum"
 	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/leader"
 	"github.com/tellor-io/telliot/pkg/logging"
 	mathU "github.com/tellor-io/telliot/pkg/math"
 	psr "github.com/tellor-io/telliot/pkg/psr/tellorMesosphere"
@@ -61,6 +62,7 @@ type Submitter struct {
 	lastSubmitValue map[int64]float64
 	lastSubmitTime  map[int64]time.Time
 	reqIDs          []int64
+	leader          *leader.Elector
 }
 
 func New(
@@ -72,8 +74,9 @@ func New(
 	account *ethereum.Account,
 	transactor transactor.Transactor,
 	psr *psr.Psr,
+	leader *leader.Elector,
 ) (*Submitter, error) {
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "apply filter logger")
 	}
@@ -90,6 +93,7 @@ func New(
 		transactor:      transactor,
 		psr:             psr,
 		reqIDs:          []int64{1, 2},
+		leader:          leader,
 		lastSubmitValue: make(map[int64]float64),
 		lastSubmitTime:  make(map[int64]time.Time),
 		submitCount: promauto.NewCounter(prometheus.CounterOpts{
@@ -174,6 +178,10 @@ func (self *Submitter) Stop() {
 }
 
 func (self *Submitter) Submit(reqID int64) error {
+	if !self.leader.IsLeader() {
+		level.Debug(self.logger).Log("msg", "standby instance, only the leader submits", "reqID", reqID)
+		return nil
+	}
 	ctx, cncl := context.WithTimeout(self.ctx, time.Minute)
 	defer cncl()
 	isReporter, err := self.contract.IsReporter(&bind.CallOpts{Context: ctx}, self.account.Address)
@@ -214,7 +222,7 @@ func (self *Submitter) Submit(reqID int64) error {
 		return errors.Wrapf(err, "submiting solution status not success status:%v, tx hash:%v", recieipt.Status, tx.Hash())
 	}
 	level.Info(self.logger).Log("msg", "successfully submited solution",
-		"txHash", tx.Hash().String(),
+		"tx", tx.Hash().String(),
 		"nonce", tx.Nonce(),
 		"gasPrice", tx.GasPrice(),
 		"gasUsed", recieipt.GasUsed,
@@ -0,0 +1,122 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+// Package supervisor restarts the components that fail
+// so that a transient error doesn't stop the whole process.
+package supervisor
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/oklog/run"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/logging"
+)
+
+const ComponentName = "supervisor"
+
+type Config struct {
+	LogLevel       string
+	Enabled        bool            `help:"Restart a component that returns an error or panics instead of stopping the process."`
+	InitialBackoff format.Duration `help:"Wait before the first restart. The wait doubles after each consecutive failure."`
+	MaxBackoff     format.Duration `help:"The longest wait between restarts. A component that runs longer than this before failing starts again from the initial backoff."`
+	MaxRestarts    uint            `help:"Stop the process when a component fails this many times in a row. 0 restarts without a limit."`
+}
+
+// Supervisor adds the components to the run group
+// with a start function that restarts them with an exponential backoff.
+type Supervisor struct {
+	logger   log.Logger
+	cfg      Config
+	restarts *prometheus.CounterVec
+}
+
+func New(logger log.Logger, cfg Config) (*Supervisor, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	if cfg.Enabled && (cfg.InitialBackoff.Duration <= 0 || cfg.MaxBackoff.Duration < cfg.InitialBackoff.Duration) {
+		return nil, errors.Errorf("initial backoff:%v should be more than 0 and not more than the max backoff:%v", cfg.InitialBackoff, cfg.MaxBackoff)
+	}
+	return &Supervisor{
+		logger: log.With(logger, "component", ComponentName),
+		cfg:    cfg,
+		restarts: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "restarts_total",
+			Help:      "The total number of restarts of a failed component",
+		}, []string{"component"}),
+	}, nil
+}
+
+// Add adds the component to the run group.
+// When the supervision is disabled it is added as it is.
+// Only the errors and the panics of the start function are handled,
+// a panic in a goroutine started by the component still stops the process.
+func (self *Supervisor) Add(g *run.Group, name string, start func() error, stop func()) {
+	if !self.cfg.Enabled {
+		g.Add(start, func(error) { stop() })
+		return
+	}
+	ctx, cncl := context.WithCancel(context.Background())
+	g.Add(func() error {
+		return self.run(ctx, name, start)
+	}, func(error) {
+		cncl()
+		stop()
+	})
+}
+
+func (self *Supervisor) run(ctx context.Context, name string, start func() error) error {
+	logger := log.With(self.logger, "supervised", name)
+	backoff := self.cfg.InitialBackoff.Duration
+	var failures uint
+	for {
+		started := time.Now()
+		err := safeStart(start)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		// It ran fine for a while so this is a new failure and not a crash loop.
+		if time.Since(started) > self.cfg.MaxBackoff.Duration {
+			backoff = self.cfg.InitialBackoff.Duration
+			failures = 0
+		}
+		failures++
+		if self.cfg.MaxRestarts > 0 && failures > self.cfg.MaxRestarts {
+			return errors.Wrapf(err, "%v failed %v times in a row", name, failures)
+		}
+
+		self.restarts.With(prometheus.Labels{"component": name}).Inc()
+		level.Error(logger).Log("msg", "component failed, restarting", "err", err, "backoff", backoff, "failures", failures)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > self.cfg.MaxBackoff.Duration {
+			backoff = self.cfg.MaxBackoff.Duration
+		}
+	}
+}
+
+// safeStart returns the panic of the start function as an error.
+func safeStart(start func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return start()
+}
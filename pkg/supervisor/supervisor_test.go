@@ -0,0 +1,68 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package supervisor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func newSupervisor(maxRestarts uint) *Supervisor {
+	return &Supervisor{
+		logger: log.NewNopLogger(),
+		cfg: Config{
+			Enabled:        true,
+			InitialBackoff: format.Duration{Duration: time.Millisecond},
+			MaxBackoff:     format.Duration{Duration: 10 * time.Millisecond},
+			MaxRestarts:    maxRestarts,
+		},
+		restarts: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "restarts_total"}, []string{"component"}),
+	}
+}
+
+func TestSupervisor(t *testing.T) {
+	// Fails twice, panics once and then exits cleanly.
+	supervisor := newSupervisor(0)
+	starts := 0
+	err := supervisor.run(context.Background(), "flaky", func() error {
+		starts++
+		switch starts {
+		case 1, 2:
+			return errors.New("transient")
+		case 3:
+			panic("crash")
+		}
+		return nil
+	})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 4, starts)
+
+	// Gives up after too many failures in a row.
+	supervisor = newSupervisor(2)
+	starts = 0
+	err = supervisor.run(context.Background(), "broken", func() error {
+		starts++
+		return errors.New("permanent")
+	})
+	testutil.NotOk(t, err)
+	testutil.Equals(t, 3, starts)
+
+	// A stopped component isn't restarted.
+	ctx, cncl := context.WithCancel(context.Background())
+	starts = 0
+	err = newSupervisor(0).run(ctx, "stopped", func() error {
+		starts++
+		cncl()
+		return errors.New("closed")
+	})
+	testutil.NotOk(t, err)
+	testutil.Equals(t, 1, starts)
+}
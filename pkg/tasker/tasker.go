@@ -7,10 +7,13 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/big"
 	"math/rand"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/event"
@@ -19,7 +22,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tellor-io/telliot/pkg/contracts"
 	"github.com/tellor-io/telliot/pkg/contracts/tellor"
-	"github.com/tellor-io/telliot/pkg/ethereum"
+	tEthereum "github.com/tellor-io/telliot/pkg/ethereum"
 	"github.com/tellor-io/telliot/pkg/logging"
 	"github.com/tellor-io/telliot/pkg/mining"
 )
@@ -41,12 +44,20 @@ type Tasker struct {
 	ctx             context.Context
 	close           context.CancelFunc
 	logger          log.Logger
-	accounts        []*ethereum.Account
+	accounts        []*tEthereum.Account
 	contract        *contracts.ITellor
 	client          *ethclient.Client
 	workSinks       map[string]chan *mining.Work
 	SubmitCancelers []SubmitCanceler
 	txPending       context.CancelFunc
+
+	// The last processed challenge and the block up to which the events are processed
+	// used to backfill the events missed while the subscription was down.
+	lastChallenge [32]byte
+	lastBlock     uint64
+	// The block of the last challenge event used to detect when a reorg removed it.
+	challengeBlock     uint64
+	challengeBlockHash common.Hash
 }
 
 func New(
@@ -55,14 +66,14 @@ func New(
 	cfg Config,
 	client *ethclient.Client,
 	contract *contracts.ITellor,
-	accounts []*ethereum.Account,
+	accounts []*tEthereum.Account,
 ) (*Tasker, map[string]chan *mining.Work, error) {
 	ctx, close := context.WithCancel(ctx)
 	workSinks := make(map[string]chan *mining.Work)
 	for _, acc := range accounts {
 		workSinks[acc.Address.String()] = make(chan *mining.Work)
 	}
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		close()
 		return nil, nil, errors.Wrap(err, "apply filter logger")
@@ -126,20 +137,14 @@ func (self *Tasker) Start() error {
 	level.Info(self.logger).Log("msg", "starting")
 
 	// Getting current challenge from the contract.
-	newVariables, err := self.contract.GetNewCurrentVariables(nil)
+	currentChallenge, err := self.currentChallenge()
 	if err != nil {
 		level.Warn(self.logger).Log("msg", "getting new current variables", "err", err)
-		return errors.Wrap(err, "getting GetNewCurrentVariables")
-	}
-
-	currentChallenge := &tellor.ITellorNewChallenge{
-		CurrentChallenge: newVariables.Challenge,
-		Difficulty:       newVariables.Difficutly,
-		CurrentRequestId: newVariables.RequestIds,
-		TotalTips:        newVariables.Tip,
+		return err
 	}
 
 	level.Info(self.logger).Log("msg", "sending the initial event")
+	self.lastChallenge = currentChallenge.CurrentChallenge
 	self.sendWork(currentChallenge)
 
 	// Subscribe and wait until the context cancellation event.
@@ -161,6 +166,11 @@ func (self *Tasker) Start() error {
 		}
 		break
 	}
+	if head, err := self.client.BlockNumber(self.ctx); err != nil {
+		level.Error(self.logger).Log("msg", "getting the block number, no backfill after a reconnect", "err", err)
+	} else {
+		self.lastBlock = head
+	}
 
 	for {
 		select {
@@ -190,20 +200,137 @@ func (self *Tasker) Start() error {
 				break
 			}
 			level.Info(self.logger).Log("msg", "re-subscribed to events")
-		case event := <-events:
-			level.Debug(self.logger).Log("msg", "new event", "reorg", event.Raw.Removed)
-			if self.txPending != nil {
-				self.txPending()
-				self.txPending = nil
+			// The removed events of a reorg while disconnected are not delivered.
+			if err := self.checkReorg(); err != nil {
+				level.Error(self.logger).Log("msg", "checking the challenge block", "err", err)
 			}
-
-			if !event.Raw.Removed { // For reorg events just cancel the old TXs without sending this one.
-				ctxPending, ctxPendingCncl := context.WithCancel(self.ctx)
-				self.txPending = ctxPendingCncl
-				go self.sendWhenConfirmed(ctxPending, event)
+			if err := self.backfill(); err != nil {
+				level.Error(self.logger).Log("msg", "backfilling the events missed while disconnected", "err", err)
 			}
+		case event := <-events:
+			self.handleEvent(event)
+		}
+	}
+}
+
+func (self *Tasker) handleEvent(event *tellor.ITellorNewChallenge) {
+	level.Debug(self.logger).Log("msg", "new event", "reorg", event.Raw.Removed)
+	if event.Raw.BlockNumber > self.lastBlock {
+		self.lastBlock = event.Raw.BlockNumber
+	}
+	if self.txPending != nil {
+		self.txPending()
+		self.txPending = nil
+	}
+
+	if !event.Raw.Removed { // For reorg events just cancel the old TXs without sending this one.
+		self.lastChallenge = event.CurrentChallenge
+		self.challengeBlock = event.Raw.BlockNumber
+		self.challengeBlockHash = event.Raw.BlockHash
+		ctxPending, ctxPendingCncl := context.WithCancel(self.ctx)
+		self.txPending = ctxPendingCncl
+		go self.sendWhenConfirmed(ctxPending, event)
+		return
+	}
+
+	// When the reorg removed the last challenge the canonical chain
+	// might still be at the previous challenge which doesn't emit a new event.
+	if event.Raw.BlockHash == self.challengeBlockHash {
+		self.resync()
+	}
+}
+
+// checkReorg checks that the block of the last challenge is still in the canonical chain.
+func (self *Tasker) checkReorg() error {
+	if self.challengeBlock == 0 {
+		return nil
+	}
+	header, err := self.client.HeaderByNumber(self.ctx, new(big.Int).SetUint64(self.challengeBlock))
+	if err != nil && err != ethereum.NotFound {
+		return errors.Wrap(err, "getting header")
+	}
+	if header == nil || header.Hash() != self.challengeBlockHash {
+		level.Info(self.logger).Log("msg", "the block of the last challenge was reorged", "block", self.challengeBlock)
+		if self.txPending != nil {
+			self.txPending()
+			self.txPending = nil
+		}
+		self.resync()
+	}
+	return nil
+}
+
+// resync sends the current challenge from the contract when it is not the last processed one.
+func (self *Tasker) resync() {
+	self.challengeBlock = 0
+	self.challengeBlockHash = common.Hash{}
+
+	current, err := self.currentChallenge()
+	if err != nil {
+		level.Error(self.logger).Log("msg", "getting the current challenge after a reorg", "err", err)
+		return
+	}
+	if current.CurrentChallenge == self.lastChallenge {
+		return
+	}
+	level.Info(self.logger).Log("msg", "sending the canonical challenge after a reorg")
+	self.lastChallenge = current.CurrentChallenge
+	for _, canceler := range self.SubmitCancelers {
+		canceler.CancelPendingSubmit()
+	}
+	self.sendWork(current)
+}
+
+func (self *Tasker) currentChallenge() (*tellor.ITellorNewChallenge, error) {
+	newVariables, err := self.contract.GetNewCurrentVariables(&bind.CallOpts{Context: self.ctx})
+	if err != nil {
+		return nil, errors.Wrap(err, "getting GetNewCurrentVariables")
+	}
+	return &tellor.ITellorNewChallenge{
+		CurrentChallenge: newVariables.Challenge,
+		Difficulty:       newVariables.Difficutly,
+		CurrentRequestId: newVariables.RequestIds,
+		TotalTips:        newVariables.Tip,
+	}, nil
+}
+
+// backfill gets the events emitted since the last processed block
+// and processes the latest challenge when it was missed.
+// Only the latest challenge matters as the older ones can't be submitted anymore.
+func (self *Tasker) backfill() error {
+	if self.lastBlock == 0 {
+		return nil
+	}
+	head, err := self.client.BlockNumber(self.ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting the block number")
+	}
+	tellorFilterer, err := tellor.NewITellorFilterer(self.contract.Address, self.client)
+	if err != nil {
+		return errors.Wrap(err, "getting filter instance")
+	}
+	iter, err := tellorFilterer.FilterNewChallenge(&bind.FilterOpts{Context: self.ctx, Start: self.lastBlock, End: &head}, nil)
+	if err != nil {
+		return errors.Wrap(err, "getting the past events")
+	}
+	defer iter.Close()
+
+	var last *tellor.ITellorNewChallenge
+	for iter.Next() {
+		if !iter.Event.Raw.Removed {
+			last = iter.Event
 		}
 	}
+	if err := iter.Error(); err != nil {
+		return errors.Wrap(err, "reading the past events")
+	}
+
+	if last != nil && last.CurrentChallenge != self.lastChallenge {
+		level.Info(self.logger).Log("msg", "backfilled a challenge missed while disconnected", "block", last.Raw.BlockNumber, "from", self.lastBlock, "to", head)
+		self.handleEvent(last)
+	}
+	self.lastBlock = head
+	return nil
 }
 
 func (self *Tasker) sendWhenConfirmed(ctx context.Context, vLog *tellor.ITellorNewChallenge) {
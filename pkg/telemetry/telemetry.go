@@ -0,0 +1,274 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+// Package telemetry sends anonymous operational stats of a deployment
+// when the operator opts in so that the team knows how telliot is used.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/logging"
+)
+
+const (
+	ComponentName = "telemetry"
+	// IDFileName is the file in the db directory that keeps the random id of the deployment.
+	IDFileName = "telemetry-id"
+)
+
+type Config struct {
+	LogLevel string
+	Enabled  bool            `help:"Send anonymous operational stats to the endpoint. Off by default. No addresses, keys, values or hosts are sent."`
+	Endpoint string          `help:"URL that receives the stats as a JSON POST request."`
+	Interval format.Duration `help:"How often to send the stats."`
+	Exclude  []string        `help:"Fields of the report not to send, for example chainID or accounts."`
+}
+
+// Report is everything that is sent.
+// The id is random and only tells apart the reports of different deployments.
+type Report struct {
+	ID                string  `json:"id"`
+	Version           string  `json:"version"`
+	OS                string  `json:"os"`
+	Arch              string  `json:"arch"`
+	ChainID           int64   `json:"chainID"`
+	UptimeSeconds     int64   `json:"uptimeSeconds"`
+	Accounts          int     `json:"accounts"`
+	TrackedSymbols    int     `json:"trackedSymbols"`
+	Submits           uint64  `json:"submits"`
+	SubmitFails       uint64  `json:"submitFails"`
+	SubmitSuccessRate float64 `json:"submitSuccessRate"`
+}
+
+// Reporter sends a report every interval with the submits since the previous report.
+type Reporter struct {
+	logger   log.Logger
+	ctx      context.Context
+	close    context.CancelFunc
+	cfg      Config
+	client   *http.Client
+	gatherer prometheus.Gatherer
+	started  time.Time
+	report   Report
+	// The submit totals at the previous report.
+	submits, fails uint64
+}
+
+// New creates a reporter for the deployment.
+// The id is kept in the idDir so that a restart reports as the same deployment,
+// an empty idDir uses a new id on every start.
+func New(logger log.Logger, ctx context.Context, cfg Config, gatherer prometheus.Gatherer, version string, chainID int64, accounts int, idDir string) (*Reporter, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	if cfg.Endpoint == "" {
+		return nil, errors.New("the telemetry endpoint is required")
+	}
+	if cfg.Interval.Duration <= 0 {
+		return nil, errors.Errorf("invalid telemetry interval:%v", cfg.Interval)
+	}
+	id, err := loadID(idDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading the telemetry id")
+	}
+	if version == "" {
+		version = "dev"
+	}
+
+	ctx, close := context.WithCancel(ctx)
+	return &Reporter{
+		logger:   log.With(logger, "component", ComponentName),
+		ctx:      ctx,
+		close:    close,
+		cfg:      cfg,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		gatherer: gatherer,
+		started:  time.Now(),
+		report: Report{
+			ID:       id,
+			Version:  version,
+			OS:       runtime.GOOS,
+			Arch:     runtime.GOARCH,
+			ChainID:  chainID,
+			Accounts: accounts,
+		},
+	}, nil
+}
+
+func (self *Reporter) Start() {
+	level.Info(self.logger).Log("msg", "sending anonymous stats", "endpoint", self.cfg.Endpoint, "interval", self.cfg.Interval, "id", self.report.ID)
+	ticker := time.NewTicker(self.cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := self.send(); err != nil {
+			level.Warn(self.logger).Log("msg", "sending the stats", "err", err)
+		}
+	}
+}
+
+func (self *Reporter) Stop() {
+	self.close()
+}
+
+func (self *Reporter) send() error {
+	body, err := self.build()
+	if err != nil {
+		return err
+	}
+	level.Debug(self.logger).Log("msg", "sending the stats", "report", string(body))
+
+	req, err := http.NewRequestWithContext(self.ctx, http.MethodPost, self.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "creating the request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "posting the stats")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("response status code not OK code:%v", resp.StatusCode)
+	}
+	return nil
+}
+
+// build returns the JSON of the report without the excluded fields.
+func (self *Reporter) build() ([]byte, error) {
+	metrics, err := self.gatherer.Gather()
+	if err != nil {
+		return nil, errors.Wrap(err, "gathering the metrics")
+	}
+	report := self.report
+	report.UptimeSeconds = int64(time.Since(self.started).Seconds())
+	report.TrackedSymbols = trackedSymbols(metrics)
+
+	submits, fails := submitTotals(metrics)
+	// A restarted counter starts again from 0.
+	if submits < self.submits || fails < self.fails {
+		self.submits, self.fails = 0, 0
+	}
+	report.Submits = submits - self.submits
+	report.SubmitFails = fails - self.fails
+	self.submits, self.fails = submits, fails
+	if total := report.Submits + report.SubmitFails; total > 0 {
+		report.SubmitSuccessRate = float64(report.Submits) / float64(total)
+	}
+
+	b, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	if len(self.cfg.Exclude) == 0 {
+		return b, nil
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range self.cfg.Exclude {
+		for field := range fields {
+			if strings.EqualFold(field, name) {
+				delete(fields, field)
+			}
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// trackedSymbols counts the symbols with a value of the index tracker.
+func trackedSymbols(metrics []*dto.MetricFamily) int {
+	symbols := make(map[string]struct{})
+	for _, family := range metrics {
+		if family.GetName() != "telliot_indexTracker_value" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "symbol" {
+					symbols[l.GetValue()] = struct{}{}
+				}
+			}
+		}
+	}
+	return len(symbols)
+}
+
+// submitTotals sums the submits and the failed submits of all submitters and accounts.
+func submitTotals(metrics []*dto.MetricFamily) (submits, fails uint64) {
+	for _, family := range metrics {
+		name := family.GetName()
+		if !strings.HasPrefix(name, "telliot_submitter") {
+			continue
+		}
+		var sum float64
+		for _, m := range family.GetMetric() {
+			sum += m.GetCounter().GetValue()
+		}
+		switch {
+		case strings.HasSuffix(name, "_submit_fails_total"):
+			fails += uint64(sum)
+		case strings.HasSuffix(name, "_submit_total"):
+			submits += uint64(sum)
+		}
+	}
+	return submits, fails
+}
+
+// loadID reads the id of the deployment or creates a new random one.
+func loadID(dir string) (string, error) {
+	if dir == "" {
+		return newID()
+	}
+	path := filepath.Join(dir, IDFileName)
+	b, err := ioutil.ReadFile(path)
+	if err == nil && len(bytes.TrimSpace(b)) > 0 {
+		return string(bytes.TrimSpace(b)), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(path, []byte(id), 0640); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
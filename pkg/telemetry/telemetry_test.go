@@ -0,0 +1,77 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestReporter(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	submits := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "telliot_submitterTellor_submit_total"}, []string{"account"})
+	fails := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "telliot_submitterTellor_submit_fails_total"}, []string{"account"})
+	values := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "telliot_indexTracker_value"}, []string{"symbol", "source"})
+	reg.MustRegister(submits, fails, values)
+	values.WithLabelValues("ETH/USD", "a").Set(1)
+	values.WithLabelValues("ETH/USD", "b").Set(1)
+	values.WithLabelValues("BTC/USD", "a").Set(1)
+	submits.WithLabelValues("0x1").Add(3)
+	submits.WithLabelValues("0x2").Add(3)
+	fails.WithLabelValues("0x1").Add(2)
+
+	var received map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testutil.Ok(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "telemetry")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := Config{
+		LogLevel: "info",
+		Enabled:  true,
+		Endpoint: srv.URL,
+		Interval: format.Duration{Duration: time.Hour},
+		Exclude:  []string{"ChainID"},
+	}
+	reporter, err := New(log.NewNopLogger(), context.Background(), cfg, reg, "v1.0.0", 1, 2, dir)
+	testutil.Ok(t, err)
+
+	testutil.Ok(t, reporter.send())
+	testutil.Equals(t, "v1.0.0", received["version"])
+	testutil.Equals(t, float64(2), received["trackedSymbols"])
+	testutil.Equals(t, float64(6), received["submits"])
+	testutil.Equals(t, float64(2), received["submitFails"])
+	testutil.Equals(t, 0.75, received["submitSuccessRate"])
+	_, ok := received["chainID"]
+	testutil.Assert(t, !ok, "an excluded field was sent")
+
+	// Only the submits since the previous report are sent.
+	submits.WithLabelValues("0x1").Add(1)
+	testutil.Ok(t, reporter.send())
+	testutil.Equals(t, float64(1), received["submits"])
+	testutil.Equals(t, float64(0), received["submitFails"])
+	testutil.Equals(t, 1.0, received["submitSuccessRate"])
+
+	// A restart keeps the id.
+	id := received["id"]
+	reporter, err = New(log.NewNopLogger(), context.Background(), cfg, reg, "v1.0.0", 1, 2, dir)
+	testutil.Ok(t, err)
+	testutil.Ok(t, reporter.send())
+	testutil.Equals(t, id, received["id"])
+}
@@ -2,6 +2,7 @@ package testutil
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -28,6 +29,21 @@ func TolerantVerifyLeakMain(m *testing.M) {
 	)
 }
 
+// Setenv sets the env variable for the duration of the test
+// and restores its previous value when the test ends.
+func Setenv(tb testing.TB, key, value string) {
+	tb.Helper()
+	prev, ok := os.LookupEnv(key)
+	Ok(tb, os.Setenv(key, value))
+	tb.Cleanup(func() {
+		if ok {
+			Ok(tb, os.Setenv(key, prev))
+			return
+		}
+		Ok(tb, os.Unsetenv(key))
+	})
+}
+
 // Assert fails the test if the condition is false.
 func Assert(tb testing.TB, condition bool, v ...interface{}) {
 	tb.Helper()
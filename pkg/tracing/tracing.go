@@ -0,0 +1,104 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"os"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const ComponentName = "tracing"
+
+// HeadersEnvName is the env variable with the headers of the OTLP requests like the auth token of a hosted collector.
+// It is read by the exporter itself.
+const HeadersEnvName = "OTEL_EXPORTER_OTLP_HEADERS"
+
+type Config struct {
+	LogLevel    string
+	Enabled     bool    `help:"Trace the fetch, parse, append, aggregate and submit stages and export the spans with OTLP over HTTP."`
+	Endpoint    string  `help:"OTLP HTTP endpoint of the collector like http://localhost:4318."`
+	SampleRatio float64 `help:"Share of the traces to keep from 0 to 1."`
+	ServiceName string  `help:"Service name of the spans to tell apart the miners that export to the same collector."`
+}
+
+// Tracer returns the tracer of a component.
+// Without Setup the spans are not recorded.
+func Tracer(component string) trace.Tracer {
+	return otel.Tracer("github.com/tellor-io/telliot/" + component)
+}
+
+// End sets the status of the span from the error and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Setup registers the global tracer provider that exports to the collector
+// and returns a function that flushes the remaining spans.
+func Setup(logger log.Logger, ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	logger = log.With(logger, "component", ComponentName)
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse tracing endpoint:%v", cfg.Endpoint)
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint.Host)}
+	switch endpoint.Scheme {
+	case "http":
+		opts = append(opts, otlptracehttp.WithInsecure())
+	case "https":
+	default:
+		return nil, errors.Errorf("tracing endpoint without http or https scheme:%v", cfg.Endpoint)
+	}
+	if endpoint.Path != "" && endpoint.Path != "/" {
+		opts = append(opts, otlptracehttp.WithURLPath(endpoint.Path))
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating the otlp exporter")
+	}
+
+	hostname, _ := os.Hostname()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.ServiceName),
+			attribute.String("host.name", hostname),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		level.Error(logger).Log("msg", "exporting spans", "err", err)
+	}))
+	level.Info(logger).Log("msg", "exporting traces", "endpoint", cfg.Endpoint, "sampleRatio", cfg.SampleRatio)
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/testutil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx, submit := Tracer("submitterTellor").Start(context.Background(), "submit")
+	_, fetch := Tracer("indexTracker").Start(ctx, "fetch")
+	End(fetch, errors.New("api down"))
+	End(submit, nil)
+
+	spans := recorder.Ended()
+	testutil.Equals(t, 2, len(spans))
+	testutil.Equals(t, "fetch", spans[0].Name())
+	testutil.Equals(t, codes.Error, spans[0].Status().Code)
+	testutil.Equals(t, "api down", spans[0].Status().Description)
+	testutil.Equals(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	testutil.Equals(t, codes.Unset, spans[1].Status().Code)
+}
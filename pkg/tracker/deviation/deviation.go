@@ -0,0 +1,316 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+// Package deviation compares the latest Tellor value of a request id
+// with the Chainlink feed of the same pair and with the local aggregate
+// for dispute monitoring and for evaluating the oracles.
+package deviation
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/contracts/chainlink"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/logging"
+	psrTellor "github.com/tellor-io/telliot/pkg/psr/tellor"
+)
+
+const ComponentName = "deviationTracker"
+
+const (
+	// ValueMetricName is the name of the stored series with the value of each oracle.
+	ValueMetricName = "oracle_value"
+	// DeviationMetricName is the name of the stored series with the deviation in percent between two oracles.
+	DeviationMetricName = "oracle_deviation_percent"
+)
+
+// The oracles that are compared.
+const (
+	OracleTellor    = "tellor"
+	OracleChainlink = "chainlink"
+	OracleLocal     = "local"
+)
+
+// Mainnet addresses of the Chainlink feeds used by default.
+const (
+	ChainlinkETHUSDMainnet = "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"
+	ChainlinkBTCUSDMainnet = "0xF4030086522a5bEEa4988F8cA5B36dbC97BeE88c"
+)
+
+type Config struct {
+	LogLevel        string
+	Enabled         bool            `help:"Compare the Tellor values with Chainlink and the local aggregates. The default feeds exist only on mainnet."`
+	Interval        format.Duration `help:"How often to read and compare the values."`
+	Feeds           []Feed          `help:"The request ids to compare and the address of the Chainlink feed of the same pair."`
+	ChainlinkMaxAge format.Duration `help:"Chainlink answers updated longer ago than this are left out of the comparison. 0 accepts answers of any age."`
+}
+
+type Feed struct {
+	RequestID int64  `help:"Tellor request id."`
+	Chainlink string `help:"Address of the Chainlink aggregator, empty to compare only with the local aggregate."`
+}
+
+// pair is the two oracles of a deviation, the second is the reference.
+type pair struct {
+	name   string
+	oracle string
+	ref    string
+}
+
+var pairs = []pair{
+	{name: "tellor_chainlink", oracle: OracleTellor, ref: OracleChainlink},
+	{name: "tellor_local", oracle: OracleTellor, ref: OracleLocal},
+	{name: "chainlink_local", oracle: OracleChainlink, ref: OracleLocal},
+}
+
+// Tracker records the values of the oracles for each feed
+// and how much they deviate from each other.
+type Tracker struct {
+	logger     log.Logger
+	ctx        context.Context
+	close      context.CancelFunc
+	cfg        Config
+	appendable storage.Appendable
+
+	tellor    func(ctx context.Context, id int64) (float64, error)
+	chainlink func(ctx context.Context, feed common.Address) (float64, error)
+	local     func(id int64, at time.Time) (float64, error)
+
+	value     *prometheus.GaugeVec
+	deviation *prometheus.GaugeVec
+}
+
+func New(
+	logger log.Logger,
+	ctx context.Context,
+	cfg Config,
+	appendable storage.Appendable,
+	client *ethclient.Client,
+	contract *contracts.ITellor,
+	psr *psrTellor.Psr,
+) (*Tracker, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	if cfg.Interval.Duration <= 0 {
+		return nil, errors.Errorf("invalid deviation tracker interval:%v", cfg.Interval)
+	}
+	if cfg.ChainlinkMaxAge.Duration < 0 {
+		return nil, errors.Errorf("invalid chainlink max age:%v", cfg.ChainlinkMaxAge)
+	}
+	for _, feed := range cfg.Feeds {
+		if _, err := psrTellor.Symbol(feed.RequestID); err != nil {
+			return nil, err
+		}
+		if feed.Chainlink != "" && !common.IsHexAddress(feed.Chainlink) {
+			return nil, errors.Errorf("invalid chainlink address:%v for request id:%v", feed.Chainlink, feed.RequestID)
+		}
+	}
+	ctx, close := context.WithCancel(ctx)
+
+	return &Tracker{
+		logger:     log.With(logger, "component", ComponentName),
+		ctx:        ctx,
+		close:      close,
+		cfg:        cfg,
+		appendable: appendable,
+		tellor: func(ctx context.Context, id int64) (float64, error) {
+			val, ok, err := contract.ITellor.GetLastNewValueById(&bind.CallOpts{Context: ctx}, big.NewInt(id))
+			if err != nil {
+				return 0, err
+			}
+			if !ok {
+				return 0, errors.New("no value on chain")
+			}
+			v, _ := new(big.Float).Quo(new(big.Float).SetInt(val), big.NewFloat(psrTellor.DefaultGranularity)).Float64()
+			return v, nil
+		},
+		chainlink: func(ctx context.Context, feed common.Address) (float64, error) {
+			return chainlinkValue(ctx, client, feed, cfg.ChainlinkMaxAge.Duration)
+		},
+		local: func(id int64, at time.Time) (float64, error) {
+			val, err := psr.GetValue(id, at)
+			return float64(val) / psrTellor.DefaultGranularity, err
+		},
+		value: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "value",
+			Help:      "The latest value of the symbol in the oracle",
+		}, []string{"symbol", "oracle"}),
+		deviation: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "deviation_percent",
+			Help:      "The deviation in percent of the first oracle of the pair from the second",
+		}, []string{"symbol", "pair"}),
+	}, nil
+}
+
+func (self *Tracker) Start() {
+	level.Info(self.logger).Log("msg", "comparing the oracle values", "feeds", len(self.cfg.Feeds), "interval", self.cfg.Interval)
+	ticker := time.NewTicker(self.cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		for _, feed := range self.cfg.Feeds {
+			if err := self.compare(feed); err != nil {
+				level.Error(self.logger).Log("msg", "comparing the oracle values", "id", feed.RequestID, "err", err)
+			}
+		}
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *Tracker) Stop() {
+	self.close()
+}
+
+// compare reads the values of all oracles for the feed and records them with their deviations.
+// An oracle without a value is skipped so that the others are still compared.
+func (self *Tracker) compare(feed Feed) error {
+	symbol, err := psrTellor.Symbol(feed.RequestID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	values := make(map[string]float64)
+
+	if v, err := self.tellor(self.ctx, feed.RequestID); err != nil {
+		level.Warn(self.logger).Log("msg", "getting the tellor value", "symbol", symbol, "err", err)
+	} else {
+		values[OracleTellor] = v
+	}
+	if feed.Chainlink != "" {
+		if v, err := self.chainlink(self.ctx, common.HexToAddress(feed.Chainlink)); err != nil {
+			level.Warn(self.logger).Log("msg", "getting the chainlink value", "symbol", symbol, "err", err)
+		} else {
+			values[OracleChainlink] = v
+		}
+	}
+	if v, err := self.local(feed.RequestID, now); err != nil {
+		level.Warn(self.logger).Log("msg", "getting the local value", "symbol", symbol, "err", err)
+	} else {
+		values[OracleLocal] = v
+	}
+
+	deviations := make(map[string]float64)
+	for _, p := range pairs {
+		v, ok := values[p.oracle]
+		ref, okRef := values[p.ref]
+		if !ok || !okRef || ref == 0 {
+			continue
+		}
+		deviations[p.name] = (v - ref) / ref * 100
+	}
+
+	for oracle, v := range values {
+		self.value.With(prometheus.Labels{"symbol": symbol, "oracle": oracle}).Set(v)
+	}
+	for name, d := range deviations {
+		self.deviation.With(prometheus.Labels{"symbol": symbol, "pair": name}).Set(d)
+	}
+	level.Debug(self.logger).Log("msg", "compared the oracle values", "symbol", symbol, "values", len(values), "deviations", len(deviations))
+	return self.record(symbol, now, values, deviations)
+}
+
+func (self *Tracker) record(symbol string, now time.Time, values, deviations map[string]float64) (err error) {
+	appender := self.appendable.Appender(self.ctx)
+	defer func() { // An appender always needs to be committed or rolled back.
+		if err != nil {
+			if err := appender.Rollback(); err != nil {
+				level.Error(self.logger).Log("msg", "db rollback failed", "err", err)
+			}
+			return
+		}
+		if errC := appender.Commit(); errC != nil {
+			err = errors.Wrap(errC, "db append commit failed")
+		}
+	}()
+
+	ts := timestamp.FromTime(now)
+	for oracle, v := range values {
+		lbls := labels.Labels{
+			labels.Label{Name: "__name__", Value: ValueMetricName},
+			labels.Label{Name: "symbol", Value: symbol},
+			labels.Label{Name: "oracle", Value: oracle},
+		}
+		sort.Sort(lbls) // This is important! The labels need to be sorted to avoid creating the same series with duplicate reference.
+		if _, err := appender.Append(0, lbls, ts, v); err != nil {
+			return errors.Wrap(err, "append values to the DB")
+		}
+	}
+	for name, d := range deviations {
+		lbls := labels.Labels{
+			labels.Label{Name: "__name__", Value: DeviationMetricName},
+			labels.Label{Name: "symbol", Value: symbol},
+			labels.Label{Name: "pair", Value: name},
+		}
+		sort.Sort(lbls)
+		if _, err := appender.Append(0, lbls, ts, d); err != nil {
+			return errors.Wrap(err, "append deviations to the DB")
+		}
+	}
+	return nil
+}
+
+// chainlinkValue returns the latest answer of the Chainlink feed
+// or an error when it was updated longer ago than the max age.
+func chainlinkValue(ctx context.Context, client *ethclient.Client, feed common.Address, maxAge time.Duration) (float64, error) {
+	caller, err := chainlink.NewAggregatorV3InterfaceCaller(feed, client)
+	if err != nil {
+		return 0, errors.Wrap(err, "creating the chainlink feed instance")
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	decimals, err := caller.Decimals(opts)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting the feed decimals")
+	}
+	round, err := caller.LatestRoundData(opts)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting the latest round")
+	}
+	if round.Answer == nil || round.Answer.Sign() <= 0 {
+		return 0, errors.Errorf("invalid answer:%v", round.Answer)
+	}
+	if err := checkAnswerAge(round.UpdatedAt, time.Now(), maxAge); err != nil {
+		return 0, err
+	}
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	v, _ := new(big.Float).Quo(new(big.Float).SetInt(round.Answer), divisor).Float64()
+	return v, nil
+}
+
+// checkAnswerAge returns an error when the answer updated at the unix time is older than the max age,
+// for example when the feed is stalled or deprecated.
+func checkAnswerAge(updatedAt *big.Int, now time.Time, maxAge time.Duration) error {
+	if updatedAt == nil || updatedAt.Sign() <= 0 {
+		return errors.New("the answer has no update time")
+	}
+	if maxAge == 0 {
+		return nil
+	}
+	if age := now.Sub(time.Unix(updatedAt.Int64(), 0)); age > maxAge {
+		return errors.Errorf("the answer is stale, updated:%v ago which is more than the max age:%v", age.Round(time.Second), maxAge)
+	}
+	return nil
+}
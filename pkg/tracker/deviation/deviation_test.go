@@ -0,0 +1,81 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package deviation
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+type memAppendable struct {
+	samples map[string]float64
+}
+
+func (self *memAppendable) Appender(context.Context) storage.Appender { return self }
+
+func (self *memAppendable) Append(ref uint64, l labels.Labels, t int64, v float64) (uint64, error) {
+	self.samples[l.String()] = v
+	return 0, nil
+}
+
+func (self *memAppendable) AppendExemplar(uint64, labels.Labels, exemplar.Exemplar) (uint64, error) {
+	return 0, nil
+}
+func (self *memAppendable) Commit() error   { return nil }
+func (self *memAppendable) Rollback() error { return nil }
+
+func TestCompare(t *testing.T) {
+	db := &memAppendable{samples: make(map[string]float64)}
+	tracker := &Tracker{
+		logger:     log.NewNopLogger(),
+		ctx:        context.Background(),
+		appendable: db,
+		tellor: func(ctx context.Context, id int64) (float64, error) {
+			return 102, nil
+		},
+		chainlink: func(ctx context.Context, feed common.Address) (float64, error) {
+			return 100, nil
+		},
+		local: func(id int64, at time.Time) (float64, error) {
+			return 0, errors.New("no values")
+		},
+		value:     prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "value"}, []string{"symbol", "oracle"}),
+		deviation: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "deviation_percent"}, []string{"symbol", "pair"}),
+	}
+
+	testutil.Ok(t, tracker.compare(Feed{RequestID: 1, Chainlink: ChainlinkETHUSDMainnet}))
+	testutil.Equals(t, 100.0, db.samples[`{__name__="oracle_value", oracle="chainlink", symbol="ETH/USD"}`])
+	testutil.Equals(t, 2.0, db.samples[`{__name__="oracle_deviation_percent", pair="tellor_chainlink", symbol="ETH/USD"}`])
+
+	// Without a local value only the deviation between the chains is recorded.
+	testutil.Equals(t, 3, len(db.samples))
+
+	tracker.local = func(id int64, at time.Time) (float64, error) {
+		return 200, nil
+	}
+	testutil.Ok(t, tracker.compare(Feed{RequestID: 1, Chainlink: ChainlinkETHUSDMainnet}))
+	testutil.Equals(t, -49.0, db.samples[`{__name__="oracle_deviation_percent", pair="tellor_local", symbol="ETH/USD"}`])
+	testutil.Equals(t, -50.0, db.samples[`{__name__="oracle_deviation_percent", pair="chainlink_local", symbol="ETH/USD"}`])
+	testutil.Equals(t, 6, len(db.samples))
+}
+
+func TestCheckAnswerAge(t *testing.T) {
+	now := time.Now()
+	updated := big.NewInt(now.Add(-2 * time.Hour).Unix())
+	testutil.Ok(t, checkAnswerAge(updated, now, 3*time.Hour))
+	testutil.NotOk(t, checkAnswerAge(updated, now, time.Hour))
+	testutil.Ok(t, checkAnswerAge(updated, now, 0), "0 should accept answers of any age")
+	testutil.NotOk(t, checkAnswerAge(big.NewInt(0), now, 0), "an incomplete round should be rejected")
+}
@@ -5,16 +5,22 @@ package dispute
 
 import (
 	"context"
+	"fmt"
+	gomath "math"
+	"math/big"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/timestamp"
 	"github.com/prometheus/prometheus/tsdb"
@@ -22,6 +28,7 @@ import (
 	"github.com/tellor-io/telliot/pkg/contracts/tellor"
 	"github.com/tellor-io/telliot/pkg/logging"
 	"github.com/tellor-io/telliot/pkg/math"
+	"github.com/tellor-io/telliot/pkg/notify"
 	psrTellor "github.com/tellor-io/telliot/pkg/psr/tellor"
 )
 
@@ -30,13 +37,17 @@ const ComponentName = "disputeTracker"
 const reorgEventWait = 3 * time.Minute
 
 type Config struct {
-	LogLevel string
+	LogLevel       string
+	AlertThreshold float64 `help:"Percentage difference between a submitted value and the local value at the same time that raises an alert. Disputed values above it get a recommendation to support the dispute. 0 disables the alerts and the recommendations."`
+	PrepareDispute bool    `help:"Include in the alert the command to begin a dispute for the submitted value so it can be started after a manual review."`
+	AutoVote       bool    `help:"Vote automatically with all accounts on open disputes when the local values give a confident recommendation."`
 }
 
 type Dispute struct {
 	logger        log.Logger
 	ctx           context.Context
 	close         context.CancelFunc
+	cfgMtx        sync.Mutex
 	cfg           Config
 	tsDB          *tsdb.DB
 	client        *ethclient.Client
@@ -44,6 +55,8 @@ type Dispute struct {
 	pendingAppend map[string]context.CancelFunc
 	mtx           sync.Mutex
 	psrTellor     *psrTellor.Psr
+	notifier      *notify.Notifier
+	alerts        *prometheus.CounterVec
 }
 
 func New(
@@ -54,8 +67,9 @@ func New(
 	client *ethclient.Client,
 	contract *contracts.ITellor,
 	psrTellor *psrTellor.Psr,
+	notifier *notify.Notifier,
 ) (*Dispute, error) {
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "apply filter logger")
 	}
@@ -66,12 +80,19 @@ func New(
 		client:        client,
 		contract:      contract,
 		psrTellor:     psrTellor,
+		notifier:      notifier,
 		cfg:           cfg,
 		ctx:           ctx,
 		close:         close,
 		tsDB:          tsDB,
 		logger:        logger,
 		pendingAppend: make(map[string]context.CancelFunc),
+		alerts: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "alerts_total",
+			Help:      "The total number of submitted values that deviated from the local value more than the alert threshold",
+		}, []string{"id"}),
 	}, nil
 }
 
@@ -132,7 +153,7 @@ func (self *Dispute) Start() {
 			level.Debug(self.logger).Log(
 				"msg", "new event",
 				"removed", event.Raw.Removed,
-				"hash", event.Raw.TxHash.String()[:8],
+				"tx", event.Raw.TxHash.String(),
 				"miner", event.Miner.String()[:8],
 			)
 			if event.Raw.Removed {
@@ -158,7 +179,7 @@ func (self *Dispute) Start() {
 					}
 					self.removePending(event)
 				case <-ctx.Done():
-					level.Debug(self.logger).Log("msg", "append canceled", "hash", event.Raw.TxHash.String()[:8])
+					level.Debug(self.logger).Log("msg", "append canceled", "tx", event.Raw.TxHash.String())
 					return
 				}
 			}(ctx)
@@ -184,13 +205,36 @@ func (self *Dispute) Stop() {
 	self.close()
 }
 
+// Reload applies the alert settings of a new config without a restart.
+func (self *Dispute) Reload(cfg Config) {
+	self.cfgMtx.Lock()
+	defer self.cfgMtx.Unlock()
+	self.cfg.AlertThreshold = cfg.AlertThreshold
+	self.cfg.PrepareDispute = cfg.PrepareDispute
+}
+
+func (self *Dispute) config() Config {
+	self.cfgMtx.Lock()
+	defer self.cfgMtx.Unlock()
+	return self.cfg
+}
+
 func (self *Dispute) addValTellor(event *tellor.TellorNonceSubmitted) (err error) {
+	cfg := self.config()
 	appender := self.tsDB.Appender(self.ctx)
 
 	// Round up the time so that all appends happen with the same TS and
 	// avoid out of order samples errors.
 	ts := timestamp.FromTime(time.Now().Round(5 * time.Second))
 
+	// Compare with the local value at the time of the submission.
+	submitTime := time.Now().Add(-reorgEventWait)
+	if header, err := self.client.HeaderByHash(self.ctx, event.Raw.BlockHash); err == nil {
+		submitTime = time.Unix(int64(header.Time), 0)
+	} else {
+		level.Warn(self.logger).Log("msg", "getting the submit block time, using the approximate time", "err", err)
+	}
+
 	defer func() { // An appender always needs to be committed or rolled back.
 		if err != nil {
 			if err := appender.Rollback(); err != nil {
@@ -199,7 +243,7 @@ func (self *Dispute) addValTellor(event *tellor.TellorNonceSubmitted) (err error
 			return
 		}
 		if errC := appender.Commit(); errC != nil {
-			err = errors.Wrap(err, "db append commit failed")
+			err = errors.Wrap(errC, "db append commit failed")
 		}
 	}()
 
@@ -216,7 +260,7 @@ func (self *Dispute) addValTellor(event *tellor.TellorNonceSubmitted) (err error
 		if err != nil {
 			return errors.Wrap(err, "append values to the DB")
 		}
-		valExp, err := self.psrTellor.GetValue(event.RequestId[i].Int64(), time.Now().Add(-reorgEventWait))
+		valExp, err := self.psrTellor.GetValue(event.RequestId[i].Int64(), submitTime)
 		if err != nil {
 			return errors.Wrapf(err, "getting value from the PSR id:%v", event.RequestId[i].Int64())
 		}
@@ -233,18 +277,80 @@ func (self *Dispute) addValTellor(event *tellor.TellorNonceSubmitted) (err error
 			return errors.Wrap(err, "append values to the DB")
 		}
 
+		diff := math.PercentageDiff(float64(valAct.Int64()), float64(valExp))
 		level.Debug(self.logger).Log(
 			"msg", "added dispute tracker values",
 			"id", event.RequestId[i].String(),
 			"miner", event.Miner.String(),
 			"oracleValue", valAct,
 			"psrValue", valExp,
-			"difference", math.PercentageDiff(float64(valAct.Int64()), float64(valExp)),
+			"difference", diff,
 		)
+
+		if cfg.AlertThreshold > 0 && gomath.Abs(diff) >= cfg.AlertThreshold {
+			self.alert(event, event.RequestId[i], valAct, valExp, diff)
+		}
 	}
 	return nil
 }
 
+// alert notifies the operator about a submitted value that deviates too much from the local value.
+// The dispute is never started automatically as it costs a dispute fee which is lost
+// when the dispute fails so the alert only includes the command to start it after a manual review.
+func (self *Dispute) alert(event *tellor.TellorNonceSubmitted, reqID, valAct *big.Int, valExp int64, diff float64) {
+	cfg := self.config()
+	self.alerts.With(prometheus.Labels{"id": reqID.String()}).Inc()
+
+	msg := fmt.Sprintf(
+		"miner %v submitted %v for request id %v which differs by %.2f%% from the local value %v, tx:%v",
+		event.Miner.String(), valAct, reqID, diff, valExp, event.Raw.TxHash.String(),
+	)
+	if cfg.PrepareDispute {
+		cmd, err := self.disputeCmd(event.Miner, reqID)
+		if err != nil {
+			level.Error(self.logger).Log("msg", "preparing the dispute", "id", reqID, "err", err)
+			msg += ", the dispute couldn't be prepared:" + err.Error()
+		} else {
+			msg += ", to dispute it run:" + cmd
+		}
+	}
+
+	self.notifier.Notify(notify.Event{
+		Component: ComponentName,
+		Severity:  notify.SeverityWarning,
+		Title:     "submitted value deviates from the local value",
+		Message:   msg,
+	})
+}
+
+// disputeCmd returns the command that begins a dispute for the value
+// submitted by the miner for the latest timestamp of the request id.
+func (self *Dispute) disputeCmd(miner common.Address, reqID *big.Int) (string, error) {
+	opts := &bind.CallOpts{Context: self.ctx}
+	count, err := self.contract.ITellor.GetNewValueCountbyRequestId(opts, reqID)
+	if err != nil {
+		return "", errors.Wrap(err, "getting value count")
+	}
+	if count.Sign() == 0 {
+		return "", errors.New("no values for the request id")
+	}
+	ts, err := self.contract.ITellor.GetTimestampbyRequestIDandIndex(opts, reqID, count.Sub(count, big.NewInt(1)))
+	if err != nil {
+		return "", errors.Wrap(err, "getting latest timestamp")
+	}
+	miners, err := self.contract.ITellor.GetMinersByRequestIdAndTimestamp(opts, reqID, ts)
+	if err != nil {
+		return "", errors.Wrap(err, "getting miners")
+	}
+	for i, m := range miners {
+		if m == miner {
+			return fmt.Sprintf("telliot dispute new %v %v %v", reqID, ts, i), nil
+		}
+	}
+	// The block with this value might not be complete yet.
+	return "", errors.Errorf("miner not found in the latest value for timestamp:%v", ts)
+}
+
 func (self *Dispute) newSubTellor(output chan *tellor.TellorNonceSubmitted) (event.Subscription, error) {
 	tellorFilterer, err := tellor.NewTellorFilterer(self.contract.Address, self.client)
 	if err != nil {
@@ -0,0 +1,161 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package dispute
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/math"
+)
+
+const FeeComponentName = "disputeFee"
+
+// feeCheckInterval is how often to check the dispute fee and the open disputes.
+const feeCheckInterval = 10 * time.Minute
+
+// Exposure is the TRB an account has locked in dispute fees for its open disputes.
+type Exposure struct {
+	Addr     common.Address `json:"addr"`
+	Disputes []int64        `json:"disputes"`
+	Locked   float64        `json:"locked"`
+}
+
+// Fee returns the current fee to start a new dispute.
+func Fee(ctx context.Context, contract *contracts.ITellor) (*big.Int, error) {
+	fee, err := contract.GetUintVar(&bind.CallOpts{Context: ctx}, ethereum.Keccak256([]byte("_DISPUTE_FEE")))
+	if err != nil {
+		return nil, errors.Wrap(err, "get dispute fee")
+	}
+	return fee, nil
+}
+
+// Exposures returns the dispute fees locked in the open disputes started by each of the addresses.
+func Exposures(ctx context.Context, contract *contracts.ITellor, addrs []common.Address) ([]*Exposure, error) {
+	exposures := make([]*Exposure, len(addrs))
+	for i, addr := range addrs {
+		exposures[i] = &Exposure{Addr: addr, Disputes: []int64{}}
+	}
+
+	ids, err := OpenDisputes(ctx, contract)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting open disputes")
+	}
+	for _, id := range ids {
+		_, _, _, _, _, reportingParty, _, uintVars, _, err := contract.GetAllDisputeVars(&bind.CallOpts{Context: ctx}, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get dispute details id:%v", id)
+		}
+		for _, exp := range exposures {
+			if exp.Addr == reportingParty {
+				exp.Disputes = append(exp.Disputes, id.Int64())
+				exp.Locked += math.BigInt18eToFloat(uintVars[8])
+			}
+		}
+	}
+	return exposures, nil
+}
+
+// FeeTracker records the current dispute fee and the fees locked
+// in the open disputes of the accounts so disputers can manage their capital.
+type FeeTracker struct {
+	logger   log.Logger
+	ctx      context.Context
+	close    context.CancelFunc
+	contract *contracts.ITellor
+	addrs    []common.Address
+	fee      prometheus.Gauge
+	locked   *prometheus.GaugeVec
+	open     *prometheus.GaugeVec
+}
+
+func NewFeeTracker(
+	logger log.Logger,
+	ctx context.Context,
+	cfg Config,
+	contract *contracts.ITellor,
+	addrs []common.Address,
+) (*FeeTracker, error) {
+	logger, err := logging.ApplyFilter(FeeComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	logger = log.With(logger, "component", FeeComponentName)
+	ctx, close := context.WithCancel(ctx)
+
+	return &FeeTracker{
+		logger:   logger,
+		ctx:      ctx,
+		close:    close,
+		contract: contract,
+		addrs:    addrs,
+		fee: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: FeeComponentName,
+			Name:      "fee_trb",
+			Help:      "The current fee in TRB to start a new dispute",
+		}),
+		locked: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: FeeComponentName,
+			Name:      "locked_trb",
+			Help:      "The TRB locked in dispute fees for the open disputes started by the account",
+		}, []string{"addr"}),
+		open: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: FeeComponentName,
+			Name:      "open_disputes",
+			Help:      "The number of open disputes started by the account",
+		}, []string{"addr"}),
+	}, nil
+}
+
+func (self *FeeTracker) Start() {
+	ticker := time.NewTicker(feeCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := self.record(); err != nil {
+			level.Error(self.logger).Log("msg", "recording dispute fees", "err", err)
+		}
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *FeeTracker) Stop() {
+	self.close()
+}
+
+func (self *FeeTracker) record() error {
+	fee, err := Fee(self.ctx, self.contract)
+	if err != nil {
+		return err
+	}
+	self.fee.Set(math.BigInt18eToFloat(fee))
+
+	exposures, err := Exposures(self.ctx, self.contract, self.addrs)
+	if err != nil {
+		return err
+	}
+	for _, exp := range exposures {
+		self.locked.With(prometheus.Labels{"addr": exp.Addr.String()}).Set(exp.Locked)
+		self.open.With(prometheus.Labels{"addr": exp.Addr.String()}).Set(float64(len(exp.Disputes)))
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package dispute
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/ethereum"
+)
+
+// unlockDelay is how long after the tally the dispute fee can be unlocked.
+const unlockDelay = 24 * time.Hour
+
+// Finalization is the next step needed to finalize a dispute.
+type Finalization struct {
+	DisputeID *big.Int
+	// Tally is set when the voting period ended and the votes are not tallied yet.
+	Tally bool
+	// Unlock is set when the votes are tallied and the dispute fee can be unlocked.
+	Unlock bool
+}
+
+// NextFinalization returns what is needed to finalize the dispute.
+func NextFinalization(ctx context.Context, contract *contracts.ITellor, disputeID *big.Int) (*Finalization, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	_, executed, _, _, _, _, _, uintVars, _, err := contract.GetAllDisputeVars(opts, disputeID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get dispute details id:%v", disputeID)
+	}
+
+	f := &Finalization{DisputeID: disputeID}
+	if !executed {
+		f.Tally = time.Now().After(time.Unix(uintVars[3].Int64(), 0))
+		return f, nil
+	}
+
+	paid, err := contract.GetDisputeUintVars(opts, disputeID, ethereum.Keccak256([]byte("_PAID")))
+	if err != nil {
+		return nil, errors.Wrapf(err, "get dispute paid status id:%v", disputeID)
+	}
+	if paid.Sign() > 0 {
+		return f, nil
+	}
+	tallyDate, err := contract.GetDisputeUintVars(opts, disputeID, ethereum.Keccak256([]byte("_TALLY_DATE")))
+	if err != nil {
+		return nil, errors.Wrapf(err, "get dispute tally date id:%v", disputeID)
+	}
+	f.Unlock = time.Now().After(time.Unix(tallyDate.Int64(), 0).Add(unlockDelay))
+	return f, nil
+}
+
+// Participated returns the ids of all disputes started, reported or voted by any of the addresses.
+func Participated(ctx context.Context, contract *contracts.ITellor, addrs []common.Address) ([]*big.Int, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	count, err := contract.GetUintVar(opts, ethereum.Keccak256([]byte("_DISPUTE_COUNT")))
+	if err != nil {
+		return nil, errors.Wrap(err, "get dispute count")
+	}
+
+	var ids []*big.Int
+	for id := int64(1); id <= count.Int64(); id++ {
+		disputeID := big.NewInt(id)
+		_, _, _, _, reportedMiner, reportingParty, _, _, _, err := contract.GetAllDisputeVars(opts, disputeID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get dispute details id:%v", id)
+		}
+		for _, addr := range addrs {
+			participated := addr == reportedMiner || addr == reportingParty
+			if !participated {
+				participated, err = contract.DidVote(opts, disputeID, addr)
+				if err != nil {
+					return nil, errors.Wrapf(err, "check vote id:%v", id)
+				}
+			}
+			if participated {
+				ids = append(ids, disputeID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
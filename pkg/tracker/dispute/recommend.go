@@ -0,0 +1,126 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package dispute
+
+import (
+	"context"
+	"fmt"
+	gomath "math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/math"
+	psrTellor "github.com/tellor-io/telliot/pkg/psr/tellor"
+)
+
+// confidentFactor is how far the difference should be from the threshold
+// for a recommendation to be confident enough for an automatic vote.
+// For example with a threshold of 10% a difference of 20% or more is a confident support
+// and a difference of 5% or less is a confident oppose.
+const confidentFactor = 2
+
+// openDisputesLookback is how far back to look for open disputes.
+// The voting is open for 7 days so older disputes are most likely already tallied.
+const openDisputesLookback = 14 * 24 * time.Hour
+
+// Recommendation is a suggested vote for a dispute based on the local historical data.
+type Recommendation struct {
+	DisputeID     int64     `json:"disputeId"`
+	RequestID     int64     `json:"requestId"`
+	Timestamp     time.Time `json:"timestamp"`
+	DisputedValue int64     `json:"disputedValue"`
+	LocalValue    int64     `json:"localValue"`
+	// Difference is the percentage difference between the disputed value and the local value.
+	Difference float64 `json:"difference"`
+	// Support is true when the disputed value looks wrong and the dispute should pass.
+	Support bool `json:"support"`
+	// Confident is set when the difference is far enough from the threshold to vote automatically.
+	Confident bool   `json:"confident"`
+	Reason    string `json:"reason"`
+}
+
+// Recommend compares the disputed value with the local value
+// for the same request id and time and recommends how to vote.
+func Recommend(
+	ctx context.Context,
+	contract *contracts.ITellor,
+	psr *psrTellor.Psr,
+	disputeID *big.Int,
+	threshold float64,
+) (*Recommendation, error) {
+	_, _, _, _, _, _, _, uintVars, _, err := contract.GetAllDisputeVars(&bind.CallOpts{Context: ctx}, disputeID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get dispute details")
+	}
+
+	// The dispute vars are:
+	// 0 - request id, 1 - timestamp, 2 - value, 3 - min execution date,
+	// 4 - number of votes, 5 - block number, 6 - miner slot, 7 - quorum, 8 - fee.
+	rec := &Recommendation{
+		DisputeID:     disputeID.Int64(),
+		RequestID:     uintVars[0].Int64(),
+		Timestamp:     time.Unix(uintVars[1].Int64(), 0),
+		DisputedValue: uintVars[2].Int64(),
+	}
+
+	rec.LocalValue, err = psr.GetValue(rec.RequestID, rec.Timestamp)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting local value id:%v timestamp:%v", rec.RequestID, rec.Timestamp)
+	}
+
+	rec.Difference = math.PercentageDiff(float64(rec.DisputedValue), float64(rec.LocalValue))
+	decide(rec, threshold)
+	return rec, nil
+}
+
+// decide sets the vote of the recommendation from its difference.
+// Without a threshold the recommendations are off
+// and none is confident so that the auto voter doesn't vote.
+func decide(rec *Recommendation, threshold float64) {
+	diff := gomath.Abs(rec.Difference)
+	if threshold <= 0 {
+		rec.Support, rec.Confident = false, false
+		rec.Reason = fmt.Sprintf("the disputed value differs by %.2f%% from the local value but no threshold is set", diff)
+		return
+	}
+	rec.Support = diff >= threshold
+	if rec.Support {
+		rec.Confident = diff >= threshold*confidentFactor
+		rec.Reason = fmt.Sprintf("the disputed value differs by %.2f%% from the local value which is more than the threshold of %v%%", diff, threshold)
+	} else {
+		rec.Confident = diff <= threshold/confidentFactor
+		rec.Reason = fmt.Sprintf("the disputed value differs by %.2f%% from the local value which is within the threshold of %v%%", diff, threshold)
+	}
+}
+
+// OpenDisputes returns the ids of the disputes that are not executed yet.
+func OpenDisputes(ctx context.Context, contract *contracts.ITellor) ([]*big.Int, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	count, err := contract.GetUintVar(opts, ethereum.Keccak256([]byte("_DISPUTE_COUNT")))
+	if err != nil {
+		return nil, errors.Wrap(err, "get dispute count")
+	}
+
+	var open []*big.Int
+	// Disputes are numbered sequentially so start from the latest one
+	// and stop when reaching disputes that are too old to still be open.
+	for id := count.Int64(); id > 0; id-- {
+		disputeID := big.NewInt(id)
+		_, executed, _, _, _, _, _, uintVars, _, err := contract.GetAllDisputeVars(opts, disputeID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get dispute details id:%v", id)
+		}
+		if time.Unix(uintVars[3].Int64(), 0).Before(time.Now().Add(-openDisputesLookback)) {
+			break
+		}
+		if !executed {
+			open = append(open, disputeID)
+		}
+	}
+	return open, nil
+}
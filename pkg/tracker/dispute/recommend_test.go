@@ -0,0 +1,31 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package dispute
+
+import (
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestDecide(t *testing.T) {
+	rec := &Recommendation{Difference: -25}
+	decide(rec, 10)
+	testutil.Assert(t, rec.Support && rec.Confident, "expected a confident support")
+
+	rec = &Recommendation{Difference: 15}
+	decide(rec, 10)
+	testutil.Assert(t, rec.Support && !rec.Confident, "expected a support that isn't confident")
+
+	rec = &Recommendation{Difference: 4}
+	decide(rec, 10)
+	testutil.Assert(t, !rec.Support && rec.Confident, "expected a confident oppose")
+
+	// Without a threshold no dispute gets a confident recommendation.
+	for _, threshold := range []float64{0, -5} {
+		rec = &Recommendation{Difference: 90}
+		decide(rec, threshold)
+		testutil.Assert(t, !rec.Support && !rec.Confident, "threshold %v gave a recommendation", threshold)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package dispute
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/gasPrice"
+	"github.com/tellor-io/telliot/pkg/leader"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/notify"
+	psrTellor "github.com/tellor-io/telliot/pkg/psr/tellor"
+	"github.com/tellor-io/telliot/pkg/txs"
+)
+
+const VoterComponentName = "disputeVoter"
+
+// voteCheckInterval is how often to check for new open disputes.
+const voteCheckInterval = time.Hour
+
+// Voter periodically checks all open disputes and recommends how to vote on them.
+// When auto voting is enabled it also votes with all accounts
+// for the disputes with a confident recommendation.
+type Voter struct {
+	logger          log.Logger
+	ctx             context.Context
+	close           context.CancelFunc
+	cfg             Config
+	client          *ethclient.Client
+	gasPriceQuerier gasPrice.GasPriceQuerier
+	contract        *contracts.ITellor
+	psr             *psrTellor.Psr
+	accounts        []*ethereum.Account
+	txs             *txs.Store
+	notifier        *notify.Notifier
+	leader          *leader.Elector
+
+	mtx             sync.Mutex
+	recommendations []*Recommendation
+	// reported are the disputes against the accounts that were already notified.
+	reported map[int64]bool
+}
+
+func NewVoter(
+	logger log.Logger,
+	ctx context.Context,
+	cfg Config,
+	client *ethclient.Client,
+	gasPriceQuerier gasPrice.GasPriceQuerier,
+	contract *contracts.ITellor,
+	psr *psrTellor.Psr,
+	accounts []*ethereum.Account,
+	txStore *txs.Store,
+	notifier *notify.Notifier,
+	leader *leader.Elector,
+) (*Voter, error) {
+	logger, err := logging.ApplyFilter(VoterComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	logger = log.With(logger, "component", VoterComponentName)
+	ctx, close := context.WithCancel(ctx)
+
+	return &Voter{
+		logger:          logger,
+		ctx:             ctx,
+		close:           close,
+		cfg:             cfg,
+		client:          client,
+		gasPriceQuerier: gasPriceQuerier,
+		contract:        contract,
+		psr:             psr,
+		accounts:        accounts,
+		txs:             txStore,
+		notifier:        notifier,
+		leader:          leader,
+		reported:        make(map[int64]bool),
+	}, nil
+}
+
+func (self *Voter) Start() {
+	ticker := time.NewTicker(voteCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := self.check(); err != nil {
+			level.Error(self.logger).Log("msg", "checking open disputes", "err", err)
+		}
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *Voter) Stop() {
+	self.close()
+}
+
+// Recommendations returns the recommendations for the open disputes from the latest check.
+func (self *Voter) Recommendations(ctx context.Context) (interface{}, error) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return append([]*Recommendation{}, self.recommendations...), nil
+}
+
+func (self *Voter) check() error {
+	ids, err := OpenDisputes(self.ctx, self.contract)
+	if err != nil {
+		return errors.Wrap(err, "getting open disputes")
+	}
+
+	var recs []*Recommendation
+	for _, id := range ids {
+		if err := self.checkReported(id); err != nil {
+			level.Error(self.logger).Log("msg", "checking the reported miner", "id", id, "err", err)
+		}
+		rec, err := Recommend(self.ctx, self.contract, self.psr, id, self.cfg.AlertThreshold)
+		if err != nil {
+			level.Error(self.logger).Log("msg", "getting recommendation", "id", id, "err", err)
+			continue
+		}
+		recs = append(recs, rec)
+		level.Info(self.logger).Log(
+			"msg", "dispute recommendation",
+			"id", rec.DisputeID,
+			"support", rec.Support,
+			"confident", rec.Confident,
+			"reason", rec.Reason,
+		)
+
+		// Only the leader votes as the instances share the accounts.
+		if self.cfg.AutoVote && rec.Confident && self.leader.IsLeader() {
+			for _, account := range self.accounts {
+				if err := self.vote(account, rec); err != nil {
+					level.Error(self.logger).Log("msg", "auto voting", "id", rec.DisputeID, "addr", account.Address.String(), "err", err)
+				}
+			}
+		}
+	}
+
+	self.mtx.Lock()
+	self.recommendations = recs
+	self.mtx.Unlock()
+	return nil
+}
+
+// checkReported notifies once about an open dispute against one of the accounts.
+func (self *Voter) checkReported(disputeID *big.Int) error {
+	if self.reported[disputeID.Int64()] {
+		return nil
+	}
+	_, _, _, _, reportedMiner, reportingParty, _, uintVars, _, err := self.contract.GetAllDisputeVars(&bind.CallOpts{Context: self.ctx}, disputeID)
+	if err != nil {
+		return errors.Wrap(err, "get dispute details")
+	}
+	for _, account := range self.accounts {
+		if account.Address != reportedMiner {
+			continue
+		}
+		self.reported[disputeID.Int64()] = true
+		self.notifier.Notify(notify.Event{
+			Component: VoterComponentName,
+			Severity:  notify.SeverityCritical,
+			Title:     "dispute against account",
+			Message: fmt.Sprintf(
+				"%v disputed the value %v of account %v for request id %v at %v, dispute id:%v",
+				reportingParty.String(), uintVars[2], account.Address.String(), uintVars[0], time.Unix(uintVars[1].Int64(), 0).UTC(), disputeID,
+			),
+		})
+	}
+	return nil
+}
+
+func (self *Voter) vote(account *ethereum.Account, rec *Recommendation) error {
+	disputeID := big.NewInt(rec.DisputeID)
+	voted, err := self.contract.DidVote(&bind.CallOpts{Context: self.ctx}, disputeID, account.Address)
+	if err != nil {
+		return errors.Wrap(err, "checking if already voted")
+	}
+	if voted {
+		return nil
+	}
+
+	var price *big.Int
+	if self.gasPriceQuerier != nil {
+		price, err = self.gasPriceQuerier.Query(self.ctx)
+		if err != nil {
+			return errors.Wrap(err, "getting gas price")
+		}
+	}
+	auth, release, err := ethereum.PrepareEthTransaction(self.ctx, self.client, account, price)
+	if err != nil {
+		return errors.Wrap(err, "prepare ethereum transaction")
+	}
+	tx, err := self.contract.Vote(auth, disputeID, rec.Support)
+	if err != nil {
+		release()
+		return errors.Wrap(err, "submit vote transaction")
+	}
+	// The status is updated when listing the history.
+	if err := self.txs.Add(txs.NewRecord(txs.TypeVote, account.Address, tx)); err != nil {
+		level.Error(self.logger).Log("msg", "recording the transaction in the history", "tx", tx.Hash().String(), "err", err)
+	}
+
+	self.notifier.Notify(notify.Event{
+		Component: VoterComponentName,
+		Severity:  notify.SeverityInfo,
+		Title:     "automatic dispute vote",
+		Message: fmt.Sprintf(
+			"account %v voted support:%v for dispute %v, %v, tx:%v",
+			account.Address.String(), rec.Support, rec.DisputeID, rec.Reason, tx.Hash().String(),
+		),
+	})
+	return nil
+}
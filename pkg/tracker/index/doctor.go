@@ -0,0 +1,186 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/schema"
+)
+
+// maxCheckBody is how much of the response is kept in a check for the output.
+const maxCheckBody = 500
+
+// Check is the result of a single get of a data source.
+type Check struct {
+	Symbol   string
+	Source   string
+	Type     IndexType
+	Status   int // The HTTP status code, 0 for the on-chain sources or when the request failed.
+	Body     string
+	Value    float64
+	Time     time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// CheckSources loads the index file and gets and parses every endpoint once without retries
+// so that the problems of a new index file show up without running the tracker.
+// The timeout is used for the endpoints without their own timeout in the index file.
+// The client is needed only for the on-chain sources and can be nil.
+// The problems of the file are returned without checks when it has errors.
+func CheckSources(ctx context.Context, cfg Config, client *ethclient.Client, timeout time.Duration) ([]Check, schema.Problems, error) {
+	// Unlike the tracker it doesn't fall back to the local index file
+	// so that the registry gets checked when it is set.
+	var (
+		b   []byte
+		err error
+	)
+	if cfg.Registry != "" {
+		b, _, err = FetchRegistry(ctx, cfg.Registry, cfg.RegistrySigner)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "get the index file of the registry:%v", cfg.Registry)
+		}
+	} else {
+		b, err = ioutil.ReadFile(cfg.IndexFile)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "read index file path:%s", cfg.IndexFile)
+		}
+	}
+	indexes, problems, err := ParseIndex(b)
+	if err != nil || problems.Err() != nil {
+		return nil, problems, err
+	}
+	indexes, err = shard(cfg, indexes)
+	if err != nil {
+		return nil, problems, err
+	}
+
+	symbols := make([]string, 0, len(indexes))
+	for symbol := range indexes {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var (
+		checks    []Check
+		endpoints []Endpoint
+	)
+	for _, symbol := range symbols {
+		api := indexes[symbol]
+		for _, endpoint := range api.Endpoints {
+			ctx, cncl := context.WithTimeout(ctx, sourceTimeout(api, endpoint, timeout))
+			checks = append(checks, check(ctx, symbol, api, endpoint, client, envFilter(cfg, cfg.Registry != "")))
+			endpoints = append(endpoints, endpoint)
+			cncl()
+		}
+	}
+	convertChecks(checks, endpoints, indexes)
+	return checks, problems, nil
+}
+
+// convertChecks applies the pair conversions and the expected range to the values of the checks.
+// The tracker quotes with its recent values so here the values of the other checks are used instead.
+func convertChecks(checks []Check, endpoints []Endpoint, indexes map[string]Apis) {
+	for i := range checks {
+		if checks[i].Err == nil {
+			checks[i].Value, checks[i].Err = convertPair(checks[i].Value, endpoints[i].Invert, "", nil)
+		}
+	}
+	direct := make(map[string][]float64)
+	for i, c := range checks {
+		if c.Err == nil && endpoints[i].QuoteVia == "" {
+			direct[c.Symbol] = append(direct[c.Symbol], c.Value)
+		}
+	}
+	quote := func(symbol string) (float64, error) {
+		if len(direct[symbol]) == 0 {
+			return 0, errors.Errorf("no successful check of:%v", symbol)
+		}
+		return median(direct[symbol]), nil
+	}
+	for i := range checks {
+		if checks[i].Err == nil {
+			checks[i].Value, checks[i].Err = convertPair(checks[i].Value, false, endpoints[i].QuoteVia, quote)
+		}
+		if checks[i].Err == nil {
+			checks[i].Err = indexes[checks[i].Symbol].Expect.checkValue(checks[i].Value)
+		}
+	}
+}
+
+// check gets the value of the endpoint as returned by the source,
+// the pair conversions and the expected range are applied after all checks.
+func check(ctx context.Context, symbol string, api Apis, endpoint Endpoint, client *ethclient.Client, allowEnv func(string) bool) (c Check) {
+	c = Check{Symbol: symbol, Source: endpoint.URL, Type: endpoint.Type}
+	endpoint.Invert, endpoint.QuoteVia = false, ""
+	if api.Expect != nil {
+		api.Expect = &Expect{Fields: api.Expect.Fields}
+	}
+	endpoint, err := prepare(endpoint, allowEnv)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	// The source keeps the env variables of the url so that the output doesn't show the api keys.
+	c.Type = endpoint.Type
+	start := time.Now()
+	defer func() { c.Duration = time.Since(start) }()
+
+	// The on-chain and the paginated sources return only the value.
+	if endpoint.Type != httpSource || endpoint.Paging != nil {
+		// The context already has the timeout of the source.
+		source, err := newDataSource(ctx, symbol, api, endpoint, client, 0, nil)
+		if err != nil {
+			c.Err = err
+			return c
+		}
+		c.Value, c.Err = source.Get(ctx)
+		c.Time = time.Now()
+		return c
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.URL, nil)
+	if err != nil {
+		c.Err = errors.Wrap(err, "creating the request")
+		return c
+	}
+	// The same transport as the tracker so that the results match.
+	httpClient := http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		c.Err = errors.Wrap(err, "fetching data")
+		return c
+	}
+	defer resp.Body.Close()
+	c.Status = resp.StatusCode
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		c.Err = errors.Wrap(err, "read response body")
+		return c
+	}
+	c.Body = string(body)
+	if len(c.Body) > maxCheckBody {
+		c.Body = c.Body[:maxCheckBody] + "..."
+	}
+	if resp.StatusCode/100 != 2 {
+		c.Err = errors.Errorf("response status code not OK code:%v", resp.StatusCode)
+		return c
+	}
+	if err := api.Expect.checkFields(body); err != nil {
+		c.Err = err
+		return c
+	}
+	c.Value, c.Time, c.Err = NewParser(endpoint).Parse(body)
+	return c
+}
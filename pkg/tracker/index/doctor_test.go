@@ -0,0 +1,60 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestCheckSources(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			fmt.Fprint(w, `{"price": "1.5"}`)
+		case "/text":
+			fmt.Fprint(w, `not json`)
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "doctor")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "index.json")
+	testutil.Ok(t, ioutil.WriteFile(path, []byte(fmt.Sprintf(`{
+		"A/USD": {"endpoints": [{"URL": "%[1]v/ok", "param": "$.price"}, {"URL": "%[1]v/limited", "param": "$.price"}]},
+		"B/USD": {"endpoints": [{"URL": "%[1]v/text", "param": "$.price"}, {"URL": "${DOCTOR_TEST_MISSING}/ok", "param": "$.price"}]}
+	}`, srv.URL)), 0644))
+
+	checks, problems, err := CheckSources(context.Background(), Config{IndexFile: path}, nil, time.Second)
+	testutil.Ok(t, err)
+	testutil.Ok(t, problems.Err())
+	testutil.Equals(t, 4, len(checks))
+
+	// The checks are in the order of the symbols and their endpoints.
+	ok, limited := checks[0], checks[1]
+	testutil.Ok(t, ok.Err)
+	testutil.Equals(t, http.StatusOK, ok.Status)
+	testutil.Equals(t, 1.5, ok.Value)
+	testutil.NotOk(t, limited.Err)
+	testutil.Equals(t, http.StatusTooManyRequests, limited.Status)
+
+	// A parse error and a missing env variable.
+	for _, c := range checks[2:] {
+		testutil.Equals(t, "B/USD", c.Symbol)
+		testutil.NotOk(t, c.Err)
+	}
+}
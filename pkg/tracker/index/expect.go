@@ -0,0 +1,88 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/yalp/jsonpath"
+)
+
+// The reasons of a rejected value.
+const (
+	RejectedField = "field"
+	RejectedRange = "range"
+)
+
+// Expect is the expected shape of the responses of the endpoints of a symbol
+// so that a provider that changes its response doesn't record a wrong field as the value.
+type Expect struct {
+	// Fields are the json paths that must be in every response of the http endpoints.
+	Fields []string
+	// Min and Max are the range of the valid values, not checked when not set.
+	Min *float64
+	Max *float64
+}
+
+// RejectedError is returned for a response that doesn't match the expected one.
+type RejectedError struct {
+	Reason string
+	Err    error
+}
+
+func (self *RejectedError) Error() string {
+	return fmt.Sprintf("rejected %v: %v", self.Reason, self.Err)
+}
+
+// checkFields returns an error when the response misses any of the expected fields.
+func (self *Expect) checkFields(body []byte) error {
+	if self == nil || len(self.Fields) == 0 {
+		return nil
+	}
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return &RejectedError{Reason: RejectedField, Err: errors.Wrap(err, "json unmarshal")}
+	}
+	for _, field := range self.Fields {
+		v, err := jsonpath.Read(data, field)
+		if err != nil || v == nil {
+			return &RejectedError{Reason: RejectedField, Err: errors.Errorf("missing field:%v", field)}
+		}
+	}
+	return nil
+}
+
+// checkValue returns an error when the value is outside of the expected range.
+func (self *Expect) checkValue(v float64) error {
+	if self == nil {
+		return nil
+	}
+	if self.Min != nil && v < *self.Min {
+		return &RejectedError{Reason: RejectedRange, Err: errors.Errorf("value:%v below the min:%v", v, *self.Min)}
+	}
+	if self.Max != nil && v > *self.Max {
+		return &RejectedError{Reason: RejectedRange, Err: errors.Errorf("value:%v above the max:%v", v, *self.Max)}
+	}
+	return nil
+}
+
+// expectSource rejects the values of a data source outside of the expected range.
+type expectSource struct {
+	DataSource
+	expect *Expect
+}
+
+func (self *expectSource) Get(ctx context.Context) (float64, error) {
+	v, err := self.DataSource.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := self.expect.checkValue(v); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestExpect(t *testing.T) {
+	body := `{"price": "2000.5"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	min, max := 1000.0, 5000.0
+	api := Apis{Expect: &Expect{Fields: []string{"$.price"}, Min: &min, Max: &max}}
+	source, err := newDataSource(context.Background(), "ETH/USD", api, Endpoint{URL: srv.URL, Type: httpSource, Parser: jsonPathParser, Param: "$.price"}, nil, 0, nil)
+	testutil.Ok(t, err)
+
+	v, err := source.Get(context.Background())
+	testutil.Ok(t, err)
+	testutil.Equals(t, 2000.5, v)
+
+	// The provider moved the price to another field.
+	body = `{"data": {"price": "2000.5"}, "price": null}`
+	_, err = source.Get(context.Background())
+	var rejected *RejectedError
+	testutil.Assert(t, errors.As(err, &rejected), "expected a rejected error got:%v", err)
+	testutil.Equals(t, RejectedField, rejected.Reason)
+
+	// The provider returns the price in cents.
+	body = `{"price": "200050"}`
+	_, err = source.Get(context.Background())
+	testutil.Assert(t, errors.As(err, &rejected), "expected a rejected error got:%v", err)
+	testutil.Equals(t, RejectedRange, rejected.Reason)
+
+	// Without the expectations any value is accepted.
+	source, err = newDataSource(context.Background(), "ETH/USD", Apis{}, Endpoint{URL: srv.URL, Type: httpSource, Parser: jsonPathParser, Param: "$.price"}, nil, 0, nil)
+	testutil.Ok(t, err)
+	v, err = source.Get(context.Background())
+	testutil.Ok(t, err)
+	testutil.Equals(t, 200050.0, v)
+}
@@ -0,0 +1,207 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	"github.com/tellor-io/telliot/pkg/schema"
+	"github.com/yalp/jsonpath"
+)
+
+// ParseIndex parses the index file and reports all problems at once.
+// The problems of the unknown keys are warnings and the rest are errors.
+func ParseIndex(b []byte) (map[string]Apis, schema.Problems, error) {
+	problems, err := schema.Validate(b, map[string]Apis{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parse index file")
+	}
+	if len(problems.Errors()) > 0 {
+		return nil, problems, nil
+	}
+
+	indexes := make(map[string]Apis)
+	if err := json.Unmarshal(b, &indexes); err != nil {
+		return nil, nil, errors.Wrap(err, "parse index file")
+	}
+
+	for symbol, api := range indexes {
+		if api.Schedule != "" {
+			if _, err := cron.ParseStandard(api.Schedule); err != nil {
+				problems = append(problems, schema.Problem{
+					Path:     symbol + ".schedule",
+					Expected: "a cron expression with the minute, hour, day of month, month and day of week fields: " + err.Error(),
+					Example:  `"0 * * * *"`,
+					Got:      `"` + api.Schedule + `"`,
+				})
+			}
+		}
+		if api.Timeout.Duration < 0 {
+			problems = append(problems, schema.Problem{
+				Path:     symbol + ".timeout",
+				Expected: "a positive duration",
+				Example:  `"10s"`,
+				Got:      `"` + api.Timeout.String() + `"`,
+			})
+		}
+		if api.Expect != nil {
+			for i, field := range api.Expect.Fields {
+				if _, err := jsonpath.Prepare(field); err != nil {
+					problems = append(problems, schema.Problem{
+						Path:     fmt.Sprintf("%v.expect.fields[%d]", symbol, i),
+						Expected: "a json path: " + err.Error(),
+						Example:  `"$.price"`,
+						Got:      `"` + field + `"`,
+					})
+				}
+			}
+			if api.Expect.Min != nil && api.Expect.Max != nil && *api.Expect.Min > *api.Expect.Max {
+				problems = append(problems, schema.Problem{
+					Path:     symbol + ".expect.max",
+					Expected: fmt.Sprintf("not less than the min:%v", *api.Expect.Min),
+					Got:      fmt.Sprintf("%v", *api.Expect.Max),
+				})
+			}
+		}
+		if len(api.Endpoints) == 0 {
+			problems = append(problems, schema.Problem{
+				Path:     symbol + ".endpoints",
+				Expected: "at least one endpoint",
+				Example:  `[{"URL": "https://api.binance.com/api/v3/ticker/price?symbol=ETHUSDT", "param": "$.price"}]`,
+			})
+		}
+		for i, endpoint := range api.Endpoints {
+			path := fmt.Sprintf("%v.endpoints[%d]", symbol, i)
+			if endpoint.URL == "" {
+				problems = append(problems, schema.Problem{
+					Path:     path + ".URL",
+					Expected: "the url of the api or the address of the contract",
+					Example:  `"https://api.binance.com/api/v3/ticker/price?symbol=ETHUSDT"`,
+				})
+			}
+			if endpoint.Timeout.Duration < 0 {
+				problems = append(problems, schema.Problem{
+					Path:     path + ".timeout",
+					Expected: "a positive duration",
+					Example:  `"10s"`,
+					Got:      `"` + endpoint.Timeout.String() + `"`,
+				})
+			}
+			if endpoint.Paging != nil {
+				problems = append(problems, checkPaging(path+".paging", endpoint)...)
+			}
+			if endpoint.QuoteVia != "" {
+				problems = append(problems, checkQuoteVia(path+".quoteVia", symbol, endpoint, indexes)...)
+			}
+			switch endpoint.Type {
+			case "", httpSource:
+				switch endpoint.Parser {
+				case "", jsonPathParser, jqParser:
+				default:
+					problems = append(problems, schema.Problem{
+						Path:     path + ".parser",
+						Expected: fmt.Sprintf("%v or %v for an %v source", jsonPathParser, jqParser, httpSource),
+						Example:  `"` + string(jsonPathParser) + `"`,
+						Got:      `"` + string(endpoint.Parser) + `"`,
+					})
+				}
+			case ethereumSource:
+				switch endpoint.Parser {
+				case uniswapParser, balancerParser:
+				default:
+					problems = append(problems, schema.Problem{
+						Path:     path + ".parser",
+						Expected: fmt.Sprintf("%v or %v for an %v source", uniswapParser, balancerParser, ethereumSource),
+						Example:  `"` + string(uniswapParser) + `"`,
+						Got:      `"` + string(endpoint.Parser) + `"`,
+					})
+				}
+			default:
+				problems = append(problems, schema.Problem{
+					Path:     path + ".type",
+					Expected: fmt.Sprintf("%v or %v", httpSource, ethereumSource),
+					Example:  `"` + string(httpSource) + `"`,
+					Got:      `"` + string(endpoint.Type) + `"`,
+				})
+			}
+		}
+	}
+	return indexes, problems, nil
+}
+
+// checkQuoteVia checks that the quote symbol has its own value
+// so that the quoted sources don't depend on each other.
+func checkQuoteVia(path, symbol string, endpoint Endpoint, indexes map[string]Apis) schema.Problems {
+	via, ok := indexes[endpoint.QuoteVia]
+	if !ok || endpoint.QuoteVia == symbol {
+		return schema.Problems{{
+			Path:     path,
+			Expected: "another symbol of the index file",
+			Example:  `"ETH/USD"`,
+			Got:      `"` + endpoint.QuoteVia + `"`,
+		}}
+	}
+	for _, e := range via.Endpoints {
+		if e.QuoteVia == "" {
+			return nil
+		}
+	}
+	return schema.Problems{{
+		Path:     path,
+		Expected: fmt.Sprintf("a symbol with at least one endpoint not quoted via another symbol, all endpoints of %v are quoted", endpoint.QuoteVia),
+		Got:      `"` + endpoint.QuoteVia + `"`,
+	}}
+}
+
+func checkPaging(path string, endpoint Endpoint) schema.Problems {
+	var problems schema.Problems
+	paging := endpoint.Paging
+	if endpoint.Type == ethereumSource {
+		problems = append(problems, schema.Problem{
+			Path:     path,
+			Expected: fmt.Sprintf("paging only for an %v source", httpSource),
+		})
+	}
+	switch {
+	case paging.Next == "" && paging.URL == "":
+		problems = append(problems, schema.Problem{
+			Path:     path,
+			Expected: "the json path of the next page url in next or the url of the pages in url",
+			Example:  `{"next": "$.next"}`,
+		})
+	case paging.Next != "" && paging.URL != "":
+		problems = append(problems, schema.Problem{
+			Path:     path,
+			Expected: "only one of next or url",
+		})
+	case paging.Next != "":
+		if _, err := jsonpath.Prepare(paging.Next); err != nil {
+			problems = append(problems, schema.Problem{
+				Path:     path + ".next",
+				Expected: "a json path: " + err.Error(),
+				Example:  `"$.next"`,
+				Got:      `"` + paging.Next + `"`,
+			})
+		}
+	case !strings.Contains(paging.URL, pagePlaceholder):
+		problems = append(problems, schema.Problem{
+			Path:     path + ".url",
+			Expected: "the url with a " + pagePlaceholder + " placeholder for the page number",
+			Example:  `"https://api.example.com/trades?page=` + pagePlaceholder + `"`,
+			Got:      `"` + paging.URL + `"`,
+		})
+	}
+	if paging.MaxPages < 0 {
+		problems = append(problems, schema.Problem{
+			Path:     path + ".maxPages",
+			Expected: "a positive number",
+			Got:      fmt.Sprintf("%v", paging.MaxPages),
+		})
+	}
+	return problems
+}
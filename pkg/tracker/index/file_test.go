@@ -0,0 +1,93 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestParseIndex(t *testing.T) {
+	b, err := ioutil.ReadFile(filepath.Join("..", "..", "..", "configs", "index.json"))
+	testutil.Ok(t, err)
+	_, problems, err := ParseIndex(b)
+	testutil.Ok(t, err)
+	testutil.Equals(t, 0, len(problems), "the default index file should be valid")
+
+	_, problems, err = ParseIndex([]byte(`{
+		"ETH/USD": {"interval": "1x", "endpoints": [{"URL": "https://a", "parser": "xpath", "retries": 1}]},
+		"BTC/USD": {"endpoints": [{"type": "ethereum", "URL": "0x1"}]}
+	}`))
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(problems.Errors()), "the type errors are reported before the other checks")
+	testutil.Equals(t, "ETH/USD.interval", problems.Errors()[0].Path)
+	testutil.Equals(t, "ETH/USD.endpoints[0].retries", problems.Warnings()[0].Path)
+
+	_, problems, err = ParseIndex([]byte(`{
+		"ETH/USD": {"endpoints": [{"URL": "https://a", "parser": "xpath"}]},
+		"BTC/USD": {"endpoints": [{"type": "ethereum", "URL": "0x1"}]},
+		"TRB/USD": {"endpoints": []}
+	}`))
+	testutil.Ok(t, err)
+	testutil.Equals(t, 3, len(problems.Errors()))
+	testutil.NotOk(t, problems.Err())
+
+	indexes, problems, err := ParseIndex([]byte(`{
+		"ETH/USD": {"schedule": "0 * * * *", "endpoints": [{"URL": "https://a"}]},
+		"BTC/USD": {"schedule": "every hour", "endpoints": [{"URL": "https://b"}]}
+	}`))
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(problems.Errors()))
+	testutil.Equals(t, "BTC/USD.schedule", problems.Errors()[0].Path)
+	testutil.Equals(t, "0 * * * *", indexes["ETH/USD"].Schedule)
+
+	indexes, problems, err = ParseIndex([]byte(`{
+		"ETH/USD": {"timeout": "20s", "endpoints": [{"URL": "https://a", "timeout": "2s"}, {"URL": "https://b"}]},
+		"BTC/USD": {"endpoints": [{"URL": "https://b", "timeout": "-1s"}]}
+	}`))
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(problems.Errors()))
+	testutil.Equals(t, "BTC/USD.endpoints[0].timeout", problems.Errors()[0].Path)
+	testutil.Equals(t, 20*time.Second, indexes["ETH/USD"].Timeout.Duration)
+	testutil.Equals(t, 2*time.Second, indexes["ETH/USD"].Endpoints[0].Timeout.Duration)
+
+	indexes, problems, err = ParseIndex([]byte(`{
+		"ETH/USD": {"expect": {"fields": ["$.price"], "min": 100, "max": 10000}, "endpoints": [{"URL": "https://a"}]},
+		"BTC/USD": {"expect": {"fields": ["price["], "min": 10, "max": 1}, "endpoints": [{"URL": "https://b"}]}
+	}`))
+	testutil.Ok(t, err)
+	testutil.Equals(t, 2, len(problems.Errors()))
+	testutil.Equals(t, 10000.0, *indexes["ETH/USD"].Expect.Max)
+
+	indexes, problems, err = ParseIndex([]byte(`{
+		"ETH/VOLUME": {"endpoints": [{"URL": "https://a", "paging": {"next": "$.next", "maxPages": 20}}]},
+		"BTC/VOLUME": {"endpoints": [
+			{"URL": "https://b", "paging": {"url": "https://b?page=2"}},
+			{"URL": "https://c", "paging": {}},
+			{"type": "ethereum", "parser": "Uniswap", "URL": "0x1", "paging": {"next": "$.next"}}
+		]}
+	}`))
+	testutil.Ok(t, err)
+	testutil.Equals(t, 3, len(problems.Errors()))
+	testutil.Equals(t, 20, indexes["ETH/VOLUME"].Endpoints[0].Paging.MaxPages)
+
+	indexes, problems, err = ParseIndex([]byte(`{
+		"ETH/USD": {"endpoints": [{"URL": "https://a", "invert": true}]},
+		"TRB/USD": {"endpoints": [
+			{"URL": "https://b", "quoteVia": "ETH/USD"},
+			{"URL": "https://c", "quoteVia": "TRB/USD"},
+			{"URL": "https://d", "quoteVia": "BTC/USD"}
+		]},
+		"TRB/BTC": {"endpoints": [{"URL": "https://e", "quoteVia": "TRB/USD"}]}
+	}`))
+	testutil.Ok(t, err)
+	testutil.Equals(t, 3, len(problems.Errors()))
+	testutil.Equals(t, 0, len(problems.Warnings()))
+	testutil.Assert(t, indexes["ETH/USD"].Endpoints[0].Invert, "the endpoint isn't inverted")
+	testutil.Equals(t, "ETH/USD", indexes["TRB/USD"].Endpoints[0].QuoteVia)
+}
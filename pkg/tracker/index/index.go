@@ -4,17 +4,21 @@
 package index
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"hash/fnv"
 	"net/url"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -24,12 +28,16 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/timestamp"
-	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/robfig/cron/v3"
 	"github.com/tellor-io/telliot/pkg/ethereum"
 	"github.com/tellor-io/telliot/pkg/format"
 	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/tracing"
 	"github.com/tellor-io/telliot/pkg/web"
 	"github.com/yalp/jsonpath"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -41,54 +49,96 @@ const (
 )
 
 type Config struct {
-	LogLevel  string
-	Interval  format.Duration
-	IndexFile string
+	LogLevel     string
+	Interval     format.Duration
+	FetchTimeout format.Duration `help:"Max time to get a value from a source including the retries. The sources of the index file can override it with their own timeout."`
+	IndexFile    string
+	Shards       uint     `help:"Split the symbols of the index file between this many tracker instances that write to the same data server. 0 or 1 tracks all symbols."`
+	Shard        uint     `help:"The shard of this instance from 0 to Shards-1. The symbols are assigned to the shards by a hash of their name."`
+	Symbols      []string `help:"Track only these symbols of the index file instead of the hash based shards."`
+	// The registry is an index file managed by a team for all its reporters.
+	Registry        string          `help:"URL of a remote index file used instead of the local one. The local index file is used only when the registry can't be reached at startup."`
+	RegistrySigner  string          `help:"Address of the account that signs the index file of the registry. The signature is at the registry URL with a .sig suffix and an index file without a valid signature isn't used."`
+	RegistryRefresh format.Duration `help:"How often to get the index file of the registry and apply its changes. 0 gets it only at startup."`
+	RegistryEnv     []string        `help:"Env variables that are substituted in the urls of the index file of the registry, like the api keys of the sources. Other env variables in these urls are an error so that the registry can't send the secrets of the reporters to any host."`
 }
 
 type IndexTracker struct {
 	logger      log.Logger
 	ctx         context.Context
 	stop        context.CancelFunc
-	tsDB        *tsdb.DB
+	tsDB        storage.Appendable
 	cfg         Config
-	dataSources map[string][]DataSource
+	client      *ethclient.Client
 	value       *prometheus.GaugeVec
 	getErrors   *prometheus.CounterVec
+	rejected    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	registryErr prometheus.Counter
+	lastAppend  int64 // Unix timestamp in milliseconds of the last successful value append.
+
+	mtx         sync.Mutex
+	index       []byte // The index file of the current data sources.
+	version     uint64 // The signed version of the index file of the registry.
+	dataSources map[string][]DataSource
+	latest      map[string]map[string]latestValue // The last value of each symbol and source for the quoted sources.
 }
 
 func New(
 	logger log.Logger,
 	ctx context.Context,
 	cfg Config,
-	tsDB *tsdb.DB,
+	tsDB storage.Appendable,
 	client *ethclient.Client,
 ) (*IndexTracker, error) {
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "apply filter logger")
 	}
 
-	dataSources, err := createDataSources(ctx, cfg, client)
+	if cfg.Registry != "" && !common.IsHexAddress(cfg.RegistrySigner) {
+		return nil, errors.Errorf("the registry needs the address of its signer, got:%q", cfg.RegistrySigner)
+	}
+	index, sig, err := LoadIndex(ctx, logger, cfg)
 	if err != nil {
-		return nil, errors.Wrap(err, "create data sources")
+		return nil, err
 	}
-
 	ctx, stop := context.WithCancel(ctx)
 
-	return &IndexTracker{
-		logger:      log.With(logger, "component", ComponentName),
-		ctx:         ctx,
-		stop:        stop,
-		dataSources: dataSources,
-		tsDB:        tsDB,
-		cfg:         cfg,
+	tracker := &IndexTracker{
+		logger: log.With(logger, "component", ComponentName),
+		ctx:    ctx,
+		stop:   stop,
+		index:  index,
+		tsDB:   tsDB,
+		cfg:    cfg,
+		client: client,
+		registryErr: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "registry_errors_total",
+			Help:      "The total number of failed refreshes of the index file of the registry.",
+		}),
 		getErrors: promauto.NewCounterVec(prometheus.CounterOpts{
 			Namespace: "telliot",
 			Subsystem: ComponentName,
 			Name:      "errors_total",
 			Help:      "The total number of get errors. Usually caused by API throtling.",
 		}, []string{"source"}),
+		rejected: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "rejected_total",
+			Help:      "The total number of responses that don't match the expected fields or range of the index file.",
+		}, []string{"source", "reason"}),
+		duration: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "fetch_duration_seconds",
+			Help:      "The time to get a value from the source including the retries, both for the successful and the failed gets.",
+			// From 50ms up to the 30s default fetch timeout and above.
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 11),
+		}, []string{"source"}),
 		value: promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: "telliot",
 			Subsystem: ComponentName,
@@ -97,123 +147,335 @@ func New(
 		},
 			[]string{"symbol", "domain", "source"},
 		),
-	}, nil
+	}
+	if sig != nil {
+		tracker.version = sig.Version
+	}
+	tracker.dataSources, err = createDataSources(ctx, logger, cfg, client, index, envFilter(cfg, sig != nil), tracker.quote)
+	if err != nil {
+		stop()
+		return nil, errors.Wrap(err, "create data sources")
+	}
+	return tracker, nil
 }
 
-func createDataSources(ctx context.Context, cfg Config, client *ethclient.Client) (map[string][]DataSource, error) {
-	// Load index file.
-	byteValue, err := ioutil.ReadFile(cfg.IndexFile)
+// createDataSources creates the sources of the index file
+// with the env variables that the filter allows substituted in the urls.
+func createDataSources(ctx context.Context, logger log.Logger, cfg Config, client *ethclient.Client, index []byte, allowEnv func(string) bool, quote Quote) (map[string][]DataSource, error) {
+	indexes, problems, err := ParseIndex(index)
 	if err != nil {
-		return nil, errors.Wrapf(err, "read index file path:%s", cfg.IndexFile)
+		return nil, err
 	}
-	// Parse to json.
-	indexes := make(map[string]Apis)
-	err = json.Unmarshal(byteValue, &indexes)
+	for _, p := range problems.Warnings() {
+		level.Warn(logger).Log("msg", "index file", "problem", p.String())
+	}
+	if err := problems.Err(); err != nil {
+		return nil, errors.Wrap(err, "invalid index file")
+	}
+
+	indexes, err = shard(cfg, indexes)
 	if err != nil {
-		return nil, errors.Wrap(err, "parse index file")
+		return nil, err
+	}
+	if len(cfg.Symbols) > 0 || cfg.Shards > 1 {
+		level.Info(logger).Log("msg", "tracking a shard of the symbols", "shard", cfg.Shard, "shards", cfg.Shards, "symbols", len(indexes))
 	}
 
 	dataSources := make(map[string][]DataSource)
 
 	for symbol, api := range indexes {
 		for _, endpoint := range api.Endpoints {
-			var err error
-			endpoint.URL = os.Expand(endpoint.URL, func(key string) string {
-				if os.Getenv(key) == "" {
-					err = errors.Errorf("missing required env variable in index url:%v", key)
-				}
-				return os.Getenv(key)
-			})
+			endpoint, err := prepare(endpoint, allowEnv)
+			if err != nil {
+				return nil, errors.Wrapf(err, "symbol:%v", symbol)
+			}
+			if _, ok := indexes[endpoint.QuoteVia]; endpoint.QuoteVia != "" && !ok {
+				return nil, errors.Errorf("the quote symbol:%v of:%v isn't tracked by this shard", endpoint.QuoteVia, symbol)
+			}
+			source, err := newDataSource(ctx, symbol, api, endpoint, client, sourceTimeout(api, endpoint, cfg.FetchTimeout.Duration), quote)
 			if err != nil {
 				return nil, err
 			}
+			dataSources[symbol] = append(dataSources[symbol], source)
+		}
 
-			var source DataSource
+	}
+	return dataSources, nil
 
-			// Default value for the api type.
-			if endpoint.Type == "" {
-				endpoint.Type = httpSource
-			}
+}
+
+// prepare expands the env variables in the urls of the endpoint and sets the default type and parser.
+// A nil allowEnv allows all env variables.
+func prepare(endpoint Endpoint, allowEnv func(string) bool) (Endpoint, error) {
+	var err error
+	endpoint.URL, err = expandEnv(endpoint.URL, allowEnv)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	if endpoint.Paging != nil {
+		// A copy so that the paging of the parsed index file stays unchanged.
+		paging := *endpoint.Paging
+		paging.URL, err = expandEnv(paging.URL, allowEnv)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		endpoint.Paging = &paging
+	}
 
-			// Default value for the parser.
-			if endpoint.Parser == "" {
-				endpoint.Parser = jsonPathParser
+	// Default value for the api type.
+	if endpoint.Type == "" {
+		endpoint.Type = httpSource
+	}
+
+	// Default value for the parser.
+	if endpoint.Parser == "" {
+		endpoint.Parser = jsonPathParser
+	}
+	return endpoint, nil
+}
+
+func expandEnv(url string, allowEnv func(string) bool) (string, error) {
+	var err error
+	url = os.Expand(url, func(key string) string {
+		if allowEnv != nil && !allowEnv(key) {
+			err = errors.Errorf("env variable not allowed in the index url of the registry:%v", key)
+			return ""
+		}
+		if os.Getenv(key) == "" {
+			err = errors.Errorf("missing required env variable in index url:%v", key)
+		}
+		return os.Getenv(key)
+	})
+	return url, err
+}
+
+// sourceTimeout returns the timeout of the endpoint, of its symbol or the default one in this order.
+func sourceTimeout(api Apis, endpoint Endpoint, def time.Duration) time.Duration {
+	if endpoint.Timeout.Duration > 0 {
+		return endpoint.Timeout.Duration
+	}
+	if api.Timeout.Duration > 0 {
+		return api.Timeout.Duration
+	}
+	return def
+}
+
+// newDataSource creates the source of the endpoint.
+// A get of the source is aborted after the timeout unless it is 0.
+// The quote is needed only for the endpoints quoted via another symbol.
+func newDataSource(ctx context.Context, symbol string, api Apis, endpoint Endpoint, client *ethclient.Client, timeout time.Duration, quote Quote) (DataSource, error) {
+	var source DataSource
+	switch endpoint.Type {
+	case httpSource:
+		{
+			jsonAPI := NewJSONapi(api.Interval.Duration, endpoint.URL, NewParser(endpoint))
+			source = jsonAPI
+			if strings.Contains(strings.ToLower(symbol), "volume") {
+				volume := NewJSONapiVolume(api.Interval.Duration, endpoint.URL, NewParser(endpoint))
+				jsonAPI, source = volume.JSONapi, volume
 			}
-			switch endpoint.Type {
-			case httpSource:
-				{
-					source = NewJSONapi(api.Interval.Duration, endpoint.URL, NewParser(endpoint))
-					if strings.Contains(strings.ToLower(symbol), "volume") {
-						source = NewJSONapiVolume(api.Interval.Duration, endpoint.URL, NewParser(endpoint))
-					}
-				}
-			case ethereumSource:
-				{
-					// Getting current network id from geth node.
-					networkID, err := client.NetworkID(ctx)
-					if err != nil {
-						return nil, err
-					}
-					// Validate and pick an ethereum address for current network id.
-					address, err := ethereum.GetAddressForNetwork(endpoint.URL, networkID.Int64())
-					if err != nil {
-						return nil, errors.Wrap(err, "getting address for network id")
-					}
-					if endpoint.Parser == uniswapParser {
-						source = NewUniswap(symbol, address, api.Interval.Duration, client)
-
-					} else if endpoint.Parser == balancerParser {
-						source = NewBalancer(symbol, address, api.Interval.Duration, client)
-					} else {
-						return nil, errors.Wrapf(err, "unknown source for on-chain index tracker")
-					}
-				}
-			default:
-				return nil, errors.Errorf("unknown index type for index object:%v", endpoint.Type)
+			jsonAPI.expect = api.Expect
+			jsonAPI.paging = endpoint.Paging
+		}
+	case ethereumSource:
+		{
+			if client == nil {
+				return nil, errors.New("on-chain sources need an ethereum node")
+			}
+			// Getting current network id from geth node.
+			networkID, err := client.NetworkID(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// Validate and pick an ethereum address for current network id.
+			address, err := ethereum.GetAddressForNetwork(endpoint.URL, networkID.Int64())
+			if err != nil {
+				return nil, errors.Wrap(err, "getting address for network id")
 			}
+			if endpoint.Parser == uniswapParser {
+				source = NewUniswap(symbol, address, api.Interval.Duration, client)
 
-			dataSources[symbol] = append(dataSources[symbol], source)
+			} else if endpoint.Parser == balancerParser {
+				source = NewBalancer(symbol, address, api.Interval.Duration, client)
+			} else {
+				return nil, errors.Errorf("unknown source for on-chain index tracker")
+			}
 		}
+	default:
+		return nil, errors.Errorf("unknown index type for index object:%v", endpoint.Type)
+	}
 
+	if endpoint.Invert || endpoint.QuoteVia != "" {
+		if endpoint.QuoteVia != "" && quote == nil {
+			return nil, errors.Errorf("no quotes for the source quoted via:%v", endpoint.QuoteVia)
+		}
+		source = &pairSource{DataSource: source, invert: endpoint.Invert, via: endpoint.QuoteVia, quote: quote}
 	}
-	return dataSources, nil
+	if api.Expect != nil && (api.Expect.Min != nil || api.Expect.Max != nil) {
+		source = &expectSource{DataSource: source, expect: api.Expect}
+	}
+	if timeout > 0 {
+		source = &timeoutSource{DataSource: source, timeout: timeout}
+	}
+	if api.Schedule != "" {
+		// Already validated when parsing the index file.
+		schedule, err := cron.ParseStandard(api.Schedule)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing the schedule of:%v", symbol)
+		}
+		source = &scheduledSource{DataSource: source, schedule: schedule}
+	}
+	return source, nil
+}
 
+// shard returns the symbols tracked by this instance,
+// the listed symbols or the ones whose hash is assigned to its shard.
+func shard(cfg Config, indexes map[string]Apis) (map[string]Apis, error) {
+	if len(cfg.Symbols) > 0 {
+		sharded := make(map[string]Apis)
+		for _, symbol := range cfg.Symbols {
+			api, ok := indexes[symbol]
+			if !ok {
+				return nil, errors.Errorf("symbol:%v isn't in the index file", symbol)
+			}
+			sharded[symbol] = api
+		}
+		return sharded, nil
+	}
+	if cfg.Shards <= 1 {
+		return indexes, nil
+	}
+	if cfg.Shard >= cfg.Shards {
+		return nil, errors.Errorf("shard:%v should be less than the number of shards:%v", cfg.Shard, cfg.Shards)
+	}
+	sharded := make(map[string]Apis)
+	for symbol, api := range indexes {
+		h := fnv.New32a()
+		h.Write([]byte(symbol))
+		if uint(h.Sum32())%cfg.Shards == cfg.Shard {
+			sharded[symbol] = api
+		}
+	}
+	return sharded, nil
 }
 
 func (self *IndexTracker) Run() error {
+	ctx, cancel := context.WithCancel(self.ctx)
+	self.start(ctx, self.sources())
+	if self.cfg.Registry == "" || self.cfg.RegistryRefresh.Duration <= 0 {
+		<-self.ctx.Done()
+		cancel()
+		return nil
+	}
+
+	ticker := time.NewTicker(self.cfg.RegistryRefresh.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-self.ctx.Done():
+			cancel()
+			return nil
+		case <-ticker.C:
+		}
+		dataSources, changed, err := self.refresh()
+		if err != nil {
+			self.registryErr.Inc()
+			level.Error(self.logger).Log("msg", "refreshing the index file of the registry, keeping the current one", "err", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		// Restart the records with the new data sources.
+		cancel()
+		ctx, cancel = context.WithCancel(self.ctx)
+		self.start(ctx, dataSources)
+	}
+}
+
+// start records the values of the data sources until the context is canceled.
+func (self *IndexTracker) start(ctx context.Context, dataSources map[string][]DataSource) {
 	delay := time.Second
-	for symbol, dataSources := range self.dataSources {
+	for symbol, dataSources := range dataSources {
 		for _, dataSource := range dataSources {
+			if scheduled, ok := dataSource.(*scheduledSource); ok {
+				go self.recordScheduled(ctx, symbol, scheduled)
+				continue
+			}
 			// Use the default interval when not set.
 			interval := dataSource.Interval()
 			if int64(interval) == 0 {
 				interval = self.cfg.Interval.Duration
 			}
 
-			go self.record(delay, symbol, interval, dataSource)
+			go self.record(ctx, delay, symbol, interval, dataSource)
 			delay += time.Second
 		}
 	}
-	<-self.ctx.Done()
-	return nil
+}
+
+// refresh gets the index file of the registry and returns its data sources when it changed.
+// An index file that isn't valid is returned as an error and the current data sources are kept.
+func (self *IndexTracker) refresh() (map[string][]DataSource, bool, error) {
+	index, sig, err := FetchRegistry(self.ctx, self.cfg.Registry, self.cfg.RegistrySigner)
+	if err != nil {
+		return nil, false, err
+	}
+	self.mtx.Lock()
+	same := bytes.Equal(index, self.index)
+	version := self.version
+	self.mtx.Unlock()
+	if same {
+		return nil, false, nil
+	}
+	// An older index file served again is a replay even when its signature is valid.
+	if sig.Version <= version {
+		return nil, false, errors.Errorf("the changed index file has version:%v which isn't newer than the current version:%v", sig.Version, version)
+	}
+	dataSources, err := createDataSources(self.ctx, self.logger, self.cfg, self.client, index, envFilter(self.cfg, true), self.quote)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "create data sources")
+	}
+
+	self.mtx.Lock()
+	self.index = index
+	self.version = sig.Version
+	self.dataSources = dataSources
+	self.mtx.Unlock()
+	level.Info(self.logger).Log("msg", "applied the changed index file of the registry", "symbols", len(dataSources))
+	return dataSources, true, nil
+}
+
+// HasSources returns whether the symbol has sources in the index file currently applied,
+// which changes when the registry serves a new index file.
+func (self *IndexTracker) HasSources(symbol string) bool {
+	_, ok := self.sources()[symbol]
+	return ok
+}
+
+func (self *IndexTracker) sources() map[string][]DataSource {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return self.dataSources
 }
 
 // record from all API calls.
 // The request delay is used to avoid rate limiting at startup
 // for when all API calls try to happen at the same time.
-func (self *IndexTracker) record(delay time.Duration, symbol string, interval time.Duration, dataSource DataSource) {
+func (self *IndexTracker) record(ctx context.Context, delay time.Duration, symbol string, interval time.Duration, dataSource DataSource) {
 	delayTicker := time.NewTicker(delay)
 	select {
 	case <-delayTicker.C:
 		break
-	case <-self.ctx.Done():
+	case <-ctx.Done():
 		level.Debug(self.logger).Log("msg", "values record loop exited")
 		return
 	}
 	delayTicker.Stop()
 
 	ticker := time.NewTicker(interval)
-	logger := log.With(self.logger, "source", dataSource.Source())
+	defer ticker.Stop()
+	logger := log.With(self.logger, "symbol", symbol, "source", dataSource.Source())
 
 	for {
 		ts := timestamp.FromTime(time.Now())
@@ -224,12 +486,12 @@ func (self *IndexTracker) record(delay time.Duration, symbol string, interval ti
 			level.Error(logger).Log("msg", "record interval to the DB", "err", err)
 		}
 
-		if err := self.recordValue(logger, ts, interval, symbol, dataSource); err != nil {
+		if err := self.recordValue(ctx, logger, ts, interval, symbol, dataSource); err != nil {
 			level.Error(logger).Log("msg", "record value to the DB", "err", err)
 		}
 
 		select {
-		case <-self.ctx.Done():
+		case <-ctx.Done():
 			level.Debug(self.logger).Log("msg", "values record loop exited")
 			return
 		case <-ticker.C:
@@ -238,6 +500,33 @@ func (self *IndexTracker) record(delay time.Duration, symbol string, interval ti
 	}
 }
 
+// recordScheduled records the values of a source at the times of its schedule.
+func (self *IndexTracker) recordScheduled(ctx context.Context, symbol string, dataSource *scheduledSource) {
+	logger := log.With(self.logger, "symbol", symbol, "source", dataSource.Source())
+	for {
+		next := dataSource.schedule.Next(time.Now())
+		level.Debug(logger).Log("msg", "waiting for the next scheduled get", "next", next)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			level.Debug(self.logger).Log("msg", "values record loop exited")
+			return
+		case <-timer.C:
+		}
+
+		// The confidence expects a value until the next scheduled time.
+		interval := dataSource.schedule.Next(next).Sub(next)
+		ts := timestamp.FromTime(time.Now())
+		if err := self.recordInterval(logger, ts, interval, symbol, dataSource); err != nil {
+			level.Error(logger).Log("msg", "record interval to the DB", "err", err)
+		}
+		if err := self.recordValue(ctx, logger, ts, interval, symbol, dataSource); err != nil {
+			level.Error(logger).Log("msg", "record value to the DB", "err", err)
+		}
+	}
+}
+
 func (self *IndexTracker) recordInterval(logger log.Logger, ts int64, interval time.Duration, symbol string, dataSource DataSource) (err error) {
 	source, err := url.Parse(dataSource.Source())
 	if err != nil {
@@ -252,7 +541,7 @@ func (self *IndexTracker) recordInterval(logger log.Logger, ts int64, interval t
 			return
 		}
 		if errC := appender.Commit(); errC != nil {
-			err = errors.Wrap(err, "db append commit failed")
+			err = errors.Wrap(errC, "db append commit failed")
 		}
 	}()
 
@@ -272,9 +561,30 @@ func (self *IndexTracker) recordInterval(logger log.Logger, ts int64, interval t
 	return nil
 }
 
-func (self *IndexTracker) recordValue(logger log.Logger, ts int64, interval time.Duration, symbol string, dataSource DataSource) (err error) {
-	value, err := dataSource.Get(self.ctx)
+func (self *IndexTracker) recordValue(ctx context.Context, logger log.Logger, ts int64, interval time.Duration, symbol string, dataSource DataSource) (err error) {
+	ctx, span := tracing.Tracer(ComponentName).Start(ctx, "record", trace.WithAttributes(
+		attribute.String("symbol", symbol),
+		attribute.String("source", dataSource.Source()),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	fetchCtx, fetchSpan := tracing.Tracer(ComponentName).Start(ctx, "fetch")
+	start := time.Now()
+	value, err := dataSource.Get(fetchCtx)
+	self.duration.With(prometheus.Labels{"source": dataSource.Source()}).Observe(time.Since(start).Seconds())
+	tracing.End(fetchSpan, err)
 	if err != nil {
+		// A rejected response usually means that the provider changed its response.
+		var rejected *RejectedError
+		if errors.As(err, &rejected) {
+			self.rejected.With(
+				prometheus.Labels{
+					"source": dataSource.Source(),
+					"reason": rejected.Reason,
+				},
+			).Inc()
+			return errors.Wrap(err, "rejected value from data source")
+		}
 		self.getErrors.With(
 			prometheus.Labels{
 				"source": dataSource.Source(),
@@ -287,8 +597,10 @@ func (self *IndexTracker) recordValue(logger log.Logger, ts int64, interval time
 	if err != nil {
 		return errors.Wrap(err, "parsing url from data source")
 	}
+	_, appendSpan := tracing.Tracer(ComponentName).Start(ctx, "append")
 	appender := self.tsDB.Appender(self.ctx)
 	defer func() { // An appender always needs to be committed or rolled back.
+		defer func() { tracing.End(appendSpan, err) }()
 		if err != nil {
 			if err := appender.Rollback(); err != nil {
 				level.Error(logger).Log("msg", "db rollback failed", "err", err)
@@ -298,7 +610,7 @@ func (self *IndexTracker) recordValue(logger log.Logger, ts int64, interval time
 			return
 		}
 		if errC := appender.Commit(); errC != nil {
-			err = errors.Wrap(err, "db append commit failed")
+			err = errors.Wrap(errC, "db append commit failed")
 		}
 	}()
 
@@ -323,6 +635,31 @@ func (self *IndexTracker) recordValue(logger log.Logger, ts int64, interval time
 		},
 	).(prometheus.Gauge).Set(value)
 
+	atomic.StoreInt64(&self.lastAppend, ts)
+	self.setLatest(symbol, dataSource.Source(), value, timestamp.Time(ts), interval)
+
+	return nil
+}
+
+// Ready returns an error when the tracker hasn't added any values
+// for longer than twice the longest data source interval.
+func (self *IndexTracker) Ready(ctx context.Context) error {
+	maxInterval := self.cfg.Interval.Duration
+	for _, dataSources := range self.sources() {
+		for _, dataSource := range dataSources {
+			if dataSource.Interval() > maxInterval {
+				maxInterval = dataSource.Interval()
+			}
+		}
+	}
+
+	last := atomic.LoadInt64(&self.lastAppend)
+	if last == 0 {
+		return errors.New("no values recorded yet")
+	}
+	if since := time.Since(timestamp.Time(last)); since > 2*maxInterval {
+		return errors.Errorf("no values recorded for %v", since.Round(time.Second))
+	}
 	return nil
 }
 
@@ -330,6 +667,50 @@ func (self *IndexTracker) Stop() {
 	self.stop()
 }
 
+// Fetch gets and records the values of the symbol from all its data sources right away
+// instead of waiting for their next interval.
+// It returns an error only when none of the data sources returned a value.
+func (self *IndexTracker) Fetch(ctx context.Context, symbol string) error {
+	dataSources, ok := self.sources()[symbol]
+	if !ok {
+		return errors.Errorf("symbol isn't tracked:%v", symbol)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mtx     sync.Mutex
+		lastErr error
+		fetched int
+	)
+	ts := timestamp.FromTime(time.Now())
+	for _, dataSource := range dataSources {
+		wg.Add(1)
+		go func(dataSource DataSource) {
+			defer wg.Done()
+			interval := dataSource.Interval()
+			if int64(interval) == 0 {
+				interval = self.cfg.Interval.Duration
+			}
+			logger := log.With(self.logger, "symbol", symbol, "source", dataSource.Source())
+			err := self.recordValue(ctx, logger, ts, interval, symbol, dataSource)
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				lastErr = err
+				return
+			}
+			fetched++
+		}(dataSource)
+	}
+	wg.Wait()
+
+	if fetched == 0 {
+		return errors.Wrapf(lastErr, "fetching symbol:%v", symbol)
+	}
+	level.Debug(self.logger).Log("msg", "fetched values on demand", "symbol", symbol, "sources", fetched)
+	return nil
+}
+
 // IndexType -> index type for Api.
 type IndexType string
 
@@ -353,6 +734,15 @@ type Endpoint struct {
 	Type   IndexType
 	Parser ParserType
 	Param  string
+	// Timeout overrides the timeout of the symbol for this endpoint.
+	Timeout format.Duration
+	// Paging gets all pages of a paginated API and sums their values.
+	Paging *Paging
+	// Invert uses 1/value for a source of the inverted pair, for example USD/ETH for ETH/USD.
+	Invert bool
+	// QuoteVia multiplies the value with the current value of this symbol for a source quoted in another currency,
+	// for example ETH/USD for a TRB/ETH source of TRB/USD. It is applied after Invert.
+	QuoteVia string
 }
 
 // Apis will be used in parsing index file.
@@ -360,7 +750,17 @@ type Apis struct {
 	// The recommended interval for calling the Get method.
 	// Some APIs will return an error if called more often
 	// Due to API rate limiting of the provider.
-	Interval  format.Duration
+	Interval format.Duration
+	// Schedule is a cron expression with the times to call the Get method
+	// for sources that should be polled only at specific times like the market close.
+	// It is used instead of the interval when set.
+	Schedule string
+	// Timeout is the max time to get a value from the endpoints,
+	// longer for slow but reliable APIs and shorter for the ones that should fail quickly.
+	// The FetchTimeout of the config is used when not set.
+	Timeout format.Duration
+	// Expect rejects the responses that don't have the expected fields or a value in the expected range.
+	Expect    *Expect
 	Endpoints []Endpoint
 }
 
@@ -384,13 +784,9 @@ type JSONapiVolume struct {
 }
 
 func (self *JSONapiVolume) Get(ctx context.Context) (float64, error) {
-	vals, err := web.Get(ctx, self.url, nil)
-	if err != nil {
-		return 0, errors.Wrapf(err, "fetching data from API url:%v", self.url)
-	}
-	val, ts, err := self.Parse(vals)
+	val, ts, err := self.get(ctx)
 	if err != nil {
-		return 0, errors.Wrapf(err, "parsing data from API url:%v", self.url)
+		return 0, err
 	}
 
 	// Use 0 value for the volume as this has already been requested.
@@ -414,16 +810,47 @@ func NewJSONapi(interval time.Duration, url string, parser Parser) *JSONapi {
 type JSONapi struct {
 	url      string
 	interval time.Duration
+	expect   *Expect
+	paging   *Paging
 	Parser
 }
 
 func (self *JSONapi) Get(ctx context.Context) (float64, error) {
-	vals, err := web.Get(ctx, self.url, nil)
+	val, _, err := self.get(ctx)
+	return val, err
+}
+
+// get returns the value and the timestamp of the response,
+// the sum of the values of all pages for a paginated API.
+func (self *JSONapi) get(ctx context.Context) (float64, time.Time, error) {
+	if self.paging != nil {
+		return self.getPages(ctx)
+	}
+	_, val, ts, err := self.getPage(ctx, self.url)
+	return val, ts, err
+}
+
+// getPage gets and parses a single response.
+func (self *JSONapi) getPage(ctx context.Context, url string) ([]byte, float64, time.Time, error) {
+	vals, err := web.Get(ctx, url, nil)
 	if err != nil {
-		return 0, errors.Wrapf(err, "fetching data from API url:%v", self.url)
+		return nil, 0, time.Time{}, errors.Wrapf(err, "fetching data from API url:%v", url)
 	}
-	val, _, err := self.Parse(vals)
-	return val, err
+	if err := self.expect.checkFields(vals); err != nil {
+		return nil, 0, time.Time{}, errors.Wrapf(err, "checking data from API url:%v", url)
+	}
+	val, ts, err := self.parse(ctx, vals)
+	if err != nil {
+		return nil, 0, time.Time{}, errors.Wrapf(err, "parsing data from API url:%v", url)
+	}
+	return vals, val, ts, nil
+}
+
+func (self *JSONapi) parse(ctx context.Context, vals []byte) (float64, time.Time, error) {
+	_, span := tracing.Tracer(ComponentName).Start(ctx, "parse")
+	val, ts, err := self.Parse(vals)
+	tracing.End(span, err)
+	return val, ts, err
 }
 
 func (self *JSONapi) Interval() time.Duration {
@@ -434,6 +861,30 @@ func (self *JSONapi) Source() string {
 	return self.url
 }
 
+// scheduledSource is a data source polled at the times of a cron schedule instead of a fixed interval.
+type scheduledSource struct {
+	DataSource
+	schedule cron.Schedule
+}
+
+// Interval returns the time between the next two scheduled gets.
+func (self *scheduledSource) Interval() time.Duration {
+	next := self.schedule.Next(time.Now())
+	return self.schedule.Next(next).Sub(next)
+}
+
+// timeoutSource aborts the gets of a data source that take longer than the timeout.
+type timeoutSource struct {
+	DataSource
+	timeout time.Duration
+}
+
+func (self *timeoutSource) Get(ctx context.Context) (float64, error) {
+	ctx, cncl := context.WithTimeout(ctx, self.timeout)
+	defer cncl()
+	return self.DataSource.Get(ctx)
+}
+
 type DataSource interface {
 	// Source returns the data source.
 	Source() string
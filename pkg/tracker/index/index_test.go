@@ -0,0 +1,87 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestShard(t *testing.T) {
+	indexes := make(map[string]Apis)
+	for i := 0; i < 50; i++ {
+		indexes[fmt.Sprintf("SYM%d/USD", i)] = Apis{}
+	}
+
+	all, err := shard(Config{}, indexes)
+	testutil.Ok(t, err)
+	testutil.Equals(t, len(indexes), len(all))
+
+	// Every symbol is tracked by exactly one shard.
+	seen := make(map[string]int)
+	for i := uint(0); i < 3; i++ {
+		sharded, err := shard(Config{Shards: 3, Shard: i}, indexes)
+		testutil.Ok(t, err)
+		testutil.Assert(t, len(sharded) > 0, "shard %v has no symbols", i)
+		for symbol := range sharded {
+			seen[symbol]++
+		}
+	}
+	testutil.Equals(t, len(indexes), len(seen))
+	for symbol, n := range seen {
+		testutil.Equals(t, 1, n, "symbol %v in more than one shard", symbol)
+	}
+
+	_, err = shard(Config{Shards: 3, Shard: 3}, indexes)
+	testutil.NotOk(t, err)
+
+	// The listed symbols override the hash based shards.
+	sharded, err := shard(Config{Shards: 3, Symbols: []string{"SYM1/USD", "SYM2/USD"}}, indexes)
+	testutil.Ok(t, err)
+	testutil.Equals(t, 2, len(sharded))
+
+	_, err = shard(Config{Symbols: []string{"MISSING/USD"}}, indexes)
+	testutil.NotOk(t, err)
+}
+
+func TestScheduledSourceInterval(t *testing.T) {
+	hourly, err := cron.ParseStandard("0 * * * *")
+	testutil.Ok(t, err)
+	testutil.Equals(t, time.Hour, (&scheduledSource{schedule: hourly}).Interval())
+
+	daily, err := cron.ParseStandard("CRON_TZ=UTC 30 16 * * *")
+	testutil.Ok(t, err)
+	testutil.Equals(t, 24*time.Hour, (&scheduledSource{schedule: daily}).Interval())
+}
+
+type slowSource struct {
+	DataSource
+}
+
+func (self *slowSource) Get(ctx context.Context) (float64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-time.After(time.Minute):
+		return 1, nil
+	}
+}
+
+func TestSourceTimeout(t *testing.T) {
+	api := Apis{Timeout: format.Duration{Duration: 20 * time.Second}}
+	testutil.Equals(t, 2*time.Second, sourceTimeout(api, Endpoint{Timeout: format.Duration{Duration: 2 * time.Second}}, time.Minute))
+	testutil.Equals(t, 20*time.Second, sourceTimeout(api, Endpoint{}, time.Minute))
+	testutil.Equals(t, time.Minute, sourceTimeout(Apis{}, Endpoint{}, time.Minute))
+
+	source := &timeoutSource{DataSource: &slowSource{}, timeout: 10 * time.Millisecond}
+	_, err := source.Get(context.Background())
+	testutil.Equals(t, context.DeadlineExceeded, err)
+}
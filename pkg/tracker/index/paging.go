@@ -0,0 +1,105 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yalp/jsonpath"
+)
+
+// DefaultMaxPages is the max number of pages of a paginated API when not set.
+const DefaultMaxPages = 10
+
+// pagePlaceholder is replaced with the number of the page in the url of the next pages.
+const pagePlaceholder = "{page}"
+
+// Paging is how to get the next pages of a paginated API like a list of trades or markets
+// whose values need to be summed. The endpoint url is the first page.
+type Paging struct {
+	// Next is the json path of the url of the next page in the response, absolute or relative to the current page.
+	// The page without it is the last one.
+	Next string
+	// URL is the url of the next pages with a {page} placeholder for the page number when the response has no next url.
+	// The page with a 0 value is the last one.
+	URL string
+	// FirstPage is the number of the page of the endpoint url, usually 0 or 1.
+	FirstPage int
+	// MaxPages fails the get of an API with more pages, DefaultMaxPages when not set.
+	MaxPages int
+}
+
+// getPages gets all pages of the API and returns the sum of their values with the timestamp of the first page.
+// An API with more than the max pages returns an error instead of a partial sum.
+func (self *JSONapi) getPages(ctx context.Context) (float64, time.Time, error) {
+	maxPages := self.paging.MaxPages
+	if maxPages == 0 {
+		maxPages = DefaultMaxPages
+	}
+	var (
+		sum     float64
+		first   time.Time
+		pageURL = self.url
+	)
+	for page := 0; page < maxPages; page++ {
+		body, val, ts, err := self.getPage(ctx, pageURL)
+		if err != nil {
+			return 0, time.Time{}, errors.Wrapf(err, "page:%v", page+1)
+		}
+		if page == 0 {
+			first = ts
+		}
+		sum += val
+
+		pageURL, err = self.paging.next(pageURL, body, page, val)
+		if err != nil {
+			return 0, time.Time{}, errors.Wrapf(err, "next page after page:%v", page+1)
+		}
+		if pageURL == "" {
+			return sum, first, nil
+		}
+	}
+	return 0, time.Time{}, errors.Errorf("more pages than the max:%v", maxPages)
+}
+
+// next returns the url of the page after the current one or empty after the last page.
+func (self *Paging) next(current string, body []byte, page int, val float64) (string, error) {
+	if self.URL != "" {
+		if val == 0 {
+			return "", nil
+		}
+		return strings.ReplaceAll(self.URL, pagePlaceholder, strconv.Itoa(self.FirstPage+page+1)), nil
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", errors.Wrap(err, "json unmarshal")
+	}
+	v, err := jsonpath.Read(data, self.Next)
+	if err != nil || v == nil {
+		return "", nil
+	}
+	next, ok := v.(string)
+	if !ok {
+		return "", errors.Errorf("the next page url isn't a string:%v", v)
+	}
+	if next == "" {
+		return "", nil
+	}
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing the page url")
+	}
+	ref, err := url.Parse(next)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing the next page url")
+	}
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestPaging(t *testing.T) {
+	// 3 pages of trades with a volume of 1, 2 and 3.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page > 3 {
+			fmt.Fprint(w, `{"trades": [], "volume": 0}`)
+			return
+		}
+		next := fmt.Sprintf(`"/trades?page=%d"`, page+1)
+		if page == 3 {
+			next = "null"
+		}
+		fmt.Fprintf(w, `{"trades": [{}], "volume": %d, "next": %s}`, page, next)
+	}))
+	defer srv.Close()
+
+	get := func(paging Paging) (float64, error) {
+		endpoint, err := prepare(Endpoint{URL: srv.URL + "/trades", Param: "$.volume", Paging: &paging}, nil)
+		testutil.Ok(t, err)
+		source, err := newDataSource(context.Background(), "TRADES/VOLUME", Apis{}, endpoint, nil, 0, nil)
+		testutil.Ok(t, err)
+		return source.Get(context.Background())
+	}
+
+	// The next page url is relative to the current page.
+	v, err := get(Paging{Next: "$.next"})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 6.0, v)
+
+	// The pages continue until the empty one.
+	v, err = get(Paging{URL: srv.URL + "/trades?page={page}", FirstPage: 1})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 6.0, v)
+
+	// A partial sum is an error.
+	_, err = get(Paging{Next: "$.next", MaxPages: 2})
+	testutil.NotOk(t, err)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Quote returns the current value of a tracked symbol.
+type Quote func(symbol string) (float64, error)
+
+// pairSource converts the value of a source that quotes another pair to the pair of its symbol,
+// for example a USD/ETH source of ETH/USD or a TRB/ETH source of TRB/USD.
+type pairSource struct {
+	DataSource
+	invert bool
+	via    string
+	quote  Quote
+}
+
+func (self *pairSource) Get(ctx context.Context) (float64, error) {
+	v, err := self.DataSource.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return convertPair(v, self.invert, self.via, self.quote)
+}
+
+// convertPair inverts the value and then multiplies it with the value of the via symbol.
+func convertPair(v float64, invert bool, via string, quote Quote) (float64, error) {
+	if invert {
+		if v == 0 {
+			return 0, errors.New("can't invert a 0 value")
+		}
+		v = 1 / v
+	}
+	if via != "" {
+		q, err := quote(via)
+		if err != nil {
+			return 0, errors.Wrapf(err, "getting the quote of:%v", via)
+		}
+		v *= q
+	}
+	return v, nil
+}
+
+// latestValue is the last value of a data source.
+type latestValue struct {
+	value    float64
+	ts       time.Time
+	interval time.Duration
+}
+
+// setLatest keeps the last value of the data source for the sources quoted via its symbol.
+func (self *IndexTracker) setLatest(symbol, source string, value float64, ts time.Time, interval time.Duration) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	if self.latest == nil {
+		self.latest = make(map[string]map[string]latestValue)
+	}
+	if self.latest[symbol] == nil {
+		self.latest[symbol] = make(map[string]latestValue)
+	}
+	self.latest[symbol][source] = latestValue{value: value, ts: ts, interval: interval}
+}
+
+// quote returns the median of the recent values of the symbol.
+// A value is recent until its source misses two intervals.
+func (self *IndexTracker) quote(symbol string) (float64, error) {
+	self.mtx.Lock()
+	var vals []float64
+	for _, l := range self.latest[symbol] {
+		if time.Since(l.ts) <= 2*l.interval {
+			vals = append(vals, l.value)
+		}
+	}
+	self.mtx.Unlock()
+	if len(vals) == 0 {
+		return 0, errors.Errorf("no recent value of:%v", symbol)
+	}
+	return median(vals), nil
+}
+
+func median(vals []float64) float64 {
+	sort.Float64s(vals)
+	mid := len(vals) / 2
+	if len(vals)%2 == 0 {
+		return (vals[mid-1] + vals[mid]) / 2
+	}
+	return vals[mid]
+}
@@ -0,0 +1,80 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestPairSource(t *testing.T) {
+	body := `{"price": "0.0005"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	endpoint := Endpoint{URL: srv.URL, Type: httpSource, Parser: jsonPathParser, Param: "$.price"}
+
+	// A USD/ETH source of ETH/USD.
+	inverted := endpoint
+	inverted.Invert = true
+	source, err := newDataSource(context.Background(), "ETH/USD", Apis{}, inverted, nil, 0, nil)
+	testutil.Ok(t, err)
+	v, err := source.Get(context.Background())
+	testutil.Ok(t, err)
+	testutil.Equals(t, 2000.0, v)
+
+	// A TRB/ETH source of TRB/USD.
+	tracker := &IndexTracker{}
+	quoted := endpoint
+	quoted.QuoteVia = "ETH/USD"
+	_, err = newDataSource(context.Background(), "TRB/USD", Apis{}, quoted, nil, 0, nil)
+	testutil.NotOk(t, err)
+	min := 10.0
+	source, err = newDataSource(context.Background(), "TRB/USD", Apis{Expect: &Expect{Min: &min}}, quoted, nil, 0, tracker.quote)
+	testutil.Ok(t, err)
+	body = `{"price": "0.02"}`
+	_, err = source.Get(context.Background())
+	testutil.NotOk(t, err)
+
+	tracker.setLatest("ETH/USD", "a", 1900, time.Now(), time.Minute)
+	tracker.setLatest("ETH/USD", "b", 2100, time.Now(), time.Minute)
+	tracker.setLatest("ETH/USD", "c", 2050, time.Now().Add(-time.Hour), time.Minute)
+	v, err = source.Get(context.Background())
+	testutil.Ok(t, err)
+	testutil.Equals(t, 40.0, v)
+
+	// The range is checked after the conversion.
+	body = `{"price": "0.004"}`
+	_, err = source.Get(context.Background())
+	testutil.NotOk(t, err)
+
+	body = `{"price": "0"}`
+	source, err = newDataSource(context.Background(), "ETH/USD", Apis{}, inverted, nil, 0, nil)
+	testutil.Ok(t, err)
+	_, err = source.Get(context.Background())
+	testutil.NotOk(t, err)
+}
+
+func TestConvertChecks(t *testing.T) {
+	indexes := map[string]Apis{"ETH/USD": {}, "TRB/USD": {}}
+	checks := []Check{
+		{Symbol: "ETH/USD", Value: 2000},
+		{Symbol: "ETH/USD", Value: 0.0005},
+		{Symbol: "TRB/USD", Value: 0.02},
+	}
+	endpoints := []Endpoint{{}, {Invert: true}, {QuoteVia: "ETH/USD"}}
+	convertChecks(checks, endpoints, indexes)
+	for _, c := range checks {
+		testutil.Ok(t, c.Err)
+	}
+	testutil.Equals(t, 2000.0, checks[1].Value)
+	testutil.Equals(t, 40.0, checks[2].Value)
+}
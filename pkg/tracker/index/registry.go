@@ -0,0 +1,140 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/web"
+)
+
+// SignatureSuffix is added to the url of the registry and to the index file path for their signature.
+const SignatureSuffix = ".sig"
+
+// Signature is the content of the signature file of an index file.
+// The version and the expiry are signed together with the index file
+// so that whoever serves the registry can't replay an older index file.
+type Signature struct {
+	// Version increases with every signed index file
+	// and the reporters reject the versions older than the one they use.
+	Version uint64 `json:"version"`
+	// Expires is the unix time after which the index file is rejected.
+	Expires   int64  `json:"expires"`
+	Signature string `json:"signature"`
+}
+
+// LoadIndex returns the index file from the registry when it is set or the local one otherwise,
+// with its signature when it is from the registry.
+// The local index file is used also when the registry can't be reached
+// so that a restart during an outage of the registry still tracks the values.
+func LoadIndex(ctx context.Context, logger log.Logger, cfg Config) ([]byte, *Signature, error) {
+	if cfg.Registry != "" {
+		b, sig, err := FetchRegistry(ctx, cfg.Registry, cfg.RegistrySigner)
+		if err == nil {
+			return b, sig, nil
+		}
+		level.Error(logger).Log("msg", "getting the index file from the registry, using the local index file", "registry", cfg.Registry, "err", err)
+	}
+	b, err := ioutil.ReadFile(cfg.IndexFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "read index file path:%s", cfg.IndexFile)
+	}
+	return b, nil, nil
+}
+
+// envFilter returns which env variables are substituted in the urls of the index file.
+// All of them for the local index file and only the ones in RegistryEnv for the index file of the registry,
+// so a leaked key of the signer can't make the reporters send their private keys to any host.
+func envFilter(cfg Config, remote bool) func(string) bool {
+	if !remote {
+		return nil
+	}
+	return func(key string) bool {
+		for _, allowed := range cfg.RegistryEnv {
+			if key == allowed {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FetchRegistry gets the index file from the registry url
+// and returns it only when its signature at the url with the signature suffix is from the signer and not expired.
+func FetchRegistry(ctx context.Context, url, signer string) ([]byte, *Signature, error) {
+	if !common.IsHexAddress(signer) {
+		return nil, nil, errors.Errorf("invalid registry signer address:%v", signer)
+	}
+	b, err := web.Get(ctx, url, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "getting the index file")
+	}
+	sigFile, err := web.Get(ctx, url+SignatureSuffix, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "getting the signature")
+	}
+	sig := &Signature{}
+	if err := json.Unmarshal(sigFile, sig); err != nil {
+		return nil, nil, errors.Wrap(err, "parsing the signature")
+	}
+	if err := Verify(b, *sig, common.HexToAddress(signer), time.Now()); err != nil {
+		return nil, nil, err
+	}
+	return b, sig, nil
+}
+
+// signedMessage is the message of the signature of the index file.
+func signedMessage(b []byte, version uint64, expires int64) []byte {
+	return append([]byte(fmt.Sprintf("telliot index file version:%d expires:%d\n", version, expires)), b...)
+}
+
+// Sign returns the signature of the index file with its version and expiry.
+// The signature is in the same format as the personal_sign of the wallets
+// of the version and expiry line followed by the index file.
+func Sign(b []byte, version uint64, expires time.Time, key *ecdsa.PrivateKey) (Signature, error) {
+	sig, err := crypto.Sign(accounts.TextHash(signedMessage(b, version, expires.Unix())), key)
+	if err != nil {
+		return Signature{}, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return Signature{Version: version, Expires: expires.Unix(), Signature: hexutil.Encode(sig)}, nil
+}
+
+// Verify returns an error when the signature of the index file isn't from the signer or is expired.
+func Verify(b []byte, signature Signature, signer common.Address, now time.Time) error {
+	if !now.Before(time.Unix(signature.Expires, 0)) {
+		return errors.Errorf("the signature of the index file expired at:%v", time.Unix(signature.Expires, 0).UTC())
+	}
+	sig, err := hexutil.Decode(signature.Signature)
+	if err != nil {
+		return errors.Wrap(err, "decoding the signature")
+	}
+	if len(sig) != crypto.SignatureLength {
+		return errors.Errorf("invalid signature length:%v", len(sig))
+	}
+	// The wallets use 27 and 28 for the recovery id.
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(signedMessage(b, signature.Version, signature.Expires)), sig)
+	if err != nil {
+		return errors.Wrap(err, "recovering the signer")
+	}
+	if got := crypto.PubkeyToAddress(*pub); got != signer {
+		return errors.Errorf("the index file is signed by:%v instead of:%v", got.Hex(), signer.Hex())
+	}
+	return nil
+}
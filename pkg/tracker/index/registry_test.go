@@ -0,0 +1,117 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestRegistry(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	testutil.Ok(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	index := []byte(`{"ETH/USD": {"endpoints": [{"URL": "https://a", "param": "$.price"}]}}`)
+	expires := time.Now().Add(time.Hour)
+	sig, err := Sign(index, 1, expires, key)
+	testutil.Ok(t, err)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, SignatureSuffix) {
+			_ = json.NewEncoder(w).Encode(sig)
+			return
+		}
+		_, _ = w.Write(index)
+	}))
+	defer srv.Close()
+
+	b, fetched, err := FetchRegistry(context.Background(), srv.URL+"/index.json", signer)
+	testutil.Ok(t, err)
+	testutil.Equals(t, index, b)
+	testutil.Equals(t, uint64(1), fetched.Version)
+
+	// Signed by another account.
+	other, err := crypto.GenerateKey()
+	testutil.Ok(t, err)
+	_, _, err = FetchRegistry(context.Background(), srv.URL+"/index.json", crypto.PubkeyToAddress(other.PublicKey).Hex())
+	testutil.NotOk(t, err)
+
+	tracker := &IndexTracker{
+		logger:      log.NewNopLogger(),
+		ctx:         context.Background(),
+		cfg:         Config{Registry: srv.URL + "/index.json", RegistrySigner: signer},
+		registryErr: prometheus.NewCounter(prometheus.CounterOpts{Name: "registry_errors_total"}),
+		index:       b,
+		version:     fetched.Version,
+	}
+	_, changed, err := tracker.refresh()
+	testutil.Ok(t, err)
+	testutil.Assert(t, !changed, "the same index file is applied again")
+
+	index = []byte(`{"ETH/USD": {"endpoints": [{"URL": "https://a", "param": "$.price"}]}, "BTC/USD": {"endpoints": [{"URL": "https://b", "param": "$.price"}]}}`)
+	sig, err = Sign(index, 2, expires, key)
+	testutil.Ok(t, err)
+	dataSources, changed, err := tracker.refresh()
+	testutil.Ok(t, err)
+	testutil.Assert(t, changed, "the changed index file isn't applied")
+	testutil.Equals(t, 2, len(dataSources))
+	testutil.Equals(t, 2, len(tracker.sources()))
+	testutil.Assert(t, tracker.HasSources("BTC/USD"), "the symbols of the changed index file should be known")
+
+	// A changed file with an old signature is rejected and the current sources are kept.
+	index = []byte(`{"TRB/USD": {"endpoints": [{"URL": "https://c", "param": "$.price"}]}}`)
+	_, _, err = tracker.refresh()
+	testutil.NotOk(t, err)
+	testutil.Equals(t, 2, len(tracker.sources()))
+
+	// An older index file replayed with its valid signature is rejected.
+	index = []byte(`{"ETH/USD": {"endpoints": [{"URL": "https://a", "param": "$.price"}]}}`)
+	sig, err = Sign(index, 1, expires, key)
+	testutil.Ok(t, err)
+	_, _, err = tracker.refresh()
+	testutil.NotOk(t, err)
+	testutil.Equals(t, 2, len(tracker.sources()))
+
+	// The same version with a changed index file is rejected as well.
+	sig, err = Sign(index, 2, expires, key)
+	testutil.Ok(t, err)
+	_, _, err = tracker.refresh()
+	testutil.NotOk(t, err)
+
+	// An expired signature is rejected.
+	sig, err = Sign(index, 3, time.Now().Add(-time.Minute), key)
+	testutil.Ok(t, err)
+	_, _, err = tracker.refresh()
+	testutil.NotOk(t, err)
+	testutil.Equals(t, 2, len(tracker.sources()))
+
+	// Only the allowed env variables are substituted in the urls of the registry.
+	testutil.Setenv(t, "TEST_REGISTRY_SECRET", "secret")
+	testutil.Setenv(t, "TEST_REGISTRY_API_KEY", "key")
+	tracker.cfg.RegistryEnv = []string{"TEST_REGISTRY_API_KEY"}
+	index = []byte(`{"ETH/USD": {"endpoints": [{"URL": "https://a?k=${TEST_REGISTRY_SECRET}", "param": "$.price"}]}}`)
+	sig, err = Sign(index, 3, expires, key)
+	testutil.Ok(t, err)
+	_, _, err = tracker.refresh()
+	testutil.NotOk(t, err)
+	testutil.Equals(t, 2, len(tracker.sources()))
+
+	index = []byte(`{"ETH/USD": {"endpoints": [{"URL": "https://a?k=${TEST_REGISTRY_API_KEY}", "param": "$.price"}]}}`)
+	sig, err = Sign(index, 4, expires, key)
+	testutil.Ok(t, err)
+	_, changed, err = tracker.refresh()
+	testutil.Ok(t, err)
+	testutil.Assert(t, changed, "the index file with an allowed env variable isn't applied")
+	testutil.Equals(t, uint64(4), tracker.version)
+}
@@ -0,0 +1,189 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/notify"
+)
+
+// The alert rules of the profit tracker.
+const (
+	RuleMinProfit   = "minProfit"
+	RuleMaxGasRatio = "maxGasRatio"
+)
+
+type AlertsConfig struct {
+	Enabled     bool
+	Interval    format.Duration `help:"How often to check the thresholds."`
+	Window      format.Duration `help:"Rolling window of the profit and the gas ratio."`
+	MinProfit   float64         `help:"Alert when the profit of an account in the window is below this many TRB. The gas costs are converted to TRB at the prices of their blocks."`
+	MaxGasRatio float64         `help:"Alert when the gas cost of an account in the window is above this share of its rewards in USD, for example 0.5. 0 disables the alert."`
+}
+
+// windowStats are the totals of an account in the alert window.
+type windowStats struct {
+	profitTRB float64
+	// pricedTRB is false when the gas of some submit can't be converted to TRB
+	// as the profit history has no price for its block.
+	pricedTRB bool
+	gasRatio  float64
+}
+
+func windowTotals(events []Event) map[string]*windowStats {
+	totals := make(map[string]*windowStats)
+	rewardUSD := make(map[string]float64)
+	gasUSD := make(map[string]float64)
+	for _, e := range events {
+		account := strings.ToLower(e.Account)
+		s, ok := totals[account]
+		if !ok {
+			s = &windowStats{pricedTRB: true}
+			totals[account] = s
+		}
+		switch e.Kind {
+		case KindReward:
+			s.profitTRB += e.Amount
+			rewardUSD[account] += e.USD
+		case KindGas:
+			gasUSD[account] += e.USD
+			if e.ETHUSD == 0 || e.TRBUSD == 0 {
+				s.pricedTRB = false
+				continue
+			}
+			s.profitTRB -= e.Amount * e.ETHUSD / e.TRBUSD
+		case KindDisputeWin:
+			s.profitTRB += e.Amount
+		case KindSlash, KindDisputeLoss:
+			s.profitTRB -= e.Amount
+		}
+	}
+	for account, s := range totals {
+		switch {
+		case gasUSD[account] == 0:
+			s.gasRatio = 0
+		case rewardUSD[account] == 0:
+			s.gasRatio = math.Inf(1)
+		default:
+			s.gasRatio = gasUSD[account] / rewardUSD[account]
+		}
+	}
+	return totals
+}
+
+// monitorAlerts checks the profit of the accounts in the rolling window against the thresholds
+// and notifies when an account crosses a threshold and when it recovers.
+// The accounts are checked only after the tracker has been running for a whole window
+// so that the missing history of a fresh start doesn't raise alerts.
+func (self *ProfitTracker) monitorAlerts() {
+	ticker := time.NewTicker(self.alertsConfig().Interval.Duration)
+	defer ticker.Stop()
+
+	started := time.Now()
+	firing := make(map[string]bool)
+	for {
+		select {
+		case <-self.ctx.Done():
+			return
+		case interval := <-self.alertsIntervalCh:
+			ticker.Reset(interval)
+			continue
+		case <-ticker.C:
+		}
+		if time.Since(started) < self.alertsConfig().Window.Duration {
+			continue
+		}
+		self.checkAlerts(firing)
+	}
+}
+
+// Reload applies the interval, the window and the thresholds of the alerts of a new config without a restart.
+func (self *ProfitTracker) Reload(cfg AlertsConfig) {
+	self.alertsMtx.Lock()
+	defer self.alertsMtx.Unlock()
+	if cfg.Interval != self.cfg.Alerts.Interval {
+		select {
+		case <-self.alertsIntervalCh:
+		default:
+		}
+		self.alertsIntervalCh <- cfg.Interval.Duration
+	}
+	self.cfg.Alerts.Interval = cfg.Interval
+	self.cfg.Alerts.Window = cfg.Window
+	self.cfg.Alerts.MinProfit = cfg.MinProfit
+	self.cfg.Alerts.MaxGasRatio = cfg.MaxGasRatio
+}
+
+func (self *ProfitTracker) alertsConfig() AlertsConfig {
+	self.alertsMtx.Lock()
+	defer self.alertsMtx.Unlock()
+	return self.cfg.Alerts
+}
+
+func (self *ProfitTracker) checkAlerts(firing map[string]bool) {
+	cfg := self.alertsConfig()
+	events, err := self.store.List(Filter{From: time.Now().Add(-cfg.Window.Duration)})
+	if err != nil {
+		level.Error(self.logger).Log("msg", "reading the profit history for the alerts", "err", err)
+		return
+	}
+	totals := windowTotals(events)
+	for _, addr := range self.addrs {
+		s, ok := totals[strings.ToLower(addr.String())]
+		if !ok {
+			s = &windowStats{pricedTRB: true}
+		}
+		self.windowProfit.With(prometheus.Labels{"addr": addr.String()}).(prometheus.Gauge).Set(s.profitTRB)
+		self.windowGasRatio.With(prometheus.Labels{"addr": addr.String()}).(prometheus.Gauge).Set(s.gasRatio)
+
+		if s.pricedTRB {
+			self.setAlert(firing, addr.String(), RuleMinProfit, s.profitTRB < cfg.MinProfit,
+				fmt.Sprintf("The profit of %v in the last %v is %.4f TRB, the threshold is %v TRB.", addr.String(), cfg.Window, s.profitTRB, cfg.MinProfit))
+		} else {
+			level.Warn(self.logger).Log("msg", "the gas of some submits has no price, skipping the profit check", "addr", addr.String())
+		}
+		if cfg.MaxGasRatio > 0 {
+			self.setAlert(firing, addr.String(), RuleMaxGasRatio, s.gasRatio > cfg.MaxGasRatio,
+				fmt.Sprintf("The gas cost of %v in the last %v is %.2f of its rewards, the threshold is %v.", addr.String(), cfg.Window, s.gasRatio, cfg.MaxGasRatio))
+		}
+	}
+}
+
+// setAlert sets the alert gauge of a rule and notifies when its state changes.
+func (self *ProfitTracker) setAlert(firing map[string]bool, addr, rule string, fire bool, msg string) {
+	gauge := self.alerts.With(prometheus.Labels{"addr": addr, "rule": rule}).(prometheus.Gauge)
+	key := addr + rule
+	switch {
+	case fire && !firing[key]:
+		gauge.Set(1)
+		firing[key] = true
+		self.notify(notify.SeverityWarning, "Profit alert", msg)
+	case !fire && firing[key]:
+		gauge.Set(0)
+		delete(firing, key)
+		self.notify(notify.SeverityInfo, "Profit alert resolved", msg)
+	case !fire:
+		gauge.Set(0)
+	}
+}
+
+func (self *ProfitTracker) notify(severity notify.Severity, title, msg string) {
+	if self.notifier == nil {
+		level.Warn(self.logger).Log("msg", title, "details", msg)
+		return
+	}
+	self.notifier.Notify(notify.Event{
+		Component: ComponentName,
+		Severity:  severity,
+		Title:     title,
+		Message:   msg,
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestWindowTotals(t *testing.T) {
+	totals := windowTotals([]Event{
+		{Account: "0xA", Kind: KindReward, Amount: 2, USD: 100},
+		// 0.01 ETH at 2500 USD is 0.5 TRB at 50 USD.
+		{Account: "0xA", Kind: KindGas, Amount: 0.01, ETHUSD: 2500, TRBUSD: 50, USD: 25},
+		{Account: "0xB", Kind: KindGas, Amount: 0.01, ETHUSD: 2500, TRBUSD: 50, USD: 25},
+		{Account: "0xC", Kind: KindReward, Amount: 1, USD: 50},
+		{Account: "0xC", Kind: KindGas, Amount: 0.01, USD: 0},
+	})
+
+	testutil.Equals(t, &windowStats{profitTRB: 1.5, pricedTRB: true, gasRatio: 0.25}, totals["0xa"])
+	testutil.Equals(t, -0.5, totals["0xb"].profitTRB)
+	testutil.Assert(t, math.IsInf(totals["0xb"].gasRatio, 1), "the gas ratio without rewards should be infinite")
+	testutil.Assert(t, !totals["0xc"].pricedTRB, "the gas without a price can't be converted to TRB")
+}
+
+func TestReloadAlerts(t *testing.T) {
+	tracker := &ProfitTracker{
+		cfg:              Config{Alerts: AlertsConfig{Enabled: true, Interval: format.Duration{Duration: time.Minute}, Window: format.Duration{Duration: time.Hour}}},
+		alertsIntervalCh: make(chan time.Duration, 1),
+	}
+	tracker.Reload(AlertsConfig{Interval: format.Duration{Duration: 2 * time.Minute}, Window: format.Duration{Duration: 2 * time.Hour}, MinProfit: 1, MaxGasRatio: 0.5})
+
+	// Enabled needs a restart so it is kept.
+	testutil.Equals(t, AlertsConfig{Enabled: true, Interval: format.Duration{Duration: 2 * time.Minute}, Window: format.Duration{Duration: 2 * time.Hour}, MinProfit: 1, MaxGasRatio: 0.5}, tracker.alertsConfig())
+	testutil.Equals(t, 2*time.Minute, <-tracker.alertsIntervalCh)
+
+	// The same interval doesn't reset the ticker.
+	tracker.Reload(AlertsConfig{Interval: format.Duration{Duration: 2 * time.Minute}})
+	testutil.Equals(t, 0, len(tracker.alertsIntervalCh))
+}
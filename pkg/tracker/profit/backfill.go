@@ -0,0 +1,41 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+)
+
+// Backfill reconstructs the profit history from the given block to the current head
+// from the Transfer and NonceSubmitted logs and the failed submits and outgoing transfers in the blocks.
+// The events already in the history are kept as these were recorded with the prices of their time.
+func (self *ProfitTracker) Backfill(from uint64) error {
+	if from == 0 {
+		return errors.New("the start block should be after the genesis block")
+	}
+	events, err := self.store.List(Filter{})
+	if err != nil {
+		return errors.Wrap(err, "reading the profit history")
+	}
+	self.recorded = make(map[string]Event)
+	for _, e := range events {
+		self.recorded[e.ID] = e
+	}
+	defer func() { self.recorded = nil }()
+
+	logger := self.logger
+	level.Info(logger).Log("msg", "backfilling the profit history", "from", from, "recorded", len(events))
+
+	if _, err := self.backfillTransfers(logger, from); err != nil {
+		return errors.Wrap(err, "backfilling the rewards")
+	}
+	if _, err := self.backfillNonceSubmitted(logger, from); err != nil {
+		return errors.Wrap(err, "backfilling the submit costs")
+	}
+	if _, err := self.backfillHeads(logger, from-1); err != nil {
+		return errors.Wrap(err, "backfilling the failed submits and outgoing transfers")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestRecordWhileBackfilling(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profit")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+	store, err := Open(dir)
+	testutil.Ok(t, err)
+
+	gauge := func() *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test"}, []string{"addr"})
+	}
+	tracker := &ProfitTracker{
+		store: store,
+		// The prices of old blocks are usually not in the DB anymore.
+		prices: func(string, time.Time) (float64, error) {
+			return 0, errors.New("no price")
+		},
+		submitProfitUSD: gauge(),
+		submitCostUSD:   gauge(),
+		profitUSD:       gauge(),
+	}
+
+	recorded := Event{ID: "1", Time: time.Now(), Account: "0xA", Kind: KindReward, Amount: 2, TRBUSD: 50, USD: 100}
+	testutil.Ok(t, store.Add(recorded))
+	tracker.recorded = map[string]Event{recorded.ID: recorded}
+
+	// The event recorded live keeps its USD value.
+	e := tracker.record(log.NewNopLogger(), Event{ID: "1", Time: recorded.Time, Account: "0xA", Kind: KindReward, Amount: 2})
+	testutil.Equals(t, recorded, e)
+	// A missed event is added without a USD value.
+	e = tracker.record(log.NewNopLogger(), Event{ID: "2", Time: recorded.Time, Account: "0xA", Kind: KindReward, Amount: 1})
+	testutil.Equals(t, 0.0, e.USD)
+
+	events, err := store.List(Filter{})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 2, len(events))
+	testutil.Equals(t, 100.0, events[0].USD)
+}
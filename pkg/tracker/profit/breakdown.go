@@ -0,0 +1,123 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+)
+
+// rangesInterval is how often to update the profit series of the ranges.
+const rangesInterval = 5 * time.Minute
+
+// Breakdown is the profit of an account in a range compared with the range of the same length before it.
+type Breakdown struct {
+	Summary
+	From              time.Time `json:"from"`
+	To                time.Time `json:"to"`
+	PreviousProfitUSD float64   `json:"previousProfitUsd"`
+	// Trend is the relative change of the profit from the previous range,
+	// for example -0.25 when it dropped by a quarter.
+	// It is null when there was no profit in the previous range.
+	Trend *float64 `json:"trend"`
+}
+
+// ParseRange parses a range like 24h, 7d or 30d.
+func ParseRange(r string) (time.Duration, error) {
+	d, err := model.ParseDuration(r)
+	if err != nil || d <= 0 {
+		return 0, errors.Errorf("invalid range:%v", r)
+	}
+	return time.Duration(d), nil
+}
+
+// Breakdowns returns the profit of each account in the range ending at the given time.
+// The events need to include the range before it for the trend.
+func Breakdowns(events []Event, r time.Duration, to time.Time) []Breakdown {
+	from := to.Add(-r)
+	var current, previous []Event
+	for _, e := range events {
+		switch {
+		case e.Time.Before(from.Add(-r)) || !e.Time.Before(to):
+		case e.Time.Before(from):
+			previous = append(previous, e)
+		default:
+			current = append(current, e)
+		}
+	}
+
+	previousProfit := make(map[string]float64)
+	for _, s := range Summarize(previous) {
+		previousProfit[strings.ToLower(s.Account)] = s.ProfitUSD
+	}
+	breakdowns := []Breakdown{}
+	for _, s := range Summarize(current) {
+		b := Breakdown{Summary: s, From: from, To: to, PreviousProfitUSD: previousProfit[strings.ToLower(s.Account)]}
+		if b.PreviousProfitUSD != 0 {
+			trend := (b.ProfitUSD - b.PreviousProfitUSD) / abs(b.PreviousProfitUSD)
+			b.Trend = &trend
+		}
+		breakdowns = append(breakdowns, b)
+	}
+	return breakdowns
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// monitorRanges updates the per account profit series of the configured ranges.
+func (self *ProfitTracker) monitorRanges() {
+	ticker := time.NewTicker(rangesInterval)
+	defer ticker.Stop()
+	for {
+		self.updateRanges()
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *ProfitTracker) updateRanges() {
+	var longest time.Duration
+	for _, r := range self.ranges {
+		if r > longest {
+			longest = r
+		}
+	}
+	now := time.Now()
+	events, err := self.store.List(Filter{From: now.Add(-2 * longest)})
+	if err != nil {
+		level.Error(self.logger).Log("msg", "reading the profit history for the ranges", "err", err)
+		return
+	}
+	for i, r := range self.ranges {
+		byAccount := make(map[string]Breakdown)
+		for _, b := range Breakdowns(events, r, now) {
+			byAccount[strings.ToLower(b.Account)] = b
+		}
+		for _, addr := range self.addrs {
+			b := byAccount[strings.ToLower(addr.String())]
+			labels := prometheus.Labels{"addr": addr.String(), "range": self.cfg.Ranges[i]}
+			self.rangeReward.With(labels).(prometheus.Gauge).Set(b.RewardUSD)
+			self.rangeGas.With(labels).(prometheus.Gauge).Set(b.GasUSD)
+			self.rangeProfit.With(labels).(prometheus.Gauge).Set(b.ProfitUSD)
+			trend := 0.0
+			if b.Trend != nil {
+				trend = *b.Trend
+			}
+			self.rangeTrend.With(labels).(prometheus.Gauge).Set(trend)
+		}
+	}
+}
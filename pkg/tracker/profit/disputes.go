@@ -0,0 +1,227 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/contracts/tellor"
+)
+
+// dispute is the outcome of a dispute whose fee is unlocked.
+type dispute struct {
+	id             *big.Int
+	votePassed     bool
+	reportedMiner  common.Address
+	reportingParty common.Address
+	requestID      *big.Int
+	timestamp      time.Time
+}
+
+func (self dispute) String() string {
+	return fmt.Sprintf("dispute %v for the value of request %v at %v", self.id, self.requestID, self.timestamp.UTC().Format(time.RFC3339))
+}
+
+// txCall is the contract method called by a transaction with its arguments.
+type txCall struct {
+	method *abi.Method
+	args   []interface{}
+}
+
+// txMethod returns the contract method called by a transaction with its arguments.
+// It returns a nil method for the transactions that don't call a method of the contract.
+// The contract pays out the rewards of a submit in several transfers
+// so the calls are cached to get each transaction only once.
+func (self *ProfitTracker) txMethod(hash common.Hash) (*abi.Method, []interface{}, error) {
+	if c, err := self.cacheTXsMethod.Get(hash); err == nil {
+		call := c.(txCall)
+		return call.method, call.args, nil
+	}
+	tx, _, err := self.client.TransactionByHash(self.ctx, hash)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "get transaction by hash")
+	}
+	var call txCall
+	if len(tx.Data()) >= 4 {
+		if method, err := self.abi.MethodById(tx.Data()[:4]); err == nil {
+			args, err := method.Inputs.Unpack(tx.Data()[4:])
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "unpack the arguments of:%v", method.Name)
+			}
+			call = txCall{method: method, args: args}
+		}
+	}
+	if err := self.cacheTXsMethod.Set(hash, call); err != nil {
+		return nil, nil, errors.Wrap(err, "adding the transaction method to the cache")
+	}
+	return call.method, call.args, nil
+}
+
+// unlockedDispute returns the dispute of an unlockDisputeFee call.
+func (self *ProfitTracker) unlockedDispute(args []interface{}) (*dispute, error) {
+	disputeID := args[0].(*big.Int)
+	_, _, votePassed, _, reportedMiner, reportingParty, _, uintVars, _, err := self.contractInstance.GetAllDisputeVars(&bind.CallOpts{Context: self.ctx}, disputeID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get dispute details id:%v", disputeID)
+	}
+	return &dispute{
+		id:             disputeID,
+		votePassed:     votePassed,
+		reportedMiner:  reportedMiner,
+		reportingParty: reportingParty,
+		requestID:      uintVars[0],
+		timestamp:      time.Unix(uintVars[1].Int64(), 0),
+	}, nil
+}
+
+// handleDisputes records the dispute fees that the contract pays out in the block
+// when these are unlocked after the vote.
+// The disputer gets back the fee and the stake of the reported miner when the vote passed,
+// otherwise the reported miner gets the fee.
+func (self *ProfitTracker) handleDisputes(logger log.Logger, header *types.Header) {
+	if !header.Bloom.Test(self.abi.Events["Transfer"].ID.Bytes()) ||
+		!header.Bloom.Test(common.BytesToHash(self.contractInstance.Address.Bytes()).Bytes()) {
+		return
+	}
+
+	logger = log.With(logger, "block", header.Number)
+	tellorFilterer, err := tellor.NewTellorFilterer(self.contractInstance.Address, self.client)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting instance", "err", err)
+		return
+	}
+	block := header.Number.Uint64()
+	iter, err := tellorFilterer.FilterTransferred(&bind.FilterOpts{Context: self.ctx, Start: block, End: &block}, []common.Address{self.contractInstance.Address}, nil)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting the payouts of the block", "err", err)
+		return
+	}
+	defer iter.Close()
+	for iter.Next() {
+		if iter.Event.Raw.BlockHash != header.Hash() {
+			continue
+		}
+		if err := self.setDisputePayout(log.With(logger, "tx", iter.Event.Raw.TxHash), header, iter.Event); err != nil {
+			level.Error(logger).Log("msg", "checking the dispute of the payout", "tx", iter.Event.Raw.TxHash, "err", err)
+		}
+	}
+	if err := iter.Error(); err != nil {
+		level.Error(logger).Log("msg", "reading the payouts of the block", "err", err)
+	}
+}
+
+// setDisputePayout records a transfer from the contract when it pays out a dispute fee of a tracked account.
+// The payout address alone is not enough as the disputer of a failed dispute is not in the payout.
+func (self *ProfitTracker) setDisputePayout(logger log.Logger, header *types.Header, event *tellor.TellorTransferred) error {
+	method, args, err := self.txMethod(event.Raw.TxHash)
+	if err != nil || method == nil || method.Name != "unlockDisputeFee" {
+		return err
+	}
+	d, err := self.unlockedDispute(args)
+	if err != nil {
+		return err
+	}
+	_, disputer := self.addrsMap[d.reportingParty]
+	_, miner := self.addrsMap[d.reportedMiner]
+
+	fee := trbAmount(event.Value)
+	switch {
+	case d.votePassed && disputer && event.To == d.reportingParty:
+		self.recordDispute(logger, header, event, d.reportingParty, KindDisputeRefund, fee, "won "+d.String())
+		stake, err := self.stakeTransfer(header, event.Raw.TxHash, d)
+		if err != nil {
+			return err
+		}
+		if stake != nil {
+			self.recordDispute(logger, header, stake, d.reportingParty, KindDisputeWin, trbAmount(stake.Value), "won "+d.String())
+		}
+	case !d.votePassed && event.To == d.reportedMiner:
+		if miner {
+			self.recordDispute(logger, header, event, d.reportedMiner, KindDisputeWin, fee, "won "+d.String())
+		}
+		if disputer {
+			self.recordDispute(logger, header, event, d.reportingParty, KindDisputeLoss, fee, "lost "+d.String())
+		}
+	}
+	return nil
+}
+
+// stakeTransfer returns the transfer of the stake of the reported miner to the disputer in the transaction.
+func (self *ProfitTracker) stakeTransfer(header *types.Header, tx common.Hash, d *dispute) (*tellor.TellorTransferred, error) {
+	tellorFilterer, err := tellor.NewTellorFilterer(self.contractInstance.Address, self.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting instance")
+	}
+	block := header.Number.Uint64()
+	iter, err := tellorFilterer.FilterTransferred(&bind.FilterOpts{Context: self.ctx, Start: block, End: &block}, []common.Address{d.reportedMiner}, []common.Address{d.reportingParty})
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the stake transfer")
+	}
+	defer iter.Close()
+	for iter.Next() {
+		if iter.Event.Raw.TxHash == tx && iter.Event.Raw.BlockHash == header.Hash() {
+			return iter.Event, nil
+		}
+	}
+	return nil, errors.Wrap(iter.Error(), "reading the stake transfer")
+}
+
+// recordDispute records a dispute event of an account from a transfer.
+func (self *ProfitTracker) recordDispute(logger log.Logger, header *types.Header, event *tellor.TellorTransferred, account common.Address, kind Kind, amount float64, cause string) {
+	id := txIDTransferOut(event) + string(kind)
+	if self.cacheDisputes.Has(id) {
+		return
+	}
+	logger = log.With(logger, "addr", account.String()[:6])
+	level.Info(logger).Log("msg", "adding dispute event", "kind", kind, "amount", amount, "cause", cause)
+	e := self.record(logger, Event{
+		ID:        id,
+		Time:      time.Unix(int64(header.Time), 0),
+		Block:     header.Number.Uint64(),
+		BlockHash: header.Hash().String(),
+		Tx:        event.Raw.TxHash.String(),
+		Account:   account.String(),
+		Kind:      kind,
+		Token:     "TRB",
+		Amount:    amount,
+		Cause:     cause,
+	})
+	if err := self.cacheDisputes.Set(id, entry{block: header.Number.Uint64(), hash: header.Hash(), addr: account, amount: amount, usd: e.USD, kind: kind}); err != nil {
+		level.Error(logger).Log("msg", "adding dispute event to the cache", "err", err)
+	}
+	self.setDisputeLocked(logger, account)
+}
+
+// setDisputeLocked sets the TRB locked in the open disputes of an account from the profit history
+// so that it is correct after a restart.
+func (self *ProfitTracker) setDisputeLocked(logger log.Logger, addr common.Address) {
+	events, err := self.store.List(Filter{Account: addr.String()})
+	if err != nil {
+		level.Error(logger).Log("msg", "reading the profit history for the locked dispute fees", "err", err)
+		return
+	}
+	var locked float64
+	for _, s := range Summarize(events) {
+		if strings.EqualFold(s.Account, addr.String()) {
+			locked = s.DisputeLockedTRB
+		}
+	}
+	self.disputeLocked.With(prometheus.Labels{"addr": addr.String()}).(prometheus.Gauge).Set(locked)
+}
+
+func trbAmount(value *big.Int) float64 {
+	trb, _ := new(big.Float).Quo(new(big.Float).SetInt(value), big.NewFloat(1e18)).Float64()
+	return trb
+}
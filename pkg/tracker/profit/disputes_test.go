@@ -0,0 +1,40 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/bluele/gcache"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tellor-io/telliot/pkg/contracts/tellor"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestTxMethodCache(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(tellor.TellorABI))
+	testutil.Ok(t, err)
+	method := contractABI.Methods["unlockDisputeFee"]
+
+	// The tracker has no client so the transactions can only come from the cache.
+	tracker := &ProfitTracker{cacheTXsMethod: gcache.New(10).LRU().Build()}
+	hash := common.HexToHash("0x1")
+	testutil.Ok(t, tracker.cacheTXsMethod.Set(hash, txCall{method: &method, args: []interface{}{big.NewInt(1)}}))
+	for i := 0; i < 2; i++ {
+		m, args, err := tracker.txMethod(hash)
+		testutil.Ok(t, err)
+		testutil.Equals(t, "unlockDisputeFee", m.Name)
+		testutil.Equals(t, []interface{}{big.NewInt(1)}, args)
+	}
+
+	// The transactions that don't call the contract are cached too.
+	other := common.HexToHash("0x2")
+	testutil.Ok(t, tracker.cacheTXsMethod.Set(other, txCall{}))
+	m, _, err := tracker.txMethod(other)
+	testutil.Ok(t, err)
+	testutil.Assert(t, m == nil, "a transaction without a contract call should have no method")
+}
@@ -0,0 +1,95 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The formats of the exported profit history.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// dateLayout is the layout of the dates of the export period.
+const dateLayout = "2006-01-02"
+
+// ParsePeriod returns the filter times of a period given as dates like 2021-01-31 or RFC3339 times.
+// The dates are in UTC and the end date is included.
+// An empty date leaves that end of the period open.
+func ParsePeriod(from, to string) (time.Time, time.Time, error) {
+	start, err := parseDate(from, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrapf(err, "invalid from date:%v", from)
+	}
+	end, err := parseDate(to, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrapf(err, "invalid to date:%v", to)
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.Errorf("the from date:%v is not before the to date:%v", from, to)
+	}
+	return start, end, nil
+}
+
+func parseDate(date string, end bool) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// Export writes the events in the given format.
+func Export(w io.Writer, format string, events []Event) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return errors.Wrap(enc.Encode(events), "encoding the events")
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"time", "block", "tx", "account", "kind", "origin", "token", "amount", "eth_usd", "trb_usd", "usd", "cause"}); err != nil {
+			return errors.Wrap(err, "writing the header")
+		}
+		for _, e := range events {
+			if err := cw.Write([]string{
+				e.Time.UTC().Format(time.RFC3339),
+				strconv.FormatUint(e.Block, 10),
+				e.Tx,
+				e.Account,
+				string(e.Kind),
+				e.Origin,
+				e.Token,
+				strconv.FormatFloat(e.Amount, 'f', -1, 64),
+				strconv.FormatFloat(e.ETHUSD, 'f', -1, 64),
+				strconv.FormatFloat(e.TRBUSD, 'f', -1, 64),
+				strconv.FormatFloat(e.USD, 'f', 2, 64),
+				e.Cause,
+			}); err != nil {
+				return errors.Wrap(err, "writing the event")
+			}
+		}
+		cw.Flush()
+		return errors.Wrap(cw.Error(), "writing the events")
+	default:
+		return errors.Errorf("invalid format:%v, supported formats are %v and %v", format, FormatCSV, FormatJSON)
+	}
+}
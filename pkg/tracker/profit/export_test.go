@@ -0,0 +1,44 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestParsePeriod(t *testing.T) {
+	from, to, err := ParsePeriod("2021-01-01", "2021-01-31")
+	testutil.Ok(t, err)
+	testutil.Equals(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	testutil.Equals(t, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = ParsePeriod("", "2021-01-31T12:00:00Z")
+	testutil.Ok(t, err)
+	testutil.Assert(t, from.IsZero(), "an empty date should leave the period open")
+	testutil.Equals(t, time.Date(2021, 1, 31, 12, 0, 0, 0, time.UTC), to)
+
+	_, _, err = ParsePeriod("2021-02-01", "2021-01-31")
+	testutil.NotOk(t, err)
+	_, _, err = ParsePeriod("01/02/2021", "")
+	testutil.NotOk(t, err)
+}
+
+func TestExport(t *testing.T) {
+	events := []Event{
+		{Time: time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC), Block: 100, Tx: "0x1", Account: "0xA", Kind: KindReward, Token: "TRB", Amount: 1.5, TRBUSD: 40, USD: 60},
+		{Time: time.Date(2021, 1, 2, 10, 0, 0, 0, time.UTC), Block: 200, Tx: "0x2", Account: "0xA", Kind: KindGas, Origin: OriginStake, Token: "ETH", Amount: 0.01, ETHUSD: 2500, USD: 25},
+	}
+
+	var b bytes.Buffer
+	testutil.Ok(t, Export(&b, FormatCSV, events))
+	testutil.Equals(t, "time,block,tx,account,kind,origin,token,amount,eth_usd,trb_usd,usd,cause\n"+
+		"2021-01-01T10:00:00Z,100,0x1,0xA,reward,,TRB,1.5,0,40,60.00,\n"+
+		"2021-01-02T10:00:00Z,200,0x2,0xA,gas,stake,ETH,0.01,2500,0,25.00,\n", b.String())
+
+	testutil.NotOk(t, Export(&b, "xml", events))
+}
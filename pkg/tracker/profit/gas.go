@@ -0,0 +1,145 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/txs"
+)
+
+// The origins of the gas expenses.
+const (
+	// OriginSubmission is the gas of the submits and of the transactions that cancel a stuck submit.
+	OriginSubmission = "submission"
+	// OriginStake is the gas of the deposits and withdrawals of the stake.
+	OriginStake = "stake"
+	// OriginDispute is the gas of the disputes, votes, tallies and unlocked dispute fees.
+	OriginDispute = "dispute"
+	// OriginCLI is the gas of the transactions sent manually with the cli commands.
+	OriginCLI = "cli"
+	// OriginOther is the gas of the other transactions of the accounts.
+	OriginOther = "other"
+)
+
+// methodOrigin returns the origin of a transaction from the contract method that it calls.
+func methodOrigin(name string) string {
+	switch name {
+	case "submitMiningSolution":
+		return OriginSubmission
+	case "depositStake", "requestStakingWithdraw", "withdrawStake":
+		return OriginStake
+	case "beginDispute", "vote", "tallyVotes", "unlockDisputeFee":
+		return OriginDispute
+	}
+	return OriginOther
+}
+
+// recordOrigin returns the origin of a transaction in the transaction history.
+func recordOrigin(r txs.Record) string {
+	if r.CLI {
+		return OriginCLI
+	}
+	switch r.Type {
+	case txs.TypeSubmit, txs.TypeCancel:
+		return OriginSubmission
+	case txs.TypeDeposit, txs.TypeRequestWithdraw, txs.TypeWithdraw:
+		return OriginStake
+	case txs.TypeDispute, txs.TypeVote, txs.TypeTally, txs.TypeUnlockFee:
+		return OriginDispute
+	}
+	return OriginOther
+}
+
+// recordTxsGas records the gas of the mined transactions in the transaction history
+// which are not in the profit history yet.
+// The successful submits are recorded from their events so these are skipped.
+func (self *ProfitTracker) recordTxsGas(records []txs.Record) {
+	var recorded map[string]struct{}
+	for _, r := range records {
+		if r.CostETH == 0 || (r.Status != txs.StatusSuccess && r.Status != txs.StatusFailed) ||
+			(r.Type == txs.TypeSubmit && r.Status == txs.StatusSuccess) {
+			continue
+		}
+		if _, ok := self.addrsMap[common.HexToAddress(r.Account)]; !ok {
+			continue
+		}
+		// Read the history only when there is a candidate.
+		if recorded == nil {
+			events, err := self.store.List(Filter{Kind: KindGas})
+			if err != nil {
+				level.Error(self.logger).Log("msg", "reading the gas expenses of the profit history", "err", err)
+				return
+			}
+			recorded = make(map[string]struct{})
+			for _, e := range events {
+				recorded[e.ID] = struct{}{}
+			}
+		}
+		if _, ok := recorded[r.Hash]; ok || self.cacheTXsGas.Has(r.Hash) {
+			continue
+		}
+
+		logger := log.With(self.logger, "addr", r.Account[:6], "tx", r.Hash)
+		receipt, err := self.client.TransactionReceipt(self.ctx, common.HexToHash(r.Hash))
+		if err != nil {
+			level.Error(logger).Log("msg", "receipt retrieval", "err", err)
+			continue
+		}
+		origin := recordOrigin(r)
+		level.Debug(logger).Log("msg", "adding gas", "amount", r.CostETH, "origin", origin)
+		e := self.record(logger, Event{
+			ID:        r.Hash,
+			Time:      self.blockTime(logger, receipt.BlockNumber),
+			Block:     receipt.BlockNumber.Uint64(),
+			BlockHash: receipt.BlockHash.String(),
+			Tx:        r.Hash,
+			Account:   common.HexToAddress(r.Account).String(),
+			Kind:      KindGas,
+			Origin:    origin,
+			Token:     "ETH",
+			Amount:    r.CostETH,
+		})
+		if err := self.cacheTXsGas.Set(r.Hash, entry{block: receipt.BlockNumber.Uint64(), hash: receipt.BlockHash, addr: common.HexToAddress(r.Account), amount: r.CostETH, usd: e.USD, kind: KindGas, origin: origin}); err != nil {
+			level.Error(logger).Log("msg", "adding gas to the cache", "err", err)
+		}
+	}
+}
+
+// gasRecorded returns whether the gas of a failed transaction is already in the profit history,
+// as the transactions sent by telliot are also recorded from the transaction history by recordTxsGas.
+// While backfilling the recorded events are added again to the metrics by record so these aren't skipped.
+func (self *ProfitTracker) gasRecorded(logger log.Logger, hash string) bool {
+	if self.recorded != nil {
+		return false
+	}
+	events, err := self.store.List(Filter{Kind: KindGas})
+	if err != nil {
+		level.Error(logger).Log("msg", "reading the gas expenses of the profit history", "err", err)
+		return false
+	}
+	for _, e := range events {
+		if e.ID == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// addGas adds a gas expense to the metrics of its origin.
+// A negative amount rolls back an expense.
+// The events recorded before the origins were added are all submits.
+func (self *ProfitTracker) addGas(origin string, addr common.Address, eth, usd float64) {
+	if origin == "" {
+		origin = OriginSubmission
+	}
+	labels := prometheus.Labels{"addr": addr.String(), "origin": origin}
+	self.gasCost.With(labels).(prometheus.Gauge).Add(eth)
+	self.gasCostUSD.With(labels).(prometheus.Gauge).Add(usd)
+	if origin == OriginSubmission {
+		self.submitCostUSD.With(prometheus.Labels{"addr": addr.String()}).(prometheus.Gauge).Add(usd)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/tellor-io/telliot/pkg/testutil"
+	"github.com/tellor-io/telliot/pkg/txs"
+)
+
+func TestRecordOrigin(t *testing.T) {
+	testutil.Equals(t, OriginSubmission, recordOrigin(txs.Record{Type: txs.TypeCancel}))
+	testutil.Equals(t, OriginStake, recordOrigin(txs.Record{Type: txs.TypeDeposit}))
+	testutil.Equals(t, OriginDispute, recordOrigin(txs.Record{Type: txs.TypeVote}))
+	testutil.Equals(t, OriginOther, recordOrigin(txs.Record{Type: txs.TypeGovVote}))
+	// The manual transactions are of the cli whatever their type.
+	testutil.Equals(t, OriginCLI, recordOrigin(txs.Record{Type: txs.TypeDeposit, CLI: true}))
+
+	testutil.Equals(t, OriginStake, methodOrigin("withdrawStake"))
+	testutil.Equals(t, OriginOther, methodOrigin("transfer"))
+}
+
+func TestGasRecorded(t *testing.T) {
+	store := OpenMemory()
+	tracker := &ProfitTracker{store: store}
+	testutil.Ok(t, store.Add(Event{ID: "0x01", Time: time.Now(), Account: "0xA", Kind: KindGas, Amount: 0.01}))
+	testutil.Ok(t, store.Add(Event{ID: "0x02", Time: time.Now(), Account: "0xA", Kind: KindReward, Amount: 1}))
+
+	testutil.Assert(t, tracker.gasRecorded(log.NewNopLogger(), "0x01"), "the gas recorded from the transaction history should be skipped")
+	testutil.Assert(t, !tracker.gasRecorded(log.NewNopLogger(), "0x02"), "only the gas expenses should match")
+	testutil.Assert(t, !tracker.gasRecorded(log.NewNopLogger(), "0x03"), "a new failed transaction should be recorded")
+
+	tracker.recorded = map[string]Event{}
+	testutil.Assert(t, !tracker.gasRecorded(log.NewNopLogger(), "0x01"), "while backfilling the recorded events are added again")
+}
@@ -0,0 +1,96 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type response struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Handler serves the realized profit of each account in TRB, ETH and USD.
+// The account query parameter selects a single account.
+// The range query parameter like 24h or 7d limits it to the latest range
+// and adds the trend from the range before it.
+func Handler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		var rng time.Duration
+		if v := r.FormValue("range"); v != "" {
+			var err error
+			if rng, err = ParseRange(v); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(response{Status: "error", Error: err.Error()})
+				return
+			}
+		}
+
+		now := time.Now()
+		filter := Filter{Account: r.FormValue("account")}
+		if rng > 0 {
+			filter.From = now.Add(-2 * rng)
+		}
+		events, err := store.List(filter)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Error: err.Error()})
+			return
+		}
+		if rng > 0 {
+			_ = json.NewEncoder(w).Encode(response{Status: "success", Data: Breakdowns(events, rng, now)})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response{Status: "success", Data: Summarize(events)})
+	}
+}
+
+// ExportHandler serves the profit events of a period filtered by the from, to and account query parameters
+// in the csv or json format selected with the format query parameter.
+// The dates are like 2021-01-31 and the whole history is exported without them.
+func ExportHandler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		format := r.FormValue("format")
+		if format == "" {
+			format = FormatCSV
+		}
+		from, to, err := ParsePeriod(r.FormValue("from"), r.FormValue("to"))
+		if err == nil && format != FormatCSV && format != FormatJSON {
+			err = errors.Errorf("invalid format:%v", format)
+		}
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Error: err.Error()})
+			return
+		}
+
+		events, err := store.List(Filter{Account: r.FormValue("account"), From: from, To: to})
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Error: err.Error()})
+			return
+		}
+		if format == FormatCSV {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", `attachment; filename="profit.csv"`)
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		// The headers are already sent so an error can't change the status.
+		_ = Export(w, format, events)
+	}
+}
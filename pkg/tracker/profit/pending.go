@@ -0,0 +1,174 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/txs"
+)
+
+// projectionSample is how many of the latest rewards and mined submits of an account
+// are averaged for the projection of its pending submits.
+const projectionSample = 10
+
+// Projection is the expected profit of the submits of an account that are sent but not mined yet.
+// It is kept apart from the realized profit and is replaced by it when the submits are mined.
+type Projection struct {
+	Account   string  `json:"account"`
+	Pending   int     `json:"pending"`
+	RewardTRB float64 `json:"rewardTrb"`
+	CostETH   float64 `json:"costEth"`
+	RewardUSD float64 `json:"rewardUsd"`
+	CostUSD   float64 `json:"costUsd"`
+	ProfitUSD float64 `json:"profitUsd"`
+}
+
+// project returns the projection of each account from its pending submits.
+// The reward of a pending submit is the average of the latest rewards of the account
+// and its cost is its gas price with the average gas used by the latest mined submits.
+// The mined submits are the most recent first and the rewards the oldest first as these are listed by the stores.
+func project(addrs []common.Address, pending, mined []txs.Record, rewards []Event, trbUSD, ethUSD float64) []Projection {
+	projections := make([]Projection, 0, len(addrs))
+	for _, addr := range addrs {
+		account := addr.String()
+
+		var gasUsed float64
+		var n int
+		for _, r := range mined {
+			if n == projectionSample {
+				break
+			}
+			if strings.EqualFold(r.Account, account) {
+				gasUsed += float64(r.GasUsed)
+				n++
+			}
+		}
+		if n > 0 {
+			gasUsed /= float64(n)
+		}
+
+		var reward float64
+		n = 0
+		for i := len(rewards) - 1; i >= 0 && n < projectionSample; i-- {
+			if strings.EqualFold(rewards[i].Account, account) {
+				reward += rewards[i].Amount
+				n++
+			}
+		}
+		if n > 0 {
+			reward /= float64(n)
+		}
+
+		p := Projection{Account: account}
+		for _, r := range pending {
+			if !strings.EqualFold(r.Account, account) {
+				continue
+			}
+			p.Pending++
+			p.RewardTRB += reward
+			p.CostETH += r.GasPrice * gasUsed / 1e9
+		}
+		p.RewardUSD = math.Round(p.RewardTRB*trbUSD*100) / 100
+		p.CostUSD = math.Round(p.CostETH*ethUSD*100) / 100
+		p.ProfitUSD = p.RewardUSD - p.CostUSD
+		projections = append(projections, p)
+	}
+	return projections
+}
+
+// monitorPending updates the projection of the pending submits
+// and records the gas of the other transactions in the transaction history when these are mined.
+func (self *ProfitTracker) monitorPending() {
+	ticker := time.NewTicker(DefaultRetry)
+	defer ticker.Stop()
+	for {
+		self.updatePending()
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *ProfitTracker) updatePending() {
+	// Mark the submits mined or dropped since the last update.
+	if err := self.txs.Resolve(self.ctx, self.client, nil); err != nil {
+		level.Error(self.logger).Log("msg", "resolving the pending submits", "err", err)
+		return
+	}
+	records, err := self.txs.List(txs.Filter{})
+	if err != nil {
+		level.Error(self.logger).Log("msg", "listing the transactions", "err", err)
+		return
+	}
+	self.recordTxsGas(records)
+
+	pending, err := self.txs.List(txs.Filter{Type: txs.TypeSubmit, Status: txs.StatusPending})
+	if err != nil {
+		level.Error(self.logger).Log("msg", "listing the pending submits", "err", err)
+		return
+	}
+	mined, err := self.txs.List(txs.Filter{Type: txs.TypeSubmit, Status: txs.StatusSuccess})
+	if err != nil {
+		level.Error(self.logger).Log("msg", "listing the mined submits", "err", err)
+		return
+	}
+	rewards, err := self.store.List(Filter{Kind: KindReward})
+	if err != nil {
+		level.Error(self.logger).Log("msg", "reading the rewards for the projection", "err", err)
+		return
+	}
+
+	var trbUSD, ethUSD float64
+	if self.prices != nil {
+		now := time.Now()
+		if trbUSD, err = self.prices("TRB/USD", now); err != nil {
+			level.Warn(self.logger).Log("msg", "no price for the projection", "symbol", "TRB/USD", "err", err)
+		}
+		if ethUSD, err = self.prices("ETH/USD", now); err != nil {
+			level.Warn(self.logger).Log("msg", "no price for the projection", "symbol", "ETH/USD", "err", err)
+		}
+	}
+
+	projections := project(self.addrs, pending, mined, rewards, trbUSD, ethUSD)
+	for _, p := range projections {
+		labels := prometheus.Labels{"addr": p.Account}
+		self.pendingSubmits.With(labels).(prometheus.Gauge).Set(float64(p.Pending))
+		self.projectedRewardUSD.With(labels).(prometheus.Gauge).Set(p.RewardUSD)
+		self.projectedCostUSD.With(labels).(prometheus.Gauge).Set(p.CostUSD)
+	}
+
+	self.projectionsMtx.Lock()
+	self.projections = projections
+	self.projectionsMtx.Unlock()
+}
+
+// PendingHandler serves the projected profit of the pending submits of each account.
+// The account query parameter selects a single account.
+func (self *ProfitTracker) PendingHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		self.projectionsMtx.Lock()
+		projections := self.projections
+		self.projectionsMtx.Unlock()
+		matched := []Projection{}
+		for _, p := range projections {
+			if account := r.FormValue("account"); account == "" || strings.EqualFold(account, p.Account) {
+				matched = append(matched, p)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(response{Status: "success", Data: matched})
+	}
+}
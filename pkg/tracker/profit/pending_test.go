@@ -0,0 +1,42 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tellor-io/telliot/pkg/testutil"
+	"github.com/tellor-io/telliot/pkg/txs"
+)
+
+func TestProject(t *testing.T) {
+	a := common.HexToAddress("0xA")
+	b := common.HexToAddress("0xB")
+
+	pending := []txs.Record{
+		{Account: a.Hex(), GasPrice: 100, Status: txs.StatusPending},
+		{Account: a.Hex(), GasPrice: 200, Status: txs.StatusPending},
+	}
+	mined := []txs.Record{
+		{Account: a.Hex(), GasUsed: 200000},
+		{Account: a.Hex(), GasUsed: 300000},
+		{Account: b.Hex(), GasUsed: 900000},
+	}
+	rewards := []Event{
+		{Account: a.Hex(), Kind: KindReward, Amount: 1},
+		{Account: a.Hex(), Kind: KindReward, Amount: 3},
+	}
+
+	projections := project([]common.Address{a, b}, pending, mined, rewards, 50, 2000)
+	testutil.Assert(t, math.Abs(projections[0].CostETH-0.075) < 1e-12, "unexpected cost:%v", projections[0].CostETH)
+	projections[0].CostETH = 0
+	testutil.Equals(t, []Projection{
+		// 2 rewards of 2 TRB and 250000 gas at 100 and 200 gwei.
+		{Account: a.Hex(), Pending: 2, RewardTRB: 4, RewardUSD: 200, CostUSD: 150, ProfitUSD: 50},
+		// The accounts without pending submits are reset to 0.
+		{Account: b.Hex()},
+	}, projections)
+}
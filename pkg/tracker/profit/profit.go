@@ -7,6 +7,7 @@ import (
 	"context"
 	"math/big"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bluele/gcache"
@@ -24,17 +25,27 @@ import (
 	"github.com/tellor-io/telliot/pkg/contracts"
 	"github.com/tellor-io/telliot/pkg/contracts/tellor"
 	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/notify"
+	"github.com/tellor-io/telliot/pkg/txs"
 )
 
 const ComponentName = "profitTracker"
 
 const DefaultRetry = 30 * time.Second
 
+// backfillProgress is how many blocks to backfill between the progress logs.
+const backfillProgress = 10000
+
 type Config struct {
-	LogLevel string
+	LogLevel   string
+	Addresses  []string `help:"Addresses tracked in addition to the accounts of the private keys, for example to watch reporters from a monitoring box without their keys."`
+	ReplayFrom uint64   `help:"Replay the rewards and costs from this block on start to rebuild the profit metrics after a downtime or a fresh install. 0 disables the replay."`
+	Ranges     []string `help:"Ranges of the per account profit series like 24h, 7d or 30d."`
+	Alerts     AlertsConfig
 }
 
 type ProfitTracker struct {
+	cfg              Config
 	netID            *big.Int
 	client           *ethclient.Client
 	logger           log.Logger
@@ -44,15 +55,51 @@ type ProfitTracker struct {
 	stop             context.CancelFunc
 	addrs            []common.Address
 	addrsMap         map[common.Address]struct{} // The same as above but used for quick matching.
+	store            *Store
+	prices           PriceFunc
+	notifier         *notify.Notifier
+	ranges           []time.Duration
+	recorded         map[string]Event // The events in the history while backfilling.
+	txs              *txs.Store
+	projections      []Projection
+	projectionsMtx   sync.Mutex
+	// alertsMtx guards the alerts config which can change on a config reload.
+	alertsMtx        sync.Mutex
+	alertsIntervalCh chan time.Duration
 
 	cacheTXsProfit     gcache.Cache
 	cacheTXsCost       gcache.Cache
 	cacheTXsCostFailed gcache.Cache
-	lastFailedBlock    int64
+	cacheTransfersOut  gcache.Cache
+	cacheDisputes      gcache.Cache
+	cacheTXsGas        gcache.Cache
+	cacheTXsMethod     gcache.Cache
 
 	submitProfit *prometheus.GaugeVec
 	submitCost   *prometheus.GaugeVec
 	balances     *prometheus.GaugeVec
+	reorgs       prometheus.Counter
+
+	submitProfitUSD *prometheus.GaugeVec
+	submitCostUSD   *prometheus.GaugeVec
+	profitUSD       *prometheus.GaugeVec
+	slashed         *prometheus.GaugeVec
+	disputeLocked   *prometheus.GaugeVec
+	gasCost         *prometheus.GaugeVec
+	gasCostUSD      *prometheus.GaugeVec
+
+	windowProfit   *prometheus.GaugeVec
+	windowGasRatio *prometheus.GaugeVec
+	alerts         *prometheus.GaugeVec
+
+	rangeReward *prometheus.GaugeVec
+	rangeGas    *prometheus.GaugeVec
+	rangeProfit *prometheus.GaugeVec
+	rangeTrend  *prometheus.GaugeVec
+
+	pendingSubmits     *prometheus.GaugeVec
+	projectedRewardUSD *prometheus.GaugeVec
+	projectedCostUSD   *prometheus.GaugeVec
 }
 
 func NewProfitTracker(
@@ -62,13 +109,30 @@ func NewProfitTracker(
 	client *ethclient.Client,
 	contractInstance *contracts.ITellor,
 	addrs []common.Address,
+	store *Store,
+	txStore *txs.Store,
+	prices PriceFunc,
+	notifier *notify.Notifier,
 ) (*ProfitTracker, error) {
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "apply filter logger")
 	}
 	logger = log.With(logger, "component", ComponentName)
 
+	if cfg.Alerts.Enabled && (cfg.Alerts.Interval.Duration <= 0 || cfg.Alerts.Window.Duration <= 0) {
+		return nil, errors.Errorf("invalid alerts interval:%v or window:%v", cfg.Alerts.Interval, cfg.Alerts.Window)
+	}
+
+	var ranges []time.Duration
+	for _, r := range cfg.Ranges {
+		d, err := ParseRange(r)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, d)
+	}
+
 	abi, err := abi.JSON(strings.NewReader(tellor.TellorABI))
 	if err != nil {
 		return nil, errors.Wrap(err, "abi read")
@@ -87,6 +151,7 @@ func NewProfitTracker(
 	ctx, cncl := context.WithCancel(ctx)
 
 	return &ProfitTracker{
+		cfg:              cfg,
 		netID:            netID,
 		client:           client,
 		logger:           logger,
@@ -94,12 +159,22 @@ func NewProfitTracker(
 		abi:              abi,
 		addrs:            addrs,
 		addrsMap:         addrsMap,
+		store:            store,
+		prices:           prices,
+		notifier:         notifier,
+		ranges:           ranges,
+		txs:              txStore,
 		ctx:              ctx,
 		stop:             cncl,
 
 		cacheTXsProfit:     gcache.New(50).LRU().Build(),
 		cacheTXsCost:       gcache.New(50).LRU().Build(),
 		cacheTXsCostFailed: gcache.New(20).LRU().Build(),
+		cacheTransfersOut:  gcache.New(20).LRU().Build(),
+		cacheDisputes:      gcache.New(20).LRU().Build(),
+		cacheTXsGas:        gcache.New(20).LRU().Build(),
+		cacheTXsMethod:     gcache.New(50).LRU().Build(),
+		alertsIntervalCh:   make(chan time.Duration, 1),
 
 		submitProfit: promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: "telliot",
@@ -125,6 +200,148 @@ func NewProfitTracker(
 		},
 			[]string{"addr", "token"},
 		),
+		reorgs: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "reorgs_total",
+			Help:      "The total number of reorgs that orphaned blocks with tracked rewards or costs",
+		}),
+		submitProfitUSD: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "submit_profit_usd",
+			Help:      "Accumulated USD value of the rewards at the TRB/USD price of their blocks for all registered addresses",
+		},
+			[]string{"addr"},
+		),
+		submitCostUSD: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "submit_cost_usd",
+			Help:      "Accumulated USD cost of the submits at the ETH/USD price of their blocks for all registered addresses",
+		},
+			[]string{"addr"},
+		),
+		profitUSD: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "profit_usd",
+			Help:      "Realized USD profit, the rewards minus the submit costs, for all registered addresses",
+		},
+			[]string{"addr"},
+		),
+		slashed: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "slashed",
+			Help:      "Accumulated TRB stake lost in disputes for all registered addresses",
+		},
+			[]string{"addr"},
+		),
+		disputeLocked: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "dispute_locked_trb",
+			Help:      "TRB locked as the fees of the open disputes begun by the registered addresses",
+		},
+			[]string{"addr"},
+		),
+		gasCost: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "gas_cost",
+			Help:      "Accumulated ETH paid for gas by origin for all registered addresses",
+		},
+			[]string{"addr", "origin"},
+		),
+		gasCostUSD: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "gas_cost_usd",
+			Help:      "Accumulated USD cost of the gas by origin for all registered addresses",
+		},
+			[]string{"addr", "origin"},
+		),
+		windowProfit: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "window_profit_trb",
+			Help:      "Profit in TRB in the rolling alert window for all registered addresses",
+		},
+			[]string{"addr"},
+		),
+		windowGasRatio: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "window_gas_ratio",
+			Help:      "Gas cost as a share of the rewards in USD in the rolling alert window for all registered addresses",
+		},
+			[]string{"addr"},
+		),
+		alerts: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "alert",
+			Help:      "1 while an alert threshold is crossed for an address",
+		},
+			[]string{"addr", "rule"},
+		),
+		rangeReward: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "range_reward_usd",
+			Help:      "USD value of the rewards in the range for all registered addresses",
+		},
+			[]string{"addr", "range"},
+		),
+		rangeGas: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "range_gas_usd",
+			Help:      "USD cost of the submits in the range for all registered addresses",
+		},
+			[]string{"addr", "range"},
+		),
+		rangeProfit: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "range_profit_usd",
+			Help:      "Net USD profit in the range for all registered addresses",
+		},
+			[]string{"addr", "range"},
+		),
+		rangeTrend: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "range_trend",
+			Help:      "Relative change of the net USD profit from the previous range, 0 without a profit in the previous range",
+		},
+			[]string{"addr", "range"},
+		),
+		pendingSubmits: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "pending_submits",
+			Help:      "Submits sent but not mined yet for all registered addresses",
+		},
+			[]string{"addr"},
+		),
+		projectedRewardUSD: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "projected_reward_usd",
+			Help:      "Projected USD value of the rewards of the pending submits for all registered addresses",
+		},
+			[]string{"addr"},
+		),
+		projectedCostUSD: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "projected_cost_usd",
+			Help:      "Projected USD cost of the pending submits for all registered addresses",
+		},
+			[]string{"addr"},
+		),
 	}, nil
 }
 
@@ -149,9 +366,22 @@ func (self *ProfitTracker) Start() error {
 		self.balances.With(prometheus.Labels{"addr": addr.String(), "token": "ETH"}).(prometheus.Gauge).Set(balance)
 	}
 
+	for _, addr := range self.addrs {
+		self.setDisputeLocked(self.logger, addr)
+	}
+
 	go self.monitorCost()
 	go self.monitorReward()
 	go self.monitorCostFailed()
+	if self.cfg.Alerts.Enabled {
+		go self.monitorAlerts()
+	}
+	if len(self.ranges) > 0 {
+		go self.monitorRanges()
+	}
+	if self.txs != nil {
+		go self.monitorPending()
+	}
 
 	<-self.ctx.Done()
 	return nil
@@ -184,6 +414,15 @@ func (self *ProfitTracker) monitorReward() {
 		}
 		break
 	}
+	lastBlock := self.head(logger)
+	if self.cfg.ReplayFrom > 0 {
+		level.Info(logger).Log("msg", "replaying events", "from", self.cfg.ReplayFrom)
+		if head, err := self.backfillTransfers(logger, self.cfg.ReplayFrom); err != nil {
+			level.Error(logger).Log("msg", "replaying events", "err", err)
+		} else {
+			lastBlock = head
+		}
+	}
 
 	for {
 		select {
@@ -213,23 +452,60 @@ func (self *ProfitTracker) monitorReward() {
 				break
 			}
 			level.Info(logger).Log("msg", "re-subscribed to events")
+			if head, err := self.backfillTransfers(logger, lastBlock); err != nil {
+				level.Error(logger).Log("msg", "backfilling the events missed while disconnected", "err", err)
+			} else {
+				lastBlock = head
+			}
 		case event := <-events:
-			logger := log.With(logger, "addr", event.To.String()[:6], "tx", event.Raw.TxHash)
-
-			if event.Raw.Removed {
-				val, err := self.cacheTXsProfit.Get(txIDTransfer(event))
-				if err != nil {
-					level.Error(logger).Log("msg", "getting cache amount for removed event", "err", err)
-					continue
-				}
-				level.Debug(logger).Log("msg", "removing cost from dropped event", "amount", val.(float64))
-				self.submitProfit.With(prometheus.Labels{"addr": event.To.String()}).(prometheus.Gauge).Sub(val.(float64))
-				continue
+			if event.Raw.BlockNumber > lastBlock {
+				lastBlock = event.Raw.BlockNumber
 			}
+			self.handleTransfer(logger, event)
+		}
+	}
+}
+
+func (self *ProfitTracker) handleTransfer(logger log.Logger, event *tellor.TellorTransferred) {
+	logger = log.With(logger, "addr", event.To.String()[:6], "tx", event.Raw.TxHash)
+
+	if event.Raw.Removed {
+		self.remove(logger, self.cacheTXsProfit, self.submitProfit, txIDTransfer(event))
+		return
+	}
+
+	self.setProfitWhenConfirmed(logger, event)
+}
 
-			self.setProfitWhenConfirmed(logger, event)
+// backfillTransfers processes the transfer events emitted since the given block
+// which were missed while the subscription was down or are replayed and returns the current head.
+// The events that were already processed are in the cache so these are skipped.
+func (self *ProfitTracker) backfillTransfers(logger log.Logger, from uint64) (uint64, error) {
+	head, err := self.client.BlockNumber(self.ctx)
+	if err != nil || from == 0 {
+		return head, errors.Wrap(err, "getting the block number")
+	}
+	tellorFilterer, err := tellor.NewTellorFilterer(self.contractInstance.Address, self.client)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting instance")
+	}
+	iter, err := tellorFilterer.FilterTransferred(
+		&bind.FilterOpts{Context: self.ctx, Start: from, End: &head},
+		[]common.Address{common.HexToAddress("0x0000000000000000000000000000000000000000")},
+		self.addrs,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting the past events")
+	}
+	defer iter.Close()
+	for iter.Next() {
+		if iter.Event.Raw.Removed || self.cacheTXsProfit.Has(txIDTransfer(iter.Event)) {
+			continue
 		}
+		level.Info(logger).Log("msg", "backfilled an event", "block", iter.Event.Raw.BlockNumber, "tx", iter.Event.Raw.TxHash)
+		self.setProfit(log.With(logger, "addr", iter.Event.To.String()[:6], "tx", iter.Event.Raw.TxHash), iter.Event)
 	}
+	return head, errors.Wrap(iter.Error(), "reading the past events")
 }
 
 func (self *ProfitTracker) monitorCost() {
@@ -256,6 +532,15 @@ func (self *ProfitTracker) monitorCost() {
 		}
 		break
 	}
+	lastBlock := self.head(logger)
+	if self.cfg.ReplayFrom > 0 {
+		level.Info(logger).Log("msg", "replaying events", "from", self.cfg.ReplayFrom)
+		if head, err := self.backfillNonceSubmitted(logger, self.cfg.ReplayFrom); err != nil {
+			level.Error(logger).Log("msg", "replaying events", "err", err)
+		} else {
+			lastBlock = head
+		}
+	}
 
 	for {
 		select {
@@ -285,23 +570,56 @@ func (self *ProfitTracker) monitorCost() {
 				break
 			}
 			level.Info(logger).Log("msg", "re-subscribed to events")
+			if head, err := self.backfillNonceSubmitted(logger, lastBlock); err != nil {
+				level.Error(logger).Log("msg", "backfilling the events missed while disconnected", "err", err)
+			} else {
+				lastBlock = head
+			}
 		case event := <-events:
-			logger := log.With(logger, "addr", event.Miner.String()[:6], "tx", event.Raw.TxHash)
-
-			if event.Raw.Removed {
-				val, err := self.cacheTXsCost.Get(txIDNonceSubmit(event))
-				if err != nil {
-					level.Error(logger).Log("msg", "getting cache amount for removed event", "err", err)
-					continue
-				}
-				level.Debug(logger).Log("msg", "removed event", "amount", val.(float64))
-				self.submitCost.With(prometheus.Labels{"addr": event.Miner.String()}).(prometheus.Gauge).Sub(val.(float64))
-				continue
+			if event.Raw.BlockNumber > lastBlock {
+				lastBlock = event.Raw.BlockNumber
 			}
+			self.handleNonceSubmitted(logger, event)
+		}
+	}
+}
+
+func (self *ProfitTracker) handleNonceSubmitted(logger log.Logger, event *tellor.TellorNonceSubmitted) {
+	logger = log.With(logger, "addr", event.Miner.String()[:6], "tx", event.Raw.TxHash)
 
-			self.setCostWhenConfirmed(logger, event)
+	if event.Raw.Removed {
+		self.remove(logger, self.cacheTXsCost, self.submitCost, txIDNonceSubmit(event))
+		return
+	}
+
+	self.setCostWhenConfirmed(logger, event)
+}
+
+// backfillNonceSubmitted processes the submit events emitted since the given block
+// which were missed while the subscription was down or are replayed and returns the current head.
+// The events that were already processed are in the cache so these are skipped.
+func (self *ProfitTracker) backfillNonceSubmitted(logger log.Logger, from uint64) (uint64, error) {
+	head, err := self.client.BlockNumber(self.ctx)
+	if err != nil || from == 0 {
+		return head, errors.Wrap(err, "getting the block number")
+	}
+	tellorFilterer, err := tellor.NewTellorFilterer(self.contractInstance.Address, self.client)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting instance")
+	}
+	iter, err := tellorFilterer.FilterNonceSubmitted(&bind.FilterOpts{Context: self.ctx, Start: from, End: &head}, self.addrs, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting the past events")
+	}
+	defer iter.Close()
+	for iter.Next() {
+		if iter.Event.Raw.Removed || self.cacheTXsCost.Has(txIDNonceSubmit(iter.Event)) {
+			continue
 		}
+		level.Info(logger).Log("msg", "backfilled an event", "block", iter.Event.Raw.BlockNumber, "tx", iter.Event.Raw.TxHash)
+		self.setCost(log.With(logger, "addr", iter.Event.Miner.String()[:6], "tx", iter.Event.Raw.TxHash), iter.Event)
 	}
+	return head, errors.Wrap(iter.Error(), "reading the past events")
 }
 
 func (self *ProfitTracker) monitorCostFailed() {
@@ -329,6 +647,16 @@ func (self *ProfitTracker) monitorCostFailed() {
 		}
 		break
 	}
+	lastHead := self.head(logger)
+	var lastHash common.Hash
+	if self.cfg.ReplayFrom > 0 {
+		level.Info(logger).Log("msg", "replaying blocks", "from", self.cfg.ReplayFrom)
+		if head, err := self.backfillHeads(logger, self.cfg.ReplayFrom-1); err != nil {
+			level.Error(logger).Log("msg", "replaying blocks", "err", err)
+		} else {
+			lastHead = head
+		}
+	}
 
 	for {
 		select {
@@ -358,71 +686,142 @@ func (self *ProfitTracker) monitorCostFailed() {
 				break
 			}
 			level.Info(logger).Log("msg", "re-subscribed to events")
+			// The events removed by a reorg while disconnected are not delivered.
+			self.rollbackOrphaned(logger)
+			if head, err := self.backfillHeads(logger, lastHead); err != nil {
+				level.Error(logger).Log("msg", "backfilling the blocks missed while disconnected", "err", err)
+			} else {
+				lastHead = head
+			}
 		case event := <-events:
-			if event.Bloom.Test(self.abi.Events["NonceSubmitted"].ID.Bytes()) {
-				logger := log.With(logger, "block", event.Number)
+			if lastHash != (common.Hash{}) && event.ParentHash != lastHash {
+				level.Info(logger).Log("msg", "reorg head", "block", event.Number)
+				self.rollbackOrphaned(logger)
+			}
+			lastHash = event.Hash()
+			if event.Number.Uint64() > lastHead {
+				lastHead = event.Number.Uint64()
+			}
+			self.handleHead(logger, event)
+		}
+	}
+}
 
-				block, err := self.client.BlockByNumber(self.ctx, event.Number)
-				if err != nil {
-					level.Error(logger).Log("msg", "get block by hash", "err", err)
-					continue
-				}
+// handleHead tracks the cost of the failed submits, the outgoing transfers and the dispute payouts in the block.
+func (self *ProfitTracker) handleHead(logger log.Logger, event *types.Header) {
+	self.handleTransfersOut(logger, event)
+	self.handleDisputes(logger, event)
 
-				level.Debug(logger).Log("msg", "new block")
+	if event.Bloom.Test(self.abi.Events["NonceSubmitted"].ID.Bytes()) {
+		logger := log.With(logger, "block", event.Number)
 
-				for _, tx := range block.Transactions() {
-					logger := log.With(logger, "tx", tx.Hash())
-					level.Debug(logger).Log("msg", "processing TX")
+		block, err := self.client.BlockByNumber(self.ctx, event.Number)
+		if err != nil {
+			level.Error(logger).Log("msg", "get block by hash", "err", err)
+			return
+		}
 
-					addr, err := types.Sender(types.LatestSignerForChainID(self.netID), tx)
-					if err != nil {
-						level.Error(logger).Log("msg", "get tx sender", "err", err)
-						continue
-					}
-					if _, ok := self.addrsMap[addr]; !ok {
-						level.Debug(logger).Log("msg", "skipping TX for unregistered address", "addr", addr)
-						continue
-					}
-					receipt, err := self.client.TransactionReceipt(self.ctx, tx.Hash())
-					if err != nil {
-						level.Error(logger).Log("msg", "receipt retrieval", "err", err)
-						continue
-					} else if receipt != nil && receipt.Status != types.ReceiptStatusSuccessful { // Track only the failed TXs. All other TXs are tracked from the emitted logs.
-						// When it is a reorg event, remove the cost for the dropped blocks.
-						if self.lastFailedBlock >= event.Number.Int64() {
-							level.Debug(logger).Log("msg", "reorg head")
-							for cachedBlock, _cost := range self.cacheTXsCostFailed.GetALL(false) {
-								if cachedBlock.(int64) >= event.Number.Int64() {
-									cost := _cost.(float64)
-									level.Debug(logger).Log("msg", "removing cost from dropped block", "amount", cost)
-									self.submitCost.With(prometheus.Labels{"addr": addr.String()}).(prometheus.Gauge).Sub(cost)
-								}
-							}
-						}
-						self.lastFailedBlock = event.Number.Int64()
-						cost, _ := big.NewFloat(0).Mul(big.NewFloat(float64(tx.GasPrice().Int64())), big.NewFloat(float64(receipt.GasUsed))).Float64()
-						cost = cost / 1e18
-						level.Debug(logger).Log("msg", "adding cost", "amount", cost)
-						self.submitCost.With(prometheus.Labels{"addr": addr.String()}).(prometheus.Gauge).Add(cost)
-
-						if err := self.cacheTXsCostFailed.Set(event.Number.Int64(), cost); err != nil {
-							level.Error(logger).Log("msg", "adding cost to the cache", "err", err)
-						}
-
-						balance, err := self.getETHBalance(addr)
-						if err != nil {
-							level.Error(logger).Log("msg", "getting ETH balance", "err", err)
-							continue
-						}
-						level.Debug(logger).Log("msg", "new ETH balance", "balance", balance)
-						self.balances.With(prometheus.Labels{"addr": addr.String(), "token": "ETH"}).(prometheus.Gauge).Set(balance)
+		level.Debug(logger).Log("msg", "new block")
+
+		for _, tx := range block.Transactions() {
+			logger := log.With(logger, "tx", tx.Hash())
+			level.Debug(logger).Log("msg", "processing TX")
+
+			if self.cacheTXsCostFailed.Has(tx.Hash().String()) || self.cacheTXsGas.Has(tx.Hash().String()) {
+				continue
+			}
+
+			addr, err := types.Sender(types.LatestSignerForChainID(self.netID), tx)
+			if err != nil {
+				level.Error(logger).Log("msg", "get tx sender", "err", err)
+				continue
+			}
+			if _, ok := self.addrsMap[addr]; !ok {
+				level.Debug(logger).Log("msg", "skipping TX for unregistered address", "addr", addr)
+				continue
+			}
+			receipt, err := self.client.TransactionReceipt(self.ctx, tx.Hash())
+			if err != nil {
+				level.Error(logger).Log("msg", "receipt retrieval", "err", err)
+				continue
+			} else if receipt != nil && receipt.Status != types.ReceiptStatusSuccessful { // Track only the failed TXs. All other TXs are tracked from the emitted logs.
+				if self.gasRecorded(logger, tx.Hash().String()) {
+					level.Debug(logger).Log("msg", "skipping the cost recorded from the transaction history")
+					continue
+				}
+				cost, _ := big.NewFloat(0).Mul(big.NewFloat(float64(tx.GasPrice().Int64())), big.NewFloat(float64(receipt.GasUsed))).Float64()
+				cost = cost / 1e18
+				origin := OriginOther
+				if len(tx.Data()) >= 4 {
+					if method, err := self.abi.MethodById(tx.Data()[:4]); err == nil {
+						origin = methodOrigin(method.Name)
 					}
 				}
+				level.Debug(logger).Log("msg", "adding cost", "amount", cost, "origin", origin)
+				if origin == OriginSubmission {
+					self.submitCost.With(prometheus.Labels{"addr": addr.String()}).(prometheus.Gauge).Add(cost)
+				}
+				e := self.record(logger, Event{
+					ID:        tx.Hash().String(),
+					Time:      time.Unix(int64(event.Time), 0),
+					Block:     event.Number.Uint64(),
+					BlockHash: event.Hash().String(),
+					Tx:        tx.Hash().String(),
+					Account:   addr.String(),
+					Kind:      KindGas,
+					Origin:    origin,
+					Token:     "ETH",
+					Amount:    cost,
+				})
+
+				if err := self.cacheTXsCostFailed.Set(tx.Hash().String(), entry{block: event.Number.Uint64(), hash: event.Hash(), addr: addr, amount: cost, usd: e.USD, kind: KindGas, origin: origin}); err != nil {
+					level.Error(logger).Log("msg", "adding cost to the cache", "err", err)
+				}
+
+				balance, err := self.getETHBalance(addr)
+				if err != nil {
+					level.Error(logger).Log("msg", "getting ETH balance", "err", err)
+					continue
+				}
+				level.Debug(logger).Log("msg", "new ETH balance", "balance", balance)
+				self.balances.With(prometheus.Labels{"addr": addr.String(), "token": "ETH"}).(prometheus.Gauge).Set(balance)
 			}
 		}
 	}
 }
 
+// backfillHeads processes the blocks after the given one
+// which were missed while the subscription was down and returns the current head.
+func (self *ProfitTracker) backfillHeads(logger log.Logger, from uint64) (uint64, error) {
+	head, err := self.client.BlockNumber(self.ctx)
+	if err != nil || from == 0 {
+		return head, errors.Wrap(err, "getting the block number")
+	}
+	for n := from + 1; n <= head; n++ {
+		header, err := self.client.HeaderByNumber(self.ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return n - 1, errors.Wrapf(err, "getting header:%v", n)
+		}
+		self.handleHead(logger, header)
+		if (n-from)%backfillProgress == 0 {
+			level.Info(logger).Log("msg", "backfilling blocks", "at", n, "to", head)
+		}
+	}
+	if head > from {
+		level.Info(logger).Log("msg", "backfilled blocks", "from", from+1, "to", head)
+	}
+	return head, nil
+}
+
+// head returns the current block number from which to backfill after a reconnect.
+func (self *ProfitTracker) head(logger log.Logger) uint64 {
+	head, err := self.client.BlockNumber(self.ctx)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting the block number, no backfill after a reconnect", "err", err)
+	}
+	return head
+}
+
 func (self *ProfitTracker) setCostWhenConfirmed(logger log.Logger, event *tellor.TellorNonceSubmitted) {
 	ticker := time.NewTicker(DefaultRetry)
 	defer ticker.Stop()
@@ -433,38 +832,65 @@ func (self *ProfitTracker) setCostWhenConfirmed(logger log.Logger, event *tellor
 			return
 		case <-ticker.C:
 		}
-		receipt, err := self.client.TransactionReceipt(self.ctx, event.Raw.TxHash)
-		if err != nil {
-			level.Error(logger).Log("msg", "receipt retrieval", "err", err)
-		} else if receipt != nil && receipt.Status == types.ReceiptStatusSuccessful { // Failed transactions cost is monitored in a different process.
-			tx, _, err := self.client.TransactionByHash(self.ctx, event.Raw.TxHash)
-			if err != nil {
-				level.Error(logger).Log("msg", "get transaction by hash", "err", err)
-				return
-			}
-			cost, _ := big.NewFloat(0).Mul(big.NewFloat(float64(tx.GasPrice().Int64())), big.NewFloat(float64(receipt.GasUsed))).Float64()
-			cost = cost / 1e18
-			level.Debug(logger).Log("msg", "adding cost", "amount", cost)
-			self.submitCost.With(prometheus.Labels{"addr": event.Miner.String()}).(prometheus.Gauge).Add(cost)
-
-			if err := self.cacheTXsCost.Set(txIDNonceSubmit(event), cost); err != nil {
-				level.Error(logger).Log("msg", "adding cost to the cache", "err", err)
-			}
-
-			balance, err := self.getETHBalance(event.Miner)
-			if err != nil {
-				level.Error(logger).Log("msg", "getting ETH balance", "err", err)
-				return
-			}
-			level.Debug(logger).Log("msg", "new ETH balance", "balance", balance)
-			self.balances.With(prometheus.Labels{"addr": event.Miner.String(), "token": "ETH"}).(prometheus.Gauge).Set(balance)
+		if self.setCost(logger, event) {
 			return
 		}
-
 		level.Debug(logger).Log("msg", "transaction not yet mined")
 	}
 }
 
+// setCost adds the cost of a mined submit and returns false when the transaction is not mined yet.
+func (self *ProfitTracker) setCost(logger log.Logger, event *tellor.TellorNonceSubmitted) bool {
+	if self.cacheTXsCost.Has(txIDNonceSubmit(event)) {
+		return true
+	}
+	receipt, err := self.client.TransactionReceipt(self.ctx, event.Raw.TxHash)
+	if err != nil {
+		level.Error(logger).Log("msg", "receipt retrieval", "err", err)
+		return false
+	}
+	if receipt == nil {
+		return false
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful { // Failed transactions cost is monitored in a different process.
+		return true
+	}
+	tx, _, err := self.client.TransactionByHash(self.ctx, event.Raw.TxHash)
+	if err != nil {
+		level.Error(logger).Log("msg", "get transaction by hash", "err", err)
+		return true
+	}
+	cost, _ := big.NewFloat(0).Mul(big.NewFloat(float64(tx.GasPrice().Int64())), big.NewFloat(float64(receipt.GasUsed))).Float64()
+	cost = cost / 1e18
+	level.Debug(logger).Log("msg", "adding cost", "amount", cost)
+	self.submitCost.With(prometheus.Labels{"addr": event.Miner.String()}).(prometheus.Gauge).Add(cost)
+	e := self.record(logger, Event{
+		ID:        txIDNonceSubmit(event),
+		Time:      self.blockTime(logger, receipt.BlockNumber),
+		Block:     receipt.BlockNumber.Uint64(),
+		BlockHash: receipt.BlockHash.String(),
+		Tx:        event.Raw.TxHash.String(),
+		Account:   event.Miner.String(),
+		Kind:      KindGas,
+		Origin:    OriginSubmission,
+		Token:     "ETH",
+		Amount:    cost,
+	})
+
+	if err := self.cacheTXsCost.Set(txIDNonceSubmit(event), entry{block: receipt.BlockNumber.Uint64(), hash: receipt.BlockHash, addr: event.Miner, amount: cost, usd: e.USD, kind: KindGas, origin: OriginSubmission}); err != nil {
+		level.Error(logger).Log("msg", "adding cost to the cache", "err", err)
+	}
+
+	balance, err := self.getETHBalance(event.Miner)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting ETH balance", "err", err)
+		return true
+	}
+	level.Debug(logger).Log("msg", "new ETH balance", "balance", balance)
+	self.balances.With(prometheus.Labels{"addr": event.Miner.String(), "token": "ETH"}).(prometheus.Gauge).Set(balance)
+	return true
+}
+
 func (self *ProfitTracker) setProfitWhenConfirmed(logger log.Logger, event *tellor.TellorTransferred) {
 	ticker := time.NewTicker(DefaultRetry)
 	defer ticker.Stop()
@@ -475,36 +901,59 @@ func (self *ProfitTracker) setProfitWhenConfirmed(logger log.Logger, event *tell
 			return
 		case <-ticker.C:
 		}
-		receipt, err := self.client.TransactionReceipt(self.ctx, event.Raw.TxHash)
-		if err != nil {
-			level.Error(logger).Log("msg", "receipt retrieval", "err", err)
+		if self.setProfit(logger, event) {
 			return
-		} else if receipt != nil {
-			if receipt.Status != types.ReceiptStatusSuccessful {
-				level.Error(logger).Log("msg", "event status not success so no profit added", "status", receipt.Status)
-				return
-			}
+		}
+		level.Debug(logger).Log("msg", "transaction not yet mined")
+	}
+}
 
-			trb, _ := big.NewFloat(float64(event.Value.Int64())).Float64()
-			trb = trb / 1e18
-			level.Debug(logger).Log("msg", "adding profit", "amount", trb)
-			self.submitProfit.With(prometheus.Labels{"addr": event.To.String()}).(prometheus.Gauge).Add(trb)
+// setProfit adds the reward of a mined transfer and returns false when the transaction is not mined yet.
+func (self *ProfitTracker) setProfit(logger log.Logger, event *tellor.TellorTransferred) bool {
+	if self.cacheTXsProfit.Has(txIDTransfer(event)) {
+		return true
+	}
+	receipt, err := self.client.TransactionReceipt(self.ctx, event.Raw.TxHash)
+	if err != nil {
+		level.Error(logger).Log("msg", "receipt retrieval", "err", err)
+		return true
+	}
+	if receipt == nil {
+		return false
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		level.Error(logger).Log("msg", "event status not success so no profit added", "status", receipt.Status)
+		return true
+	}
 
-			if err := self.cacheTXsProfit.Set(txIDTransfer(event), trb); err != nil {
-				level.Error(logger).Log("msg", "adding amount to the cache", "err", err)
-			}
+	trb, _ := big.NewFloat(float64(event.Value.Int64())).Float64()
+	trb = trb / 1e18
+	level.Debug(logger).Log("msg", "adding profit", "amount", trb)
+	self.submitProfit.With(prometheus.Labels{"addr": event.To.String()}).(prometheus.Gauge).Add(trb)
+	e := self.record(logger, Event{
+		ID:        txIDTransfer(event),
+		Time:      self.blockTime(logger, receipt.BlockNumber),
+		Block:     receipt.BlockNumber.Uint64(),
+		BlockHash: receipt.BlockHash.String(),
+		Tx:        event.Raw.TxHash.String(),
+		Account:   event.To.String(),
+		Kind:      KindReward,
+		Token:     "TRB",
+		Amount:    trb,
+	})
+
+	if err := self.cacheTXsProfit.Set(txIDTransfer(event), entry{block: receipt.BlockNumber.Uint64(), hash: receipt.BlockHash, addr: event.To, amount: trb, usd: e.USD, kind: KindReward}); err != nil {
+		level.Error(logger).Log("msg", "adding amount to the cache", "err", err)
+	}
 
-			balance, err := self.getTRBBalance(event.To)
-			if err != nil {
-				level.Error(logger).Log("msg", "getting TRB balance", "err", err)
-				return
-			}
-			level.Debug(logger).Log("msg", "new TRB balance", "balance", balance)
-			self.balances.With(prometheus.Labels{"addr": event.To.String(), "token": "TRB"}).(prometheus.Gauge).Set(balance)
-			return
-		}
-		level.Debug(logger).Log("msg", "transaction not yet mined")
+	balance, err := self.getTRBBalance(event.To)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting TRB balance", "err", err)
+		return true
 	}
+	level.Debug(logger).Log("msg", "new TRB balance", "balance", balance)
+	self.balances.With(prometheus.Labels{"addr": event.To.String(), "token": "TRB"}).(prometheus.Gauge).Set(balance)
+	return true
 }
 
 func (self *ProfitTracker) nonceSubmittedSub(output chan *tellor.TellorNonceSubmitted) (event.Subscription, error) {
@@ -0,0 +1,117 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"math/big"
+
+	"github.com/bluele/gcache"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// entry is an amount added to the metrics.
+// The block is kept to roll it back when the block is reorged out of the canonical chain.
+type entry struct {
+	block  uint64
+	hash   common.Hash
+	addr   common.Address
+	amount float64
+	usd    float64
+	kind   Kind
+	origin string // The origin of a gas expense.
+}
+
+// remove rolls back the amount of a cached entry.
+// The entry is removed from the cache first so that an amount
+// is not rolled back twice when the removed event and the reorg check race.
+func (self *ProfitTracker) remove(logger log.Logger, cache gcache.Cache, gauge *prometheus.GaugeVec, id interface{}) bool {
+	val, err := cache.Get(id)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting cache amount for removed event", "err", err)
+		return false
+	}
+	if !cache.Remove(id) {
+		return false
+	}
+	e := val.(entry)
+	level.Debug(logger).Log("msg", "removing amount from dropped event", "block", e.block, "addr", e.addr, "amount", e.amount)
+	if gauge != nil {
+		gauge.With(prometheus.Labels{"addr": e.addr.String()}).(prometheus.Gauge).Sub(e.amount)
+	}
+	if e.kind == KindSlash {
+		self.slashed.With(prometheus.Labels{"addr": e.addr.String()}).(prometheus.Gauge).Sub(e.amount)
+	}
+	self.addUSD(e.kind, e.addr, -e.usd)
+	if e.kind == KindGas {
+		self.addGas(e.origin, e.addr, -e.amount, -e.usd)
+	}
+	if err := self.store.Remove(id.(string)); err != nil {
+		level.Error(logger).Log("msg", "removing the event from the profit history", "err", err)
+	}
+	switch e.kind {
+	case KindDisputeFee, KindDisputeRefund, KindDisputeLoss:
+		self.setDisputeLocked(logger, e.addr)
+	}
+	return true
+}
+
+// rollbackOrphaned removes the amounts added from the blocks that are no longer in the canonical chain
+// and then processes the canonical chain again from the fork.
+func (self *ProfitTracker) rollbackOrphaned(logger log.Logger) {
+	canonical := make(map[uint64]common.Hash)
+	var fork uint64
+	for _, tracked := range []struct {
+		cache gcache.Cache
+		gauge *prometheus.GaugeVec
+	}{
+		{self.cacheTXsProfit, self.submitProfit},
+		{self.cacheTXsCost, self.submitCost},
+		{self.cacheTXsCostFailed, self.submitCost},
+		{self.cacheTransfersOut, nil},
+		{self.cacheDisputes, nil},
+		{self.cacheTXsGas, nil},
+	} {
+		for id, val := range tracked.cache.GetALL(false) {
+			e := val.(entry)
+			hash, ok := canonical[e.block]
+			if !ok {
+				header, err := self.client.HeaderByNumber(self.ctx, new(big.Int).SetUint64(e.block))
+				// After a reorg to a shorter chain the block doesn't exist anymore.
+				if err != nil && err != ethereum.NotFound {
+					level.Error(logger).Log("msg", "getting header", "block", e.block, "err", err)
+					continue
+				}
+				if header != nil {
+					hash = header.Hash()
+				}
+				canonical[e.block] = hash
+			}
+			if hash == e.hash {
+				continue
+			}
+			if self.remove(logger, tracked.cache, tracked.gauge, id) && (fork == 0 || e.block < fork) {
+				fork = e.block
+			}
+		}
+	}
+	if fork == 0 {
+		return
+	}
+
+	self.reorgs.Inc()
+	level.Info(logger).Log("msg", "rolled back the amounts from orphaned blocks, processing the canonical chain", "from", fork)
+	if _, err := self.backfillTransfers(logger, fork); err != nil {
+		level.Error(logger).Log("msg", "processing the canonical transfers", "err", err)
+	}
+	if _, err := self.backfillNonceSubmitted(logger, fork); err != nil {
+		level.Error(logger).Log("msg", "processing the canonical submits", "err", err)
+	}
+	if _, err := self.backfillHeads(logger, fork-1); err != nil {
+		level.Error(logger).Log("msg", "processing the canonical blocks", "err", err)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The cost basis methods of the tax report.
+const (
+	MethodFIFO = "fifo"
+	MethodLIFO = "lifo"
+)
+
+// Report is the yearly tax report of the tracked accounts.
+type Report struct {
+	Year     int             `json:"year"`
+	Method   string          `json:"method"`
+	Accounts []AccountReport `json:"accounts"`
+}
+
+// AccountReport is the tax report of a single account.
+// The USD values are 0 for the events without a price in the profit history.
+type AccountReport struct {
+	Account string `json:"account"`
+	// Income are the rewards at their fair market value when received.
+	Income    []Event `json:"income"`
+	IncomeUSD float64 `json:"incomeUsd"`
+	// Expenses are the gas costs of the submits.
+	Expenses   []Event    `json:"expenses"`
+	ExpenseUSD float64    `json:"expenseUsd"`
+	Disposals  []Disposal `json:"disposals"`
+	GainUSD    float64    `json:"gainUsd"`
+}
+
+// Disposal is TRB sent from the account with the cost basis of the rewards it is matched with.
+type Disposal struct {
+	Time         time.Time `json:"time"`
+	Tx           string    `json:"tx"`
+	Amount       float64   `json:"amount"`
+	ProceedsUSD  float64   `json:"proceedsUsd"`
+	CostBasisUSD float64   `json:"costBasisUsd"`
+	GainUSD      float64   `json:"gainUsd"`
+	// Cause is set for the stake lost in a dispute which has no proceeds.
+	Cause string `json:"cause,omitempty"`
+	// Unmatched is the amount not covered by the recorded rewards, for example
+	// TRB bought or received before the history started. Its cost basis is 0.
+	Unmatched float64 `json:"unmatched"`
+}
+
+// dust is the amount left from the float rounding when a lot is used up.
+const dust = 1e-12
+
+// lot is TRB received with a reward which wasn't disposed yet.
+type lot struct {
+	amount float64
+	usd    float64 // Per TRB.
+}
+
+// NewReport creates the tax report of the given year in UTC from the profit history.
+// The events before the year are needed to match the disposals with the rewards they dispose.
+func NewReport(events []Event, year int, method string) (Report, error) {
+	if method != MethodFIFO && method != MethodLIFO {
+		return Report{}, errors.Errorf("invalid cost basis method:%v, supported methods are %v and %v", method, MethodFIFO, MethodLIFO)
+	}
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	events = append([]Event(nil), events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+
+	byAccount := make(map[string]*AccountReport)
+	lots := make(map[string][]lot)
+	for _, e := range events {
+		if !e.Time.Before(end) {
+			break
+		}
+		account := strings.ToLower(e.Account)
+		inYear := !e.Time.Before(start)
+		r, ok := byAccount[account]
+		if !ok {
+			r = &AccountReport{Account: e.Account, Income: []Event{}, Expenses: []Event{}, Disposals: []Disposal{}}
+			byAccount[account] = r
+		}
+		switch e.Kind {
+		case KindReward, KindDisputeWin:
+			lots[account] = append(lots[account], lot{amount: e.Amount, usd: e.TRBUSD})
+			if inYear {
+				r.Income = append(r.Income, e)
+				r.IncomeUSD += e.USD
+			}
+		case KindGas:
+			if inYear {
+				r.Expenses = append(r.Expenses, e)
+				r.ExpenseUSD += e.USD
+			}
+		case KindTransfer, KindSlash, KindDisputeLoss:
+			d := Disposal{Time: e.Time, Tx: e.Tx, Amount: e.Amount, ProceedsUSD: e.USD}
+			if e.Kind != KindTransfer {
+				d.ProceedsUSD = 0
+				d.Cause = e.Cause
+			}
+			lots[account], d.CostBasisUSD, d.Unmatched = dispose(lots[account], e.Amount, method)
+			d.CostBasisUSD = round(d.CostBasisUSD)
+			d.GainUSD = round(d.ProceedsUSD - d.CostBasisUSD)
+			if inYear {
+				r.Disposals = append(r.Disposals, d)
+				r.GainUSD += d.GainUSD
+			}
+		}
+	}
+
+	report := Report{Year: year, Method: method, Accounts: []AccountReport{}}
+	for _, r := range byAccount {
+		if len(r.Income) == 0 && len(r.Expenses) == 0 && len(r.Disposals) == 0 {
+			continue
+		}
+		r.IncomeUSD = round(r.IncomeUSD)
+		r.ExpenseUSD = round(r.ExpenseUSD)
+		r.GainUSD = round(r.GainUSD)
+		report.Accounts = append(report.Accounts, *r)
+	}
+	sort.Slice(report.Accounts, func(i, j int) bool { return report.Accounts[i].Account < report.Accounts[j].Account })
+	return report, nil
+}
+
+// dispose removes the amount from the lots in the order of the method
+// and returns the remaining lots, the cost basis of the removed amount
+// and the amount that wasn't covered by the lots.
+func dispose(lots []lot, amount float64, method string) ([]lot, float64, float64) {
+	var basis float64
+	for amount > dust && len(lots) > 0 {
+		i := 0
+		if method == MethodLIFO {
+			i = len(lots) - 1
+		}
+		used := lots[i].amount
+		if used > amount {
+			used = amount
+		}
+		basis += used * lots[i].usd
+		amount -= used
+		lots[i].amount -= used
+		if lots[i].amount <= dust {
+			lots = append(lots[:i], lots[i+1:]...)
+		}
+	}
+	if amount <= dust {
+		amount = 0
+	}
+	return lots, basis, amount
+}
+
+func round(usd float64) float64 {
+	return math.Round(usd*100) / 100
+}
@@ -0,0 +1,54 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestReport(t *testing.T) {
+	at := func(month, day int) time.Time { return time.Date(2021, time.Month(month), day, 0, 0, 0, 0, time.UTC) }
+	events := []Event{
+		{Time: at(1, 1).AddDate(0, 0, -10), Account: "0xA", Kind: KindReward, Amount: 2, TRBUSD: 10, USD: 20},
+		{Time: at(2, 1), Account: "0xA", Kind: KindReward, Amount: 2, TRBUSD: 30, USD: 60},
+		{Time: at(2, 1), Account: "0xA", Kind: KindGas, Amount: 0.01, ETHUSD: 1000, USD: 10},
+		{Time: at(3, 1), Account: "0xA", Kind: KindTransfer, Amount: 3, TRBUSD: 50, USD: 150},
+		{Time: at(4, 1), Account: "0xA", Kind: KindTransfer, Amount: 2, TRBUSD: 50, USD: 100},
+		{Time: at(5, 1), Account: "0xA", Kind: KindReward, Amount: 1, TRBUSD: 40, USD: 40},
+		{Time: at(6, 1), Account: "0xA", Kind: KindSlash, Amount: 1, TRBUSD: 50, USD: 50, Cause: "lost dispute 1"},
+		// Only the events up to the end of the year are used.
+		{Time: at(12, 31).AddDate(0, 0, 1), Account: "0xA", Kind: KindReward, Amount: 1, TRBUSD: 50, USD: 50},
+		// Accounts without events in the year are not in the report.
+		{Time: at(1, 1).AddDate(0, 0, -1), Account: "0xB", Kind: KindReward, Amount: 1, TRBUSD: 10, USD: 10},
+	}
+
+	report, err := NewReport(events, 2021, MethodFIFO)
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(report.Accounts))
+	r := report.Accounts[0]
+	testutil.Equals(t, 2, len(r.Income))
+	testutil.Equals(t, 100.0, r.IncomeUSD)
+	testutil.Equals(t, 10.0, r.ExpenseUSD)
+	testutil.Equals(t, []Disposal{
+		// 2 TRB at 10 and 1 TRB at 30.
+		{Time: at(3, 1), Amount: 3, ProceedsUSD: 150, CostBasisUSD: 50, GainUSD: 100},
+		// The last TRB at 30 and 1 TRB without a recorded reward.
+		{Time: at(4, 1), Amount: 2, ProceedsUSD: 100, CostBasisUSD: 30, GainUSD: 70, Unmatched: 1},
+		// The slashed stake has no proceeds so it is a loss of its cost basis.
+		{Time: at(6, 1), Amount: 1, CostBasisUSD: 40, GainUSD: -40, Cause: "lost dispute 1"},
+	}, r.Disposals)
+	testutil.Equals(t, 130.0, r.GainUSD)
+
+	report, err = NewReport(events, 2021, MethodLIFO)
+	testutil.Ok(t, err)
+	// 2 TRB at 30 and 1 TRB at 10, then the last TRB at 10.
+	testutil.Equals(t, 70.0, report.Accounts[0].Disposals[0].CostBasisUSD)
+	testutil.Equals(t, 10.0, report.Accounts[0].Disposals[1].CostBasisUSD)
+
+	_, err = NewReport(events, 2021, "average")
+	testutil.NotOk(t, err)
+}
@@ -0,0 +1,308 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FileName is the name of the profit history file in the db directory.
+const FileName = "profit.jsonl"
+
+// MaxMemoryEvents is how many events a store in memory keeps,
+// the oldest ones are dropped so a long running stateless miner doesn't grow forever.
+const MaxMemoryEvents = 10000
+
+// Kind is the type of a profit event.
+type Kind string
+
+const (
+	// KindReward is the TRB minted to a reporter for a submit.
+	KindReward Kind = "reward"
+	// KindGas is the ETH paid for a submit, including the failed ones.
+	KindGas Kind = "gas"
+	// KindTransfer is the TRB sent from a reporter to another address.
+	// It is not an expense but a disposal of the received rewards.
+	KindTransfer Kind = "transfer"
+	// KindSlash is the stake a reporter lost in a dispute.
+	KindSlash Kind = "slash"
+	// KindDisputeFee is the TRB locked as the fee of a dispute begun by the account until the vote.
+	KindDisputeFee Kind = "disputeFee"
+	// KindDisputeRefund is the fee returned to the disputer when the vote passed.
+	KindDisputeRefund Kind = "disputeRefund"
+	// KindDisputeWin is the TRB won in a dispute, the stake of the reported miner
+	// for the disputer or the fee of a failed dispute for the reported miner.
+	KindDisputeWin Kind = "disputeWin"
+	// KindDisputeLoss is the fee that the disputer forfeits when the vote failed.
+	KindDisputeLoss Kind = "disputeLoss"
+)
+
+// Event is a single reward or expense of a tracked account
+// with the prices at the time of its block.
+type Event struct {
+	ID        string    `json:"id"`
+	Time      time.Time `json:"time"`
+	Block     uint64    `json:"block"`
+	BlockHash string    `json:"blockHash"`
+	Tx        string    `json:"tx"`
+	Account   string    `json:"account"`
+	Kind      Kind      `json:"kind"`
+	Origin    string    `json:"origin,omitempty"` // What sent the transaction of a gas expense.
+	Token     string    `json:"token"`
+	Amount    float64   `json:"amount"`
+	ETHUSD    float64   `json:"ethUsd"` // 0 when the price wasn't available.
+	TRBUSD    float64   `json:"trbUsd"` // 0 when the price wasn't available.
+	USD       float64   `json:"usd"`
+	Cause     string    `json:"cause,omitempty"` // The dispute of a slash or a dispute event.
+	Removed   bool      `json:"removed,omitempty"`
+}
+
+// Filter selects the events to list.
+// The empty fields match all events.
+type Filter struct {
+	Account string
+	Kind    Kind
+	From    time.Time
+	To      time.Time
+}
+
+func (self Filter) match(e Event) bool {
+	if self.Account != "" && !strings.EqualFold(self.Account, e.Account) {
+		return false
+	}
+	if self.Kind != "" && self.Kind != e.Kind {
+		return false
+	}
+	if !self.From.IsZero() && e.Time.Before(self.From) {
+		return false
+	}
+	if !self.To.IsZero() && !e.Time.Before(self.To) {
+		return false
+	}
+	return true
+}
+
+// Store is the profit history.
+// Like the transaction history it is a journal where every change of an event is appended as a new line
+// and the last line of an event is its current state.
+type Store struct {
+	path string
+	// memory keeps the current state of the events in mem instead of the file.
+	memory   bool
+	mem      []Event
+	memIndex map[string]int
+	mtx      sync.Mutex
+}
+
+// Open returns the store in the given directory.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, errors.Wrapf(err, "creating the db directory:%v", dir)
+	}
+	return &Store{path: filepath.Join(dir, FileName)}, nil
+}
+
+// OpenMemory returns a store that keeps the history only in memory
+// for a stateless miner that doesn't write to the disk.
+// The history is lost when the process exits and only the last MaxMemoryEvents events are kept.
+func OpenMemory() *Store {
+	return &Store{memory: true, memIndex: make(map[string]int)}
+}
+
+// Add appends a new or changed event.
+// A nil store doesn't record anything so the tracker can run without a history.
+func (self *Store) Add(e Event) error {
+	if self == nil {
+		return nil
+	}
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.memory {
+		self.addMemory(e)
+		return nil
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "marshal event")
+	}
+	f, err := os.OpenFile(self.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return errors.Wrap(err, "open the history file")
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return errors.Wrap(err, "write to the history file")
+	}
+	return errors.Wrap(f.Close(), "close the history file")
+}
+
+// addMemory sets the current state of an event in memory
+// and drops the oldest events above MaxMemoryEvents.
+func (self *Store) addMemory(e Event) {
+	if i, ok := self.memIndex[e.ID]; ok {
+		self.mem[i] = e
+		return
+	}
+	self.memIndex[e.ID] = len(self.mem)
+	self.mem = append(self.mem, e)
+	if len(self.mem) <= MaxMemoryEvents {
+		return
+	}
+	self.mem = append([]Event(nil), self.mem[len(self.mem)-MaxMemoryEvents:]...)
+	self.memIndex = make(map[string]int, len(self.mem))
+	for i, e := range self.mem {
+		self.memIndex[e.ID] = i
+	}
+}
+
+// Remove marks an event as removed, for example when its block was reorged out of the canonical chain.
+func (self *Store) Remove(id string) error {
+	return self.Add(Event{ID: id, Removed: true})
+}
+
+// List returns the matching events, the oldest first.
+func (self *Store) List(filter Filter) ([]Event, error) {
+	if self == nil {
+		return nil, nil
+	}
+	events, err := self.all()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []Event{}
+	for _, e := range events {
+		if !e.Removed && filter.match(e) {
+			matched = append(matched, e)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Time.Before(matched[j].Time) })
+	return matched, nil
+}
+
+// all returns the current state of all events in the order these were added.
+func (self *Store) all() ([]Event, error) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.memory {
+		return append([]Event(nil), self.mem...), nil
+	}
+	f, err := os.Open(self.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "open the history file")
+	}
+	defer f.Close()
+
+	var events []Event
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// Skip a line partially written when the process crashed.
+			continue
+		}
+		if i, ok := index[e.ID]; ok {
+			events[i] = e
+			continue
+		}
+		index[e.ID] = len(events)
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read the history file")
+	}
+	return events, nil
+}
+
+// Summary is the realized profit of an account.
+type Summary struct {
+	Account    string  `json:"account"`
+	RewardTRB  float64 `json:"rewardTrb"`
+	GasETH     float64 `json:"gasEth"`
+	RewardUSD  float64 `json:"rewardUsd"`
+	GasUSD     float64 `json:"gasUsd"`
+	SlashedTRB float64 `json:"slashedTrb"`
+	SlashedUSD float64 `json:"slashedUsd"`
+	ProfitUSD  float64 `json:"profitUsd"`
+
+	DisputeLockedTRB float64 `json:"disputeLockedTrb"`
+	DisputeWonTRB    float64 `json:"disputeWonTrb"`
+	DisputeWonUSD    float64 `json:"disputeWonUsd"`
+	DisputeLostTRB   float64 `json:"disputeLostTrb"`
+	DisputeLostUSD   float64 `json:"disputeLostUsd"`
+
+	// GasUSDByOrigin splits the gas cost by what sent the transactions.
+	GasUSDByOrigin map[string]float64 `json:"gasUsdByOrigin,omitempty"`
+}
+
+// Summarize returns the totals of the events for each account sorted by the account.
+// The USD totals include only the events with a known price.
+func Summarize(events []Event) []Summary {
+	byAccount := make(map[string]*Summary)
+	for _, e := range events {
+		account := strings.ToLower(e.Account)
+		s, ok := byAccount[account]
+		if !ok {
+			s = &Summary{Account: e.Account}
+			byAccount[account] = s
+		}
+		switch e.Kind {
+		case KindReward:
+			s.RewardTRB += e.Amount
+			s.RewardUSD += e.USD
+			s.ProfitUSD += e.USD
+		case KindGas:
+			s.GasETH += e.Amount
+			s.GasUSD += e.USD
+			s.ProfitUSD -= e.USD
+			origin := e.Origin
+			if origin == "" {
+				origin = OriginSubmission
+			}
+			if s.GasUSDByOrigin == nil {
+				s.GasUSDByOrigin = make(map[string]float64)
+			}
+			s.GasUSDByOrigin[origin] += e.USD
+		case KindSlash:
+			s.SlashedTRB += e.Amount
+			s.SlashedUSD += e.USD
+			s.ProfitUSD -= e.USD
+		case KindDisputeFee:
+			s.DisputeLockedTRB += e.Amount
+		case KindDisputeRefund:
+			s.DisputeLockedTRB -= e.Amount
+		case KindDisputeWin:
+			s.DisputeWonTRB += e.Amount
+			s.DisputeWonUSD += e.USD
+			s.ProfitUSD += e.USD
+		case KindDisputeLoss:
+			s.DisputeLockedTRB -= e.Amount
+			s.DisputeLostTRB += e.Amount
+			s.DisputeLostUSD += e.USD
+			s.ProfitUSD -= e.USD
+		}
+	}
+
+	summaries := make([]Summary, 0, len(byAccount))
+	for _, s := range byAccount {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Account < summaries[j].Account })
+	return summaries
+}
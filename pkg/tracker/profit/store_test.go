@@ -0,0 +1,130 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profit")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := Open(dir)
+	testutil.Ok(t, err)
+
+	now := time.Now()
+	add := func(e Event) {
+		t.Helper()
+		testutil.Ok(t, store.Add(e))
+	}
+	add(Event{ID: "1", Time: now.Add(-time.Hour), Account: "0xA", Kind: KindReward, Amount: 2, USD: 60})
+	add(Event{ID: "2", Time: now.Add(-2 * time.Hour), Account: "0xA", Kind: KindGas, Amount: 0.01, USD: 25})
+	add(Event{ID: "3", Time: now, Account: "0xB", Kind: KindReward, Amount: 1, USD: 30})
+	add(Event{ID: "4", Time: now, Account: "0xB", Kind: KindGas, Origin: OriginCLI, Amount: 0.02})
+	add(Event{ID: "5", Time: now.Add(time.Hour), Account: "0xB", Kind: KindSlash, Amount: 500, USD: 100, Cause: "lost dispute 1"})
+	// A replayed event replaces the earlier one.
+	add(Event{ID: "1", Time: now.Add(-time.Hour), Account: "0xA", Kind: KindReward, Amount: 2, USD: 60})
+	testutil.Ok(t, store.Remove("3"))
+
+	events, err := store.List(Filter{})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 4, len(events))
+	testutil.Equals(t, "2", events[0].ID)
+	testutil.Equals(t, "4", events[2].ID)
+
+	events, err = store.List(Filter{Account: "0xa", Kind: KindReward})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(events))
+
+	events, err = store.List(Filter{From: now.Add(-90 * time.Minute), To: now})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(events))
+	testutil.Equals(t, "1", events[0].ID)
+
+	events, err = store.List(Filter{})
+	testutil.Ok(t, err)
+	testutil.Equals(t, []Summary{
+		// The gas recorded without an origin is of the submits.
+		{Account: "0xA", RewardTRB: 2, GasETH: 0.01, RewardUSD: 60, GasUSD: 25, GasUSDByOrigin: map[string]float64{OriginSubmission: 25}, ProfitUSD: 35},
+		{Account: "0xB", GasETH: 0.02, GasUSDByOrigin: map[string]float64{OriginCLI: 0}, SlashedTRB: 500, SlashedUSD: 100, ProfitUSD: -100},
+	}, Summarize(events))
+}
+
+func TestBreakdowns(t *testing.T) {
+	now := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	events := []Event{
+		// Before the previous range.
+		{Time: now.Add(-15 * day), Account: "0xA", Kind: KindReward, Amount: 1, USD: 1000},
+		// The previous range.
+		{Time: now.Add(-10 * day), Account: "0xA", Kind: KindReward, Amount: 2, USD: 100},
+		{Time: now.Add(-9 * day), Account: "0xA", Kind: KindGas, Amount: 0.01, USD: 20},
+		// The latest range.
+		{Time: now.Add(-2 * day), Account: "0xA", Kind: KindReward, Amount: 2, USD: 80},
+		{Time: now.Add(-day), Account: "0xA", Kind: KindGas, Amount: 0.01, USD: 20},
+		{Time: now.Add(-day), Account: "0xB", Kind: KindReward, Amount: 1, USD: 50},
+		// After the range.
+		{Time: now, Account: "0xB", Kind: KindReward, Amount: 1, USD: 50},
+	}
+
+	breakdowns := Breakdowns(events, 7*day, now)
+	testutil.Equals(t, 2, len(breakdowns))
+	a := breakdowns[0]
+	testutil.Equals(t, Summary{Account: "0xA", RewardTRB: 2, GasETH: 0.01, RewardUSD: 80, GasUSD: 20, GasUSDByOrigin: map[string]float64{OriginSubmission: 20}, ProfitUSD: 60}, a.Summary)
+	testutil.Equals(t, now.Add(-7*day), a.From)
+	testutil.Equals(t, 80.0, a.PreviousProfitUSD)
+	testutil.Equals(t, -0.25, *a.Trend)
+	testutil.Equals(t, 50.0, breakdowns[1].ProfitUSD)
+	testutil.Assert(t, breakdowns[1].Trend == nil, "an account without a previous profit should have no trend")
+
+	_, err := ParseRange("7d")
+	testutil.Ok(t, err)
+	_, err = ParseRange("-1h")
+	testutil.NotOk(t, err)
+}
+
+func TestSummarizeDisputes(t *testing.T) {
+	events := []Event{
+		// A won dispute returns the fee and adds the stake of the reported miner.
+		{Account: "0xA", Kind: KindDisputeFee, Amount: 10, USD: 500},
+		{Account: "0xA", Kind: KindDisputeRefund, Amount: 10, USD: 500},
+		{Account: "0xA", Kind: KindDisputeWin, Amount: 500, USD: 25000},
+		// A lost dispute forfeits the fee.
+		{Account: "0xA", Kind: KindDisputeFee, Amount: 20, USD: 1000},
+		{Account: "0xA", Kind: KindDisputeLoss, Amount: 20, USD: 1000},
+		// The fee of an open dispute stays locked.
+		{Account: "0xA", Kind: KindDisputeFee, Amount: 30, USD: 1500},
+		// The reported miner of a failed dispute gets the fee.
+		{Account: "0xB", Kind: KindDisputeWin, Amount: 20, USD: 1000},
+	}
+	testutil.Equals(t, []Summary{
+		{Account: "0xA", ProfitUSD: 24000, DisputeLockedTRB: 30, DisputeWonTRB: 500, DisputeWonUSD: 25000, DisputeLostTRB: 20, DisputeLostUSD: 1000},
+		{Account: "0xB", ProfitUSD: 1000, DisputeWonTRB: 20, DisputeWonUSD: 1000},
+	}, Summarize(events))
+}
+
+func TestMemoryLimit(t *testing.T) {
+	store := OpenMemory()
+	now := time.Now()
+	for i := 0; i < MaxMemoryEvents+10; i++ {
+		testutil.Ok(t, store.Add(Event{ID: strconv.Itoa(i), Time: now.Add(time.Duration(i) * time.Second), Account: "0xA", Kind: KindReward, Amount: 1}))
+	}
+	events, err := store.List(Filter{})
+	testutil.Ok(t, err)
+	testutil.Equals(t, MaxMemoryEvents, len(events))
+	testutil.Equals(t, "10", events[0].ID, "the oldest events should be dropped")
+
+	testutil.Ok(t, store.Remove("10"))
+	events, err = store.List(Filter{})
+	testutil.Ok(t, err)
+	testutil.Equals(t, MaxMemoryEvents-1, len(events))
+}
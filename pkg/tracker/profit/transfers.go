@@ -0,0 +1,146 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/contracts/tellor"
+	"github.com/tellor-io/telliot/pkg/notify"
+)
+
+// handleTransfersOut records the TRB sent from the tracked accounts in the block.
+// The transfers can be in transactions sent by other accounts so the block bloom
+// is checked for the transfer event with a tracked sender before querying the logs of the block.
+func (self *ProfitTracker) handleTransfersOut(logger log.Logger, header *types.Header) {
+	if !header.Bloom.Test(self.abi.Events["Transfer"].ID.Bytes()) {
+		return
+	}
+	var senders []common.Address
+	for _, addr := range self.addrs {
+		if header.Bloom.Test(common.BytesToHash(addr.Bytes()).Bytes()) {
+			senders = append(senders, addr)
+		}
+	}
+	if len(senders) == 0 {
+		return
+	}
+
+	logger = log.With(logger, "block", header.Number)
+	tellorFilterer, err := tellor.NewTellorFilterer(self.contractInstance.Address, self.client)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting instance", "err", err)
+		return
+	}
+	block := header.Number.Uint64()
+	iter, err := tellorFilterer.FilterTransferred(&bind.FilterOpts{Context: self.ctx, Start: block, End: &block}, senders, nil)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting the transfers of the block", "err", err)
+		return
+	}
+	defer iter.Close()
+	for iter.Next() {
+		// The logs are filtered by the block number so skip the logs of another block at the same height after a reorg.
+		if iter.Event.Raw.BlockHash != header.Hash() || self.cacheTransfersOut.Has(txIDTransferOut(iter.Event)) {
+			continue
+		}
+		self.setTransferOut(log.With(logger, "addr", iter.Event.From.String()[:6], "tx", iter.Event.Raw.TxHash), header, iter.Event)
+	}
+	if err := iter.Error(); err != nil {
+		level.Error(logger).Log("msg", "reading the transfers of the block", "err", err)
+	}
+}
+
+// setTransferOut records an outgoing transfer.
+// The stake of a reporter that lost a dispute is transferred to the disputer
+// so such transfers are recorded as slashes
+// and the fee of a new dispute is transferred to the contract where it is locked until the vote.
+func (self *ProfitTracker) setTransferOut(logger log.Logger, header *types.Header, event *tellor.TellorTransferred) {
+	trb := trbAmount(event.Value)
+	kind := KindTransfer
+	var cause string
+	method, args, err := self.txMethod(event.Raw.TxHash)
+	if err != nil {
+		level.Error(logger).Log("msg", "checking the method of the transfer", "err", err)
+	}
+	switch {
+	case method == nil:
+	case method.Name == "unlockDisputeFee":
+		if cause, err = self.slashCause(event, args); err != nil {
+			level.Error(logger).Log("msg", "checking whether the transfer is a slash", "err", err)
+		}
+	case method.Name == "beginDispute" && event.To == self.contractInstance.Address:
+		kind = KindDisputeFee
+		cause = fmt.Sprintf("fee of the dispute for the value of request %v at %v", args[0], time.Unix(args[1].(*big.Int).Int64(), 0).UTC().Format(time.RFC3339))
+		level.Info(logger).Log("msg", "adding dispute fee", "amount", trb)
+	}
+	if kind == KindTransfer && cause != "" {
+		kind = KindSlash
+		level.Warn(logger).Log("msg", "account slashed", "amount", trb, "cause", cause)
+		self.slashed.With(prometheus.Labels{"addr": event.From.String()}).(prometheus.Gauge).Add(trb)
+		// Don't notify again about the old slashes found by a replay.
+		if time.Since(time.Unix(int64(header.Time), 0)) < time.Hour {
+			self.notify(notify.SeverityCritical, "Account slashed", fmt.Sprintf("%v was slashed %v TRB as it %v.", event.From.String(), trb, cause))
+		}
+	} else if kind == KindTransfer {
+		level.Debug(logger).Log("msg", "adding outgoing transfer", "amount", trb)
+	}
+	e := self.record(logger, Event{
+		ID:        txIDTransferOut(event),
+		Time:      time.Unix(int64(header.Time), 0),
+		Block:     header.Number.Uint64(),
+		BlockHash: header.Hash().String(),
+		Tx:        event.Raw.TxHash.String(),
+		Account:   event.From.String(),
+		Kind:      kind,
+		Token:     "TRB",
+		Amount:    trb,
+		Cause:     cause,
+	})
+
+	if err := self.cacheTransfersOut.Set(txIDTransferOut(event), entry{block: header.Number.Uint64(), hash: header.Hash(), addr: event.From, amount: trb, usd: e.USD, kind: kind}); err != nil {
+		level.Error(logger).Log("msg", "adding transfer to the cache", "err", err)
+	}
+	if kind == KindDisputeFee {
+		self.setDisputeLocked(logger, event.From)
+	}
+
+	balance, err := self.getTRBBalance(event.From)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting TRB balance", "err", err)
+		return
+	}
+	level.Debug(logger).Log("msg", "new TRB balance", "balance", balance)
+	self.balances.With(prometheus.Labels{"addr": event.From.String(), "token": "TRB"}).(prometheus.Gauge).Set(balance)
+}
+
+// slashCause returns the cause of the transfer when it is the stake of a reported miner
+// that lost a dispute, which is transferred to the disputer when the dispute fee is unlocked.
+// It returns an empty cause for the other transfers.
+// The args are the arguments of the unlockDisputeFee call of the transfer.
+func (self *ProfitTracker) slashCause(event *tellor.TellorTransferred, args []interface{}) (string, error) {
+	d, err := self.unlockedDispute(args)
+	if err != nil {
+		return "", err
+	}
+	if !d.votePassed || d.reportedMiner != event.From || d.reportingParty != event.To {
+		return "", nil
+	}
+	return "lost " + d.String(), nil
+}
+
+// txIDTransferOut includes the log index so that
+// the transfers between the same accounts in one transaction are all recorded.
+func txIDTransferOut(event *tellor.TellorTransferred) string {
+	return event.Raw.TxHash.String() + event.From.String() + event.To.String() + strconv.FormatUint(uint64(event.Raw.Index), 10)
+}
@@ -0,0 +1,26 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tellor-io/telliot/pkg/contracts/tellor"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestTxIDTransferOut(t *testing.T) {
+	transfer := func(index uint) *tellor.TellorTransferred {
+		return &tellor.TellorTransferred{
+			From: common.HexToAddress("0x1"),
+			To:   common.HexToAddress("0x2"),
+			Raw:  types.Log{TxHash: common.HexToHash("0x3"), Index: index},
+		}
+	}
+	// Two transfers between the same accounts in one transaction.
+	testutil.Assert(t, txIDTransferOut(transfer(0)) != txIDTransferOut(transfer(1)), "the transfers of one transaction should have different ids")
+	testutil.Equals(t, txIDTransferOut(transfer(1)), txIDTransferOut(transfer(1)))
+}
@@ -0,0 +1,85 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package profit
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PriceFunc returns the price of a symbol like ETH/USD at the given time.
+type PriceFunc func(symbol string, at time.Time) (float64, error)
+
+// blockTime returns the time of a block or the current time when the header is not available.
+func (self *ProfitTracker) blockTime(logger log.Logger, number *big.Int) time.Time {
+	header, err := self.client.HeaderByNumber(self.ctx, number)
+	if err != nil {
+		level.Error(logger).Log("msg", "getting the block time, using the current time", "block", number, "err", err)
+		return time.Now()
+	}
+	return time.Unix(int64(header.Time), 0)
+}
+
+// record snapshots the ETH/USD and TRB/USD prices at the time of the event,
+// adds its USD value to the metrics and saves it in the profit history.
+// It returns the event with the USD value set.
+// While backfilling an event that is already in the history is returned as it was recorded.
+func (self *ProfitTracker) record(logger log.Logger, e Event) Event {
+	if recorded, ok := self.recorded[e.ID]; ok {
+		self.addUSD(recorded.Kind, common.HexToAddress(recorded.Account), recorded.USD)
+		if recorded.Kind == KindGas {
+			self.addGas(recorded.Origin, common.HexToAddress(recorded.Account), recorded.Amount, recorded.USD)
+		}
+		return recorded
+	}
+	if self.prices != nil {
+		for symbol, price := range map[string]*float64{"ETH/USD": &e.ETHUSD, "TRB/USD": &e.TRBUSD} {
+			p, err := self.prices(symbol, e.Time)
+			if err != nil {
+				level.Warn(logger).Log("msg", "no price for the USD value", "symbol", symbol, "err", err)
+				continue
+			}
+			*price = p
+		}
+	}
+	switch e.Kind {
+	case KindReward, KindTransfer, KindSlash, KindDisputeFee, KindDisputeRefund, KindDisputeWin, KindDisputeLoss:
+		e.USD = math.Round(e.Amount*e.TRBUSD*100) / 100
+	case KindGas:
+		e.USD = math.Round(e.Amount*e.ETHUSD*100) / 100
+	}
+	self.addUSD(e.Kind, common.HexToAddress(e.Account), e.USD)
+	if e.Kind == KindGas {
+		self.addGas(e.Origin, common.HexToAddress(e.Account), e.Amount, e.USD)
+	}
+
+	if err := self.store.Add(e); err != nil {
+		level.Error(logger).Log("msg", "adding the event to the profit history", "err", err)
+	}
+	return e
+}
+
+// addUSD adds a USD amount to the metrics of the given kind and to the net profit.
+// A negative amount rolls back an event.
+// The gas expenses are added to the metrics of their origin by addGas.
+func (self *ProfitTracker) addUSD(kind Kind, addr common.Address, usd float64) {
+	labels := prometheus.Labels{"addr": addr.String()}
+	switch kind {
+	case KindReward:
+		self.submitProfitUSD.With(labels).(prometheus.Gauge).Add(usd)
+		self.profitUSD.With(labels).(prometheus.Gauge).Add(usd)
+	case KindGas:
+		self.profitUSD.With(labels).(prometheus.Gauge).Sub(usd)
+	case KindDisputeWin:
+		self.profitUSD.With(labels).(prometheus.Gauge).Add(usd)
+	case KindSlash, KindDisputeLoss:
+		self.profitUSD.With(labels).(prometheus.Gauge).Sub(usd)
+	}
+}
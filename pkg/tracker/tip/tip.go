@@ -0,0 +1,255 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package tip
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/tellor-io/telliot/pkg/contracts"
+	"github.com/tellor-io/telliot/pkg/contracts/tellor"
+	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/math"
+)
+
+const ComponentName = "tipTracker"
+
+// TotalMetricName is the name of the stored series with the current total tip of each request id.
+const TotalMetricName = "tip_total"
+
+// pollInterval is how often to refresh the total tips of the known request ids.
+// The totals also change without any event when a value is mined and the tips are paid out.
+const pollInterval = time.Minute
+
+type Config struct {
+	LogLevel string
+}
+
+// Tracker records the tips for each request id so that the operators
+// can see which requests are currently worth mining.
+type Tracker struct {
+	logger   log.Logger
+	ctx      context.Context
+	close    context.CancelFunc
+	tsDB     *tsdb.DB
+	client   *ethclient.Client
+	contract *contracts.ITellor
+
+	// known returns whether a request id has local sources.
+	known func(id int64) bool
+
+	mtx      sync.Mutex
+	ids      map[int64]struct{}
+	unknowns map[int64]*Unknown
+
+	total   *prometheus.GaugeVec
+	added   *prometheus.CounterVec
+	unknown *prometheus.GaugeVec
+}
+
+func New(
+	logger log.Logger,
+	ctx context.Context,
+	cfg Config,
+	tsDB *tsdb.DB,
+	client *ethclient.Client,
+	contract *contracts.ITellor,
+	known func(id int64) bool,
+) (*Tracker, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	logger = log.With(logger, "component", ComponentName)
+	ctx, close := context.WithCancel(ctx)
+
+	return &Tracker{
+		logger:   logger,
+		ctx:      ctx,
+		close:    close,
+		tsDB:     tsDB,
+		client:   client,
+		contract: contract,
+		known:    known,
+		ids:      make(map[int64]struct{}),
+		unknowns: make(map[int64]*Unknown),
+		total: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "total_trb",
+			Help:      "The current total tip in TRB for the request id",
+		}, []string{"id"}),
+		added: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "added_trb_total",
+			Help:      "The total TRB added as tips for the request id",
+		}, []string{"id"}),
+		unknown: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "telliot",
+			Subsystem: ComponentName,
+			Name:      "unknown_total_trb",
+			Help:      "The current total tip in TRB for a request id without local sources",
+		}, []string{"id", "query"}),
+	}, nil
+}
+
+func (self *Tracker) Start() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	events := make(chan *tellor.TellorTipAdded)
+	var sub event.Subscription
+	for {
+		var err error
+		sub, err = self.newSub(events)
+		if err == nil {
+			break
+		}
+		level.Error(self.logger).Log("msg", "initial subscribing to events failed", "err", err)
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	self.poll()
+
+	for {
+		select {
+		case <-self.ctx.Done():
+			return
+		case err := <-sub.Err():
+			if err != nil {
+				level.Error(self.logger).Log("msg", "subscription error", "err", err)
+			}
+			// Trying to resubscribe until it succeeds.
+			for {
+				sub, err = self.newSub(events)
+				if err == nil {
+					break
+				}
+				level.Error(self.logger).Log("msg", "re-subscribing to events failed", "err", err)
+				select {
+				case <-self.ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+			level.Info(self.logger).Log("msg", "re-subscribed to events")
+		case event := <-events:
+			if event.Raw.Removed {
+				continue
+			}
+			id := event.RequestId.Int64()
+			self.mtx.Lock()
+			self.ids[id] = struct{}{}
+			self.mtx.Unlock()
+
+			tip := math.BigInt18eToFloat(event.Tip)
+			self.added.With(prometheus.Labels{"id": event.RequestId.String()}).Add(tip)
+			level.Debug(self.logger).Log("msg", "tip added", "id", id, "tip", tip, "sender", event.Sender.String())
+			if err := self.record(id, event.TotalTips); err != nil {
+				level.Error(self.logger).Log("msg", "recording total tip", "id", id, "err", err)
+			}
+		case <-poll.C:
+			self.poll()
+		}
+	}
+}
+
+func (self *Tracker) Stop() {
+	self.close()
+}
+
+// poll refreshes the totals of the top request ids and of all ids that received a tip.
+func (self *Tracker) poll() {
+	top, err := self.contract.GetTopRequestIDs(&bind.CallOpts{Context: self.ctx})
+	if err != nil {
+		level.Error(self.logger).Log("msg", "getting the top request ids", "err", err)
+	}
+
+	self.mtx.Lock()
+	for _, id := range top {
+		if id != nil && id.Sign() > 0 {
+			self.ids[id.Int64()] = struct{}{}
+		}
+	}
+	ids := make([]int64, 0, len(self.ids))
+	for id := range self.ids {
+		ids = append(ids, id)
+	}
+	self.mtx.Unlock()
+
+	for _, id := range ids {
+		_, total, err := self.contract.GetRequestVars(&bind.CallOpts{Context: self.ctx}, big.NewInt(id))
+		if err != nil {
+			level.Error(self.logger).Log("msg", "getting the total tip", "id", id, "err", err)
+			continue
+		}
+		if err := self.record(id, total); err != nil {
+			level.Error(self.logger).Log("msg", "recording total tip", "id", id, "err", err)
+		}
+	}
+}
+
+func (self *Tracker) record(id int64, total *big.Int) (err error) {
+	v := math.BigInt18eToFloat(total)
+	idStr := big.NewInt(id).String()
+	self.total.With(prometheus.Labels{"id": idStr}).Set(v)
+	self.discover(id, v)
+
+	appender := self.tsDB.Appender(self.ctx)
+	defer func() { // An appender always needs to be committed or rolled back.
+		if err != nil {
+			if err := appender.Rollback(); err != nil {
+				level.Error(self.logger).Log("msg", "db rollback failed", "err", err)
+			}
+			return
+		}
+		if errC := appender.Commit(); errC != nil {
+			err = errors.Wrap(errC, "db append commit failed")
+		}
+	}()
+
+	lbls := labels.Labels{
+		labels.Label{Name: "__name__", Value: TotalMetricName},
+		labels.Label{Name: "contract", Value: "tellor"},
+		labels.Label{Name: "id", Value: idStr},
+	}
+	sort.Sort(lbls) // This is important! The labels need to be sorted to avoid creating the same series with duplicate reference.
+	if _, err := appender.Append(0, lbls, timestamp.FromTime(time.Now()), v); err != nil {
+		return errors.Wrap(err, "append values to the DB")
+	}
+	return nil
+}
+
+func (self *Tracker) newSub(output chan *tellor.TellorTipAdded) (event.Subscription, error) {
+	filterer, err := tellor.NewTellorFilterer(self.contract.Address, self.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting instance")
+	}
+	sub, err := filterer.WatchTipAdded(&bind.WatchOpts{Context: self.ctx}, output, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting channel")
+	}
+	return sub, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package tip
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Unknown is a tipped request id without local sources
+// so the operator can add sources to get its tips.
+type Unknown struct {
+	ID int64 `json:"id"`
+	// Query is the name of the data id in the lens contract, empty when it has no name.
+	Query     string    `json:"query"`
+	TotalTip  float64   `json:"totalTip"`
+	FirstSeen time.Time `json:"firstSeen"`
+}
+
+type response struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Unknowns returns the tipped request ids without local sources with the highest tip first.
+func (self *Tracker) Unknowns() []Unknown {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	unknowns := make([]Unknown, 0, len(self.unknowns))
+	for _, u := range self.unknowns {
+		unknowns = append(unknowns, *u)
+	}
+	sort.Slice(unknowns, func(i, j int) bool {
+		if unknowns[i].TotalTip != unknowns[j].TotalTip {
+			return unknowns[i].TotalTip > unknowns[j].TotalTip
+		}
+		return unknowns[i].ID < unknowns[j].ID
+	})
+	return unknowns
+}
+
+// UnknownHandler serves the tipped request ids without local sources.
+func (self *Tracker) UnknownHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(response{Status: "success", Data: self.Unknowns()})
+	}
+}
+
+// discover records the total tip of a request id that has no local sources
+// and logs it when it gets a tip after having none.
+func (self *Tracker) discover(id int64, total float64) {
+	if self.known == nil || self.known(id) {
+		return
+	}
+	self.mtx.Lock()
+	u, ok := self.unknowns[id]
+	self.mtx.Unlock()
+
+	// Only this goroutine writes the query so it can be read without the lock.
+	var query string
+	if ok {
+		query = u.Query
+	} else {
+		query = self.query(id)
+	}
+
+	self.mtx.Lock()
+	if !ok {
+		u = &Unknown{ID: id, Query: query, FirstSeen: time.Now()}
+		self.unknowns[id] = u
+	}
+	prev := u.TotalTip
+	u.TotalTip = total
+	self.mtx.Unlock()
+
+	self.unknown.With(prometheus.Labels{"id": strconv.FormatInt(id, 10), "query": query}).Set(total)
+	if prev == 0 && total > 0 {
+		level.Warn(self.logger).Log("msg", "tips for a request id without local sources, add sources to the index file to get them", "id", id, "query", query, "totalTip", total)
+	}
+}
+
+// query returns the name of the request id in the lens contract.
+func (self *Tracker) query(id int64) string {
+	ids, err := self.contract.DataIDsAll(&bind.CallOpts{Context: self.ctx})
+	if err != nil {
+		level.Debug(self.logger).Log("msg", "getting the data ids", "err", err)
+		return ""
+	}
+	for _, d := range ids {
+		if d.Id != nil && d.Id.Int64() == id {
+			return d.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package tip
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestUnknowns(t *testing.T) {
+	tracker := &Tracker{
+		logger: log.NewNopLogger(),
+		known:  func(id int64) bool { return id == 1 },
+		// Already discovered so that the queries aren't read from the contract.
+		unknowns: map[int64]*Unknown{
+			60: {ID: 60, Query: "SUSHI/USD"},
+			61: {ID: 61, Query: "AAVE/USD"},
+		},
+		unknown: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "unknown_total_trb"}, []string{"id", "query"}),
+	}
+
+	tracker.discover(1, 100)
+	tracker.discover(60, 5)
+	tracker.discover(61, 20)
+
+	// The request ids with local sources aren't listed and the highest tip is first.
+	unknowns := tracker.Unknowns()
+	testutil.Equals(t, 2, len(unknowns))
+	testutil.Equals(t, int64(61), unknowns[0].ID)
+	testutil.Equals(t, 20.0, unknowns[0].TotalTip)
+	testutil.Equals(t, int64(60), unknowns[1].ID)
+
+	w := httptest.NewRecorder()
+	tracker.UnknownHandler()(w, httptest.NewRequest("GET", "/api/v1/tips/unknown", nil))
+	var resp struct {
+		Status string
+		Data   []Unknown
+	}
+	testutil.Ok(t, json.NewDecoder(w.Body).Decode(&resp))
+	testutil.Equals(t, "success", resp.Status)
+	testutil.Equals(t, "AAVE/USD", resp.Data[0].Query)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package transactor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/logging"
+)
+
+// Drainer waits for the pending transactions of all transactors on shutdown.
+// It should be stopped before the components that send the transactions
+// so that their contexts are canceled only after the drain.
+type Drainer struct {
+	logger      log.Logger
+	ctx         context.Context
+	close       context.CancelFunc
+	cfg         Config
+	mtx         sync.Mutex
+	transactors []*TransactorDefault
+}
+
+func NewDrainer(logger log.Logger, ctx context.Context, cfg Config) (*Drainer, error) {
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply filter logger")
+	}
+	ctx, close := context.WithCancel(ctx)
+	return &Drainer{
+		logger: log.With(logger, "component", ComponentName),
+		ctx:    ctx,
+		close:  close,
+		cfg:    cfg,
+	}, nil
+}
+
+// Add registers a transactor to drain on shutdown.
+func (self *Drainer) Add(transactor *TransactorDefault) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.transactors = append(self.transactors, transactor)
+}
+
+func (self *Drainer) Start() error {
+	<-self.ctx.Done()
+	return nil
+}
+
+// Stop drains all transactors at the same time and
+// returns when these are drained or the drain timeout expires.
+func (self *Drainer) Stop() {
+	defer self.close()
+	if self.cfg.DrainTimeout.Duration == 0 {
+		return
+	}
+
+	self.mtx.Lock()
+	transactors := self.transactors
+	self.mtx.Unlock()
+
+	ctx, cncl := context.WithTimeout(context.Background(), self.cfg.DrainTimeout.Duration)
+	defer cncl()
+	var wg sync.WaitGroup
+	for _, transactor := range transactors {
+		wg.Add(1)
+		go func(transactor *TransactorDefault) {
+			defer wg.Done()
+			transactor.Drain(ctx)
+		}(transactor)
+	}
+	wg.Wait()
+	level.Debug(self.logger).Log("msg", "drained the transactors", "count", len(transactors))
+}
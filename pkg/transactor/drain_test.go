@@ -0,0 +1,60 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package transactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/tellor-io/telliot/pkg/format"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestDrain(t *testing.T) {
+	newTransactor := func() *TransactorDefault {
+		return &TransactorDefault{logger: log.NewNopLogger(), drained: make(chan struct{})}
+	}
+	drainer, err := NewDrainer(log.NewNopLogger(), context.Background(), Config{LogLevel: "info", DrainTimeout: format.Duration{Duration: time.Minute}})
+	testutil.Ok(t, err)
+
+	idle, busy := newTransactor(), newTransactor()
+	drainer.Add(idle)
+	drainer.Add(busy)
+	testutil.Assert(t, busy.begin(), "a transaction should start before the drain")
+
+	drained := make(chan struct{})
+	go func() {
+		drainer.Stop()
+		close(drained)
+	}()
+
+	// No new transactions while draining.
+	for !busy.isDraining() {
+		time.Sleep(time.Millisecond)
+	}
+	testutil.Assert(t, !busy.begin(), "a transaction shouldn't start while draining")
+	testutil.Assert(t, !idle.begin(), "a transaction shouldn't start while draining")
+
+	select {
+	case <-drained:
+		t.Fatal("the drain should wait for the pending transaction")
+	case <-time.After(50 * time.Millisecond):
+	}
+	busy.end()
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("the drain should complete after the pending transaction")
+	}
+
+	// The drain is bounded by the context.
+	stuck := newTransactor()
+	testutil.Assert(t, stuck.begin(), "a transaction should start before the drain")
+	ctx, cncl := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cncl()
+	stuck.Drain(ctx)
+	testutil.Assert(t, stuck.isDraining(), "the transactor should be draining")
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"math/big"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -17,8 +18,10 @@ import (
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
 	"github.com/tellor-io/telliot/pkg/ethereum"
+	"github.com/tellor-io/telliot/pkg/format"
 	"github.com/tellor-io/telliot/pkg/gasPrice"
 	"github.com/tellor-io/telliot/pkg/logging"
+	"github.com/tellor-io/telliot/pkg/txs"
 )
 
 const ComponentName = "transactor"
@@ -27,6 +30,20 @@ type Config struct {
 	LogLevel      string
 	GasMax        uint
 	GasMultiplier int
+	BumpBlocks    uint64          `help:"Replace a transaction with a higher gas price when it isn't mined after this many blocks. 0 disables it."`
+	BumpPercent   uint            `help:"How much to increase the gas price of a replacement transaction in percents. The nodes require at least 10."`
+	CancelOnClose bool            `help:"Cancel a pending transaction when its submission window closes so it doesn't get mined and fail."`
+	DrainTimeout  format.Duration `help:"On shutdown stop sending new transactions and wait up to this long for the pending ones to be mined. The ones still pending are resolved from the transaction history on the next start. 0 exits right away."`
+	PrivateRelay  PrivateRelayConfig
+}
+
+// PrivateRelayConfig sets sending the transactions through a private relay like the Flashbots RPC.
+// The relay doesn't broadcast the transactions to the public mempool which prevents frontrunning
+// and it doesn't include failed transactions so these don't cost any gas.
+type PrivateRelayConfig struct {
+	Enabled        bool   `help:"Send the submit transactions through a private relay instead of the public mempool."`
+	URL            string `help:"RPC url of the private relay."`
+	FallbackBlocks uint64 `help:"Send the transaction to the public mempool when it isn't mined after this many blocks. 0 disables the fallback."`
 }
 
 // Transactor takes care of sending transactions over the blockchain network.
@@ -41,7 +58,16 @@ type TransactorDefault struct {
 	logger          log.Logger
 	gasPriceQuerier gasPrice.GasPriceQuerier
 	client          *ethclient.Client
+	relay           *ethclient.Client
 	account         *ethereum.Account
+	nonces          *ethereum.NonceManager
+	txs             *txs.Store
+
+	// The transactions being sent that are waited for on shutdown.
+	pendingMtx sync.Mutex
+	pending    int
+	draining   bool
+	drained    chan struct{}
 }
 
 func New(
@@ -50,12 +76,17 @@ func New(
 	gasPriceQuerier gasPrice.GasPriceQuerier,
 	client *ethclient.Client,
 	account *ethereum.Account,
+	txStore *txs.Store,
 ) (*TransactorDefault, error) {
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "apply filter logger")
 	}
 
+	if (cfg.BumpBlocks > 0 || cfg.CancelOnClose) && cfg.BumpPercent < 10 {
+		return nil, errors.Errorf("the gas price bump should be at least 10 percent:%v", cfg.BumpPercent)
+	}
+
 	ctx, cncl := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cncl()
 	netID, err := client.NetworkID(ctx)
@@ -63,28 +94,43 @@ func New(
 		return nil, errors.Wrap(err, "getting network id")
 	}
 
+	var relay *ethclient.Client
+	if cfg.PrivateRelay.Enabled {
+		relay, err = ethclient.DialContext(ctx, cfg.PrivateRelay.URL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "connecting to the private relay:%v", cfg.PrivateRelay.URL)
+		}
+	}
+
 	return &TransactorDefault{
 		netID:           netID,
 		cfg:             cfg,
 		logger:          log.With(logger, "component", ComponentName),
 		gasPriceQuerier: gasPriceQuerier,
 		client:          client,
+		relay:           relay,
 		account:         account,
+		nonces:          ethereum.Nonces(client, account.Address),
+		txs:             txStore,
+		drained:         make(chan struct{}),
 	}, nil
 }
 
 func (self *TransactorDefault) Transact(ctx context.Context, contractCall func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, *types.Receipt, error) {
-	nonce, err := self.client.NonceAt(ctx, self.account.Address, nil)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "getting nonce for miner address")
+	if !self.begin() {
+		return nil, nil, errors.New("shutting down so not sending new transactions")
 	}
+	defer self.end()
 
-	// Use the same nonce in case there is a stuck transaction so that it resubmits the same TX with higher gas price.
-	IntNonce := int64(nonce)
+	if err := ethereum.CheckNodeSync(ctx, self.client); err != nil {
+		return nil, nil, err
+	}
 
+	// The querier returns an error when the price is above the hard max
+	// so the transaction is aborted instead of overpaying during gas spikes.
 	gasPrice, err := self.gasPriceQuerier.Query(ctx)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "getting data from the db")
+		return nil, nil, errors.Wrap(err, "getting gas price")
 	}
 
 	mul := self.cfg.GasMultiplier
@@ -112,7 +158,14 @@ func (self *TransactorDefault) Transact(ctx context.Context, contractCall func(*
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "creating transactor")
 		}
-		auth.Nonce = big.NewInt(IntNonce)
+		// The nonce manager is shared with the other components using the same account
+		// so concurrent transactions don't collide.
+		nonce, err := self.nonces.Next(ctx)
+		if err != nil {
+			finalError = err
+			continue
+		}
+		auth.Nonce = new(big.Int).SetUint64(nonce)
 		auth.Value = big.NewInt(0)      // in weiF
 		auth.GasLimit = uint64(3000000) // in units
 		if gasPrice.Cmp(big.NewInt(0)) == 0 {
@@ -126,30 +179,22 @@ func (self *TransactorDefault) Transact(ctx context.Context, contractCall func(*
 			// First time, try base gas price.
 			auth.GasPrice = gasPrice
 		}
-		max := self.cfg.GasMax
-		var maxGasPrice *big.Int
-		gasPrice1 := big.NewInt(params.GWei)
-		if max > 0 {
-			maxGasPrice = gasPrice1.Mul(gasPrice1, big.NewInt(int64(max)))
-		} else {
-			maxGasPrice = gasPrice1.Mul(gasPrice1, big.NewInt(int64(100)))
-		}
-
-		if auth.GasPrice.Cmp(maxGasPrice) > 0 {
+		if maxGasPrice := self.maxGasPrice(); auth.GasPrice.Cmp(maxGasPrice) > 0 {
 			level.Info(self.logger).Log("msg", "gas price too high, will default to the max price", "current", auth.GasPrice, "defaultMax", maxGasPrice)
 			auth.GasPrice = maxGasPrice
 		}
 
-		tx, err := contractCall(auth)
+		tx, err := self.send(ctx, auth, contractCall, self.relay != nil)
 		if err != nil {
+			// In these cases the nonce is already used so the nonce manager
+			// gives a new one for the retry, otherwise it is released to be reused.
 			if strings.Contains(strings.ToLower(err.Error()), "nonce too low") { // Can't use error type matching because of the way the eth client is implemented.
-				IntNonce = IntNonce + 1
-				level.Warn(self.logger).Log("msg", "last transaction has been confirmed so will increase the nonce and resend the transaction.")
-
+				level.Warn(self.logger).Log("msg", "last transaction has been confirmed so will use a new nonce and resend the transaction.")
 			} else if strings.Contains(strings.ToLower(err.Error()), "replacement transaction underpriced") { // Can't use error type matching because of the way the eth client is implemented.
-				level.Warn(self.logger).Log("msg", "last transaction is stuck so will increase the gas price and try to resend")
+				level.Warn(self.logger).Log("msg", "another transaction with the same nonce is pending so will use a new nonce and resend the transaction")
 				finalError = err
 			} else {
+				self.nonces.Release(nonce)
 				finalError = errors.Wrap(err, "contract call")
 			}
 
@@ -163,11 +208,259 @@ func (self *TransactorDefault) Transact(ctx context.Context, contractCall func(*
 			}
 		}
 
-		receipt, err := bind.WaitMined(ctx, self.client, tx)
+		mined, receipt, err := self.waitMined(ctx, auth, contractCall, tx)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "transaction result tx:%v", tx.Hash())
 		}
-		return tx, receipt, nil
+		return mined, receipt, nil
 	}
 	return nil, nil, errors.Wrapf(finalError, "submit tx after 5 attempts")
 }
+
+// Drain stops sending new transactions and waits until the pending ones are mined or the context is done.
+// It is called on shutdown before the contexts of the transactions are canceled
+// so that a restart doesn't leave a submit without its result.
+func (self *TransactorDefault) Drain(ctx context.Context) {
+	self.pendingMtx.Lock()
+	if !self.draining {
+		self.draining = true
+		if self.pending == 0 {
+			close(self.drained)
+		}
+	}
+	pending := self.pending
+	self.pendingMtx.Unlock()
+
+	if pending == 0 {
+		return
+	}
+	level.Info(self.logger).Log("msg", "waiting for the pending transactions before shutting down", "count", pending)
+	select {
+	case <-self.drained:
+		level.Info(self.logger).Log("msg", "pending transactions completed")
+	case <-ctx.Done():
+		level.Warn(self.logger).Log("msg", "shutting down with pending transactions, these are resolved from the transaction history on the next start")
+	}
+}
+
+func (self *TransactorDefault) begin() bool {
+	self.pendingMtx.Lock()
+	defer self.pendingMtx.Unlock()
+	if self.draining {
+		return false
+	}
+	self.pending++
+	return true
+}
+
+func (self *TransactorDefault) end() {
+	self.pendingMtx.Lock()
+	defer self.pendingMtx.Unlock()
+	self.pending--
+	if self.draining && self.pending == 0 {
+		close(self.drained)
+	}
+}
+
+func (self *TransactorDefault) isDraining() bool {
+	self.pendingMtx.Lock()
+	defer self.pendingMtx.Unlock()
+	return self.draining
+}
+
+// maxGasPrice returns the max gas price from the config
+// or the hard max of the gas price querier when it is lower.
+func (self *TransactorDefault) maxGasPrice() *big.Int {
+	max := self.cfg.GasMax
+	if max == 0 {
+		max = 100
+	}
+	maxGasPrice := new(big.Int).Mul(big.NewInt(params.GWei), big.NewInt(int64(max)))
+
+	if limiter, ok := self.gasPriceQuerier.(gasPrice.Limiter); ok {
+		if hardMax := limiter.Max(); hardMax != nil && hardMax.Cmp(maxGasPrice) < 0 {
+			return hardMax
+		}
+	}
+	return maxGasPrice
+}
+
+// send signs the transaction and sends it through the private relay
+// or otherwise through the client of the contract binding.
+func (self *TransactorDefault) send(ctx context.Context, auth *bind.TransactOpts, contractCall func(*bind.TransactOpts) (*types.Transaction, error), private bool) (*types.Transaction, error) {
+	auth.NoSend = private
+	tx, err := contractCall(auth)
+	if err != nil {
+		return nil, err
+	}
+	if private {
+		if err := self.relay.SendTransaction(ctx, tx); err != nil {
+			return nil, errors.Wrap(err, "sending through the private relay")
+		}
+		level.Info(self.logger).Log("msg", "transaction sent through the private relay", "tx", tx.Hash().String())
+	}
+	self.record(txs.NewRecord(txs.TypeSubmit, self.account.Address, tx))
+	return tx, nil
+}
+
+// recordMined records the mined transaction and the replaced ones which will never be mined.
+func (self *TransactorDefault) recordMined(mined *types.Transaction, receipt *types.Receipt, sent []*types.Transaction) {
+	for _, tx := range sent {
+		r := txs.NewRecord(txs.TypeSubmit, self.account.Address, tx)
+		if tx.Hash() == mined.Hash() {
+			r.SetReceipt(receipt)
+		} else {
+			r.Status = txs.StatusDropped
+		}
+		self.record(r)
+	}
+}
+
+func (self *TransactorDefault) record(r txs.Record) {
+	if err := self.txs.Add(r); err != nil {
+		level.Error(self.logger).Log("msg", "recording the transaction in the history", "tx", r.Hash, "err", err)
+	}
+}
+
+// waitMined waits until the transaction or any of its replacements is mined.
+// A transaction sent through the private relay is also sent to the public mempool
+// when it isn't mined within the configured number of blocks.
+// A stuck transaction is replaced with a higher gas price and when the context is canceled,
+// because the submission window has closed, the pending transaction is canceled.
+func (self *TransactorDefault) waitMined(
+	ctx context.Context,
+	auth *bind.TransactOpts,
+	contractCall func(*bind.TransactOpts) (*types.Transaction, error),
+	tx *types.Transaction,
+) (*types.Transaction, *types.Receipt, error) {
+	start, err := self.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "getting the block number")
+	}
+	lastSent := start
+	private := self.relay != nil
+	sent := []*types.Transaction{tx}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		for _, tx := range sent {
+			receipt, err := self.client.TransactionReceipt(ctx, tx.Hash())
+			if receipt != nil {
+				self.recordMined(tx, receipt, sent)
+				return tx, receipt, nil
+			}
+			if err != nil {
+				level.Debug(self.logger).Log("msg", "receipt retrieval failed", "tx", tx.Hash().String(), "err", err)
+			}
+		}
+
+		head, err := self.client.BlockNumber(ctx)
+		if err != nil {
+			level.Debug(self.logger).Log("msg", "getting the block number", "err", err)
+		} else {
+			if private && self.cfg.PrivateRelay.FallbackBlocks > 0 && head >= start+self.cfg.PrivateRelay.FallbackBlocks {
+				last := sent[len(sent)-1]
+				level.Warn(self.logger).Log("msg", "transaction not mined through the private relay, sending it to the public mempool", "tx", last.Hash().String(), "blocks", head-start)
+				if err := self.client.SendTransaction(ctx, last); err != nil {
+					level.Error(self.logger).Log("msg", "sending to the public mempool", "err", err)
+				}
+				private = false
+			}
+			if self.cfg.BumpBlocks > 0 && head >= lastSent+self.cfg.BumpBlocks {
+				lastSent = head
+				replacement, err := self.bump(ctx, auth, contractCall, private)
+				if err != nil {
+					level.Warn(self.logger).Log("msg", "replacing the stuck transaction", "err", err)
+				} else {
+					sent = append(sent, replacement)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			// On shutdown the transaction is left pending to be resolved on the next start
+			// as its submission window is still open.
+			if self.isDraining() {
+				level.Warn(self.logger).Log("msg", "shutting down before the transaction was mined", "tx", sent[len(sent)-1].Hash().String())
+				return nil, nil, ctx.Err()
+			}
+			if self.cfg.CancelOnClose {
+				self.cancel(auth, private)
+			}
+			return nil, nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// bump sends the transaction again with a higher gas price bounded by the max gas price.
+func (self *TransactorDefault) bump(
+	ctx context.Context,
+	auth *bind.TransactOpts,
+	contractCall func(*bind.TransactOpts) (*types.Transaction, error),
+	private bool,
+) (*types.Transaction, error) {
+	price, err := self.bumpedGasPrice(auth.GasPrice)
+	if err != nil {
+		return nil, err
+	}
+	prev := auth.GasPrice
+	auth.GasPrice = price
+	tx, err := self.send(ctx, auth, contractCall, private)
+	if err != nil {
+		auth.GasPrice = prev
+		return nil, errors.Wrap(err, "sending the replacement transaction")
+	}
+	level.Info(self.logger).Log("msg", "replaced the stuck transaction with a higher gas price", "tx", tx.Hash().String(), "gasPrice", price)
+	return tx, nil
+}
+
+// cancel replaces the pending transaction with an empty transfer to the same account.
+func (self *TransactorDefault) cancel(auth *bind.TransactOpts, private bool) {
+	// The submit context is already canceled so use a new one.
+	ctx, cncl := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cncl()
+
+	price, err := self.bumpedGasPrice(auth.GasPrice)
+	if err != nil {
+		level.Warn(self.logger).Log("msg", "can't cancel the pending transaction", "err", err)
+		return
+	}
+	tx := types.NewTransaction(auth.Nonce.Uint64(), self.account.Address, big.NewInt(0), 21000, price, nil)
+	tx, err = auth.Signer(self.account.Address, tx)
+	if err != nil {
+		level.Error(self.logger).Log("msg", "signing the cancel transaction", "err", err)
+		return
+	}
+
+	client := self.client
+	if private {
+		client = self.relay
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		// Most likely the pending transaction was just mined.
+		level.Warn(self.logger).Log("msg", "canceling the pending transaction", "nonce", auth.Nonce, "err", err)
+		return
+	}
+	self.record(txs.NewRecord(txs.TypeCancel, self.account.Address, tx))
+	level.Info(self.logger).Log("msg", "canceled the pending transaction as its submission window has closed", "nonce", auth.Nonce, "tx", tx.Hash().String())
+}
+
+// bumpedGasPrice returns the gas price for a replacement transaction.
+func (self *TransactorDefault) bumpedGasPrice(current *big.Int) (*big.Int, error) {
+	price := new(big.Int).Mul(current, big.NewInt(int64(100+self.cfg.BumpPercent)))
+	price.Div(price, big.NewInt(100))
+
+	max := self.maxGasPrice()
+	if price.Cmp(max) > 0 {
+		price = max
+	}
+	// The nodes accept a replacement only with a higher gas price.
+	if price.Cmp(current) <= 0 {
+		return nil, errors.Errorf("the gas price is already at the max:%v", max)
+	}
+	return price, nil
+}
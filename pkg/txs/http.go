@@ -0,0 +1,63 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package txs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultLimit is how many records to return when the request doesn't set a limit.
+const defaultLimit = 100
+
+type response struct {
+	Status string   `json:"status"`
+	Data   []Record `json:"data,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Handler serves the transaction history filtered by the type, account, status, since and limit query parameters.
+// The since parameter is a duration like 24h.
+// It only reads the history and the pending transactions are updated by the Resolver.
+func Handler(store *Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		filter := Filter{
+			Type:    Type(r.FormValue("type")),
+			Account: r.FormValue("account"),
+			Status:  Status(r.FormValue("status")),
+			Limit:   defaultLimit,
+		}
+		if since := r.FormValue("since"); since != "" {
+			d, err := time.ParseDuration(since)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(response{Status: "error", Error: "invalid since duration: " + err.Error()})
+				return
+			}
+			filter.Since = time.Now().Add(-d)
+		}
+		if limit := r.FormValue("limit"); limit != "" {
+			l, err := strconv.Atoi(limit)
+			if err != nil || l < 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(response{Status: "error", Error: "invalid limit: " + limit})
+				return
+			}
+			filter.Limit = l
+		}
+
+		records, err := store.List(filter)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Error: err.Error()})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response{Status: "success", Data: records})
+	}
+}
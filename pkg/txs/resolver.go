@@ -0,0 +1,57 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package txs
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+)
+
+// ResolveInterval is how often the resolver updates the pending transactions.
+const ResolveInterval = 30 * time.Second
+
+// Resolver updates the pending transactions of the history in the background
+// so that the readers of the history, like the API, don't write to it.
+type Resolver struct {
+	logger log.Logger
+	ctx    context.Context
+	close  context.CancelFunc
+	store  *Store
+	client Client
+	ethUSD PriceFunc
+}
+
+func NewResolver(logger log.Logger, ctx context.Context, store *Store, client Client, ethUSD PriceFunc) *Resolver {
+	ctx, close := context.WithCancel(ctx)
+	return &Resolver{
+		logger: log.With(logger, "component", ComponentName),
+		ctx:    ctx,
+		close:  close,
+		store:  store,
+		client: client,
+		ethUSD: ethUSD,
+	}
+}
+
+func (self *Resolver) Start() {
+	ticker := time.NewTicker(ResolveInterval)
+	defer ticker.Stop()
+	for {
+		if err := self.store.Resolve(self.ctx, self.client, self.ethUSD); err != nil {
+			level.Warn(self.logger).Log("msg", "resolving pending transactions", "err", err)
+		}
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (self *Resolver) Stop() {
+	self.close()
+}
@@ -0,0 +1,326 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package txs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/pkg/errors"
+)
+
+const ComponentName = "txs"
+
+// FileName is the name of the transaction history file in the db directory.
+const FileName = "txs.jsonl"
+
+// MaxMemoryRecords is how many transactions a store in memory keeps,
+// the oldest ones are dropped so a long running stateless miner doesn't grow forever.
+const MaxMemoryRecords = 10000
+
+// Type is the operation of a transaction.
+type Type string
+
+const (
+	TypeSubmit          Type = "submit"
+	TypeDeposit         Type = "deposit"
+	TypeRequestWithdraw Type = "requestWithdraw"
+	TypeWithdraw        Type = "withdraw"
+	TypeTransfer        Type = "transfer"
+	TypeApprove         Type = "approve"
+	TypeDispute         Type = "dispute"
+	TypeVote            Type = "vote"
+	TypeTally           Type = "tally"
+	TypeUnlockFee       Type = "unlockFee"
+	TypeGovVote         Type = "govVote"
+	TypeGovTally        Type = "govTally"
+	TypeCancel          Type = "cancel"
+)
+
+// Status is the state of a transaction.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusSuccess Status = "success"
+	StatusFailed  Status = "failed"
+	// StatusDropped is for transactions that were never mined,
+	// for example because these were replaced with a higher gas price or canceled.
+	StatusDropped Status = "dropped"
+)
+
+// Record is a single transaction sent by telliot.
+type Record struct {
+	Time     time.Time `json:"time"`
+	Type     Type      `json:"type"`
+	Account  string    `json:"account"`
+	Hash     string    `json:"hash"`
+	Nonce    uint64    `json:"nonce"`
+	GasPrice float64   `json:"gasPrice"` // In gwei.
+	GasUsed  uint64    `json:"gasUsed"`
+	Status   Status    `json:"status"`
+	CostETH  float64   `json:"costEth"`
+	CostUSD  float64   `json:"costUsd"`
+	CLI      bool      `json:"cli,omitempty"` // Sent manually with a cli command.
+}
+
+// NewRecord creates a record for a transaction that was just sent.
+func NewRecord(typ Type, account common.Address, tx *types.Transaction) Record {
+	gasPrice, _ := new(big.Float).Quo(new(big.Float).SetInt(tx.GasPrice()), big.NewFloat(params.GWei)).Float64()
+	return Record{
+		Time:     time.Now(),
+		Type:     typ,
+		Account:  account.Hex(),
+		Hash:     tx.Hash().Hex(),
+		Nonce:    tx.Nonce(),
+		GasPrice: gasPrice,
+		Status:   StatusPending,
+	}
+}
+
+// SetReceipt sets the status, gas used and cost from the transaction receipt.
+func (self *Record) SetReceipt(receipt *types.Receipt) {
+	self.Status = StatusSuccess
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		self.Status = StatusFailed
+	}
+	self.GasUsed = receipt.GasUsed
+	self.CostETH = float64(receipt.GasUsed) * self.GasPrice / 1e9
+}
+
+// Filter selects the records to list.
+// The empty fields match all records.
+type Filter struct {
+	Type    Type
+	Account string
+	Status  Status
+	Since   time.Time
+	Limit   int
+}
+
+func (self Filter) match(r Record) bool {
+	if self.Type != "" && self.Type != r.Type {
+		return false
+	}
+	if self.Account != "" && !strings.EqualFold(self.Account, r.Account) {
+		return false
+	}
+	if self.Status != "" && self.Status != r.Status {
+		return false
+	}
+	if !self.Since.IsZero() && r.Time.Before(self.Since) {
+		return false
+	}
+	return true
+}
+
+// Store is the transaction history.
+// It is a journal where every change of a transaction is appended as a new line
+// so that the miner and the cli commands can write to it at the same time.
+// The last line of a transaction is its current state.
+type Store struct {
+	path string
+	// memory keeps the current state of the transactions in mem instead of the file.
+	memory   bool
+	mem      []Record
+	memIndex map[string]int
+	mtx      sync.Mutex
+}
+
+// Open returns the store in the given directory.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, errors.Wrapf(err, "creating the db directory:%v", dir)
+	}
+	return &Store{path: filepath.Join(dir, FileName)}, nil
+}
+
+// OpenMemory returns a store that keeps the history only in memory
+// for a stateless miner that doesn't write to the disk.
+// The history is lost when the process exits and only the last MaxMemoryRecords transactions are kept.
+func OpenMemory() *Store {
+	return &Store{memory: true, memIndex: make(map[string]int)}
+}
+
+// Add appends a new or changed record.
+// A nil store doesn't record anything so the components can run without a history.
+func (self *Store) Add(r Record) error {
+	if self == nil {
+		return nil
+	}
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.memory {
+		self.addMemory(r)
+		return nil
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, "marshal record")
+	}
+	f, err := os.OpenFile(self.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return errors.Wrap(err, "open the history file")
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return errors.Wrap(err, "write to the history file")
+	}
+	return errors.Wrap(f.Close(), "close the history file")
+}
+
+// addMemory sets the current state of a transaction in memory
+// and drops the oldest transactions above MaxMemoryRecords.
+func (self *Store) addMemory(r Record) {
+	if i, ok := self.memIndex[r.Hash]; ok {
+		// Keep the time when the transaction was sent.
+		r.Time = self.mem[i].Time
+		self.mem[i] = r
+		return
+	}
+	self.memIndex[r.Hash] = len(self.mem)
+	self.mem = append(self.mem, r)
+	if len(self.mem) <= MaxMemoryRecords {
+		return
+	}
+	self.mem = append([]Record(nil), self.mem[len(self.mem)-MaxMemoryRecords:]...)
+	self.memIndex = make(map[string]int, len(self.mem))
+	for i, r := range self.mem {
+		self.memIndex[r.Hash] = i
+	}
+}
+
+// List returns the matching records, the most recent first.
+func (self *Store) List(filter Filter) ([]Record, error) {
+	records, err := self.all()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []Record{}
+	for _, r := range records {
+		if filter.match(r) {
+			matched = append(matched, r)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Time.After(matched[j].Time) })
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, nil
+}
+
+// all returns the current state of all transactions in the order these were sent.
+func (self *Store) all() ([]Record, error) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.memory {
+		return append([]Record(nil), self.mem...), nil
+	}
+	f, err := os.Open(self.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "open the history file")
+	}
+	defer f.Close()
+
+	var records []Record
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// Skip a line partially written when the process crashed.
+			continue
+		}
+		if i, ok := index[r.Hash]; ok {
+			// Keep the time when the transaction was sent.
+			r.Time = records[i].Time
+			records[i] = r
+			continue
+		}
+		index[r.Hash] = len(records)
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read the history file")
+	}
+	return records, nil
+}
+
+// Client is the part of the ethereum client needed to resolve the pending transactions.
+type Client interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// PriceFunc returns the ETH/USD price at the given time.
+type PriceFunc func(at time.Time) (float64, error)
+
+// Resolve updates the pending transactions that were mined or dropped since these were sent
+// and when ethUSD is not nil sets the USD cost of the mined transactions.
+func (self *Store) Resolve(ctx context.Context, client Client, ethUSD PriceFunc) error {
+	records, err := self.all()
+	if err != nil {
+		return err
+	}
+
+	nonces := make(map[string]uint64)
+	for _, r := range records {
+		changed := false
+		if r.Status == StatusPending {
+			receipt, err := client.TransactionReceipt(ctx, common.HexToHash(r.Hash))
+			if err != nil && err != ethereum.NotFound {
+				return errors.Wrapf(err, "getting the receipt of tx:%v", r.Hash)
+			}
+			if receipt != nil {
+				r.SetReceipt(receipt)
+				changed = true
+			} else {
+				nonce, ok := nonces[r.Account]
+				if !ok {
+					nonce, err = client.NonceAt(ctx, common.HexToAddress(r.Account), nil)
+					if err != nil {
+						return errors.Wrapf(err, "getting the nonce of account:%v", r.Account)
+					}
+					nonces[r.Account] = nonce
+				}
+				// Another transaction with the same nonce was mined.
+				if r.Nonce < nonce {
+					r.Status = StatusDropped
+					changed = true
+				}
+			}
+		}
+		if ethUSD != nil && r.CostETH > 0 && r.CostUSD == 0 {
+			if price, err := ethUSD(r.Time); err == nil {
+				r.CostUSD = math.Round(r.CostETH*price*100) / 100
+				changed = true
+			}
+		}
+		if changed {
+			if err := self.Add(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
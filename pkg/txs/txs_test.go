@@ -0,0 +1,81 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package txs
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txs")
+	testutil.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := Open(dir)
+	testutil.Ok(t, err)
+	testStore(t, store)
+
+	// The in memory store of a stateless miner behaves the same.
+	testStore(t, OpenMemory())
+}
+
+func testStore(t *testing.T, store *Store) {
+	now := time.Now()
+	add := func(r Record) {
+		t.Helper()
+		testutil.Ok(t, store.Add(r))
+	}
+	add(Record{Time: now.Add(-2 * time.Hour), Type: TypeSubmit, Account: "0xA", Hash: "0x1", Status: StatusPending})
+	add(Record{Time: now.Add(-time.Hour), Type: TypeDeposit, Account: "0xB", Hash: "0x2", Status: StatusPending})
+	add(Record{Time: now, Type: TypeSubmit, Account: "0xA", Hash: "0x3", Status: StatusPending})
+	// A later change of the first transaction keeps its send time.
+	add(Record{Time: now.Add(time.Hour), Type: TypeSubmit, Account: "0xA", Hash: "0x1", Status: StatusSuccess, GasUsed: 100})
+
+	records, err := store.List(Filter{})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 3, len(records))
+	testutil.Equals(t, "0x3", records[0].Hash)
+	testutil.Equals(t, "0x1", records[2].Hash)
+	testutil.Equals(t, StatusSuccess, records[2].Status)
+	testutil.Equals(t, uint64(100), records[2].GasUsed)
+	testutil.Assert(t, records[2].Time.Equal(now.Add(-2*time.Hour)), "the send time should be kept")
+
+	records, err = store.List(Filter{Type: TypeSubmit, Account: "0xa"})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 2, len(records))
+
+	records, err = store.List(Filter{Status: StatusPending, Since: now.Add(-90 * time.Minute)})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 2, len(records))
+
+	records, err = store.List(Filter{Limit: 1})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(records))
+	testutil.Equals(t, "0x3", records[0].Hash)
+}
+
+func TestMemoryLimit(t *testing.T) {
+	store := OpenMemory()
+	now := time.Now()
+	for i := 0; i < MaxMemoryRecords+10; i++ {
+		testutil.Ok(t, store.Add(Record{Time: now.Add(time.Duration(i) * time.Second), Hash: strconv.Itoa(i), Status: StatusPending}))
+	}
+	records, err := store.List(Filter{})
+	testutil.Ok(t, err)
+	testutil.Equals(t, MaxMemoryRecords, len(records))
+	testutil.Equals(t, "10", records[len(records)-1].Hash, "the oldest transactions should be dropped")
+
+	// The kept transactions can still be changed.
+	testutil.Ok(t, store.Add(Record{Time: now.Add(time.Hour), Hash: "10", Status: StatusSuccess}))
+	records, err = store.List(Filter{Status: StatusSuccess})
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(records))
+	testutil.Equals(t, now.Add(10*time.Second).Unix(), records[0].Time.Unix())
+}
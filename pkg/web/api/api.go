@@ -31,6 +31,9 @@ import (
 	"github.com/prometheus/prometheus/util/stats"
 )
 
+// Prefix is the path under which the latest API version is registered.
+const Prefix = "/api/" + LatestVersion
+
 type status string
 
 const (
@@ -41,15 +44,20 @@ const (
 type errorType string
 
 const (
-	errorTimeout     errorType = "timeout"
-	errorCanceled    errorType = "canceled"
-	errorExec        errorType = "execution"
-	errorBadData     errorType = "bad_data"
-	errorInternal    errorType = "internal"
-	errorUnavailable errorType = "unavailable"
-	errorNotFound    errorType = "not_found"
+	errorTimeout      errorType = "timeout"
+	errorCanceled     errorType = "canceled"
+	errorExec         errorType = "execution"
+	errorBadData      errorType = "bad_data"
+	errorInternal     errorType = "internal"
+	errorUnavailable  errorType = "unavailable"
+	errorNotFound     errorType = "not_found"
+	errorUnauthorized errorType = "unauthorized"
 )
 
+// maxPointsPerTimeseries limits the number of returned points per timeseries.
+// This is sufficient for 60s resolution for a week or 1h resolution for a year.
+const maxPointsPerTimeseries = 11000
+
 var (
 	LocalhostRepresentations = []string{"127.0.0.1", "localhost", "::1"}
 )
@@ -87,7 +95,14 @@ type API struct {
 	QueryEngine       *promql.Engine
 	now               func() time.Time
 	remoteReadHandler http.Handler
-	logger            log.Logger
+	// remoteWriteHandler is nil when the db is read only.
+	remoteWriteHandler http.Handler
+	logger             log.Logger
+	appendable         storage.Appendable
+	token              string
+	maxSeries          int
+	endpoints          []endpoint
+	router             *route.Router
 }
 
 func init() {
@@ -100,6 +115,8 @@ func New(
 	ctx context.Context,
 	qe *promql.Engine,
 	q storage.SampleAndChunkQueryable,
+	token string,
+	maxSeries int,
 ) *API {
 
 	configFunc := func() promConfig.Config { return promConfig.Config{} }
@@ -109,6 +126,14 @@ func New(
 		now:               time.Now,
 		logger:            logger,
 		remoteReadHandler: remote.NewReadHandler(logger, nil, q, configFunc, 5e7, 10, 1048576),
+		token:             token,
+		maxSeries:         maxSeries,
+	}
+
+	// Write endpoints are available only when using a local db.
+	if appendable, ok := q.(storage.Appendable); ok {
+		a.appendable = appendable
+		a.remoteWriteHandler = remote.NewWriteHandler(logger, appendable)
 	}
 
 	return a
@@ -121,44 +146,225 @@ func setUnavailStatusOnTSDBNotReady(r apiFuncResult) apiFuncResult {
 	return r
 }
 
-// Register the API's endpoints in the given router.
+// Register the API's endpoints in the given router
+// under the prefix of every supported API version.
 func (api *API) Register(r *route.Router) {
-	wrap := func(f apiFunc) http.HandlerFunc {
-		hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			result := setUnavailStatusOnTSDBNotReady(f(r))
-			if result.finalizer != nil {
-				defer result.finalizer()
-			}
-			if result.err != nil {
-				api.respondError(w, result.err, result.data)
-				return
-			}
-
-			if result.data != nil {
-				api.respond(w, result.data, result.warnings)
-				return
-			}
-			w.WriteHeader(http.StatusNoContent)
-		})
-		return httputil.CompressionHandler{
-			Handler: hf,
-		}.ServeHTTP
+	api.endpoints = []endpoint{
+		{
+			methods: []string{http.MethodGet, http.MethodPost},
+			path:    "/query",
+			summary: "Evaluate an instant PromQL query.",
+			params: []param{
+				queryParam("query", "string", "PromQL expression.", true),
+				queryParam("time", "string", "Evaluation timestamp as RFC3339 or unix timestamp. Defaults to now.", false),
+				paramTimeout,
+				paramStats,
+			},
+			fn: api.query,
+		},
+		{
+			methods: []string{http.MethodGet, http.MethodPost},
+			path:    "/query_range",
+			summary: "Evaluate a PromQL query over a range of time.",
+			params: []param{
+				queryParam("query", "string", "PromQL expression.", true),
+				queryParam("start", "string", "Start timestamp as RFC3339 or unix timestamp.", true),
+				queryParam("end", "string", "End timestamp as RFC3339 or unix timestamp.", true),
+				queryParam("step", "string", "Query resolution step width as a duration or a number of seconds.", true),
+				paramTimeout,
+				paramStats,
+			},
+			fn: api.queryRange,
+		},
+		{
+			methods: []string{http.MethodGet, http.MethodPost},
+			path:    "/labels",
+			summary: "List all label names.",
+			params:  []param{paramStart, paramEnd, paramMatch, paramLimit, paramOffset},
+			fn:      api.labelNames,
+		},
+		{
+			methods: []string{http.MethodGet},
+			path:    "/label/:name/values",
+			summary: "List all values of a label.",
+			params:  []param{pathParam("name", "Label name."), paramStart, paramEnd, paramMatch, paramLimit, paramOffset},
+			fn:      api.labelValues,
+		},
+		{
+			methods: []string{http.MethodGet, http.MethodPost},
+			path:    "/series",
+			summary: "List all series that match the selectors.",
+			params:  []param{queryParam("match[]", "string", "Series selector. Can be repeated.", true), paramStart, paramEnd, paramLimit, paramOffset},
+			fn:      api.series,
+		},
+		{
+			methods: []string{http.MethodGet},
+			path:    "/series/:symbol",
+			summary: "Values of a symbol averaged over each step.",
+			params: []param{
+				paramSymbol,
+				queryParam("start", "string", "Start timestamp as RFC3339 or unix timestamp. Defaults to 24h before the end.", false),
+				queryParam("end", "string", "End timestamp as RFC3339 or unix timestamp. Defaults to now.", false),
+				queryParam("step", "string", "Step width as a duration or a number of seconds. Defaults to a step that returns around 500 points.", false),
+				queryParam("source", "string", "Return only the values of this data source.", false),
+			},
+			fn: api.symbolSeries,
+		},
+		{
+			methods: []string{http.MethodGet},
+			path:    "/samples",
+			summary: "Raw values recorded from each data source ordered by series and time.",
+			params: []param{
+				queryParam("symbol", "string", "Return only the values of this symbol.", false),
+				queryParam("source", "string", "Return only the values of this data source.", false),
+				queryParam("start", "string", "Start timestamp as RFC3339 or unix timestamp. Defaults to 24h before the end.", false),
+				queryParam("end", "string", "End timestamp as RFC3339 or unix timestamp. Defaults to now.", false),
+				queryParam("limit", "integer", "Maximum number of samples to return. Defaults to 1000.", false),
+				paramOffset,
+			},
+			fn: api.samples,
+		},
+		{
+			methods: []string{http.MethodGet},
+			path:    "/export",
+			summary: "Export the values of a symbol as CSV.",
+			params: []param{
+				queryParam("symbol", "string", "Symbol in the format BASE_QUOTE, for example ETH_USD.", true),
+				queryParam("start", "string", "Start timestamp as RFC3339 or unix timestamp. Defaults to 24h before the end.", false),
+				queryParam("end", "string", "End timestamp as RFC3339 or unix timestamp. Defaults to now.", false),
+				queryParam("step", "string", "Export the values averaged over each step instead of the raw samples from every source.", false),
+				queryParam("format", "string", "Export format. Only csv is supported.", false),
+			},
+			handler: api.export,
+		},
+		{
+			methods: []string{http.MethodPost},
+			path:    "/read",
+			summary: "Prometheus remote read.",
+			handler: api.remoteRead,
+		},
+		{
+			methods: []string{http.MethodPost},
+			path:    "/write",
+			summary: "Prometheus remote write used by the index tracker shards to add their values.",
+			auth:    true,
+			handler: api.authorizedHandler(api.remoteWrite),
+		},
+		// Endpoints used by Grafana when telliot is added as a Prometheus datasource.
+		// The tsdb doesn't keep metric metadata or exemplars so these always return empty results.
+		{
+			methods: []string{http.MethodGet},
+			path:    "/metadata",
+			summary: "Metric metadata. Always empty.",
+			fn:      api.metricMetadata,
+		},
+		{
+			methods: []string{http.MethodGet, http.MethodPost},
+			path:    "/query_exemplars",
+			summary: "Exemplars for a query. Always empty.",
+			params:  []param{queryParam("query", "string", "PromQL expression.", true), paramStart, paramEnd},
+			fn:      api.queryExemplars,
+		},
+		{
+			methods: []string{http.MethodPost},
+			path:    "/manual/:symbol",
+			summary: "Add a manual value for a symbol.",
+			params: []param{
+				paramSymbol,
+				queryParam("value", "number", "The value.", true),
+				queryParam("timestamp", "string", "Timestamp of the value as RFC3339 or unix timestamp. Defaults to now.", false),
+				queryParam("interval", "string", "How often new values are expected. Defaults to 1h.", false),
+			},
+			auth: true,
+			fn:   api.authorized(api.manualValue),
+		},
+		{
+			methods: []string{http.MethodGet},
+			path:    "/log/level",
+			summary: "The current log level of every component.",
+			fn:      api.logLevels,
+		},
+		{
+			methods: []string{http.MethodPost},
+			path:    "/log/level/:component",
+			summary: "Change the log level of a component without a restart.",
+			params: []param{
+				pathParam("component", "Component name as listed by /log/level."),
+				queryParam("level", "string", "The new level - debug, info, warn or error.", true),
+				queryParam("duration", "string", "Revert to the configured level after this duration. Defaults to keeping the level until the next restart.", false),
+			},
+			auth: true,
+			fn:   api.authorized(api.setLogLevel),
+		},
+	}
+
+	api.router = r
+	for _, e := range api.endpoints {
+		api.register(e)
 	}
+}
 
-	r.Get("/query", wrap(api.query))
-	r.Post("/query", wrap(api.query))
-	r.Get("/query_range", wrap(api.queryRange))
-	r.Post("/query_range", wrap(api.queryRange))
+// Param is a query parameter of an endpoint added with AddHandler.
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+}
 
-	r.Get("/labels", wrap(api.labelNames))
-	r.Post("/labels", wrap(api.labelNames))
-	r.Get("/label/:name/values", wrap(api.labelValues))
+// AddHandler registers a GET endpoint of another component under the prefix of every supported API version
+// and adds it to the OpenAPI specification. It must be called after Register.
+func (api *API) AddHandler(path, summary string, handler http.HandlerFunc, params ...Param) {
+	e := endpoint{
+		methods: []string{http.MethodGet},
+		path:    path,
+		summary: summary,
+		handler: handler,
+	}
+	for _, p := range params {
+		e.params = append(e.params, queryParam(p.Name, p.Type, p.Description, false))
+	}
+	api.endpoints = append(api.endpoints, e)
+	api.register(e)
+}
 
-	r.Get("/series", wrap(api.series))
-	r.Post("/series", wrap(api.series))
+func (api *API) register(e endpoint) {
+	for _, v := range versions {
+		vr := api.router.WithPrefix("/api/" + v.name)
+		handler := e.handler
+		if e.fn != nil {
+			handler = api.wrap(v, e.path, e.fn)
+		}
+		handler = v.setHeaders(handler)
+		for _, method := range e.methods {
+			switch method {
+			case http.MethodGet:
+				vr.Get(e.path, handler)
+			case http.MethodPost:
+				vr.Post(e.path, handler)
+			}
+		}
+	}
+}
 
-	r.Post("/read", http.HandlerFunc(api.remoteRead))
+// wrap converts an apiFunc to a http handler that responds with the
+// shape expected by clients of the given API version.
+func (api *API) wrap(v version, path string, f apiFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result := setUnavailStatusOnTSDBNotReady(f(r))
+		if result.finalizer != nil {
+			defer result.finalizer()
+		}
+		if result.err != nil {
+			api.respondError(w, result.err, result.data)
+			return
+		}
 
+		if result.data != nil {
+			api.respond(w, v.convert(path, result.data), result.warnings)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
 }
 
 type queryData struct {
@@ -177,6 +383,14 @@ func (api *API) remoteRead(w http.ResponseWriter, r *http.Request) {
 	api.remoteReadHandler.ServeHTTP(w, r)
 }
 
+func (api *API) remoteWrite(w http.ResponseWriter, r *http.Request) {
+	if api.remoteWriteHandler == nil {
+		api.respondError(w, &apiError{errorUnavailable, errors.New("the db is read only")}, nil)
+		return
+	}
+	api.remoteWriteHandler.ServeHTTP(w, r)
+}
+
 func (api *API) query(r *http.Request) (result apiFuncResult) {
 	ts, err := parseTimeParam(r, "time", api.now())
 	if err != nil {
@@ -219,6 +433,9 @@ func (api *API) query(r *http.Request) (result apiFuncResult) {
 	if res.Err != nil {
 		return apiFuncResult{nil, returnAPIError(res.Err), res.Warnings, qry.Close}
 	}
+	if err := api.checkSeriesLimit(seriesCount(res.Value)); err != nil {
+		return apiFuncResult{nil, err, res.Warnings, qry.Close}
+	}
 
 	// Optional stats field in response if parameter "stats" is not empty.
 	var qs *stats.QueryStats
@@ -257,7 +474,7 @@ func (api *API) queryRange(r *http.Request) (result apiFuncResult) {
 
 	// For safety, limit the number of returned points per timeseries.
 	// This is sufficient for 60s resolution for a week or 1h resolution for a year.
-	if end.Sub(start)/step > 11000 {
+	if end.Sub(start)/step > maxPointsPerTimeseries {
 		err := errors.New("exceeded maximum resolution of 11,000 points per timeseries. Try decreasing the query resolution (?step=XX)")
 		return apiFuncResult{nil, &apiError{errorBadData, err}, nil, nil}
 	}
@@ -298,6 +515,9 @@ func (api *API) queryRange(r *http.Request) (result apiFuncResult) {
 	if res.Err != nil {
 		return apiFuncResult{nil, returnAPIError(res.Err), res.Warnings, qry.Close}
 	}
+	if err := api.checkSeriesLimit(seriesCount(res.Value)); err != nil {
+		return apiFuncResult{nil, err, res.Warnings, qry.Close}
+	}
 
 	// Optional stats field in response if parameter "stats" is not empty.
 	var qs *stats.QueryStats
@@ -312,6 +532,25 @@ func (api *API) queryRange(r *http.Request) (result apiFuncResult) {
 	}, nil, res.Warnings, qry.Close}
 }
 
+// checkSeriesLimit returns an error when the number of series
+// is above the configured limit to avoid huge responses.
+func (api *API) checkSeriesLimit(count int) *apiError {
+	if api.maxSeries > 0 && count > api.maxSeries {
+		return &apiError{errorBadData, errors.Errorf("query returned more than the maximum of %v series, try a more specific query", api.maxSeries)}
+	}
+	return nil
+}
+
+func seriesCount(v parser.Value) int {
+	switch v := v.(type) {
+	case promql.Vector:
+		return len(v)
+	case promql.Matrix:
+		return len(v)
+	}
+	return 1
+}
+
 func returnAPIError(err error) *apiError {
 	if err == nil {
 		return nil
@@ -330,6 +569,10 @@ func returnAPIError(err error) *apiError {
 }
 
 func (api *API) labelNames(r *http.Request) apiFuncResult {
+	p, res, ok := parsePage(r, 0)
+	if !ok {
+		return res
+	}
 	start, err := parseTimeParam(r, "start", minTime)
 	if err != nil {
 		return invalidParamError(err, "start")
@@ -393,7 +636,8 @@ func (api *API) labelNames(r *http.Request) apiFuncResult {
 	if names == nil {
 		names = []string{}
 	}
-	return apiFuncResult{names, nil, warnings, nil}
+	from, to := p.bounds(len(names))
+	return apiFuncResult{names[from:to], nil, warnings, nil}
 }
 
 func (api *API) labelValues(r *http.Request) (result apiFuncResult) {
@@ -403,6 +647,10 @@ func (api *API) labelValues(r *http.Request) (result apiFuncResult) {
 	if !model.LabelNameRE.MatchString(name) {
 		return apiFuncResult{nil, &apiError{errorBadData, errors.Errorf("invalid label name: %q", name)}, nil, nil}
 	}
+	p, res, ok := parsePage(r, 0)
+	if !ok {
+		return res
+	}
 
 	start, err := parseTimeParam(r, "start", minTime)
 	if err != nil {
@@ -469,7 +717,8 @@ func (api *API) labelValues(r *http.Request) (result apiFuncResult) {
 
 	sort.Strings(vals)
 
-	return apiFuncResult{vals, nil, warnings, closer}
+	from, to := p.bounds(len(vals))
+	return apiFuncResult{vals[from:to], nil, warnings, closer}
 }
 
 var (
@@ -487,6 +736,10 @@ func (api *API) series(r *http.Request) (result apiFuncResult) {
 	if len(r.Form["match[]"]) == 0 {
 		return apiFuncResult{nil, &apiError{errorBadData, errors.New("no match[] parameter provided")}, nil, nil}
 	}
+	p, res, ok := parsePage(r, 0)
+	if !ok {
+		return res
+	}
 
 	start, err := parseTimeParam(r, "start", minTime)
 	if err != nil {
@@ -533,8 +786,16 @@ func (api *API) series(r *http.Request) (result apiFuncResult) {
 
 	set := storage.NewMergeSeriesSet(sets, storage.ChainedSeriesMerge)
 	metrics := []labels.Labels{}
-	for set.Next() {
+	var count int
+	for !p.done(count) && set.Next() {
+		count++
+		if count <= p.offset {
+			continue
+		}
 		metrics = append(metrics, set.At().Labels())
+		if err := api.checkSeriesLimit(len(metrics)); err != nil {
+			return apiFuncResult{nil, err, set.Warnings(), closer}
+		}
 	}
 
 	warnings := set.Warnings()
@@ -545,6 +806,17 @@ func (api *API) series(r *http.Request) (result apiFuncResult) {
 	return apiFuncResult{metrics, nil, warnings, closer}
 }
 
+func (api *API) metricMetadata(r *http.Request) apiFuncResult {
+	return apiFuncResult{struct{}{}, nil, nil, nil}
+}
+
+func (api *API) queryExemplars(r *http.Request) apiFuncResult {
+	if _, err := parser.ParseExpr(r.FormValue("query")); err != nil {
+		return invalidParamError(err, "query")
+	}
+	return apiFuncResult{[]interface{}{}, nil, nil, nil}
+}
+
 // GlobalURLOptions contains fields used for deriving the global URL for local targets.
 type GlobalURLOptions struct {
 	ListenAddress string
@@ -604,6 +876,10 @@ func (api *API) respondError(w http.ResponseWriter, apiErr *apiError, data inter
 		code = http.StatusInternalServerError
 	case errorNotFound:
 		code = http.StatusNotFound
+	case errorUnauthorized:
+		code = http.StatusUnauthorized
+	case errorUnavailable:
+		code = http.StatusServiceUnavailable
 	default:
 		code = http.StatusInternalServerError
 	}
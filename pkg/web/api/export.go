@@ -0,0 +1,161 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/tellor-io/telliot/pkg/format"
+)
+
+// export streams the values of a symbol as CSV.
+// Without a step all raw samples from every data source are exported
+// and with a step the values are averaged the same way as the series endpoint.
+func (api *API) export(w http.ResponseWriter, r *http.Request) {
+	fail := func(err *apiError) {
+		api.respondError(w, err, nil)
+	}
+
+	if f := r.FormValue("format"); f != "" && f != "csv" {
+		fail(&apiError{errorBadData, errors.Errorf("unsupported format:%v, only csv is supported", f)})
+		return
+	}
+
+	symbol := r.FormValue("symbol")
+	if !symbolRegexp.MatchString(symbol) {
+		fail(&apiError{errorBadData, errors.Errorf("symbol should be in the format BASE/QUOTE or BASE_QUOTE got:%q", symbol)})
+		return
+	}
+	symbol = format.SanitizeMetricName(symbol)
+
+	end, err := parseTimeParam(r, "end", api.now())
+	if err != nil {
+		fail(&apiError{errorBadData, errors.Wrap(err, "invalid parameter \"end\"")})
+		return
+	}
+	start, err := parseTimeParam(r, "start", end.Add(-defaultSeriesRange))
+	if err != nil {
+		fail(&apiError{errorBadData, errors.Wrap(err, "invalid parameter \"start\"")})
+		return
+	}
+	if end.Before(start) {
+		fail(&apiError{errorBadData, errors.New("end timestamp must not be before start time")})
+		return
+	}
+
+	if s := r.FormValue("step"); s != "" {
+		step, err := parseDuration(s)
+		if err != nil || step < time.Second {
+			fail(&apiError{errorBadData, errors.Errorf("invalid parameter \"step\", it should be at least 1s got:%v", s)})
+			return
+		}
+		if end.Sub(start)/step > maxPointsPerTimeseries {
+			fail(&apiError{errorBadData, errors.Errorf("exceeded maximum resolution of %v points per timeseries. Try increasing the step", maxPointsPerTimeseries)})
+			return
+		}
+		api.exportAggregated(w, r, symbol, start, end, step)
+		return
+	}
+	api.exportRaw(w, r, symbol, start, end)
+}
+
+func (api *API) exportRaw(w http.ResponseWriter, r *http.Request, symbol string, start, end time.Time) {
+	mint, maxt := timestamp.FromTime(start), timestamp.FromTime(end)
+	q, err := api.Queryable.Querier(r.Context(), mint, maxt)
+	if err != nil {
+		api.respondError(w, &apiError{errorExec, err}, nil)
+		return
+	}
+	defer q.Close()
+
+	set := q.Select(true, &storage.SelectHints{Start: mint, End: maxt},
+		labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, indexValueMetricName),
+		labels.MustNewMatcher(labels.MatchEqual, "symbol", symbol),
+	)
+
+	cw := startCSV(w, symbol, start, end, "timestamp", "symbol", "source", "value")
+	for set.Next() {
+		series := set.At()
+		source := series.Labels().Get("source")
+		it := series.Iterator()
+		for it.Next() {
+			t, v := it.At()
+			if t < mint || t > maxt {
+				continue
+			}
+			if err := cw.Write([]string{formatCSVTime(t), symbol, source, formatCSVValue(v)}); err != nil {
+				level.Error(api.logger).Log("msg", "writing csv export", "err", err)
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			level.Error(api.logger).Log("msg", "reading samples for the csv export", "err", err)
+			return
+		}
+	}
+	if err := set.Err(); err != nil {
+		level.Error(api.logger).Log("msg", "selecting series for the csv export", "err", err)
+	}
+	cw.Flush()
+}
+
+func (api *API) exportAggregated(w http.ResponseWriter, r *http.Request, symbol string, start, end time.Time, step time.Duration) {
+	qry, err := api.QueryEngine.NewRangeQuery(
+		api.Queryable,
+		`avg(avg_over_time(`+indexValueMetricName+`{symbol="`+symbol+`"}[`+strconv.FormatInt(int64(step/time.Second), 10)+`s]))`,
+		start,
+		end,
+		step,
+	)
+	if err != nil {
+		api.respondError(w, &apiError{errorInternal, err}, nil)
+		return
+	}
+	defer qry.Close()
+
+	res := qry.Exec(r.Context())
+	if res.Err != nil {
+		api.respondError(w, returnAPIError(res.Err), nil)
+		return
+	}
+
+	cw := startCSV(w, symbol, start, end, "timestamp", "symbol", "value")
+	for _, series := range res.Value.(promql.Matrix) {
+		for _, p := range series.Points {
+			if err := cw.Write([]string{formatCSVTime(p.T), symbol, formatCSVValue(p.V)}); err != nil {
+				level.Error(api.logger).Log("msg", "writing csv export", "err", err)
+				return
+			}
+		}
+	}
+	cw.Flush()
+}
+
+func startCSV(w http.ResponseWriter, symbol string, start, end time.Time, header ...string) *csv.Writer {
+	filename := symbol + "_" + start.UTC().Format("20060102T150405") + "_" + end.UTC().Format("20060102T150405") + ".csv"
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write(header)
+	return cw
+}
+
+func formatCSVTime(t int64) string {
+	return timestamp.Time(t).UTC().Format(time.RFC3339Nano)
+}
+
+func formatCSVValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/route"
+	"github.com/tellor-io/telliot/pkg/logging"
+)
+
+// logLevels returns the current log level of every component.
+func (api *API) logLevels(r *http.Request) apiFuncResult {
+	return apiFuncResult{logging.Levels(), nil, nil, nil}
+}
+
+// setLogLevel changes the log level of a component without a restart.
+// With a duration the level reverts to the configured one once it passes.
+func (api *API) setLogLevel(r *http.Request) apiFuncResult {
+	component := route.Param(r.Context(), "component")
+	lvl := r.FormValue("level")
+
+	var revertAfter time.Duration
+	if d := r.FormValue("duration"); d != "" {
+		var err error
+		revertAfter, err = parseDuration(d)
+		if err != nil {
+			return invalidParamError(err, "duration")
+		}
+	}
+
+	if _, ok := logging.Levels()[component]; !ok {
+		return apiFuncResult{nil, &apiError{errorNotFound, errors.Errorf("unknown component:%v", component)}, nil, nil}
+	}
+	if err := logging.SetLevel(component, lvl, revertAfter); err != nil {
+		return invalidParamError(err, "level")
+	}
+	level.Info(api.logger).Log("msg", "log level changed", "logComponent", component, "level", lvl, "duration", revertAfter)
+
+	return apiFuncResult{logging.Levels(), nil, nil, nil}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"crypto/subtle"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/route"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/tellor-io/telliot/pkg/format"
+)
+
+const (
+	// ManualSource is the source label of all manually added values.
+	ManualSource = "manual"
+	// defaultManualInterval is used when the request doesn't set how long the value is expected to remain valid.
+	defaultManualInterval = time.Hour
+	// maxClockSkew is how far in the future a manual value timestamp is allowed to be.
+	maxClockSkew = time.Minute
+
+	// The index tracker metric names.
+	// The index package can't be imported here as it already imports the web package.
+	indexValueMetricName    = "indexTracker_value"
+	indexIntervalMetricName = "indexTracker_interval"
+)
+
+var symbolRegexp = regexp.MustCompile(`^[A-Za-z0-9]+[/_][A-Za-z0-9]+$`)
+
+// authorized rejects requests without a valid bearer token.
+// When no token is configured all requests are rejected
+// so that write endpoints are disabled by default.
+func (api *API) authorized(f apiFunc) apiFunc {
+	return func(r *http.Request) apiFuncResult {
+		if err := api.checkToken(r); err != nil {
+			return apiFuncResult{nil, err, nil, nil}
+		}
+		return f(r)
+	}
+}
+
+// authorizedHandler is like authorized for the endpoints with custom responses.
+func (api *API) authorizedHandler(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := api.checkToken(r); err != nil {
+			api.respondError(w, err, nil)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (api *API) checkToken(r *http.Request) *apiError {
+	if api.token == "" {
+		return &apiError{errorUnauthorized, errors.New("endpoint disabled, no api token is configured")}
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(api.token)) != 1 {
+		return &apiError{errorUnauthorized, errors.New("invalid or missing bearer token")}
+	}
+	return nil
+}
+
+type manualData struct {
+	Symbol    string  `json:"symbol"`
+	Value     float64 `json:"value"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+// manualValue appends a manually provided value to the db.
+// The value is recorded as an index tracker value with a "manual" source
+// so the aggregator uses it the same way as all other data sources.
+func (api *API) manualValue(r *http.Request) (result apiFuncResult) {
+	if api.appendable == nil {
+		return apiFuncResult{nil, &apiError{errorUnavailable, errors.New("the db is read only")}, nil, nil}
+	}
+
+	symbol := route.Param(r.Context(), "symbol")
+	if !symbolRegexp.MatchString(symbol) {
+		return invalidParamError(errors.Errorf("symbol should be in the format BASE/QUOTE or BASE_QUOTE got:%v", symbol), "symbol")
+	}
+
+	value, err := strconv.ParseFloat(r.FormValue("value"), 64)
+	if err != nil {
+		return invalidParamError(err, "value")
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		return invalidParamError(errors.Errorf("value must be a positive number got:%v", value), "value")
+	}
+
+	now := api.now()
+	ts, err := parseTimeParam(r, "timestamp", now)
+	if err != nil {
+		return invalidParamError(err, "timestamp")
+	}
+	if ts.After(now.Add(maxClockSkew)) {
+		return invalidParamError(errors.Errorf("timestamp is in the future:%v", ts), "timestamp")
+	}
+
+	interval := defaultManualInterval
+	if i := r.FormValue("interval"); i != "" {
+		interval, err = parseDuration(i)
+		if err != nil {
+			return invalidParamError(err, "interval")
+		}
+		if interval <= 0 {
+			return invalidParamError(errors.New("zero or negative interval is not accepted"), "interval")
+		}
+	}
+
+	symbol = format.SanitizeMetricName(symbol)
+	t := timestamp.FromTime(ts)
+
+	appender := api.appendable.Appender(r.Context())
+	defer func() { // An appender always needs to be committed or rolled back.
+		if result.err != nil {
+			if err := appender.Rollback(); err != nil {
+				level.Error(api.logger).Log("msg", "db rollback failed", "err", err)
+			}
+			return
+		}
+		if err := appender.Commit(); err != nil {
+			result = apiFuncResult{nil, &apiError{errorInternal, errors.Wrap(err, "db append commit failed")}, nil, nil}
+		}
+	}()
+
+	for _, sample := range []struct {
+		name  string
+		value float64
+	}{
+		{indexIntervalMetricName, float64(interval)},
+		{indexValueMetricName, value},
+	} {
+		lbls := labels.Labels{
+			labels.Label{Name: "__name__", Value: sample.name},
+			labels.Label{Name: "source", Value: ManualSource},
+			labels.Label{Name: "domain", Value: ManualSource},
+			labels.Label{Name: "symbol", Value: symbol},
+		}
+		sort.Sort(lbls) // This is important! The labels need to be sorted to avoid creating the same series with duplicate reference.
+
+		if _, err := appender.Append(0, lbls, t, sample.value); err != nil {
+			return apiFuncResult{nil, &apiError{errorBadData, errors.Wrap(err, "append value to the db")}, nil, nil}
+		}
+	}
+
+	level.Info(api.logger).Log("msg", "added manual value", "symbol", symbol, "value", value, "timestamp", ts)
+
+	return apiFuncResult{&manualData{
+		Symbol:    symbol,
+		Value:     value,
+		Timestamp: ts.Unix(),
+	}, nil, nil, nil}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/common/route"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestAuthorized(t *testing.T) {
+	for name, tc := range map[string]struct {
+		token  string
+		header string
+		code   int
+	}{
+		"no configured token":                     {token: "", header: "", code: http.StatusUnauthorized},
+		"no configured token with an empty token": {token: "", header: "Bearer ", code: http.StatusUnauthorized},
+		"missing token":                           {token: "secret", header: "", code: http.StatusUnauthorized},
+		"wrong token":                             {token: "secret", header: "Bearer wrong", code: http.StatusUnauthorized},
+		// The db is read only in the test so an authorized request fails after the check.
+		"valid token": {token: "secret", header: "Bearer secret", code: http.StatusServiceUnavailable},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := &API{logger: log.NewNopLogger(), token: tc.token}
+			router := route.New()
+			api.Register(router)
+
+			// The write endpoint checks the token with authorizedHandler.
+			for _, path := range []string{"/manual/ETH_USD?value=1", "/write"} {
+				req := httptest.NewRequest("POST", Prefix+path, nil)
+				if tc.header != "" {
+					req.Header.Set("Authorization", tc.header)
+				}
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				testutil.Equals(t, tc.code, rec.Code, path)
+			}
+		})
+	}
+}
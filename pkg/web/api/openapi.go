@@ -0,0 +1,202 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-kit/kit/log/level"
+)
+
+// endpoint describes a single API route.
+// The same definitions are used to register the routes
+// and to generate the OpenAPI specification so both are always in sync.
+type endpoint struct {
+	methods []string
+	path    string
+	summary string
+	params  []param
+	auth    bool
+	// fn is the handler of endpoints that respond with the standard API response.
+	fn apiFunc
+	// handler is used instead of fn for endpoints with custom responses.
+	handler http.HandlerFunc
+}
+
+type param struct {
+	name        string
+	in          string
+	typ         string
+	description string
+	required    bool
+}
+
+func pathParam(name, description string) param {
+	return param{name: name, in: "path", typ: "string", description: description, required: true}
+}
+
+func queryParam(name, typ, description string, required bool) param {
+	return param{name: name, in: "query", typ: typ, description: description, required: required}
+}
+
+var (
+	paramStart   = queryParam("start", "string", "Start timestamp as RFC3339 or unix timestamp.", false)
+	paramEnd     = queryParam("end", "string", "End timestamp as RFC3339 or unix timestamp.", false)
+	paramMatch   = queryParam("match[]", "string", "Series selector. Can be repeated.", false)
+	paramTimeout = queryParam("timeout", "string", "Evaluation timeout.", false)
+	paramStats   = queryParam("stats", "string", "Include query statistics when not empty.", false)
+	paramSymbol  = pathParam("symbol", "Symbol in the format BASE_QUOTE, for example ETH_USD.")
+)
+
+type openAPIDoc struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       openAPIInfo                     `json:"info"`
+	Servers    []openAPIServer                 `json:"servers"`
+	Paths      map[string]map[string]openAPIOp `json:"paths"`
+	Components openAPIComponents               `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIOp struct {
+	Summary     string                     `json:"summary"`
+	OperationID string                     `json:"operationId"`
+	Parameters  []openAPIParam             `json:"parameters,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type openAPIParam struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required"`
+	Schema      openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref        string                   `json:"$ref,omitempty"`
+	Type       string                   `json:"type,omitempty"`
+	Enum       []string                 `json:"enum,omitempty"`
+	Items      *openAPISchema           `json:"items,omitempty"`
+	Properties map[string]openAPISchema `json:"properties,omitempty"`
+	Required   []string                 `json:"required,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]openAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+var routeParamRegexp = regexp.MustCompile(`:([a-zA-Z_]+)`)
+
+// openAPI generates the OpenAPI specification for all registered endpoints.
+func (api *API) openAPI() *openAPIDoc {
+	ref := func(name string) openAPIMediaType {
+		return openAPIMediaType{Schema: openAPISchema{Ref: "#/components/schemas/" + name}}
+	}
+
+	doc := &openAPIDoc{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:   "Telliot API",
+			Version: "1",
+		},
+		Servers: []openAPIServer{{URL: Prefix}},
+		Paths:   make(map[string]map[string]openAPIOp),
+		Components: openAPIComponents{
+			Schemas: map[string]openAPISchema{
+				"Response": {
+					Type:     "object",
+					Required: []string{"status"},
+					Properties: map[string]openAPISchema{
+						"status":    {Type: "string", Enum: []string{string(statusSuccess), string(statusError)}},
+						"data":      {},
+						"errorType": {Type: "string"},
+						"error":     {Type: "string"},
+						"warnings":  {Type: "array", Items: &openAPISchema{Type: "string"}},
+					},
+				},
+			},
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+
+	for _, e := range api.endpoints {
+		path := routeParamRegexp.ReplaceAllString(e.path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]openAPIOp)
+		}
+		for _, method := range e.methods {
+			op := openAPIOp{
+				Summary:     e.summary,
+				OperationID: strings.ToLower(method) + operationName(e.path),
+				Responses: map[string]openAPIResponse{
+					"200":     {Description: "Success", Content: map[string]openAPIMediaType{"application/json": ref("Response")}},
+					"default": {Description: "Error", Content: map[string]openAPIMediaType{"application/json": ref("Response")}},
+				},
+			}
+			for _, p := range e.params {
+				op.Parameters = append(op.Parameters, openAPIParam{
+					Name:        p.name,
+					In:          p.in,
+					Description: p.description,
+					Required:    p.required,
+					Schema:      openAPISchema{Type: p.typ},
+				})
+			}
+			if e.auth {
+				op.Security = []map[string][]string{{"bearerAuth": {}}}
+			}
+			doc.Paths[path][strings.ToLower(method)] = op
+		}
+	}
+	return doc
+}
+
+// operationName converts a route path to a camel case name
+// for example /label/:name/values becomes LabelNameValues.
+func operationName(path string) string {
+	var name string
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == ':' || r == '_' }) {
+		name += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return name
+}
+
+// ServeOpenAPI responds with the OpenAPI specification of the API.
+func (api *API) ServeOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(api.openAPI()); err != nil {
+		level.Error(api.logger).Log("msg", "error writing the openapi spec", "err", err)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/common/route"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestAddHandler(t *testing.T) {
+	api := &API{logger: log.NewNopLogger()}
+	router := route.New()
+	api.Register(router)
+	api.AddHandler("/txs", "The transaction history.", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, Param{Name: "limit", Type: "integer", Description: "Maximum number of transactions."})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", Prefix+"/txs", nil))
+	testutil.Equals(t, http.StatusOK, rec.Code)
+	testutil.Equals(t, LatestVersion, rec.Header().Get("X-Telliot-Api-Version"))
+
+	op, ok := api.openAPI().Paths["/txs"]["get"]
+	testutil.Assert(t, ok, "the added endpoint should be in the OpenAPI specification")
+	testutil.Equals(t, "The transaction history.", op.Summary)
+	testutil.Equals(t, 1, len(op.Parameters))
+	testutil.Equals(t, "limit", op.Parameters[0].Name)
+}
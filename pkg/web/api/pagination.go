@@ -0,0 +1,69 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// maxPageLimit is the maximum number of items a single page can return.
+const maxPageLimit = 10000
+
+var (
+	paramLimit  = queryParam("limit", "integer", "Maximum number of items to return.", false)
+	paramOffset = queryParam("offset", "integer", "Number of items to skip.", false)
+)
+
+type page struct {
+	offset int
+	limit  int
+}
+
+// parsePage reads the limit and offset parameters.
+// A zero limit means that all items are returned.
+func parsePage(r *http.Request, defaultLimit int) (page, apiFuncResult, bool) {
+	p := page{limit: defaultLimit}
+	if v := r.FormValue("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return p, invalidParamError(err, "limit"), false
+		}
+		if limit <= 0 || limit > maxPageLimit {
+			return p, invalidParamError(errors.Errorf("limit should be between 1 and %v", maxPageLimit), "limit"), false
+		}
+		p.limit = limit
+	}
+	if v := r.FormValue("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return p, invalidParamError(err, "offset"), false
+		}
+		if offset < 0 {
+			return p, invalidParamError(errors.New("negative offset is not accepted"), "offset"), false
+		}
+		p.offset = offset
+	}
+	return p, apiFuncResult{}, true
+}
+
+// bounds returns the slice indexes of the page for a list with the given length.
+func (p page) bounds(length int) (int, int) {
+	from := p.offset
+	if from > length {
+		from = length
+	}
+	to := length
+	if p.limit > 0 && from+p.limit < to {
+		to = from + p.limit
+	}
+	return from, to
+}
+
+// done returns true when the page already contains all requested items.
+func (p page) done(count int) bool {
+	return p.limit > 0 && count >= p.offset+p.limit
+}
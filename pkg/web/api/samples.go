@@ -0,0 +1,117 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/tellor-io/telliot/pkg/format"
+)
+
+// defaultSamplesLimit is the page size when the request doesn't set a limit.
+const defaultSamplesLimit = 1000
+
+type sample struct {
+	Symbol    string  `json:"symbol"`
+	Source    string  `json:"source"`
+	Timestamp float64 `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+type samplesData struct {
+	Samples []sample `json:"samples"`
+	Offset  int      `json:"offset"`
+	Limit   int      `json:"limit"`
+	// Next is the offset of the next page and is not set for the last page.
+	Next *int `json:"next,omitempty"`
+}
+
+// samples returns the raw values recorded from each data source.
+// The samples are ordered by series labels and then by time
+// and are returned in pages to avoid huge responses.
+func (api *API) samples(r *http.Request) (result apiFuncResult) {
+	p, res, ok := parsePage(r, defaultSamplesLimit)
+	if !ok {
+		return res
+	}
+
+	end, err := parseTimeParam(r, "end", api.now())
+	if err != nil {
+		return invalidParamError(err, "end")
+	}
+	start, err := parseTimeParam(r, "start", end.Add(-defaultSeriesRange))
+	if err != nil {
+		return invalidParamError(err, "start")
+	}
+	if end.Before(start) {
+		return invalidParamError(errors.New("end timestamp must not be before start time"), "end")
+	}
+
+	matchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, indexValueMetricName)}
+	if symbol := r.FormValue("symbol"); symbol != "" {
+		matchers = append(matchers, labels.MustNewMatcher(labels.MatchEqual, "symbol", format.SanitizeMetricName(symbol)))
+	}
+	if source := r.FormValue("source"); source != "" {
+		matchers = append(matchers, labels.MustNewMatcher(labels.MatchEqual, "source", source))
+	}
+
+	mint, maxt := timestamp.FromTime(start), timestamp.FromTime(end)
+	q, err := api.Queryable.Querier(r.Context(), mint, maxt)
+	if err != nil {
+		return apiFuncResult{nil, &apiError{errorExec, err}, nil, nil}
+	}
+	defer q.Close()
+
+	data := &samplesData{
+		Samples: []sample{},
+		Offset:  p.offset,
+		Limit:   p.limit,
+	}
+
+	var (
+		count    int
+		warnings storage.Warnings
+	)
+	set := q.Select(true, &storage.SelectHints{Start: mint, End: maxt}, matchers...)
+	for data.Next == nil && set.Next() {
+		series := set.At()
+		lbls := series.Labels()
+		it := series.Iterator()
+		for it.Next() {
+			t, v := it.At()
+			if t < mint || t > maxt {
+				continue
+			}
+			count++
+			if count <= p.offset {
+				continue
+			}
+			if p.done(count - 1) {
+				// There is at least one more sample after this page.
+				next := p.offset + p.limit
+				data.Next = &next
+				break
+			}
+			data.Samples = append(data.Samples, sample{
+				Symbol:    lbls.Get("symbol"),
+				Source:    lbls.Get("source"),
+				Timestamp: float64(t) / 1000,
+				Value:     v,
+			})
+		}
+		if err := it.Err(); err != nil {
+			return apiFuncResult{nil, &apiError{errorExec, err}, nil, nil}
+		}
+	}
+	warnings = append(warnings, set.Warnings()...)
+	if err := set.Err(); err != nil {
+		return apiFuncResult{nil, &apiError{errorExec, err}, warnings, nil}
+	}
+
+	return apiFuncResult{data, nil, warnings, nil}
+}
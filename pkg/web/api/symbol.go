@@ -0,0 +1,119 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/route"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/util/httputil"
+	"github.com/tellor-io/telliot/pkg/format"
+)
+
+const (
+	// defaultSeriesRange is used when the request doesn't set a start time.
+	defaultSeriesRange = 24 * time.Hour
+	// defaultSeriesPoints is the number of points returned when the request doesn't set a step.
+	defaultSeriesPoints = 500
+)
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type symbolSeriesData struct {
+	Symbol string         `json:"symbol"`
+	Source string         `json:"source,omitempty"`
+	Step   float64        `json:"step"`
+	Points []promql.Point `json:"points"`
+}
+
+// symbolSeries returns the values of a symbol downsampled to the requested step.
+// Every point is the average of all samples from all sources within its step
+// so it is suitable for charting long periods without downloading all raw samples.
+func (api *API) symbolSeries(r *http.Request) (result apiFuncResult) {
+	symbol := route.Param(r.Context(), "symbol")
+	if !symbolRegexp.MatchString(symbol) {
+		return invalidParamError(errors.Errorf("symbol should be in the format BASE_QUOTE got:%v", symbol), "symbol")
+	}
+	symbol = format.SanitizeMetricName(symbol)
+
+	end, err := parseTimeParam(r, "end", api.now())
+	if err != nil {
+		return invalidParamError(err, "end")
+	}
+	start, err := parseTimeParam(r, "start", end.Add(-defaultSeriesRange))
+	if err != nil {
+		return invalidParamError(err, "start")
+	}
+	if end.Before(start) {
+		return invalidParamError(errors.New("end timestamp must not be before start time"), "end")
+	}
+
+	step := end.Sub(start) / defaultSeriesPoints
+	if s := r.FormValue("step"); s != "" {
+		step, err = parseDuration(s)
+		if err != nil {
+			return invalidParamError(err, "step")
+		}
+		if step <= 0 {
+			return invalidParamError(errors.New("zero or negative step widths are not accepted"), "step")
+		}
+	}
+	step = step.Truncate(time.Second)
+	if step < time.Second {
+		step = time.Second
+	}
+	if end.Sub(start)/step > maxPointsPerTimeseries {
+		err := errors.Errorf("exceeded maximum resolution of %v points per timeseries. Try increasing the step", maxPointsPerTimeseries)
+		return apiFuncResult{nil, &apiError{errorBadData, err}, nil, nil}
+	}
+
+	// Round up the end time so that the last step includes the most recent samples.
+	if rem := end.Sub(start) % step; rem != 0 {
+		end = end.Add(step - rem)
+	}
+
+	selector := `symbol="` + symbol + `"`
+	source := r.FormValue("source")
+	if source != "" {
+		selector += `,source="` + labelValueEscaper.Replace(source) + `"`
+	}
+	window := strconv.FormatInt(int64(step/time.Second), 10) + "s"
+
+	qry, err := api.QueryEngine.NewRangeQuery(
+		api.Queryable,
+		`avg(avg_over_time(`+indexValueMetricName+`{`+selector+`}[`+window+`]))`,
+		start,
+		end,
+		step,
+	)
+	if err != nil {
+		return apiFuncResult{nil, &apiError{errorInternal, err}, nil, nil}
+	}
+	defer func() {
+		if result.finalizer == nil {
+			qry.Close()
+		}
+	}()
+
+	res := qry.Exec(httputil.ContextFromRequest(r.Context(), r))
+	if res.Err != nil {
+		return apiFuncResult{nil, returnAPIError(res.Err), res.Warnings, qry.Close}
+	}
+
+	data := &symbolSeriesData{
+		Symbol: symbol,
+		Source: source,
+		Step:   step.Seconds(),
+		Points: []promql.Point{},
+	}
+	if matrix := res.Value.(promql.Matrix); len(matrix) > 0 {
+		data.Points = matrix[0].Points
+	}
+	return apiFuncResult{data, nil, res.Warnings, qry.Close}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"net/http"
+)
+
+// LatestVersion is the current API version.
+// The handlers always produce responses in the shape of this version.
+const LatestVersion = "v1"
+
+// version is a supported API version.
+//
+// When a response shape changes in a way that breaks clients
+// a new version is added with the new shape as the latest one
+// and a compat conversion is added to the previous versions
+// so these keep serving the old shape until they are removed.
+//
+// For example when the samples response changes in v2 the versions become:
+//
+//	{name: "v1", deprecated: true, compat: []compat{{path: "/samples", convert: samplesV2ToV1}}},
+//	{name: "v2"},
+type version struct {
+	name string
+	// deprecated versions still work but responses include
+	// headers pointing clients to the latest version.
+	deprecated bool
+	compat     []compat
+}
+
+// compat converts the response data of an endpoint from
+// the latest version to the shape of an older version.
+type compat struct {
+	path    string
+	convert func(data interface{}) interface{}
+}
+
+// versions lists all supported API versions ordered from the oldest to the latest.
+var versions = []version{
+	{name: LatestVersion},
+}
+
+// convert returns the response data in the shape of this version.
+func (self version) convert(path string, data interface{}) interface{} {
+	for _, c := range self.compat {
+		if c.path == path {
+			data = c.convert(data)
+		}
+	}
+	return data
+}
+
+// setHeaders adds the version headers to all responses
+// so clients can detect when they use a deprecated version.
+func (self version) setHeaders(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Telliot-Api-Version", self.name)
+		if self.deprecated {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", `</api/`+LatestVersion+`>; rel="successor-version"`)
+		}
+		h(w, r)
+	}
+}
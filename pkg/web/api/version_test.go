@@ -0,0 +1,41 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestVersionCompat(t *testing.T) {
+	api := &API{logger: log.NewNopLogger()}
+	fn := func(r *http.Request) apiFuncResult {
+		return apiFuncResult{[]string{"new"}, nil, nil, nil}
+	}
+
+	v := version{
+		name:       "v0",
+		deprecated: true,
+		compat: []compat{{
+			path:    "/test",
+			convert: func(data interface{}) interface{} { return "old" },
+		}},
+	}
+
+	rec := httptest.NewRecorder()
+	v.setHeaders(api.wrap(v, "/test", fn))(rec, httptest.NewRequest("GET", "/api/v0/test", nil))
+	testutil.Equals(t, `{"status":"success","data":"old"}`, rec.Body.String())
+	testutil.Equals(t, "v0", rec.Header().Get("X-Telliot-Api-Version"))
+	testutil.Equals(t, "true", rec.Header().Get("Deprecation"))
+
+	latest := version{name: LatestVersion}
+	rec = httptest.NewRecorder()
+	latest.setHeaders(api.wrap(latest, "/test", fn))(rec, httptest.NewRequest("GET", "/api/v1/test", nil))
+	testutil.Equals(t, `{"status":"success","data":["new"]}`, rec.Body.String())
+	testutil.Equals(t, "", rec.Header().Get("Deprecation"))
+}
@@ -0,0 +1,178 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// minCompressSize is the minimum response size to compress.
+// Smaller responses are sent as they are because
+// the compression overhead is bigger than the saved bandwidth.
+const minCompressSize = 1024
+
+// compress wraps the handler and compresses the responses with gzip or deflate
+// depending on the encodings accepted by the client.
+// Responses that already have a content encoding are not modified.
+func compress(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.close()
+		h.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding returns the preferred supported encoding from an Accept-Encoding header
+// or an empty string when the client doesn't accept any of the supported encodings.
+func negotiateEncoding(header string) string {
+	var (
+		best  string
+		bestQ float64
+	)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(f, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if name == "*" {
+			name = "gzip"
+		}
+		if name != "gzip" && name != "deflate" {
+			continue
+		}
+		// Prefer gzip when both have the same weight.
+		if q > bestQ || (q == bestQ && name == "gzip") {
+			best, bestQ = name, q
+		}
+	}
+	if bestQ <= 0 {
+		return ""
+	}
+	return best
+}
+
+// compressWriter buffers the beginning of the response
+// and starts compressing only when it is big enough.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	status      int
+	buf         []byte
+	writer      io.WriteCloser
+	passThrough bool
+}
+
+func (self *compressWriter) WriteHeader(code int) {
+	if self.status == 0 {
+		self.status = code
+	}
+}
+
+func (self *compressWriter) Write(p []byte) (int, error) {
+	if self.status == 0 {
+		self.status = http.StatusOK
+	}
+	switch {
+	case self.writer != nil:
+		return self.writer.Write(p)
+	case self.passThrough:
+		return self.ResponseWriter.Write(p)
+	}
+
+	// Handlers that already encode their responses like the
+	// metrics and remote read endpoints are not compressed again.
+	if self.Header().Get("Content-Encoding") != "" || self.status == http.StatusNoContent || self.status == http.StatusNotModified {
+		self.startPassThrough()
+		return self.ResponseWriter.Write(p)
+	}
+
+	self.buf = append(self.buf, p...)
+	if len(self.buf) >= minCompressSize {
+		if err := self.startCompression(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends the buffered data to the client.
+func (self *compressWriter) Flush() {
+	if self.writer == nil && !self.passThrough {
+		if err := self.startCompression(); err != nil {
+			return
+		}
+	}
+	if f, ok := self.writer.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := self.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (self *compressWriter) startPassThrough() {
+	self.passThrough = true
+	if self.status == 0 {
+		self.status = http.StatusOK
+	}
+	self.ResponseWriter.WriteHeader(self.status)
+}
+
+func (self *compressWriter) startCompression() error {
+	if self.status == 0 {
+		self.status = http.StatusOK
+	}
+	self.Header().Set("Content-Encoding", self.encoding)
+	self.Header().Del("Content-Length")
+	self.ResponseWriter.WriteHeader(self.status)
+
+	switch self.encoding {
+	case "gzip":
+		self.writer = gzip.NewWriter(self.ResponseWriter)
+	default:
+		// Deflate in HTTP means the zlib format.
+		self.writer = zlib.NewWriter(self.ResponseWriter)
+	}
+
+	buf := self.buf
+	self.buf = nil
+	_, err := self.writer.Write(buf)
+	return err
+}
+
+// close flushes the compressor or when the response
+// was too small to compress writes it as it is.
+func (self *compressWriter) close() {
+	if self.writer != nil {
+		_ = self.writer.Close()
+		return
+	}
+	if self.passThrough {
+		return
+	}
+	if self.status == 0 && len(self.buf) == 0 {
+		// The handler didn't write anything so let the server send the default response.
+		return
+	}
+	self.startPassThrough()
+	_, _ = self.ResponseWriter.Write(self.buf)
+}
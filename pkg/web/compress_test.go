@@ -0,0 +1,58 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("telliot", minCompressSize)
+	h := compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			_, _ = w.Write([]byte(large))
+		case "/small":
+			_, _ = w.Write([]byte("ok"))
+		case "/encoded":
+			w.Header().Set("Content-Encoding", "snappy")
+			_, _ = w.Write([]byte(large))
+		}
+	}))
+
+	do := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("/large", "deflate;q=0.5, gzip")
+	testutil.Equals(t, "gzip", rec.Header().Get("Content-Encoding"))
+	r, err := gzip.NewReader(rec.Body)
+	testutil.Ok(t, err)
+	body, err := ioutil.ReadAll(r)
+	testutil.Ok(t, err)
+	testutil.Equals(t, large, string(body))
+
+	rec = do("/large", "gzip;q=0")
+	testutil.Equals(t, "", rec.Header().Get("Content-Encoding"))
+	testutil.Equals(t, large, rec.Body.String())
+
+	rec = do("/small", "gzip")
+	testutil.Equals(t, "", rec.Header().Get("Content-Encoding"))
+	testutil.Equals(t, "ok", rec.Body.String())
+
+	rec = do("/encoded", "gzip")
+	testutil.Equals(t, "snappy", rec.Header().Get("Content-Encoding"))
+	testutil.Equals(t, large, rec.Body.String())
+}
@@ -0,0 +1,56 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CorsConfig controls which cross-origin requests are allowed to use the API.
+// An empty AllowedOrigins list disables the CORS headers completely.
+type CorsConfig struct {
+	AllowedOrigins []string `help:"Origins allowed to make cross-origin requests, like the origin of a dashboard. Use * to allow any origin. No CORS headers are sent when empty."`
+	AllowedMethods []string `help:"Methods allowed for cross-origin requests."`
+	AllowedHeaders []string `help:"Request headers allowed for cross-origin requests."`
+}
+
+// cors wraps the handler and sets the CORS headers
+// for requests coming from an allowed origin.
+// Preflight requests are answered directly without calling the wrapped handler.
+func cors(cfg CorsConfig, h http.Handler) http.Handler {
+	if len(cfg.AllowedOrigins) == 0 {
+		return h
+	}
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !originAllowed(cfg.AllowedOrigins, origin) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func originAllowed(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
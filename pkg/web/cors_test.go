@@ -0,0 +1,49 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestCors(t *testing.T) {
+	cfg := CorsConfig{
+		AllowedOrigins: []string{"https://dashboard.example"},
+		AllowedMethods: []string{"GET"},
+		AllowedHeaders: []string{"Content-Type"},
+	}
+	h := cors(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Allowed origin.
+	req := httptest.NewRequest("GET", "/api/v1/query", nil)
+	req.Header.Set("Origin", "https://dashboard.example")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	testutil.Equals(t, http.StatusOK, rec.Code)
+	testutil.Equals(t, "https://dashboard.example", rec.Header().Get("Access-Control-Allow-Origin"))
+
+	// Preflight.
+	req = httptest.NewRequest("OPTIONS", "/api/v1/query", nil)
+	req.Header.Set("Origin", "https://dashboard.example")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	testutil.Equals(t, http.StatusNoContent, rec.Code)
+	testutil.Equals(t, "GET", rec.Header().Get("Access-Control-Allow-Methods"))
+	testutil.Equals(t, "Content-Type", rec.Header().Get("Access-Control-Allow-Headers"))
+
+	// Not allowed origin.
+	req = httptest.NewRequest("GET", "/api/v1/query", nil)
+	req.Header.Set("Origin", "https://other.example")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	testutil.Equals(t, http.StatusOK, rec.Code)
+	testutil.Equals(t, "", rec.Header().Get("Access-Control-Allow-Origin"))
+}
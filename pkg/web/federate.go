@@ -0,0 +1,123 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/golang/protobuf/proto"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/timestamp"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// federateLookback is how far back to look for the latest sample of a series.
+const federateLookback = 5 * time.Minute
+
+// defaultFederateMatcher selects the tracked values when the request doesn't set any match[] parameter.
+const defaultFederateMatcher = `{__name__=~"indexTracker_value|oracle_value|psr_value|tip_total"}`
+
+// federate exposes the latest sample of every matching stored series
+// in the Prometheus exposition format so that an external Prometheus
+// can scrape the tracked values directly into its own TSDB.
+func federate(logger log.Logger, tsDB storage.Queryable) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "error parsing form values: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		selectors := r.Form["match[]"]
+		if len(selectors) == 0 {
+			selectors = []string{defaultFederateMatcher}
+		}
+		var matcherSets [][]*labels.Matcher
+		for _, s := range selectors {
+			matchers, err := parser.ParseMetricSelector(s)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			matcherSets = append(matcherSets, matchers)
+		}
+
+		maxt := timestamp.FromTime(time.Now())
+		mint := maxt - federateLookback.Milliseconds()
+		q, err := tsDB.Querier(r.Context(), mint, maxt)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer q.Close()
+
+		hints := &storage.SelectHints{Start: mint, End: maxt}
+		var sets []storage.SeriesSet
+		for _, matchers := range matcherSets {
+			sets = append(sets, q.Select(true, hints, matchers...))
+		}
+		set := storage.NewMergeSeriesSet(sets, storage.ChainedSeriesMerge)
+
+		families := make(map[string]*dto.MetricFamily)
+		for set.Next() {
+			series := set.At()
+
+			// Find the latest sample within the lookback period.
+			var (
+				t     int64
+				v     float64
+				found bool
+			)
+			it := series.Iterator()
+			for it.Next() {
+				t, v = it.At()
+				found = true
+			}
+			if it.Err() != nil || !found {
+				continue
+			}
+
+			m := &dto.Metric{TimestampMs: proto.Int64(t), Untyped: &dto.Untyped{Value: proto.Float64(v)}}
+			var name string
+			for _, l := range series.Labels() {
+				if l.Name == labels.MetricName {
+					name = l.Value
+					continue
+				}
+				m.Label = append(m.Label, &dto.LabelPair{Name: proto.String(l.Name), Value: proto.String(l.Value)})
+			}
+			family, ok := families[name]
+			if !ok {
+				family = &dto.MetricFamily{Name: proto.String(name), Type: dto.MetricType_UNTYPED.Enum()}
+				families[name] = family
+			}
+			family.Metric = append(family.Metric, m)
+		}
+		if err := set.Err(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		names := make([]string, 0, len(families))
+		for name := range families {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		format := expfmt.Negotiate(r.Header)
+		w.Header().Set("Content-Type", string(format))
+		enc := expfmt.NewEncoder(w, format)
+		for _, name := range names {
+			if err := enc.Encode(families[name]); err != nil {
+				level.Error(logger).Log("msg", "federation encoding", "err", err)
+				return
+			}
+		}
+	}
+}
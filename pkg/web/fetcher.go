@@ -20,7 +20,8 @@ func Get(ctx context.Context, url string, headers map[string]string) ([]byte, er
 	client := http.Client{Transport: tr}
 	ticker := time.NewTicker(1 * time.Second)
 
-	req, err := http.NewRequest("GET", url, nil)
+	// The context aborts the request in flight as well, for example after the timeout of the source.
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
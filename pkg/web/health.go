@@ -0,0 +1,93 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// checkTimeout limits how long a single readiness check can take
+// so that a hanging component doesn't block the probe.
+const checkTimeout = 5 * time.Second
+
+// ReadinessCheck returns an error when the component
+// is not yet ready or is not working as expected.
+type ReadinessCheck func(ctx context.Context) error
+
+type checkResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type healthResponse struct {
+	Status string        `json:"status"`
+	Checks []checkResult `json:"checks,omitempty"`
+}
+
+type health struct {
+	mtx    sync.Mutex
+	names  []string
+	checks []ReadinessCheck
+}
+
+func (self *health) add(name string, check ReadinessCheck) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.names = append(self.names, name)
+	self.checks = append(self.checks, check)
+}
+
+// serveLive reports that the process is up and able to handle requests.
+func (self *health) serveLive(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// serveReady runs all registered checks concurrently and
+// responds with 503 when any of them fails.
+func (self *health) serveReady(w http.ResponseWriter, r *http.Request) {
+	self.mtx.Lock()
+	names := append([]string(nil), self.names...)
+	checks := append([]ReadinessCheck(nil), self.checks...)
+	self.mtx.Unlock()
+
+	results := make([]checkResult, len(checks))
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+			defer cancel()
+			results[i] = checkResult{Name: names[i], Status: "ok"}
+			if err := checks[i](ctx); err != nil {
+				results[i].Status = "fail"
+				results[i].Error = err.Error()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	resp := healthResponse{Status: "ready", Checks: results}
+	code := http.StatusOK
+	for _, result := range results {
+		if result.Error != "" {
+			resp.Status = "not ready"
+			code = http.StatusServiceUnavailable
+			break
+		}
+	}
+	writeHealth(w, code, resp)
+}
+
+func writeHealth(w http.ResponseWriter, code int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestReady(t *testing.T) {
+	h := &health{}
+	h.add("ok", func(context.Context) error { return nil })
+
+	rec := httptest.NewRecorder()
+	h.serveReady(rec, httptest.NewRequest("GET", "/ready", nil))
+	testutil.Equals(t, http.StatusOK, rec.Code)
+
+	h.add("broken", func(context.Context) error { return errors.New("not connected") })
+
+	rec = httptest.NewRecorder()
+	h.serveReady(rec, httptest.NewRequest("GET", "/ready", nil))
+	testutil.Equals(t, http.StatusServiceUnavailable, rec.Code)
+
+	var resp healthResponse
+	testutil.Ok(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	testutil.Equals(t, "not ready", resp.Status)
+	testutil.Equals(t, []checkResult{
+		{Name: "ok", Status: "ok"},
+		{Name: "broken", Status: "fail", Error: "not connected"},
+	}, resp.Checks)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"crypto/subtle"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// sweepInterval is how often the limiters of the idle clients are removed.
+const sweepInterval = time.Minute
+
+// RateLimitConfig limits how many requests a single client can make
+// so that an exposed API can't overload the storage engine.
+type RateLimitConfig struct {
+	RequestsPerSecond  float64 `help:"Maximum sustained requests per second for a single client IP or for the clients with the api token. 0 disables the rate limiting."`
+	Burst              int     `help:"Maximum number of requests a single client can make at once above the sustained rate."`
+	MaxRequestBodySize int64   `help:"Maximum size in bytes of a request body. 0 means no limit."`
+}
+
+type visitor struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	cfg       RateLimitConfig
+	token     string
+	idle      time.Duration
+	mtx       sync.Mutex
+	visitors  map[string]*visitor
+	lastSweep time.Time
+}
+
+// rateLimit wraps the handler and rejects requests
+// from clients that exceed the configured rate.
+// The health check endpoints are never limited so that probes keep working.
+// The clients with the api token share one limiter and all others are limited by their IP.
+func rateLimit(cfg RateLimitConfig, token string, h http.Handler) http.Handler {
+	if cfg.MaxRequestBodySize > 0 {
+		next := h
+		h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, cfg.MaxRequestBodySize)
+			next.ServeHTTP(w, r)
+		})
+	}
+	if cfg.RequestsPerSecond <= 0 {
+		return h
+	}
+
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+	l := &rateLimiter{
+		cfg:      cfg,
+		token:    token,
+		visitors: make(map[string]*visitor),
+		// After this time the bucket of a client is full again
+		// so removing it doesn't change its limit.
+		idle: time.Duration(float64(cfg.Burst) / cfg.RequestsPerSecond * float64(time.Second)),
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.URL.Path == "/ready" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if !l.allow(l.clientKey(r), time.Now()) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(1/cfg.RequestsPerSecond))))
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (self *rateLimiter) allow(key string, now time.Time) bool {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	// Remove the clients with a full bucket
+	// to avoid growing the map forever.
+	if now.Sub(self.lastSweep) > sweepInterval {
+		for k, v := range self.visitors {
+			if now.Sub(v.lastSeen) >= self.idle {
+				delete(self.visitors, k)
+			}
+		}
+		self.lastSweep = now
+	}
+
+	v, ok := self.visitors[key]
+	if !ok {
+		v = &visitor{limiter: rate.NewLimiter(rate.Limit(self.cfg.RequestsPerSecond), self.cfg.Burst)}
+		self.visitors[key] = v
+	}
+	v.lastSeen = now
+	return v.limiter.AllowN(now, 1)
+}
+
+// clientKey identifies the client by the api token when it is the configured one or otherwise by its IP address,
+// so a client can't get a new limiter for every request by sending random tokens.
+func (self *rateLimiter) clientKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); self.token != "" && strings.HasPrefix(auth, "Bearer ") {
+		if subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(self.token)) == 1 {
+			return "token"
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
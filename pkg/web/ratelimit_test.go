@@ -0,0 +1,58 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestRateLimit(t *testing.T) {
+	h := rateLimit(RateLimitConfig{RequestsPerSecond: 0.001, Burst: 2}, "token", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(path, remoteAddr, token string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = remoteAddr
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Burst allows 2 requests and the third one is rejected.
+	testutil.Equals(t, http.StatusOK, do("/api/v1/query", "10.0.0.1:1234", ""))
+	testutil.Equals(t, http.StatusOK, do("/api/v1/query", "10.0.0.1:1235", ""))
+	testutil.Equals(t, http.StatusTooManyRequests, do("/api/v1/query", "10.0.0.1:1236", ""))
+
+	// Other clients have their own limit.
+	testutil.Equals(t, http.StatusOK, do("/api/v1/query", "10.0.0.2:1234", ""))
+	testutil.Equals(t, http.StatusOK, do("/api/v1/query", "10.0.0.1:1234", "token"))
+
+	// A wrong token doesn't get its own limiter.
+	testutil.Equals(t, http.StatusTooManyRequests, do("/api/v1/query", "10.0.0.1:1234", "random"))
+
+	// Health checks are never limited.
+	testutil.Equals(t, http.StatusOK, do("/ready", "10.0.0.1:1234", ""))
+}
+
+func TestRateLimitEviction(t *testing.T) {
+	l := &rateLimiter{
+		cfg:      RateLimitConfig{RequestsPerSecond: 1, Burst: 2},
+		idle:     2 * time.Second,
+		visitors: make(map[string]*visitor),
+	}
+	now := time.Now()
+	testutil.Assert(t, l.allow("ip:10.0.0.1", now), "the first request should be allowed")
+	testutil.Assert(t, l.allow("ip:10.0.0.2", now.Add(sweepInterval)), "the first request should be allowed")
+	testutil.Assert(t, l.allow("ip:10.0.0.2", now.Add(2*sweepInterval)), "the request should be allowed")
+	testutil.Equals(t, 1, len(l.visitors), "the idle client should be removed")
+}
@@ -0,0 +1,65 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"runtime/metrics"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runtimeCollector exports the scalar runtime/metrics samples
+// that are not in the default Go collector, like the heap by class
+// and the scheduler stats, to diagnose memory growth on a running miner.
+type runtimeCollector struct {
+	samples []metrics.Sample
+	descs   []*prometheus.Desc
+	kinds   []prometheus.ValueType
+}
+
+func newRuntimeCollector() *runtimeCollector {
+	c := &runtimeCollector{}
+	for _, d := range metrics.All() {
+		if d.Kind != metrics.KindUint64 && d.Kind != metrics.KindFloat64 {
+			continue
+		}
+		kind := prometheus.GaugeValue
+		if d.Cumulative {
+			kind = prometheus.CounterValue
+		}
+		c.samples = append(c.samples, metrics.Sample{Name: d.Name})
+		c.descs = append(c.descs, prometheus.NewDesc(runtimeMetricName(d.Name), d.Description, nil, nil))
+		c.kinds = append(c.kinds, kind)
+	}
+	return c
+}
+
+// runtimeMetricName converts a name like /gc/heap/allocs:bytes to go_runtime_gc_heap_allocs_bytes.
+func runtimeMetricName(name string) string {
+	name = strings.NewReplacer("/", "_", ":", "_", "-", "_", "*", "x").Replace(strings.TrimPrefix(name, "/"))
+	return "go_runtime_" + name
+}
+
+func (self *runtimeCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range self.descs {
+		ch <- d
+	}
+}
+
+func (self *runtimeCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics.Read(self.samples)
+	for i, s := range self.samples {
+		var v float64
+		switch s.Value.Kind() {
+		case metrics.KindUint64:
+			v = float64(s.Value.Uint64())
+		case metrics.KindFloat64:
+			v = s.Value.Float64()
+		default:
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(self.descs[i], self.kinds[i], v)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tellor-io/telliot/pkg/testutil"
+)
+
+func TestRuntimeCollector(t *testing.T) {
+	testutil.Equals(t, "go_runtime_gc_heap_allocs_bytes", runtimeMetricName("/gc/heap/allocs:bytes"))
+
+	reg := prometheus.NewRegistry()
+	testutil.Ok(t, reg.Register(newRuntimeCollector()))
+	families, err := reg.Gather()
+	testutil.Ok(t, err)
+
+	names := make(map[string]bool)
+	for _, f := range families {
+		names[f.GetName()] = true
+	}
+	testutil.Assert(t, names["go_runtime_sched_goroutines_goroutines"], "missing the goroutines metric")
+}
@@ -0,0 +1,80 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// StatusProvider returns the current status of a component.
+// The result is included in the /api/v1/status response and
+// should be a struct or a slice that can be encoded as JSON.
+type StatusProvider func(ctx context.Context) (interface{}, error)
+
+type componentStatus struct {
+	Name  string      `json:"name"`
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+type statusData struct {
+	Components []componentStatus `json:"components"`
+}
+
+type statusResponse struct {
+	Status string     `json:"status"`
+	Data   statusData `json:"data"`
+}
+
+type status struct {
+	mtx       sync.Mutex
+	names     []string
+	providers []StatusProvider
+}
+
+func (self *status) add(name string, provider StatusProvider) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.names = append(self.names, name)
+	self.providers = append(self.providers, provider)
+}
+
+// serve collects the status of all components in a single document.
+// A failing provider doesn't fail the whole request,
+// its error is included in the result instead.
+func (self *status) serve(w http.ResponseWriter, r *http.Request) {
+	self.mtx.Lock()
+	names := append([]string(nil), self.names...)
+	providers := append([]StatusProvider(nil), self.providers...)
+	self.mtx.Unlock()
+
+	components := make([]componentStatus, len(providers))
+	var wg sync.WaitGroup
+	for i := range providers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+			defer cancel()
+			components[i].Name = names[i]
+			data, err := providers[i](ctx)
+			if err != nil {
+				components[i].Error = err.Error()
+				return
+			}
+			components[i].Data = data
+		}(i)
+	}
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(statusResponse{
+		Status: "success",
+		Data:   statusData{Components: components},
+	})
+}
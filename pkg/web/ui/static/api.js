@@ -0,0 +1,102 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+"use strict";
+
+// The API documentation is rendered from the OpenAPI specification
+// with the embedded assets only so that the page works without access to a CDN.
+
+function el(tag, attrs, ...children) {
+  const e = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    e.setAttribute(k, v);
+  }
+  for (const c of children) {
+    e.append(c instanceof Node ? c : document.createTextNode(String(c)));
+  }
+  return e;
+}
+
+// call sends the request of an endpoint with the values of its form
+// and shows the response below it.
+async function call(method, path, op, form, out) {
+  let url = path;
+  const query = new URLSearchParams();
+  for (const p of op.parameters || []) {
+    const value = form.elements[p.name].value;
+    if (value === "") {
+      continue;
+    }
+    if (p.in === "path") {
+      url = url.replace("{" + p.name + "}", encodeURIComponent(value));
+    } else {
+      query.append(p.name, value);
+    }
+  }
+  if (query.toString() !== "") {
+    url += "?" + query.toString();
+  }
+  const headers = {};
+  const token = document.getElementById("token").value;
+  if (op.security && token !== "") {
+    headers["Authorization"] = "Bearer " + token;
+  }
+  out.textContent = method.toUpperCase() + " " + url + "\n";
+  try {
+    const resp = await fetch(url, { method: method.toUpperCase(), headers: headers });
+    let text = await resp.text();
+    try {
+      text = JSON.stringify(JSON.parse(text), null, 2);
+    } catch (err) {
+      // Not all endpoints respond with JSON.
+    }
+    out.textContent += resp.status + " " + resp.statusText + "\n\n" + text;
+  } catch (err) {
+    out.textContent += err;
+  }
+}
+
+function renderEndpoint(server, path, method, op) {
+  const form = el("form", {});
+  const params = op.parameters || [];
+  if (params.length > 0) {
+    form.append(el("table", {},
+      el("thead", {}, el("tr", {}, el("th", {}, "Parameter"), el("th", {}, "In"), el("th", {}, "Description"), el("th", {}, "Value"))),
+      el("tbody", {}, ...params.map((p) => el("tr", {},
+        el("td", {}, p.name + (p.required ? " *" : "")),
+        el("td", {}, p.in),
+        el("td", {}, p.description || ""),
+        el("td", {}, el("input", { name: p.name })),
+      ))),
+    ));
+  }
+  const out = el("pre", { class: "response" });
+  form.append(el("button", { type: "submit" }, "Send"));
+  form.addEventListener("submit", (e) => {
+    e.preventDefault();
+    call(method, server + path, op, form, out);
+  });
+  return el("details", { class: "card" },
+    el("summary", {}, el("b", {}, method.toUpperCase()), " " + server + path + " - " + op.summary + (op.security ? " (token)" : "")),
+    form,
+    out,
+  );
+}
+
+async function render() {
+  const main = document.getElementById("endpoints");
+  try {
+    const resp = await fetch("/api/openapi.json");
+    const doc = await resp.json();
+    const server = doc.servers && doc.servers.length > 0 ? doc.servers[0].url : "";
+    for (const path of Object.keys(doc.paths).sort()) {
+      for (const [method, op] of Object.entries(doc.paths[path])) {
+        main.append(renderEndpoint(server, path, method, op));
+      }
+    }
+  } catch (err) {
+    main.append(el("p", { class: "fail" }, "Loading the API specification: " + err));
+  }
+}
+
+render();
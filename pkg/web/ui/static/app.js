@@ -0,0 +1,264 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+"use strict";
+
+const refreshInterval = 30 * 1000;
+const sparkRange = 6 * 3600;
+const sparkStep = 300;
+
+async function query(q) {
+  const resp = await fetch("/api/v1/query?query=" + encodeURIComponent(q));
+  const body = await resp.json();
+  if (body.status !== "success") {
+    throw new Error(body.error);
+  }
+  return body.data.result;
+}
+
+async function queryRange(q, start, end, step) {
+  const params = new URLSearchParams({ query: q, start: start, end: end, step: step });
+  const resp = await fetch("/api/v1/query_range?" + params.toString());
+  const body = await resp.json();
+  if (body.status !== "success") {
+    throw new Error(body.error);
+  }
+  return body.data.result;
+}
+
+// metrics returns the process metrics in the Prometheus text format
+// parsed as a list of {name, labels, value}.
+async function metrics() {
+  const resp = await fetch("/metrics");
+  if (!resp.ok) {
+    throw new Error("metrics status " + resp.status);
+  }
+  const text = await resp.text();
+  const out = [];
+  for (const line of text.split("\n")) {
+    if (line === "" || line.startsWith("#")) {
+      continue;
+    }
+    const m = line.match(/^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{(.*)\})?\s+(\S+)/);
+    if (!m) {
+      continue;
+    }
+    const labels = {};
+    if (m[3]) {
+      for (const pair of m[3].matchAll(/([a-zA-Z_][a-zA-Z0-9_]*)="((?:[^"\\]|\\.)*)"/g)) {
+        labels[pair[1]] = pair[2];
+      }
+    }
+    out.push({ name: m[1], labels: labels, value: parseFloat(m[4]) });
+  }
+  return out;
+}
+
+function el(tag, attrs, ...children) {
+  const e = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    e.setAttribute(k, v);
+  }
+  for (const c of children) {
+    e.append(c instanceof Node ? c : document.createTextNode(String(c)));
+  }
+  return e;
+}
+
+function fmt(v) {
+  if (!isFinite(v)) {
+    return "-";
+  }
+  if (Math.abs(v) >= 1000) {
+    return v.toFixed(2);
+  }
+  return v.toPrecision(6);
+}
+
+function fmtAge(seconds) {
+  if (seconds < 120) {
+    return Math.round(seconds) + "s ago";
+  }
+  if (seconds < 7200) {
+    return Math.round(seconds / 60) + "m ago";
+  }
+  return Math.round(seconds / 3600) + "h ago";
+}
+
+function sparkline(values) {
+  const w = 160;
+  const h = 32;
+  const svg = document.createElementNS("http://www.w3.org/2000/svg", "svg");
+  svg.setAttribute("class", "spark");
+  svg.setAttribute("width", w);
+  svg.setAttribute("height", h);
+  if (values.length < 2) {
+    return svg;
+  }
+  const min = Math.min(...values);
+  const max = Math.max(...values);
+  const span = max - min || 1;
+  const points = values.map((v, i) => {
+    const x = (i / (values.length - 1)) * w;
+    const y = h - 2 - ((v - min) / span) * (h - 4);
+    return x.toFixed(1) + "," + y.toFixed(1);
+  });
+  const line = document.createElementNS("http://www.w3.org/2000/svg", "polyline");
+  line.setAttribute("points", points.join(" "));
+  svg.append(line);
+  return svg;
+}
+
+function fill(id, rows, cols) {
+  const tbody = document.querySelector("#" + id + " tbody");
+  tbody.replaceChildren();
+  if (rows.length === 0) {
+    tbody.append(el("tr", {}, el("td", { class: "empty", colspan: cols }, "no data")));
+    return;
+  }
+  for (const r of rows) {
+    tbody.append(r);
+  }
+}
+
+function fail(id, cols, err) {
+  const tbody = document.querySelector("#" + id + " tbody");
+  tbody.replaceChildren(el("tr", {}, el("td", { class: "fail", colspan: cols }, "unavailable: " + err.message)));
+}
+
+async function renderSymbols() {
+  const now = Date.now() / 1000;
+  const [current, counts, history] = await Promise.all([
+    query("avg by(symbol)(indexTracker_value)"),
+    query("count by(symbol)(indexTracker_value)"),
+    queryRange("avg by(symbol)(indexTracker_value)", now - sparkRange, now, sparkStep),
+  ]);
+  const sources = {};
+  for (const c of counts) {
+    sources[c.metric.symbol] = c.value[1];
+  }
+  const spark = {};
+  for (const h of history) {
+    spark[h.metric.symbol] = h.values.map((v) => parseFloat(v[1]));
+  }
+  current.sort((a, b) => a.metric.symbol.localeCompare(b.metric.symbol));
+  fill("symbols", current.map((s) => el("tr", {},
+    el("td", {}, s.metric.symbol),
+    el("td", { class: "num" }, fmt(parseFloat(s.value[1]))),
+    el("td", { class: "num" }, sources[s.metric.symbol] || 0),
+    el("td", {}, sparkline(spark[s.metric.symbol] || [])),
+  )), 4);
+}
+
+async function renderSources() {
+  const [age, interval] = await Promise.all([
+    query("time() - max by(symbol, source)(max_over_time(timestamp(indexTracker_value)[1h:1m]))"),
+    query("max by(symbol, source)(last_over_time(indexTracker_interval[1h])) / 1e9"),
+  ]);
+  const intervals = {};
+  for (const i of interval) {
+    intervals[i.metric.symbol + i.metric.source] = parseFloat(i.value[1]);
+  }
+  age.sort((a, b) => a.metric.symbol.localeCompare(b.metric.symbol) || a.metric.source.localeCompare(b.metric.source));
+  fill("sources", age.map((s) => {
+    const seconds = parseFloat(s.value[1]);
+    const expected = intervals[s.metric.symbol + s.metric.source] || 60;
+    let status = ["ok", "healthy"];
+    if (seconds > 5 * expected) {
+      status = ["fail", "stale"];
+    } else if (seconds > 2 * expected) {
+      status = ["warn", "lagging"];
+    }
+    return el("tr", {},
+      el("td", {}, s.metric.symbol),
+      el("td", {}, s.metric.source),
+      el("td", {}, fmtAge(seconds)),
+      el("td", { class: status[0] }, status[1]),
+    );
+  }), 4);
+}
+
+async function renderSubmissions() {
+  const [submitted, local] = await Promise.all([
+    query("last_over_time(oracle_value[6h])"),
+    query("last_over_time(psr_value[6h])"),
+  ]);
+  const expected = {};
+  for (const l of local) {
+    expected[l.metric.id] = parseFloat(l.value[1]);
+  }
+  submitted.sort((a, b) => parseInt(a.metric.id) - parseInt(b.metric.id));
+  fill("submissions", submitted.map((s) => {
+    const val = parseFloat(s.value[1]);
+    const exp = expected[s.metric.id];
+    const diff = exp ? ((val - exp) / exp) * 100 : NaN;
+    return el("tr", {},
+      el("td", {}, s.metric.id),
+      el("td", {}, s.metric.miner),
+      el("td", { class: "num" }, val),
+      el("td", { class: "num" }, exp === undefined ? "-" : exp),
+      el("td", { class: "num " + (Math.abs(diff) > 5 ? "fail" : "") }, isFinite(diff) ? diff.toFixed(2) + "%" : "-"),
+    );
+  }), 5);
+}
+
+async function renderMetrics() {
+  const all = await metrics();
+  const byAddr = {};
+  const account = (addr) => {
+    byAddr[addr] = byAddr[addr] || { profit: 0, cost: 0, TRB: NaN, ETH: NaN };
+    return byAddr[addr];
+  };
+  let submits = 0;
+  let fails = 0;
+  let fetchErrors = 0;
+  for (const m of all) {
+    switch (m.name) {
+      case "telliot_profitTracker_submit_profit":
+        account(m.labels.addr).profit = m.value;
+        break;
+      case "telliot_profitTracker_submit_cost":
+        account(m.labels.addr).cost = m.value;
+        break;
+      case "telliot_profitTracker_balances":
+        account(m.labels.addr)[m.labels.token] = m.value;
+        break;
+      case "telliot_submitterTellor_submit_total":
+        submits += m.value;
+        break;
+      case "telliot_submitterTellor_submit_fails_total":
+        fails += m.value;
+        break;
+      case "telliot_indexTracker_errors_total":
+        fetchErrors += m.value;
+        break;
+    }
+  }
+  fill("profit", Object.entries(byAddr).map(([addr, a]) => el("tr", {},
+    el("td", {}, addr),
+    el("td", { class: "num" }, fmt(a.profit)),
+    el("td", { class: "num" }, fmt(a.cost)),
+    el("td", { class: "num" }, fmt(a.TRB)),
+    el("td", { class: "num" }, fmt(a.ETH)),
+  )), 5);
+
+  const cards = document.getElementById("components");
+  cards.replaceChildren(
+    el("div", { class: "card " + (fails > 0 ? "warn" : "ok") }, "Submitter: " + submits + " submitted, " + fails + " failed"),
+    el("div", { class: "card " + (fetchErrors > 0 ? "warn" : "ok") }, "Index tracker: " + fetchErrors + " fetch errors"),
+  );
+}
+
+async function refresh() {
+  const tasks = [
+    ["symbols", 4, renderSymbols],
+    ["sources", 4, renderSources],
+    ["submissions", 5, renderSubmissions],
+    ["profit", 5, renderMetrics],
+  ];
+  await Promise.all(tasks.map(([id, cols, fn]) => fn().catch((err) => fail(id, cols, err))));
+  document.getElementById("updated").textContent = "updated " + new Date().toLocaleTimeString();
+}
+
+refresh();
+setInterval(refresh, refreshInterval);
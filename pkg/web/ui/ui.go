@@ -0,0 +1,34 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+package ui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/prometheus/common/route"
+)
+
+//go:embed static
+var static embed.FS
+
+// Register serves the embedded operator dashboard under the /ui prefix
+// and redirects the root path to it.
+func Register(r *route.Router) {
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		// Can't happen as the static folder is embedded at build time.
+		panic(err)
+	}
+	fileServer := http.StripPrefix("/ui", http.FileServer(http.FS(assets)))
+
+	r.Get("/", func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, "/ui/", http.StatusFound)
+	})
+	r.Get("/ui", func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, "/ui/", http.StatusMovedPermanently)
+	})
+	r.Get("/ui/*filepath", fileServer.ServeHTTP)
+}
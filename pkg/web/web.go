@@ -5,15 +5,18 @@ package web
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"os"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/route"
 	"github.com/prometheus/prometheus/promql"
@@ -21,90 +24,227 @@ import (
 	"github.com/tellor-io/telliot/pkg/format"
 	"github.com/tellor-io/telliot/pkg/logging"
 	"github.com/tellor-io/telliot/pkg/web/api"
+	"github.com/tellor-io/telliot/pkg/web/ui"
 )
 
-const ComponentName = "web"
+const (
+	ComponentName = "web"
+	// APITokenEnvName is the env variable with the bearer token
+	// required by the api endpoints that modify data.
+	APITokenEnvName = "API_TOKEN"
+)
 
 type Config struct {
-	LogLevel    string
-	ListenHost  string
-	ListenPort  uint
-	ReadTimeout format.Duration
+	LogLevel           string
+	ListenHost         string
+	ListenPort         uint
+	ReadTimeout        format.Duration
+	Cors               CorsConfig
+	QueryTimeout       format.Duration `help:"Maximum time a PromQL query can run before it is aborted."`
+	QueryMaxSamples    int             `help:"Maximum number of samples a single PromQL query can load in memory."`
+	QueryLookbackDelta format.Duration `help:"The maximum lookback duration for retrieving metrics during PromQL expression evaluations."`
+	QueryMaxSeries     int             `help:"Maximum number of series a single query can return. 0 means no limit."`
+	RateLimit          RateLimitConfig
+	MetricsListenHost  string `help:"Host for a separate listener serving the metrics, debug and health endpoints."`
+	MetricsListenPort  uint   `help:"Port for a separate listener serving the metrics, debug and health endpoints. When 0 these are served on the main listener together with the api."`
+	Debug              bool   `help:"Serve the pprof profiles under /debug/pprof and the expvars under /debug/vars, and add the Go runtime/metrics to /metrics as go_runtime_*. Best used with MetricsListenPort to keep these internal."`
 }
 
 type Web struct {
-	logger log.Logger
-	cfg    Config
-	ctx    context.Context
-	stop   context.CancelFunc
-	srv    *http.Server
+	logger         log.Logger
+	cfg            Config
+	ctx            context.Context
+	stop           context.CancelFunc
+	handler        http.Handler
+	metricsHandler http.Handler
+	srvMtx         sync.Mutex
+	srv            *http.Server
+	metricsSrv     *http.Server
+	health         *health
+	status         *status
+	api            *api.API
 }
 
 func New(logger log.Logger, ctx context.Context, tsDB storage.SampleAndChunkQueryable, cfg Config) (*Web, error) {
-	logger, err := logging.ApplyFilter(cfg.LogLevel, logger)
+	logger, err := logging.ApplyFilter(ComponentName, cfg.LogLevel, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "apply filter logger")
 	}
 	router := route.New()
 
-	router.Get("/debug/*subpath", serveDebug)
-	router.Post("/debug/*subpath", serveDebug)
+	health := &health{}
+	router.Get("/healthz", health.serveLive)
+	router.Get("/ready", health.serveReady)
+
+	// The metrics and debug endpoints can be served on a separate listener
+	// so that these can be kept internal while the api is exposed publicly.
+	var metricsHandler http.Handler
+	metricsRouter := router
+	if cfg.MetricsListenPort != 0 {
+		metricsRouter = route.New()
+		metricsRouter.Get("/healthz", health.serveLive)
+		metricsRouter.Get("/ready", health.serveReady)
+		metricsHandler = compress(metricsRouter)
+	}
+
+	if cfg.Debug {
+		metricsRouter.Get("/debug/*subpath", serveDebug)
+		metricsRouter.Post("/debug/*subpath", serveDebug)
+		if err := prometheus.Register(newRuntimeCollector()); err != nil {
+			return nil, errors.Wrap(err, "register the runtime metrics")
+		}
+	}
 
-	router.Get("/metrics", promhttp.Handler().ServeHTTP)
+	metricsRouter.Get("/metrics", promhttp.Handler().ServeHTTP)
 
 	opts := promql.EngineOpts{
 		Logger:               logger,
 		Reg:                  nil,
-		MaxSamples:           100000,
-		Timeout:              10 * time.Second,
+		MaxSamples:           cfg.QueryMaxSamples,
+		Timeout:              cfg.QueryTimeout.Duration,
+		LookbackDelta:        cfg.QueryLookbackDelta.Duration,
 		EnableAtModifier:     true,
 		EnableNegativeOffset: true,
 	}
 	engine := promql.NewEngine(opts)
 
-	api := api.New(logger, ctx, engine, tsDB)
-	api.Register(router.WithPrefix("/api/v1"))
+	apiToken := os.Getenv(APITokenEnvName)
+	apiSrv := api.New(logger, ctx, engine, tsDB, apiToken, cfg.QueryMaxSeries)
+	apiSrv.Register(router)
+	router.Get("/federate", federate(logger, tsDB))
+
+	status := &status{}
+	router.Get(api.Prefix+"/status", status.serve)
+	router.Get("/api/openapi.json", apiSrv.ServeOpenAPI)
+	router.Get("/api/docs", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ui/api.html", http.StatusFound)
+	})
+
+	ui.Register(router)
 
 	mux := http.NewServeMux()
 	mux.Handle("/", router)
 
-	srv := &http.Server{
-		Handler:     mux,
-		ReadTimeout: cfg.ReadTimeout.Duration,
-		Addr:        fmt.Sprintf("%s:%d", cfg.ListenHost, cfg.ListenPort),
-	}
-
 	ctx, stop := context.WithCancel(ctx)
 
 	return &Web{
-		logger: log.With(logger, "component", ComponentName),
-		cfg:    cfg,
-		ctx:    ctx,
-		stop:   stop,
-		srv:    srv,
+		logger:         log.With(logger, "component", ComponentName),
+		cfg:            cfg,
+		ctx:            ctx,
+		stop:           stop,
+		handler:        cors(cfg.Cors, rateLimit(cfg.RateLimit, apiToken, compress(mux))),
+		metricsHandler: metricsHandler,
+		health:         health,
+		status:         status,
+		api:            apiSrv,
 	}, nil
 
 }
 
+// Start serves until Stop is called or one of the listeners fails.
+// The servers are created on every start so that it can be started again after a failure.
 func (self *Web) Start() error {
-	level.Info(self.logger).Log("msg", "starting", "addr", self.srv.Addr)
-	if err := self.srv.ListenAndServe(); err != http.ErrServerClosed {
-		return errors.Wrapf(err, "ListenAndServe")
+	srv := &http.Server{
+		Handler:     self.handler,
+		ReadTimeout: self.cfg.ReadTimeout.Duration,
+		Addr:        fmt.Sprintf("%s:%d", self.cfg.ListenHost, self.cfg.ListenPort),
+	}
+	srvs := []*http.Server{srv}
+	var metricsSrv *http.Server
+	if self.metricsHandler != nil {
+		metricsSrv = &http.Server{
+			Handler:     self.metricsHandler,
+			ReadTimeout: self.cfg.ReadTimeout.Duration,
+			Addr:        fmt.Sprintf("%s:%d", self.cfg.MetricsListenHost, self.cfg.MetricsListenPort),
+		}
+		srvs = append(srvs, metricsSrv)
 	}
-	return nil
+	self.srvMtx.Lock()
+	if self.ctx.Err() != nil {
+		self.srvMtx.Unlock()
+		return nil
+	}
+	self.srv, self.metricsSrv = srv, metricsSrv
+	self.srvMtx.Unlock()
+
+	errs := make(chan error, 2)
+	if metricsSrv != nil {
+		go func() {
+			level.Info(self.logger).Log("msg", "starting metrics listener", "addr", metricsSrv.Addr)
+			if err := metricsSrv.ListenAndServe(); err != http.ErrServerClosed {
+				errs <- errors.Wrapf(err, "metrics ListenAndServe")
+				return
+			}
+			errs <- nil
+		}()
+	}
+
+	level.Info(self.logger).Log("msg", "starting", "addr", srv.Addr)
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			errs <- errors.Wrapf(err, "ListenAndServe")
+			return
+		}
+		errs <- nil
+	}()
+
+	// Return on the first error or when the servers are closed.
+	err := <-errs
+	if err != nil {
+		// Close the other listener so that a restart can listen again.
+		for _, srv := range srvs {
+			srv.Close()
+		}
+		for i := 1; i < len(srvs); i++ {
+			<-errs
+		}
+	}
+	return err
+}
+
+// AddReadinessCheck registers a check that is run on every call to the /ready endpoint.
+// The endpoint reports not ready when any of the checks returns an error.
+func (self *Web) AddReadinessCheck(name string, check ReadinessCheck) {
+	self.health.add(name, check)
+}
+
+// AddStatusProvider registers a component that is included in the /api/v1/status response.
+func (self *Web) AddStatusProvider(name string, provider StatusProvider) {
+	self.status.add(name, provider)
+}
+
+// AddAPIHandler registers a handler for a GET endpoint of another component under the API prefix
+// and adds it to the OpenAPI specification.
+func (self *Web) AddAPIHandler(path, summary string, handler http.HandlerFunc, params ...api.Param) {
+	self.api.AddHandler(path, summary, handler, params...)
 }
 
 func (self *Web) Stop() {
+	self.srvMtx.Lock()
+	defer self.srvMtx.Unlock()
 	self.stop()
+	if self.srv == nil {
+		return
+	}
 	if err := self.srv.Close(); err != nil {
 		level.Error(self.logger).Log("msg", "closing srv", "err", err)
 	}
+	if self.metricsSrv != nil {
+		if err := self.metricsSrv.Close(); err != nil {
+			level.Error(self.logger).Log("msg", "closing metrics srv", "err", err)
+		}
+	}
 }
 
 func serveDebug(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 	subpath := route.Param(ctx, "subpath")
 
+	if subpath == "/vars" {
+		expvar.Handler().ServeHTTP(w, req)
+		return
+	}
+
 	if subpath == "/pprof" {
 		http.Redirect(w, req, req.URL.Path+"/", http.StatusMovedPermanently)
 		return
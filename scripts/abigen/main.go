@@ -0,0 +1,41 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+// abigen generates the go binding of a contract from its ABI file
+// for the contracts without a verified deployment that contraget could download,
+// like the interfaces and the contracts of an upgrade before it is deployed.
+// The ABI files have only the part of the contract used by telliot.
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+func main() {
+	abiPath := flag.String("abi", "", "path of the ABI file")
+	typ := flag.String("type", "", "name of the contract type in the binding")
+	pkg := flag.String("pkg", "", "package name of the binding")
+	out := flag.String("out", "", "path of the generated binding")
+	flag.Parse()
+	if *abiPath == "" || *typ == "" || *pkg == "" || *out == "" {
+		flag.Usage()
+		log.Fatal("all flags are required")
+	}
+
+	abi, err := ioutil.ReadFile(*abiPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := bind.Bind([]string{*typ}, []string{strings.TrimSpace(string(abi))}, []string{""}, nil, *pkg, bind.LangGo, nil, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, []byte(code), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
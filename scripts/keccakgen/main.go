@@ -0,0 +1,124 @@
+// Copyright (c) The Tellor Authors.
+// Licensed under the MIT License.
+
+// keccakgen generates the AVX2 assembly of the keccak-f[1600] permutation
+// of 4 interleaved states used by the CPU mining kernel.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+// roundConstants are the iota constants of the 24 rounds.
+var roundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// rotations are the rho offsets of the lanes indexed by x+5y.
+var rotations = [25]uint{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// laneSize is the size of a lane of the 4 states.
+const laneSize = 32
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalf("usage: %v <output .s file>", os.Args[0])
+	}
+	var b bytes.Buffer
+	w := func(format string, args ...interface{}) {
+		fmt.Fprintf(&b, format+"\n", args...)
+	}
+
+	w("// Code generated by scripts/keccakgen. DO NOT EDIT.")
+	w("")
+	w("//go:build amd64")
+	w("// +build amd64")
+	w("")
+	w(`#include "textflag.h"`)
+	w("")
+	for i, rc := range roundConstants {
+		w("DATA rc<>+0x%02x(SB)/8, $0x%016x", i*8, rc)
+	}
+	w("GLOBL rc<>(SB), RODATA|NOPTR, $%d", len(roundConstants)*8)
+	w("")
+	w("// func keccakF1600x4(a *[25][4]uint64, b *[25][4]uint64)")
+	w("TEXT ·keccakF1600x4(SB), NOSPLIT, $0-16")
+	w("\tMOVQ a+0(FP), DI")
+	w("\tMOVQ b+8(FP), SI")
+	w("\tLEAQ rc<>(SB), R8")
+	w("\tMOVQ $%d, CX", len(roundConstants))
+	w("")
+	w("round:")
+
+	w("\t// Theta, the parities of the columns in Y0-Y4.")
+	for x := 0; x < 5; x++ {
+		w("\tVMOVDQU %d(DI), Y%d", x*laneSize, x)
+		for y := 1; y < 5; y++ {
+			w("\tVPXOR %d(DI), Y%d, Y%d", (x+5*y)*laneSize, x, x)
+		}
+	}
+	w("\t// D[x] = C[x-1] ^ rot(C[x+1], 1) in Y5-Y9.")
+	for x := 0; x < 5; x++ {
+		next, prev := (x+1)%5, (x+4)%5
+		w("\tVPSLLQ $1, Y%d, Y10", next)
+		w("\tVPSRLQ $63, Y%d, Y11", next)
+		w("\tVPOR Y10, Y11, Y11")
+		w("\tVPXOR Y%d, Y11, Y%d", prev, 5+x)
+	}
+
+	w("\t// Theta, rho and pi into b.")
+	for i := 0; i < 25; i++ {
+		x, y := i%5, i/5
+		dst := y + 5*((2*x+3*y)%5)
+		w("\tVMOVDQU %d(DI), Y0", i*laneSize)
+		w("\tVPXOR Y%d, Y0, Y0", 5+x)
+		if r := rotations[i]; r != 0 {
+			w("\tVPSLLQ $%d, Y0, Y1", r)
+			w("\tVPSRLQ $%d, Y0, Y0", 64-r)
+			w("\tVPOR Y1, Y0, Y0")
+		}
+		w("\tVMOVDQU Y0, %d(SI)", dst*laneSize)
+	}
+
+	w("\t// Chi and iota back into a.")
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			w("\tVMOVDQU %d(SI), Y%d", (x+5*y)*laneSize, x)
+		}
+		for x := 0; x < 5; x++ {
+			w("\tVPANDN Y%d, Y%d, Y10", (x+2)%5, (x+1)%5)
+			w("\tVPXOR Y%d, Y10, Y10", x)
+			if x == 0 && y == 0 {
+				w("\tVPBROADCASTQ (R8), Y11")
+				w("\tVPXOR Y11, Y10, Y10")
+			}
+			w("\tVMOVDQU Y10, %d(DI)", (x+5*y)*laneSize)
+		}
+	}
+
+	w("")
+	w("\tADDQ $8, R8")
+	w("\tDECQ CX")
+	w("\tJNZ round")
+	w("\tVZEROUPPER")
+	w("\tRET")
+
+	if err := ioutil.WriteFile(os.Args[1], b.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}